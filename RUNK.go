@@ -9,6 +9,7 @@ behavior is preserved.
 import (
 	"github.com/Patrick-ring-motive/utils"
 	"math"
+	"math/big"
 	"reflect"
 	"strconv"
 )
@@ -226,7 +227,7 @@ func fromSint[To Number, From Number](from From) To {
 				return To(sintToUint[uint](from))
 			case reflect.Uint8:
 				return To(sintToUint[uint8](from))
-			case reflect.Int16:
+			case reflect.Uint16:
 				return To(sintToUint[uint16](from))
 			case reflect.Uint32:
 				return To(sintToUint[uint32](from))
@@ -272,7 +273,7 @@ func sintToInt[To Number, From Number](from From, to To) To {
 				return To(sintToUint[uint](from))
 			case reflect.Uint8:
 				return To(sintToUint[uint8](from))
-			case reflect.Int16:
+			case reflect.Uint16:
 				return To(sintToUint[uint16](from))
 			case reflect.Uint32:
 				return To(sintToUint[uint32](from))
@@ -356,7 +357,7 @@ func fromUint[To Number, From Number](from From) To {
 				return To(uintToUint[uint](from))
 			case reflect.Uint8:
 				return To(uintToUint[uint8](from))
-			case reflect.Int16:
+			case reflect.Uint16:
 				return To(uintToUint[uint16](from))
 			case reflect.Uint32:
 				return To(uintToUint[uint32](from))
@@ -402,7 +403,7 @@ func uintToInt[To Number, From Number](from From, to To) To {
 				return To(uintToUint[uint](from))
 			case reflect.Uint8:
 				return To(uintToUint[uint8](from))
-			case reflect.Int16:
+			case reflect.Uint16:
 				return To(uintToUint[uint16](from))
 			case reflect.Uint32:
 				return To(uintToUint[uint32](from))
@@ -481,7 +482,7 @@ func fromFloat[To Number, From Number](from From, roundMode ...func(float64) flo
 				return To(floatToUint[uint](from, mode))
 			case reflect.Uint8:
 				return To(floatToUint[uint8](from, mode))
-			case reflect.Int16:
+			case reflect.Uint16:
 				return To(floatToUint[uint16](from, mode))
 			case reflect.Uint32:
 				return To(floatToUint[uint32](from, mode))
@@ -527,7 +528,7 @@ func floatToInt[To Number, From Number](from From, to To, roundMode func(float64
 				return To(floatToUint[uint](from, roundMode))
 			case reflect.Uint8:
 				return To(floatToUint[uint8](from, roundMode))
-			case reflect.Int16:
+			case reflect.Uint16:
 				return To(floatToUint[uint16](from, roundMode))
 			case reflect.Uint32:
 				return To(floatToUint[uint32](from, roundMode))
@@ -571,7 +572,7 @@ func floatToUint[To Number, From Number](from From, roundMode func(float64) floa
 	if math.IsInf(fl, 1) || fl > flmax || r > flmax {
 		return max
 	}
-	return To(from)
+	return To(r)
 }
 
 func floatToFloat[To Number, From Number](from From) To {
@@ -582,6 +583,7 @@ func floatToFloat[To Number, From Number](from From) To {
 ConvertNum is the most flexible conversion function as it accepts an any type.
 It is needed to make number conversion more concise. Even though it is intended to use with numbers, it will make a best effort to convert non number types. Typical usade looks like
 `ConvertNum[int](11.2)` which will return 11.
+A *big.Float (like the results of BigSqrt and friends) is rounded and saturated the same way, a nil one counts as NaN.
 */
 func ConvertNum[To Number](f any) To {
 	if f == nil {
@@ -614,6 +616,16 @@ func ConvertNum[To Number](f any) To {
 		return ConvertNumber[To](v)
 	case float64:
 		return ConvertNumber[To](v)
+	case *big.Float:
+		return bigFloatTo[To](v)
+	case Float16:
+		return Float16To[To](v)
+	case BFloat16:
+		return BFloat16To[To](v)
+	case Int128:
+		return Int128To[To](v)
+	case Uint128:
+		return Uint128To[To](v)
 	case bool:
 		if v {
 			return To(1)
@@ -952,7 +964,7 @@ func Hypot[N Number, M Number](x N, y M) N {
 }
 
 func Ilogb[N Number](num N) N {
-	return ConvertNumber[N](math.Gamma(float64(num)))
+	return ConvertNumber[N](math.Ilogb(float64(num)))
 }
 
 func Inf[N Number](num N) float64 {
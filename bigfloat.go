@@ -0,0 +1,836 @@
+package RUNK
+
+import (
+	"math"
+	"math/big"
+	"reflect"
+	"sync"
+)
+
+/*
+These are arbitrary precision versions of the main wrappers. They take a *big.Float and the precision in bits that
+the result should be rounded to. A prec of 0 uses the precision of x.
+Everything is computed with extra guard bits and then rounded once at the end, so the result is the correctly
+rounded value except in the rare cases where the true value lands within the guard bits of a rounding boundary.
+big.Float has no NaN so a nil *big.Float plays that role here. Anything that would be NaN in math returns nil and
+a nil argument propagates. ConvertNum understands *big.Float (nil included) so results can go back to any Number.
+*/
+
+/*how many extra bits everything is computed with before the final rounding*/
+const bigGuardBits = 64
+
+/*NewBigFloat converts any Number into a *big.Float. NaN gives nil. A prec of 0 picks a precision that is exact for N.*/
+func NewBigFloat[N Number](n N, prec uint) *big.Float {
+	if isFloat[N]() {
+		f := float64(n)
+		if math.IsNaN(f) {
+			return nil
+		}
+		if prec == 0 {
+			prec = 53
+		}
+		return new(big.Float).SetPrec(prec).SetFloat64(f)
+	}
+	if prec == 0 {
+		prec = 64
+	}
+	return new(big.Float).SetPrec(prec).SetInt(bigFromInt(n))
+}
+
+/*bigFloatTo converts a *big.Float into any Number with the same saturation as ConvertNumber*/
+func bigFloatTo[To Number](f *big.Float) To {
+	if f == nil {
+		return ConvertNumber[To](math.NaN())
+	}
+	var to To
+	if isFloat[To]() {
+		if reflect.TypeOf(to).Kind() == reflect.Float32 {
+			v, _ := f.Float32()
+			return To(v)
+		}
+		v, _ := f.Float64()
+		return To(v)
+	}
+	if f.IsInf() {
+		v, _ := f.Float64()
+		return ConvertNumber[To](v)
+	}
+	r, _ := f.Rat(nil)
+	return bigIntTo[To](roundBigQuo(r.Num(), r.Denom(), math.Round))
+}
+
+func resultPrec(x *big.Float, prec uint) uint {
+	if prec == 0 {
+		prec = x.Prec()
+	}
+	if prec == 0 {
+		prec = 53
+	}
+	return prec
+}
+
+func newBig(prec uint) *big.Float {
+	return new(big.Float).SetPrec(prec)
+}
+
+func bigInt64(n int64, prec uint) *big.Float {
+	return newBig(prec).SetInt64(n)
+}
+
+func roundBig(x *big.Float, prec uint) *big.Float {
+	if x == nil {
+		return nil
+	}
+	return newBig(prec).Set(x)
+}
+
+func bigInf(neg bool, prec uint) *big.Float {
+	return newBig(prec).SetInf(neg)
+}
+
+/*bigIsInt reports whether x is a finite whole number*/
+func bigIsInt(x *big.Float) bool {
+	return !x.IsInf() && x.IsInt()
+}
+
+/*bigIsOdd reports whether x is an odd whole number*/
+func bigIsOdd(x *big.Float) bool {
+	if !bigIsInt(x) {
+		return false
+	}
+	i, _ := x.Int(nil)
+	return i.Bit(0) == 1
+}
+
+/*bigExpOf is the binary exponent of x, 0 for zero*/
+func bigExpOf(x *big.Float) int {
+	if x.Sign() == 0 || x.IsInf() {
+		return 0
+	}
+	return x.MantExp(nil)
+}
+
+/*small reports whether term is negligible next to sum at prec bits*/
+func small(term *big.Float, sum *big.Float, prec uint) bool {
+	if term.Sign() == 0 {
+		return true
+	}
+	if sum.Sign() == 0 {
+		return false
+	}
+	return bigExpOf(term) < bigExpOf(sum)-int(prec)-2
+}
+
+/*atanInv is atan(1/n) from the Taylor series, used for Machin's formula*/
+func atanInv(n int64, prec uint) *big.Float {
+	term := newBig(prec).Quo(bigInt64(1, prec), bigInt64(n, prec))
+	nn := bigInt64(n*n, prec)
+	sum := newBig(prec).Set(term)
+	for k := int64(1); ; k++ {
+		term.Quo(term, nn)
+		t := newBig(prec).Quo(term, bigInt64(2*k+1, prec))
+		if small(t, sum, prec) {
+			break
+		}
+		if k%2 == 1 {
+			sum.Sub(sum, t)
+		} else {
+			sum.Add(sum, t)
+		}
+	}
+	return sum
+}
+
+var bigConstMu sync.Mutex
+var bigPiCache, bigLn2Cache *big.Float
+
+/*BigPi is π to prec bits*/
+func BigPi(prec uint) *big.Float {
+	bigConstMu.Lock()
+	defer bigConstMu.Unlock()
+	if bigPiCache == nil || bigPiCache.Prec() < prec {
+		wp := prec + bigGuardBits
+		a := newBig(wp).Mul(bigInt64(4, wp), atanInv(5, wp))
+		a.Sub(a, atanInv(239, wp))
+		bigPiCache = a.Mul(a, bigInt64(4, wp))
+	}
+	return roundBig(bigPiCache, prec)
+}
+
+/*bigLn2 is ln(2) to prec bits from ln 2 = 2 atanh(1/3)*/
+func bigLn2(prec uint) *big.Float {
+	bigConstMu.Lock()
+	defer bigConstMu.Unlock()
+	if bigLn2Cache == nil || bigLn2Cache.Prec() < prec {
+		wp := prec + bigGuardBits
+		bigLn2Cache = newBig(wp).Mul(bigInt64(2, wp), atanhSeries(newBig(wp).Quo(bigInt64(1, wp), bigInt64(3, wp)), wp))
+	}
+	return roundBig(bigLn2Cache, prec)
+}
+
+/*atanhSeries is z + z^3/3 + z^5/5 ... which converges quickly for small z*/
+func atanhSeries(z *big.Float, prec uint) *big.Float {
+	z2 := newBig(prec).Mul(z, z)
+	pow := newBig(prec).Set(z)
+	sum := newBig(prec).Set(z)
+	for k := int64(1); ; k++ {
+		pow.Mul(pow, z2)
+		t := newBig(prec).Quo(pow, bigInt64(2*k+1, prec))
+		if small(t, sum, prec) {
+			break
+		}
+		sum.Add(sum, t)
+	}
+	return sum
+}
+
+func BigSqrt(x *big.Float, prec uint) *big.Float {
+	if x == nil {
+		return nil
+	}
+	prec = resultPrec(x, prec)
+	switch {
+	case x.Sign() < 0:
+		return nil
+	case x.Sign() == 0 || x.IsInf():
+		return roundBig(x, prec)
+	}
+	return roundBig(newBig(prec+bigGuardBits).Sqrt(x), prec)
+}
+
+/*BigCbrt uses Newton's method starting from the float64 cube root, each step doubles the correct bits*/
+func BigCbrt(x *big.Float, prec uint) *big.Float {
+	if x == nil {
+		return nil
+	}
+	prec = resultPrec(x, prec)
+	if x.Sign() == 0 || x.IsInf() {
+		return roundBig(x, prec)
+	}
+	wp := prec + bigGuardBits
+	mant := new(big.Float)
+	e := x.MantExp(mant)
+	r := ((e % 3) + 3) % 3
+	m, _ := mant.Float64()
+	y := newBig(wp).SetFloat64(math.Cbrt(math.Ldexp(m, r)))
+	y.SetMantExp(y, (e-r)/3)
+	three := bigInt64(3, wp)
+	for bitsOK := uint(50); ; bitsOK *= 2 {
+		// y = y - (y^3 - x) / (3y^2)
+		y2 := newBig(wp).Mul(y, y)
+		num := newBig(wp).Mul(y2, y)
+		num.Sub(num, x)
+		den := newBig(wp).Mul(three, y2)
+		y.Sub(y, num.Quo(num, den))
+		if bitsOK > wp {
+			break
+		}
+	}
+	return roundBig(y, prec)
+}
+
+func BigExp(x *big.Float, prec uint) *big.Float {
+	if x == nil {
+		return nil
+	}
+	prec = resultPrec(x, prec)
+	return roundBig(bigExp(x, prec+bigGuardBits), prec)
+}
+
+/*
+bigExp reduces x to k ln2 + r, shrinks r by 2^s so the Taylor series converges fast and then squares back up.
+The squarings each lose a little so s is added onto the working precision.
+*/
+func bigExp(x *big.Float, wp uint) *big.Float {
+	switch {
+	case x.Sign() == 0:
+		return bigInt64(1, wp)
+	case x.IsInf():
+		if x.Sign() > 0 {
+			return bigInf(false, wp)
+		}
+		return newBig(wp)
+	}
+	// anything past this overflows or underflows the exponent range of big.Float
+	if bigExpOf(x) > 32 {
+		if x.Sign() > 0 {
+			return bigInf(false, wp)
+		}
+		return newBig(wp)
+	}
+	const s = 16
+	kf := newBig(64).Quo(x, bigLn2(64))
+	k, _ := kf.Int64()
+	wp2 := wp + s + 64
+	r := newBig(wp2).Mul(bigInt64(k, wp2), bigLn2(wp2))
+	r.Sub(x, r)
+	r.SetMantExp(r, -s)
+	sum := bigInt64(1, wp2)
+	term := bigInt64(1, wp2)
+	for n := int64(1); ; n++ {
+		term.Mul(term, r)
+		term.Quo(term, bigInt64(n, wp2))
+		if small(term, sum, wp2) {
+			break
+		}
+		sum.Add(sum, term)
+	}
+	for i := 0; i < s; i++ {
+		sum.Mul(sum, sum)
+	}
+	if k > math.MaxInt32 {
+		return bigInf(false, wp)
+	}
+	if k < math.MinInt32 {
+		return newBig(wp)
+	}
+	e := bigExpOf(sum) + int(k)
+	if e > big.MaxExp {
+		return bigInf(false, wp)
+	}
+	if e < big.MinExp {
+		return newBig(wp)
+	}
+	return sum.SetMantExp(sum, int(k))
+}
+
+func BigLog(x *big.Float, prec uint) *big.Float {
+	if x == nil {
+		return nil
+	}
+	prec = resultPrec(x, prec)
+	return roundBig(bigLog(x, prec+bigGuardBits), prec)
+}
+
+/*
+bigLog splits x into m * 2^e with m in [1/√2, √2) so that ln(m) = 2 atanh((m-1)/(m+1)) has a small argument and
+no cancellation happens when x is close to 1.
+*/
+func bigLog(x *big.Float, wp uint) *big.Float {
+	switch {
+	case x.Sign() < 0:
+		return nil
+	case x.Sign() == 0:
+		return bigInf(true, wp)
+	case x.IsInf():
+		return bigInf(false, wp)
+	}
+	m := newBig(wp)
+	e := x.MantExp(m)
+	if m.Cmp(big.NewFloat(math.Sqrt2/2)) < 0 {
+		m.SetMantExp(m, 1)
+		e--
+	}
+	one := bigInt64(1, wp)
+	z := newBig(wp).Quo(newBig(wp).Sub(m, one), newBig(wp).Add(m, one))
+	res := newBig(wp).Mul(bigInt64(2, wp), atanhSeries(z, wp))
+	if e != 0 {
+		eb := bits64(int64(e))
+		res.Add(res, newBig(wp+eb).Mul(bigInt64(int64(e), wp+eb), bigLn2(wp+eb)))
+	}
+	return res
+}
+
+/*bits64 is how many bits |n| needs*/
+func bits64(n int64) uint {
+	b := uint(0)
+	if n < 0 {
+		n = -n
+	}
+	for n > 0 {
+		b++
+		n >>= 1
+	}
+	return b
+}
+
+func BigLog2(x *big.Float, prec uint) *big.Float {
+	if x == nil {
+		return nil
+	}
+	prec = resultPrec(x, prec)
+	wp := prec + bigGuardBits
+	l := bigLog(x, wp)
+	if l == nil || l.IsInf() {
+		return roundBig(l, prec)
+	}
+	return roundBig(l.Quo(l, bigLn2(wp)), prec)
+}
+
+func BigLog10(x *big.Float, prec uint) *big.Float {
+	if x == nil {
+		return nil
+	}
+	prec = resultPrec(x, prec)
+	wp := prec + bigGuardBits
+	l := bigLog(x, wp)
+	if l == nil || l.IsInf() {
+		return roundBig(l, prec)
+	}
+	return roundBig(l.Quo(l, bigLog(bigInt64(10, wp), wp)), prec)
+}
+
+/*BigPow follows the special cases of math.Pow. Whole number powers are done by repeated squaring.*/
+func BigPow(x *big.Float, y *big.Float, prec uint) *big.Float {
+	if x == nil || y == nil {
+		return nil
+	}
+	prec = resultPrec(x, prec)
+	wp := prec + bigGuardBits
+	one := bigInt64(1, wp)
+	switch {
+	case y.Sign() == 0 || x.Cmp(one) == 0:
+		return bigInt64(1, prec)
+	case y.IsInf():
+		c := newBig(wp).Abs(x).Cmp(one)
+		switch {
+		case c == 0:
+			return bigInt64(1, prec)
+		case (c > 0) == (y.Sign() > 0):
+			return bigInf(false, prec)
+		}
+		return newBig(prec)
+	case x.IsInf() || x.Sign() == 0:
+		neg := x.Signbit() && bigIsOdd(y)
+		if (x.Sign() == 0) == (y.Sign() > 0) {
+			return signedZero(neg, prec)
+		}
+		return bigInf(neg, prec)
+	case x.Sign() < 0 && !bigIsInt(y):
+		return nil
+	}
+	neg := x.Sign() < 0 && bigIsOdd(y)
+	ax := newBig(wp).Abs(x)
+	if bigIsInt(y) && bigExpOf(y) <= 32 {
+		n, _ := y.Int64()
+		res := bigPowInt(ax, n, wp+bits64(n))
+		if neg {
+			res.Neg(res)
+		}
+		return roundBig(res, prec)
+	}
+	// y ln|x| has to be accurate to wp bits after the point so it needs as many extra bits as it has before it
+	l := bigLog(ax, 64)
+	extra := bigExpOf(y) + bigExpOf(l) + 8
+	switch {
+	case extra < 0:
+		extra = 0
+	case extra > 64:
+		extra = 64
+	}
+	l = bigLog(ax, wp+uint(extra))
+	res := bigExp(l.Mul(l, y), wp)
+	if neg {
+		res.Neg(res)
+	}
+	return roundBig(res, prec)
+}
+
+func signedZero(neg bool, prec uint) *big.Float {
+	z := newBig(prec)
+	if neg {
+		z.Neg(z)
+	}
+	return z
+}
+
+func bigPowInt(x *big.Float, n int64, wp uint) *big.Float {
+	inv := n < 0
+	if inv {
+		n = -n
+	}
+	res := bigInt64(1, wp)
+	base := newBig(wp).Set(x)
+	for n > 0 {
+		if n&1 == 1 {
+			res.Mul(res, base)
+		}
+		base.Mul(base, base)
+		n >>= 1
+	}
+	if inv {
+		res.Quo(bigInt64(1, wp), res)
+	}
+	return res
+}
+
+/*
+bigSinCos reduces x by multiples of π/2 using a value of π that has enough extra bits to cover the size of x and
+then runs the Taylor series on what is left. quadrant picks which of ±sin/±cos each result comes from.
+*/
+func bigSinCos(x *big.Float, wp uint) (*big.Float, *big.Float) {
+	if x.IsInf() {
+		return nil, nil
+	}
+	if x.Sign() == 0 {
+		return newBig(wp).Set(x), bigInt64(1, wp)
+	}
+	e := bigExpOf(x)
+	extra := uint(0)
+	if e > 0 {
+		extra = uint(e)
+	}
+	rp := wp + extra + bigGuardBits
+	halfPi := BigPi(rp)
+	halfPi.SetMantExp(halfPi, -1)
+	kf := newBig(rp).Quo(x, halfPi)
+	k, _ := newBig(rp).Add(kf, newBig(rp).SetFloat64(0.5*float64(kf.Sign()))).Int(nil)
+	r := newBig(rp).Mul(newBig(rp).SetInt(k), halfPi)
+	r.Sub(x, r)
+	r.SetPrec(wp)
+	s, c := sinCosSeries(r, wp)
+	switch new(big.Int).And(k, big.NewInt(3)).Int64() {
+	case 1:
+		s, c = c, s.Neg(s)
+	case 2:
+		s, c = s.Neg(s), c.Neg(c)
+	case 3:
+		s, c = c.Neg(c), s
+	}
+	return s, c
+}
+
+func sinCosSeries(r *big.Float, wp uint) (*big.Float, *big.Float) {
+	r2 := newBig(wp).Mul(r, r)
+	s := newBig(wp).Set(r)
+	term := newBig(wp).Set(r)
+	for n := int64(1); ; n++ {
+		term.Mul(term, r2)
+		term.Quo(term, bigInt64((2*n)*(2*n+1), wp))
+		if small(term, s, wp) {
+			break
+		}
+		if n%2 == 1 {
+			s.Sub(s, term)
+		} else {
+			s.Add(s, term)
+		}
+	}
+	c := bigInt64(1, wp)
+	term = bigInt64(1, wp)
+	for n := int64(1); ; n++ {
+		term.Mul(term, r2)
+		term.Quo(term, bigInt64((2*n-1)*(2*n), wp))
+		if small(term, c, wp) {
+			break
+		}
+		if n%2 == 1 {
+			c.Sub(c, term)
+		} else {
+			c.Add(c, term)
+		}
+	}
+	return s, c
+}
+
+func BigSin(x *big.Float, prec uint) *big.Float {
+	if x == nil {
+		return nil
+	}
+	prec = resultPrec(x, prec)
+	s, _ := bigSinCos(x, prec+bigGuardBits)
+	return roundBig(s, prec)
+}
+
+func BigCos(x *big.Float, prec uint) *big.Float {
+	if x == nil {
+		return nil
+	}
+	prec = resultPrec(x, prec)
+	_, c := bigSinCos(x, prec+bigGuardBits)
+	return roundBig(c, prec)
+}
+
+func BigTan(x *big.Float, prec uint) *big.Float {
+	if x == nil {
+		return nil
+	}
+	prec = resultPrec(x, prec)
+	s, c := bigSinCos(x, prec+bigGuardBits)
+	if s == nil {
+		return nil
+	}
+	return roundBig(s.Quo(s, c), prec)
+}
+
+func BigAtan(x *big.Float, prec uint) *big.Float {
+	if x == nil {
+		return nil
+	}
+	prec = resultPrec(x, prec)
+	return roundBig(bigAtan(x, prec+bigGuardBits), prec)
+}
+
+/*
+bigAtan folds |x| > 1 over with atan(x) = π/2 - atan(1/x) and then halves the argument a few times with
+atan(x) = 2 atan(x / (1 + sqrt(1 + x^2))) so the series converges quickly.
+*/
+func bigAtan(x *big.Float, wp uint) *big.Float {
+	if x.Sign() == 0 {
+		return newBig(wp).Set(x)
+	}
+	neg := x.Sign() < 0
+	if x.IsInf() {
+		res := BigPi(wp)
+		res.SetMantExp(res, -1)
+		if neg {
+			res.Neg(res)
+		}
+		return res
+	}
+	const halvings = 8
+	wp2 := wp + halvings + 8
+	one := bigInt64(1, wp2)
+	a := newBig(wp2).Abs(x)
+	flip := a.Cmp(one) > 0
+	if flip {
+		a.Quo(one, a)
+	}
+	for i := 0; i < halvings; i++ {
+		d := newBig(wp2).Mul(a, a)
+		d.Add(d, one)
+		d.Sqrt(d)
+		d.Add(d, one)
+		a.Quo(a, d)
+	}
+	a2 := newBig(wp2).Mul(a, a)
+	sum := newBig(wp2).Set(a)
+	pow := newBig(wp2).Set(a)
+	for k := int64(1); ; k++ {
+		pow.Mul(pow, a2)
+		t := newBig(wp2).Quo(pow, bigInt64(2*k+1, wp2))
+		if small(t, sum, wp2) {
+			break
+		}
+		if k%2 == 1 {
+			sum.Sub(sum, t)
+		} else {
+			sum.Add(sum, t)
+		}
+	}
+	sum.SetMantExp(sum, halvings)
+	if flip {
+		halfPi := BigPi(wp2)
+		halfPi.SetMantExp(halfPi, -1)
+		sum.Sub(halfPi, sum)
+	}
+	if neg {
+		sum.Neg(sum)
+	}
+	return sum
+}
+
+/*BigAtan2 follows the quadrant and signed zero rules of math.Atan2*/
+func BigAtan2(y *big.Float, x *big.Float, prec uint) *big.Float {
+	if x == nil || y == nil {
+		return nil
+	}
+	prec = resultPrec(y, prec)
+	wp := prec + bigGuardBits
+	if x.IsInf() || y.IsInf() || x.Sign() == 0 || y.Sign() == 0 {
+		// the answer is a multiple of π/4 so math.Atan2 on stand-ins with the same signs gives the multiple
+		stand := func(v *big.Float) float64 {
+			switch {
+			case v.IsInf():
+				return math.Inf(v.Sign())
+			case v.Sign() == 0 && v.Signbit():
+				return math.Copysign(0, -1)
+			}
+			return float64(v.Sign())
+		}
+		q := math.Atan2(stand(y), stand(x)) / math.Pi
+		if q == 0 {
+			return signedZero(math.Signbit(q), prec)
+		}
+		return roundBig(newBig(wp).Mul(newBig(wp).SetFloat64(q), BigPi(wp)), prec)
+	}
+	q := newBig(wp).Quo(y, x)
+	a := bigAtan(q.Abs(q), wp)
+	if x.Sign() < 0 {
+		a.Sub(BigPi(wp), a)
+	}
+	if y.Sign() < 0 {
+		a.Neg(a)
+	}
+	return roundBig(a, prec)
+}
+
+func BigSinh(x *big.Float, prec uint) *big.Float {
+	if x == nil {
+		return nil
+	}
+	prec = resultPrec(x, prec)
+	wp := prec + bigGuardBits
+	if x.Sign() == 0 || x.IsInf() {
+		return roundBig(x, prec)
+	}
+	if bigExpOf(x) <= 0 {
+		// |x| < 1, the series avoids the cancellation in e^x - e^-x
+		x2 := newBig(wp).Mul(x, x)
+		sum := newBig(wp).Set(x)
+		term := newBig(wp).Set(x)
+		for n := int64(1); ; n++ {
+			term.Mul(term, x2)
+			term.Quo(term, bigInt64((2*n)*(2*n+1), wp))
+			if small(term, sum, wp) {
+				break
+			}
+			sum.Add(sum, term)
+		}
+		return roundBig(sum, prec)
+	}
+	ex := bigExp(x, wp)
+	if ex.IsInf() || ex.Sign() == 0 {
+		return bigInf(x.Sign() < 0, prec)
+	}
+	res := newBig(wp).Quo(bigInt64(1, wp), ex)
+	res.Sub(ex, res)
+	return roundBig(res.SetMantExp(res, -1), prec)
+}
+
+var bernoulliMu sync.Mutex
+var bernoulliCache = []*big.Rat{big.NewRat(1, 1), big.NewRat(-1, 2)}
+
+/*bernoulli returns B_n with B_1 = -1/2, built up from B_n = -1/(n+1) Σ C(n+1,k) B_k and cached*/
+func bernoulli(n int) *big.Rat {
+	bernoulliMu.Lock()
+	defer bernoulliMu.Unlock()
+	for m := len(bernoulliCache); m <= n; m++ {
+		sum := new(big.Rat)
+		if m%2 == 1 {
+			bernoulliCache = append(bernoulliCache, sum)
+			continue
+		}
+		c := big.NewInt(1)
+		for k := 0; k < m; k++ {
+			sum.Add(sum, new(big.Rat).Mul(new(big.Rat).SetInt(c), bernoulliCache[k]))
+			c.Mul(c, big.NewInt(int64(m+1-k)))
+			c.Quo(c, big.NewInt(int64(k+1)))
+		}
+		sum.Quo(sum, big.NewRat(int64(-(m+1)), 1))
+		bernoulliCache = append(bernoulliCache, sum)
+	}
+	return bernoulliCache[n]
+}
+
+/*
+BigGamma uses the reflection formula below 1/2 and otherwise shifts x up until the Stirling series for lnΓ
+converges to the working precision in a handful of terms, then divides the shift back out.
+Poles follow math.Gamma: ±0 gives ±Inf and the negative integers give nil.
+*/
+func BigGamma(x *big.Float, prec uint) *big.Float {
+	if x == nil {
+		return nil
+	}
+	prec = resultPrec(x, prec)
+	wp := prec + bigGuardBits
+	switch {
+	case x.Sign() == 0:
+		return bigInf(x.Signbit(), prec)
+	case x.IsInf():
+		if x.Sign() > 0 {
+			return bigInf(false, prec)
+		}
+		return nil
+	case x.Sign() < 0 && bigIsInt(x):
+		return nil
+	}
+	return roundBig(bigGamma(x, wp), prec)
+}
+
+func bigGamma(x *big.Float, wp uint) *big.Float {
+	half := newBig(wp).SetFloat64(0.5)
+	if x.Cmp(half) < 0 {
+		// Γ(x) = π / (sin(πx) Γ(1-x)), sin(πx) is taken on the distance to the nearest integer to keep it accurate
+		e := bigExpOf(x)
+		xp := wp
+		if e > 0 {
+			xp += uint(e)
+		}
+		n, _ := newBig(xp).Add(x, newBig(xp).SetFloat64(0.5*float64(x.Sign()))).Int(nil)
+		f := newBig(xp).Sub(x, newBig(xp).SetInt(n))
+		f.SetPrec(wp)
+		pi := BigPi(wp)
+		s, _ := sinCosSeries(newBig(wp).Mul(pi, f), wp)
+		if n.Bit(0) == 1 {
+			s.Neg(s)
+		}
+		g := bigGamma(newBig(xp).Sub(bigInt64(1, xp), x), wp)
+		if g.IsInf() {
+			return newBig(wp)
+		}
+		return pi.Quo(pi, s.Mul(s, g))
+	}
+	// shift up to at least z0 so the Stirling terms shrink fast
+	z0 := newBig(wp).SetInt64(int64(wp))
+	z := newBig(wp).Set(x)
+	prod := bigInt64(1, wp)
+	for z.Cmp(z0) < 0 {
+		prod.Mul(prod, z)
+		z.Add(z, bigInt64(1, wp))
+	}
+	// lnΓ(z) is about z ln z so it needs that many more bits before the point to keep wp bits in the exp
+	ze := bigExpOf(z)
+	lp := wp + uint(ze) + bits64(int64(ze)) + 16
+	lz := bigLog(z, lp)
+	// (z - 1/2) ln z - z + ln(2π)/2
+	lg := newBig(lp).Sub(z, newBig(lp).SetFloat64(0.5))
+	lg.Mul(lg, lz)
+	lg.Sub(lg, z)
+	twoPi := BigPi(lp)
+	twoPi.SetMantExp(twoPi, 1)
+	l2p := bigLog(twoPi, lp)
+	lg.Add(lg, l2p.SetMantExp(l2p, -1))
+	zpow := newBig(lp).Set(z)
+	z2 := newBig(lp).Mul(z, z)
+	// the terms keep shrinking until k is about πz and the smallest is about e^(-2πz), with z at least wp that is
+	// far past the last bit, so the loop ends on the term size alone
+	for k := 1; ; k++ {
+		b := bernoulli(2 * k)
+		t := newBig(lp).SetRat(b)
+		t.Quo(t, bigInt64(int64(2*k*(2*k-1)), lp))
+		t.Quo(t, zpow)
+		if small(t, lg, lp) {
+			break
+		}
+		lg.Add(lg, t)
+		zpow.Mul(zpow, z2)
+	}
+	g := bigExp(lg, wp)
+	return g.Quo(g, prod)
+}
+
+/*
+BigErf uses erf(x) = 2/√π e^(-x²) Σ 2^n x^(2n+1) / (1·3·5···(2n+1)). Every term is positive so nothing cancels.
+Once erfc(x) is below the last bit the result is just ±1.
+*/
+func BigErf(x *big.Float, prec uint) *big.Float {
+	if x == nil {
+		return nil
+	}
+	prec = resultPrec(x, prec)
+	wp := prec + bigGuardBits
+	if x.Sign() == 0 {
+		return roundBig(x, prec)
+	}
+	x2 := newBig(wp).Mul(x, x)
+	if x.IsInf() || x2.Cmp(newBig(wp).SetFloat64(float64(wp)*math.Ln2+8)) > 0 {
+		return bigInt64(int64(x.Sign()), prec)
+	}
+	twoX2 := newBig(wp).SetMantExp(x2, 1)
+	sum := newBig(wp).Set(x)
+	term := newBig(wp).Set(x)
+	for n := int64(0); ; n++ {
+		term.Mul(term, twoX2)
+		term.Quo(term, bigInt64(2*n+3, wp))
+		if small(term, sum, wp) && newBig(wp).SetInt64(n).Cmp(x2) > 0 {
+			break
+		}
+		sum.Add(sum, term)
+	}
+	sum.Mul(sum, bigExp(newBig(wp).Neg(x2), wp))
+	sqrtPi := BigPi(wp)
+	sqrtPi.Sqrt(sqrtPi)
+	sum.Quo(sum, sqrtPi)
+	return roundBig(sum.SetMantExp(sum, 1), prec)
+}
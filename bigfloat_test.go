@@ -0,0 +1,183 @@
+package RUNK
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func bigOf(t *testing.T, s string, prec uint) *big.Float {
+	t.Helper()
+	f, _, err := big.ParseFloat(s, 10, prec, big.ToNearestEven)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestBigConstants(t *testing.T) {
+	const prec = 300
+	tests := []struct {
+		name string
+		got  *big.Float
+		want string
+	}{
+		{"pi", BigPi(prec), "3.14159265358979323846264338327950288419716939937510582097494459230781640628620899862803482534211706798214808651"},
+		{"e", BigExp(big.NewFloat(1).SetPrec(prec), prec), "2.71828182845904523536028747135266249775724709369995957496696762772407663035354759457138217852516642742746639193"},
+		{"sqrt2", BigSqrt(big.NewFloat(2).SetPrec(prec), prec), "1.41421356237309504880168872420969807856967187537694807317667973799073247846210703885038753432764157273501384623"},
+		{"ln10", BigLog(big.NewFloat(10).SetPrec(prec), prec), "2.30258509299404568401799145468436420760110148862877297603332790096757260967735248023599720508959829834196778404"},
+		{"cbrt2", BigCbrt(big.NewFloat(2).SetPrec(prec), prec), "1.25992104989487316476721060727822835057025146470150798008197511215529967651395948372939656243625509415431025603"},
+		{"gamma(1/2)", BigGamma(big.NewFloat(0.5).SetPrec(prec), prec), "1.77245385090551602729816748334114518279754945612238712821380778985291128459103218137495065673854466541622682362"},
+		{"erf(1)", BigErf(big.NewFloat(1).SetPrec(prec), prec), "0.84270079294971486934122063508260925929606699796630290845993789783471725409601084126198332534814488845415826162"},
+		{"atan(1)*4", new(big.Float).Mul(BigAtan(big.NewFloat(1).SetPrec(prec), prec), big.NewFloat(4)), "3.14159265358979323846264338327950288419716939937510582097494459230781640628620899862803482534211706798214808651"},
+	}
+	for _, tt := range tests {
+		want := bigOf(t, tt.want, prec)
+		diff := new(big.Float).Sub(tt.got, want)
+		if diff.Sign() != 0 && diff.MantExp(nil)-want.MantExp(nil) > -250 {
+			t.Errorf("%s = %s, want %s", tt.name, tt.got.Text('g', 60), tt.want[:60])
+		}
+	}
+}
+
+func TestBigGammaHighPrecision(t *testing.T) {
+	// Γ(1/2) = √π and Γ(3/2) = √π/2 well past where a fixed number of Stirling terms runs out
+	const prec = 6000
+	want := BigSqrt(BigPi(prec+64), prec+64)
+	for _, tt := range []struct {
+		x     float64
+		scale int
+	}{{0.5, 0}, {1.5, -1}} {
+		got := BigGamma(big.NewFloat(tt.x), prec)
+		diff := new(big.Float).Sub(got, new(big.Float).SetMantExp(want, tt.scale))
+		if got.Prec() != prec || diff.Sign() != 0 && diff.MantExp(nil)-want.MantExp(nil) > -prec+2 {
+			t.Errorf("Γ(%v) at %d bits is only good to %d bits", tt.x, prec, want.MantExp(nil)-diff.MantExp(nil))
+		}
+	}
+}
+
+/*ulpDiff is how many float64 ulps apart a and b are*/
+func ulpDiff(a float64, b float64) float64 {
+	if a == b {
+		return 0
+	}
+	return math.Abs(a-b) / (math.Nextafter(math.Abs(b), math.Inf(1)) - math.Abs(b))
+}
+
+func TestBigAgreesWithMath(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	fns := []struct {
+		name string
+		big  func(*big.Float, uint) *big.Float
+		f    func(float64) float64
+		lo   float64
+		hi   float64
+		ulps float64
+	}{
+		{"exp", BigExp, math.Exp, -700, 700, 1},
+		{"log", BigLog, math.Log, 1e-300, 1e300, 1},
+		{"log2", BigLog2, math.Log2, 1e-10, 1e10, 1},
+		{"log10", BigLog10, math.Log10, 1e-10, 1e10, 1},
+		{"sqrt", BigSqrt, math.Sqrt, 0, 1e300, 0},
+		{"sin", BigSin, math.Sin, -1e6, 1e6, 1},
+		{"cos", BigCos, math.Cos, -100, 100, 1},
+		{"tan", BigTan, math.Tan, -1.5, 1.5, 2},
+		{"atan", BigAtan, math.Atan, -1e3, 1e3, 1},
+		{"sinh", BigSinh, math.Sinh, -50, 50, 2},
+		// math.Gamma goes through Pow for big x and is tens of ulps out up there
+		{"gamma", BigGamma, math.Gamma, -20.5, 170, 100},
+		{"erf", BigErf, math.Erf, -6, 6, 1},
+	}
+	for _, fn := range fns {
+		for i := 0; i < 200; i++ {
+			x := fn.lo + rng.Float64()*(fn.hi-fn.lo)
+			if fn.name == "log" || fn.name == "sqrt" {
+				x = math.Exp(math.Log(fn.hi) * (rng.Float64()*2 - 1))
+			}
+			got, _ := fn.big(big.NewFloat(x), 53).Float64()
+			// rounding the result to 53 bits has to give the same float as rounding a much more precise one
+			precise, _ := fn.big(big.NewFloat(x), 200).Float64()
+			if got != precise {
+				t.Errorf("%s(%v) = %v is not correctly rounded, want %v", fn.name, x, got, precise)
+			}
+			if d := ulpDiff(got, fn.f(x)); d > fn.ulps {
+				t.Errorf("%s(%v) = %v is %v ulps from math", fn.name, x, got, d)
+			}
+		}
+	}
+}
+
+func TestBigPowAndAtan2(t *testing.T) {
+	const prec = 200
+	x := big.NewFloat(2).SetPrec(prec)
+	if got := BigPow(x, big.NewFloat(100), prec); got.Cmp(new(big.Float).SetMantExp(big.NewFloat(1), 100)) != 0 {
+		t.Errorf("2^100 = %s", got.Text('g', 40))
+	}
+	// 2^0.5 is the square root
+	r := BigPow(x, big.NewFloat(0.5), prec)
+	if d := new(big.Float).Sub(r, BigSqrt(x, prec)); d.Sign() != 0 && d.MantExp(nil) > -190 {
+		t.Errorf("2^0.5 = %s", r.Text('g', 60))
+	}
+	powCases := [][2]float64{{-2, 3}, {-8, 1.0 / 3}, {0, -1}, {1, math.Inf(1)}, {-1, math.Inf(1)}, {0.5, -2}, {math.Inf(-1), 3}}
+	for _, c := range powCases {
+		want := math.Pow(c[0], c[1])
+		got := BigPow(big.NewFloat(c[0]), big.NewFloat(c[1]), 53)
+		if math.IsNaN(want) {
+			if got != nil {
+				t.Errorf("Pow(%v, %v) = %v, want nil", c[0], c[1], got)
+			}
+			continue
+		}
+		if f, _ := got.Float64(); f != want {
+			t.Errorf("Pow(%v, %v) = %v, want %v", c[0], c[1], f, want)
+		}
+	}
+	atanCases := [][2]float64{{1, -1}, {-1, -1}, {0, -1}, {math.Copysign(0, -1), -1}, {math.Inf(1), math.Inf(-1)}, {3, 0}}
+	for _, c := range atanCases {
+		got, _ := BigAtan2(big.NewFloat(c[0]), big.NewFloat(c[1]), 53).Float64()
+		if want := math.Atan2(c[0], c[1]); got != want || math.Signbit(got) != math.Signbit(want) {
+			t.Errorf("Atan2(%v, %v) = %v, want %v", c[0], c[1], got, want)
+		}
+	}
+}
+
+func TestBigNaNAndConversions(t *testing.T) {
+	if BigSqrt(big.NewFloat(-1), 53) != nil {
+		t.Error("Sqrt(-1) is not nil")
+	}
+	if BigLog(big.NewFloat(-1), 53) != nil {
+		t.Error("Log(-1) is not nil")
+	}
+	if BigGamma(big.NewFloat(-3), 53) != nil {
+		t.Error("Gamma(-3) is not nil")
+	}
+	if g := BigGamma(big.NewFloat(0), 53); !g.IsInf() {
+		t.Errorf("Gamma(0) = %v", g)
+	}
+	if BigExp(nil, 53) != nil || BigAtan2(nil, big.NewFloat(1), 53) != nil {
+		t.Error("nil does not propagate")
+	}
+	if NewBigFloat(math.NaN(), 0) != nil {
+		t.Error("NewBigFloat(NaN) is not nil")
+	}
+	if f := NewBigFloat(uint64(math.MaxUint64), 0); f.Prec() != 64 || !f.IsInt() {
+		t.Errorf("NewBigFloat(MaxUint64) = %v prec %d", f, f.Prec())
+	}
+	if got := ConvertNum[int8](BigSqrt(big.NewFloat(1e6), 53)); got != math.MaxInt8 {
+		t.Errorf("ConvertNum[int8](1000) = %d", got)
+	}
+	if got := ConvertNum[int](BigSqrt(big.NewFloat(-1), 53)); got != 0 {
+		t.Errorf("ConvertNum of nil = %d", got)
+	}
+	if got := ConvertNum[int](BigSqrt(big.NewFloat(6.25), 53)); got != 3 {
+		t.Errorf("ConvertNum[int](2.5) = %d", got)
+	}
+	if got := ConvertNum[float32](BigPi(100)); got != math.Pi {
+		t.Errorf("ConvertNum[float32](pi) = %v", got)
+	}
+	// a prec of 0 keeps the precision of x
+	if got := BigSqrt(new(big.Float).SetPrec(500).SetInt64(2), 0); got.Prec() != 500 {
+		t.Errorf("prec 0 gave %d bits", got.Prec())
+	}
+}
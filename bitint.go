@@ -0,0 +1,199 @@
+package RUNK
+
+/*
+BitInt is an integer type with a fixed number of bits that is narrower than the Go type holding it, like 24 bit
+audio samples or 12 bit ADC readings. The width comes from the Bits method so any named integer can be one:
+
+	type Int20 int32
+	func (Int20) Bits() uint { return 20 }
+
+Signed widths are two's complement. Bits has to fit in the underlying type and is clamped to 1..64.
+The Go type does not stop a value from going outside the width, use BitIntFrom or WrapBitInt to get one that is
+always in range.
+*/
+type BitInt interface {
+	Int
+	Bits() uint
+}
+
+type Int12 int16
+type Uint12 uint16
+type Int24 int32
+type Uint24 uint32
+type Int48 int64
+type Uint48 uint64
+
+func (Int12) Bits() uint  { return 12 }
+func (Uint12) Bits() uint { return 12 }
+func (Int24) Bits() uint  { return 24 }
+func (Uint24) Bits() uint { return 24 }
+func (Int48) Bits() uint  { return 48 }
+func (Uint48) Bits() uint { return 48 }
+
+func bitWidth[W BitInt]() uint {
+	var w W
+	return max(1, min(w.Bits(), 64))
+}
+
+/*MaxBitInt is the largest value that fits in the width of W, 8388607 for Int24*/
+func MaxBitInt[W BitInt]() W {
+	b := bitWidth[W]()
+	if isSigned[W]() {
+		return W(uint64(1)<<(b-1) - 1)
+	}
+	return W(uint64(1)<<b - 1)
+}
+
+/*MinBitInt is the smallest value that fits in the width of W, -8388608 for Int24 and 0 for any unsigned W*/
+func MinBitInt[W BitInt]() W {
+	if isSigned[W]() {
+		return W(-int64(1) << (bitWidth[W]() - 1))
+	}
+	return 0
+}
+
+/*
+BitIntFrom converts any Number into W saturating at the edges of the width, following the same rules as
+sintToSint and uintToUint. Negative values become 0 for unsigned widths, NaN becomes 0 and +-Inf becomes the
+max/min. Floats are rounded with roundMode, math.Round by default.
+*/
+func BitIntFrom[W BitInt, N Number](n N, roundMode ...RoundingMode) W {
+	if isSigned[W]() {
+		v := ConvertNumberBy[int64](n, roundMode...)
+		return W(max(int64(MinBitInt[W]()), min(v, int64(MaxBitInt[W]()))))
+	}
+	v := ConvertNumberBy[uint64](n, roundMode...)
+	return W(min(v, uint64(MaxBitInt[W]())))
+}
+
+/*
+WrapBitInt converts any Number into W keeping only the low bits, the way a built in integer conversion like
+int8(300) wraps. Floats are rounded with roundMode and pinned to 64 bits first since there are no bits to keep
+past that.
+*/
+func WrapBitInt[W BitInt, N Number](n N, roundMode ...RoundingMode) W {
+	var raw uint64
+	switch {
+	case isFloat[N]() && n < 0:
+		raw = uint64(ConvertNumberBy[int64](n, roundMode...))
+	case isFloat[N]():
+		raw = ConvertNumberBy[uint64](n, roundMode...)
+	case isSigned[N]():
+		raw = uint64(int64(n))
+	default:
+		raw = uint64(n)
+	}
+	return extendBits[W](raw)
+}
+
+/*BitIntTo converts a W to any Number with the same saturation as ConvertNumber*/
+func BitIntTo[To Number, W BitInt](w W) To {
+	if isSigned[W]() {
+		return ConvertNumber[To](int64(w))
+	}
+	return ConvertNumber[To](uint64(w))
+}
+
+/*extendBits keeps the low bits of raw and sign extends them when W is signed*/
+func extendBits[W BitInt](raw uint64) W {
+	b := bitWidth[W]()
+	mask := uint64(1)<<b - 1
+	raw &= mask
+	if isSigned[W]() && raw>>(b-1)&1 == 1 {
+		raw |= ^mask
+	}
+	return W(raw)
+}
+
+/*PackedLen is how many bytes n values of W take when they are packed end to end*/
+func PackedLen[W BitInt](n int) int {
+	return (n*int(bitWidth[W]()) + 7) / 8
+}
+
+/*
+The packers lay the values end to end with no padding between them, so two Uint12s take three bytes. Only the
+low bits of each value are written, the same bits WrapBitInt keeps. Little endian puts the lowest bit first
+(24 bit WAV samples), big endian puts the highest bit first (network and most sensor streams). For widths that are
+a multiple of 8 these are the usual byte orders.
+They pack or unpack as many whole values as fit in both slices and return how many that was, like copy. Unused bits
+in the last byte written are set to 0.
+*/
+
+func PackLE[W BitInt](dst []byte, src []W) int {
+	b := bitWidth[W]()
+	n := min(len(src), len(dst)*8/int(b))
+	pos := uint(0)
+	for _, w := range src[:n] {
+		v := uint64(w)
+		for done := uint(0); done < b; {
+			shift := pos % 8
+			k := min(8-shift, b-done)
+			mask := byte(1)<<k - 1
+			dst[pos/8] = dst[pos/8]&^(mask<<shift) | (byte(v>>done)&mask)<<shift
+			done += k
+			pos += k
+		}
+	}
+	if pos%8 != 0 {
+		dst[pos/8] &= byte(1)<<(pos%8) - 1
+	}
+	return n
+}
+
+func PackBE[W BitInt](dst []byte, src []W) int {
+	b := bitWidth[W]()
+	n := min(len(src), len(dst)*8/int(b))
+	pos := uint(0)
+	for _, w := range src[:n] {
+		v := uint64(w)
+		for done := uint(0); done < b; {
+			used := pos % 8
+			k := min(8-used, b-done)
+			mask := byte(1)<<k - 1
+			shift := 8 - used - k
+			dst[pos/8] = dst[pos/8]&^(mask<<shift) | (byte(v>>(b-done-k))&mask)<<shift
+			done += k
+			pos += k
+		}
+	}
+	if pos%8 != 0 {
+		dst[pos/8] &^= byte(0xff) >> (pos % 8)
+	}
+	return n
+}
+
+func UnpackLE[W BitInt](dst []W, src []byte) int {
+	b := bitWidth[W]()
+	n := min(len(dst), len(src)*8/int(b))
+	pos := uint(0)
+	for i := range dst[:n] {
+		var v uint64
+		for done := uint(0); done < b; {
+			shift := pos % 8
+			k := min(8-shift, b-done)
+			v |= uint64(src[pos/8]>>shift&(byte(1)<<k-1)) << done
+			done += k
+			pos += k
+		}
+		dst[i] = extendBits[W](v)
+	}
+	return n
+}
+
+func UnpackBE[W BitInt](dst []W, src []byte) int {
+	b := bitWidth[W]()
+	n := min(len(dst), len(src)*8/int(b))
+	pos := uint(0)
+	for i := range dst[:n] {
+		var v uint64
+		for done := uint(0); done < b; {
+			used := pos % 8
+			k := min(8-used, b-done)
+			v = v<<k | uint64(src[pos/8]>>(8-used-k)&(byte(1)<<k-1))
+			done += k
+			pos += k
+		}
+		dst[i] = extendBits[W](v)
+	}
+	return n
+}
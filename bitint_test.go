@@ -0,0 +1,148 @@
+package RUNK
+
+import (
+	"bytes"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+type int20 int32
+
+func (int20) Bits() uint { return 20 }
+
+type uint1 uint8
+
+func (uint1) Bits() uint { return 1 }
+
+func TestBitIntLimits(t *testing.T) {
+	if MaxBitInt[Int24]() != 8388607 || MinBitInt[Int24]() != -8388608 {
+		t.Error("Int24 limits are wrong")
+	}
+	if MaxBitInt[Uint12]() != 4095 || MinBitInt[Uint12]() != 0 {
+		t.Error("Uint12 limits are wrong")
+	}
+	if MaxBitInt[int20]() != 1<<19-1 || MinBitInt[int20]() != -1<<19 {
+		t.Error("a user defined width doesn't work")
+	}
+	if MaxBitInt[uint1]() != 1 {
+		t.Error("a one bit width doesn't work")
+	}
+	if MaxBitInt[Uint48]() != 1<<48-1 || MinBitInt[Int48]() != -1<<47 {
+		t.Error("48 bit limits are wrong")
+	}
+}
+
+func TestBitIntFrom(t *testing.T) {
+	tests := []struct {
+		name string
+		got  int64
+		want int64
+	}{
+		{"int24 too big", int64(BitIntFrom[Int24](1 << 30)), 8388607},
+		{"int24 too small", int64(BitIntFrom[Int24](-1e9)), -8388608},
+		{"uint12 negative", int64(BitIntFrom[Uint12](-5)), 0},
+		{"uint12 too big", int64(BitIntFrom[Uint12](uint64(math.MaxUint64))), 4095},
+		{"NaN", int64(BitIntFrom[Int12](math.NaN())), 0},
+		{"+Inf", int64(BitIntFrom[Int12](math.Inf(1))), 2047},
+		{"round", int64(BitIntFrom[Int12](2.5)), 3},
+		{"floor", int64(BitIntFrom[Int12](-2.5, math.Floor)), -3},
+		{"wrap int24", int64(WrapBitInt[Int24](1 << 23)), -8388608},
+		{"wrap uint12", int64(WrapBitInt[Uint12](4097)), 1},
+		{"wrap negative into uint12", int64(WrapBitInt[Uint12](-1)), 4095},
+		{"wrap float", int64(WrapBitInt[Int12](-2049.0)), 2047},
+		{"wrap int20", int64(WrapBitInt[int20](int64(-1) << 40)), 0},
+		{"to int8", int64(BitIntTo[int8](Int24(-300))), -128},
+		{"to uint8", int64(BitIntTo[uint8](Int12(-1))), 0},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %d, want %d", tt.name, tt.got, tt.want)
+		}
+	}
+	if got := BitIntTo[float32](Uint48(1<<48 - 1)); got != float32(1<<48-1) {
+		t.Errorf("BitIntTo[float32] = %v", got)
+	}
+}
+
+/*The named types over uint16 go through the reflect fallback of ConvertNumber, which used to match Int16 twice*/
+func TestConvertNumberNamedUint16(t *testing.T) {
+	if got := ConvertNumber[Uint12](-1); got != 0 {
+		t.Errorf("ConvertNumber[Uint12](-1) = %d, want 0", got)
+	}
+	if got := ConvertNumber[Uint12](uint64(70000)); got != math.MaxUint16 {
+		t.Errorf("ConvertNumber[Uint12](70000) = %d, want %d", got, math.MaxUint16)
+	}
+	if got := ConvertNumber[Uint12](1e9); got != math.MaxUint16 {
+		t.Errorf("ConvertNumber[Uint12](1e9) = %d, want %d", got, math.MaxUint16)
+	}
+	if got := ConvertNumber[Uint12](int64(70000)); got != math.MaxUint16 {
+		t.Errorf("ConvertNumber[Uint12](int64 70000) = %d", got)
+	}
+}
+
+func TestPackUnpack(t *testing.T) {
+	src := []Uint12{0xabc, 0x123, 0xfff}
+	le := make([]byte, PackedLen[Uint12](len(src)))
+	if len(le) != 5 {
+		t.Fatalf("PackedLen = %d, want 5", len(le))
+	}
+	for i := range le {
+		le[i] = 0xff
+	}
+	if n := PackLE(le, src); n != 3 || !bytes.Equal(le, []byte{0xbc, 0x3a, 0x12, 0xff, 0x0f}) {
+		t.Errorf("PackLE = %d % x", n, le)
+	}
+	be := make([]byte, 5)
+	if n := PackBE(be, src); n != 3 || !bytes.Equal(be, []byte{0xab, 0xc1, 0x23, 0xff, 0xf0}) {
+		t.Errorf("PackBE = %d % x", n, be)
+	}
+	out := make([]Uint12, 3)
+	if n := UnpackLE(out, le); n != 3 || out[0] != 0xabc || out[1] != 0x123 || out[2] != 0xfff {
+		t.Errorf("UnpackLE = %d %x", n, out)
+	}
+	if n := UnpackBE(out, be); n != 3 || out[0] != 0xabc || out[2] != 0xfff {
+		t.Errorf("UnpackBE = %d %x", n, out)
+	}
+	// 24 bit little endian is the WAV layout
+	wav := make([]byte, 6)
+	PackLE(wav, []Int24{-2, 0x123456})
+	if !bytes.Equal(wav, []byte{0xfe, 0xff, 0xff, 0x56, 0x34, 0x12}) {
+		t.Errorf("24 bit LE = % x", wav)
+	}
+	back := make([]Int24, 2)
+	UnpackLE(back, wav)
+	if back[0] != -2 || back[1] != 0x123456 {
+		t.Errorf("24 bit LE back = %v", back)
+	}
+	// only whole values are packed, like copy
+	if n := PackLE(make([]byte, 2), src); n != 1 {
+		t.Errorf("packed %d values into 2 bytes, want 1", n)
+	}
+	if n := UnpackBE(out, nil); n != 0 {
+		t.Errorf("unpacked %d values from nothing", n)
+	}
+}
+
+func TestPackRoundTripRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(6))
+	vals := make([]int20, 101)
+	for i := range vals {
+		vals[i] = WrapBitInt[int20](rng.Int63())
+	}
+	for _, pack := range []struct {
+		name   string
+		pack   func([]byte, []int20) int
+		unpack func([]int20, []byte) int
+	}{{"LE", PackLE[int20], UnpackLE[int20]}, {"BE", PackBE[int20], UnpackBE[int20]}} {
+		buf := make([]byte, PackedLen[int20](len(vals)))
+		pack.pack(buf, vals)
+		got := make([]int20, len(vals))
+		pack.unpack(got, buf)
+		for i := range vals {
+			if got[i] != vals[i] {
+				t.Fatalf("%s value %d = %d, want %d", pack.name, i, got[i], vals[i])
+			}
+		}
+	}
+}
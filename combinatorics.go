@@ -0,0 +1,563 @@
+package RUNK
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"math/bits"
+	"sync"
+)
+
+var ErrDomain = errors.New("RUNK: argument out of domain")
+
+/*
+The counting functions are exact for integer N. The count is built as a big integer and fitted into N the same
+way the Mat arithmetic does it, so one that doesn't fit is ErrOverflow with MaxNum under Saturate or the low bits
+under Wrap. Counts that are hopelessly big are spotted from their logarithm first and never built, and under Wrap
+only their low bits are worked out, which takes well under a millisecond for even the biggest Binomial. The Stirling
+numbers are the exception, their low bits still need the recurrence, and past 2^24 steps of it they give up
+with MaxNum and ErrOverflow under Wrap too. Negative arguments are ErrDomain.
+
+A float N gets whole number arguments counted exactly and rounded once, +-Inf with ErrOverflow past the range of
+N, and other arguments go through Gamma and Lgamma the way Factorial(0.5) = Γ(1.5) asks for.
+*/
+
+/*Factorial is n!*/
+func Factorial[N Number](n N, overflow ...OverflowPolicy) (N, error) {
+	x := float64(n)
+	if isFloat[N]() && !whole(x) {
+		return fitGamma[N](gammaRatio([]float64{x + 1}, nil))
+	}
+	b, ok := countArg(n)
+	if !ok {
+		return NaN[N](), ErrDomain
+	}
+	lg, _ := math.Lgamma(x + 1)
+	return fitCount[N](lg/math.Ln2, func(wrap bool) *big.Int {
+		return fallingProduct(b, b.Uint64(), wrap)
+	}, pickOverflowPolicy(overflow))
+}
+
+/*Binomial is n choose k, the number of ways to pick k things out of n. It is 0 when k > n*/
+func Binomial[N Number](n N, k N, overflow ...OverflowPolicy) (N, error) {
+	x, y := float64(n), float64(k)
+	if isFloat[N]() && (!whole(x) || !whole(y)) {
+		if y >= 0 && x-y >= 0 {
+			return fitGamma[N](math.Exp(logBinomial(x, y)))
+		}
+		return fitGamma[N](gammaRatio([]float64{x + 1}, []float64{y + 1, x - y + 1}))
+	}
+	bn, okn := countArg(n)
+	bk, okk := countArg(k)
+	switch {
+	case !okn || !okk:
+		return NaN[N](), ErrDomain
+	case bk.Cmp(bn) > 0:
+		return 0, nil
+	}
+	return fitCount[N](logBinomial(x, y)/math.Ln2, func(wrap bool) *big.Int {
+		return binomial(bn, bk, wrap)
+	}, pickOverflowPolicy(overflow))
+}
+
+/*Permutations is n!/(n-k)!, the number of ways to line up k things out of n. It is 0 when k > n*/
+func Permutations[N Number](n N, k N, overflow ...OverflowPolicy) (N, error) {
+	x, y := float64(n), float64(k)
+	if isFloat[N]() && (!whole(x) || !whole(y)) {
+		return fitGamma[N](gammaRatio([]float64{x + 1}, []float64{x - y + 1}))
+	}
+	bn, okn := countArg(n)
+	bk, okk := countArg(k)
+	switch {
+	case !okn || !okk:
+		return NaN[N](), ErrDomain
+	case bk.Cmp(bn) > 0:
+		return 0, nil
+	}
+	lg, _ := math.Lgamma(y + 1)
+	return fitCount[N]((logBinomial(x, y)+lg)/math.Ln2, func(wrap bool) *big.Int {
+		return fallingProduct(bn, bk.Uint64(), wrap)
+	}, pickOverflowPolicy(overflow))
+}
+
+/*Multinomial is (k1+k2+...)!/(k1!k2!...), the number of ways to split that many things into groups of those sizes*/
+func Multinomial[N Number](ks []N, overflow ...OverflowPolicy) (N, error) {
+	var sum float64
+	fractional := false
+	for _, k := range ks {
+		sum += float64(k)
+		fractional = fractional || !whole(float64(k))
+	}
+	if isFloat[N]() && fractional {
+		den := make([]float64, len(ks))
+		for i, k := range ks {
+			den[i] = float64(k) + 1
+		}
+		return fitGamma[N](gammaRatio([]float64{sum + 1}, den))
+	}
+	// the product of the binomials of each group against everything before it
+	bs := make([]*big.Int, len(ks))
+	var log2, s float64
+	for i, k := range ks {
+		b, ok := countArg(k)
+		if !ok {
+			return NaN[N](), ErrDomain
+		}
+		bs[i] = b
+		s += float64(k)
+		log2 += logBinomial(s, float64(k)) / math.Ln2
+	}
+	return fitCount[N](log2, func(wrap bool) *big.Int {
+		r, total := big.NewInt(1), new(big.Int)
+		for _, b := range bs {
+			total.Add(total, b)
+			r.Mul(r, binomial(total, b, wrap))
+			if wrap {
+				r.And(r, lowBits)
+			}
+		}
+		return r
+	}, pickOverflowPolicy(overflow))
+}
+
+/*Catalan is the nth Catalan number (2n choose n)/(n+1), 1 1 2 5 14 42 ...*/
+func Catalan[N Number](n N, overflow ...OverflowPolicy) (N, error) {
+	x := float64(n)
+	if isFloat[N]() && !whole(x) {
+		return fitGamma[N](gammaRatio([]float64{2*x + 1}, []float64{x + 1, x + 2}))
+	}
+	b, ok := countArg(n)
+	if !ok {
+		return NaN[N](), ErrDomain
+	}
+	return fitCount[N]((logBinomial(2*x, x)-math.Log1p(x))/math.Ln2, func(wrap bool) *big.Int {
+		two := new(big.Int).Lsh(b, 1)
+		if wrap {
+			// (2n choose n)/(n+1) = (2n choose n) - (2n choose n+1) and the subtraction survives wrapping
+			c := binomial(two, b, true)
+			c.Sub(c, binomial(two, new(big.Int).Add(b, big.NewInt(1)), true))
+			return c.And(c, lowBits)
+		}
+		c := binomial(two, b, false)
+		return c.Quo(c, new(big.Int).Add(b, big.NewInt(1)))
+	}, pickOverflowPolicy(overflow))
+}
+
+/*Stirling2 is the Stirling number of the second kind, the number of ways to split n things into k non empty sets*/
+func Stirling2[N Number](n N, k N, overflow ...OverflowPolicy) (N, error) {
+	return stirling(false, n, k, overflow)
+}
+
+/*Stirling1 is the unsigned Stirling number of the first kind, the number of ways to arrange n things into k cycles*/
+func Stirling1[N Number](n N, k N, overflow ...OverflowPolicy) (N, error) {
+	return stirling(true, n, k, overflow)
+}
+
+/*
+LogBinomial is the natural log of Binomial(n, k) for when the count itself is too big for anything, say the
+probabilities of a binomial distribution with n in the millions. It is done with Stirling's series so nothing
+cancels even for n far bigger than k, and it takes any real n and k. It is -Inf when there are no ways at all.
+*/
+func LogBinomial[N Number](n N, k N) float64 {
+	return logBinomial(float64(n), float64(k))
+}
+
+func whole(x float64) bool {
+	return x == math.Trunc(x) && !math.IsInf(x, 0)
+}
+
+/*countArg reads a whole number argument as a big integer, false when it is negative*/
+func countArg[N Number](n N) (*big.Int, bool) {
+	if isFloat[N]() {
+		x := float64(n)
+		if !whole(x) || x < 0 {
+			return nil, false
+		}
+		b, _ := new(big.Float).SetFloat64(x).Int(nil)
+		return b, true
+	}
+	if n < 0 {
+		return nil, false
+	}
+	return bigFromInt(n), true
+}
+
+var lowBits = new(big.Int).SetUint64(math.MaxUint64)
+
+/*
+fitCount fits an exact count into N. log2 is the size of the count so the hopeless ones don't get built, and
+exact builds it, or just its low 64 bits when wrap is set, nil when those would take too long.
+*/
+func fitCount[N Number](log2 float64, exact func(wrap bool) *big.Int, policy OverflowPolicy) (N, error) {
+	// the sizes are from floats so they only decide with a bit to spare
+	if isFloat[N]() {
+		if log2 > 1025 {
+			return N(math.Inf(1)), ErrOverflow
+		}
+		n := bigIntTo[N](exact(false))
+		if IsInf(n, 0) {
+			return n, ErrOverflow
+		}
+		return n, nil
+	}
+	if log2 <= 65 {
+		return fitBig[N](exact(false), policy)
+	}
+	if policy == Wrap {
+		if low := exact(true); low != nil {
+			n, _ := fitBig[N](low, Wrap)
+			return n, ErrOverflow
+		}
+	}
+	return MaxNum[N](), ErrOverflow
+}
+
+/*fitGamma is the float answer for arguments that aren't whole numbers*/
+func fitGamma[N Number](x float64) (N, error) {
+	n := N(x)
+	switch {
+	case math.IsNaN(x):
+		return n, ErrDomain
+	case IsInf(n, 0):
+		return n, ErrOverflow
+	}
+	return n, nil
+}
+
+/*gammaRatio is the product of Γ(num) over the product of Γ(den), in logs with the signs kept*/
+func gammaRatio(num []float64, den []float64) float64 {
+	l, sign := 0.0, 1
+	for _, a := range num {
+		if nonPositiveInt(a) {
+			return math.NaN()
+		}
+		v, s := math.Lgamma(a)
+		l, sign = l+v, sign*s
+	}
+	for _, b := range den {
+		if nonPositiveInt(b) {
+			return 0
+		}
+		v, s := math.Lgamma(b)
+		l, sign = l-v, sign*s
+	}
+	return float64(sign) * math.Exp(l)
+}
+
+/*fallingProduct is top*(top-1)*... for count terms, kept mod 2^64 when wrapping, which hits 0 within 67 terms*/
+func fallingProduct(top *big.Int, count uint64, wrap bool) *big.Int {
+	r, f := big.NewInt(1), new(big.Int).Set(top)
+	one := big.NewInt(1)
+	for i := uint64(0); i < count; i++ {
+		r.Mul(r, f)
+		f.Sub(f, one)
+		if wrap {
+			r.And(r, lowBits)
+			if r.Sign() == 0 {
+				break
+			}
+		}
+	}
+	return r
+}
+
+/*binomial is n choose k for k <= n, multiplying up so every partial result is itself a binomial*/
+func binomial(n *big.Int, k *big.Int, wrap bool) *big.Int {
+	if k.Cmp(n) > 0 {
+		return new(big.Int)
+	}
+	rest := new(big.Int).Sub(n, k)
+	if rest.Cmp(k) < 0 {
+		k, rest = rest, k
+	}
+	if wrap && n.IsUint64() {
+		return new(big.Int).SetUint64(binomialLow(n.Uint64(), k.Uint64()))
+	}
+	if wrap {
+		// a running total in Multinomial or 2n in Catalan can pass 64 bits
+		e := onesCount(k) + onesCount(rest) - onesCount(n)
+		low := oddFactorialLowBig(n) * inverseOdd(oddFactorialLowBig(k)*oddFactorialLowBig(rest)) << e
+		return new(big.Int).SetUint64(low)
+	}
+	r := big.NewInt(1)
+	f, d := new(big.Int).Set(rest), new(big.Int)
+	one := big.NewInt(1)
+	for i := uint64(1); i <= k.Uint64(); i++ {
+		f.Add(f, one)
+		r.Mul(r, f)
+		r.Quo(r, d.SetUint64(i))
+	}
+	return r
+}
+
+/*
+binomialLow is n choose k mod 2^64. Division doesn't work mod 2^64 so the powers of 2 are taken out and counted
+separately with Kummer's theorem, the odd parts have inverses. Small k divides the terms out one by one, past
+that it is the odd parts of the three factorials, which take O(log^2 n) polynomial evaluations however big n is.
+*/
+func binomialLow(n uint64, k uint64) uint64 {
+	// the power of 2 in n choose k is the number of carries adding k and n-k
+	e := bits.OnesCount64(k) + bits.OnesCount64(n-k) - bits.OnesCount64(n)
+	if k > 1<<10 {
+		return oddFactorialLow(n) * inverseOdd(oddFactorialLow(k)*oddFactorialLow(n-k)) << e
+	}
+	num, den := uint64(1), uint64(1)
+	for i := uint64(1); i <= k; i++ {
+		a := n - k + i
+		num *= a >> bits.TrailingZeros64(a)
+		den *= i >> bits.TrailingZeros64(i)
+	}
+	return num * inverseOdd(den) << e
+}
+
+/*
+oddFactorialLow is n! with the powers of 2 taken out, mod 2^64. Splitting n! into its odd and even terms gives
+n! = (the odd numbers up to n) * 2^(n/2) * (n/2)!, so it is the product of the odd numbers up to n>>j for every j.
+*/
+func oddFactorialLow(n uint64) uint64 {
+	r := uint64(1)
+	for ; n > 1; n >>= 1 {
+		r *= oddProductLow((n + 1) / 2)
+	}
+	return r
+}
+
+/*
+oddFactorialLowBig is oddFactorialLow for any n. The odd numbers mod 2^64 come round every 2^63 of them and those
+multiply to 1 mod 2^64, so for the big terms only the count mod 2^63 matters.
+*/
+func oddFactorialLowBig(n *big.Int) uint64 {
+	r, m := uint64(1), new(big.Int).Set(n)
+	mask := new(big.Int).SetUint64(1<<63 - 1)
+	for !m.IsUint64() {
+		c := new(big.Int).Add(m, big.NewInt(1))
+		c.Rsh(c, 1)
+		r *= oddProductLow(c.And(c, mask).Uint64())
+		m.Rsh(m, 1)
+	}
+	return r * oddFactorialLow(m.Uint64())
+}
+
+func onesCount(b *big.Int) int {
+	c := 0
+	for _, w := range b.Bits() {
+		c += bits.OnesCount(uint(w))
+	}
+	return c
+}
+
+/*
+oddProductLow is 1*3*5*...*(2c-1) mod 2^64. The product of a block of 2^b of them starting at 2t+1 is the
+polynomial F_b(t) = (2t+1)(2t+3)...(2t+2^(b+1)-1), whose coefficient of t^i is a multiple of 2^i, so mod 2^64 it
+has at most 64 terms. c is split into its bits and each block is one evaluation of F_b.
+*/
+func oddProductLow(c uint64) uint64 {
+	r, t := uint64(1), uint64(0)
+	for b := bits.Len64(c) - 1; b >= 0; b-- {
+		if c&(1<<b) == 0 {
+			continue
+		}
+		f := oddBlock(b)
+		v := uint64(0)
+		for i := 63; i >= 0; i-- {
+			v = v*t + f[i]
+		}
+		r *= v
+		t += 1 << b
+	}
+	return r
+}
+
+var oddBlockMu sync.Mutex
+var oddBlockCache = [][64]uint64{{1, 2}}
+
+/*oddBlock returns the coefficients of F_b, built up with F_b+1(t) = F_b(t) * F_b(t + 2^b) and cached*/
+func oddBlock(b int) [64]uint64 {
+	oddBlockMu.Lock()
+	defer oddBlockMu.Unlock()
+	for m := len(oddBlockCache); m <= b; m++ {
+		f := oddBlockCache[m-1]
+		// Taylor shift by 2^(m-1), a Horner pass per coefficient
+		g, s := f, uint64(1)<<(m-1)
+		for i := 0; i < 63; i++ {
+			for j := 62; j >= i; j-- {
+				g[j] += s * g[j+1]
+			}
+		}
+		var h [64]uint64
+		for i := range f {
+			for j := 0; i+j < 64; j++ {
+				h[i+j] += f[i] * g[j]
+			}
+		}
+		oddBlockCache = append(oddBlockCache, h)
+	}
+	return oddBlockCache[b]
+}
+
+/*inverseOdd is 1/d mod 2^64 for odd d by Newton's method, every round doubles the correct bits*/
+func inverseOdd(d uint64) uint64 {
+	// d*d = 1 mod 8 so d is its own inverse to 3 bits
+	x := d
+	for i := 0; i < 5; i++ {
+		x *= 2 - d*x
+	}
+	return x
+}
+
+/*
+stirlingWrapSteps caps the recurrence under Wrap, where the size of the count no longer bounds the work. On the
+diagonal the same cap means n-k up to 2^12.
+*/
+const stirlingWrapSteps = 1 << 24
+
+func stirling[N Number](first bool, n N, k N, overflow []OverflowPolicy) (N, error) {
+	bn, okn := countArg(n)
+	bk, okk := countArg(k)
+	switch {
+	case !okn || !okk:
+		return NaN[N](), ErrDomain
+	case bk.Cmp(bn) > 0:
+		return 0, nil
+	case bk.Cmp(bn) == 0:
+		return 1, nil
+	case bk.Sign() == 0:
+		return 0, nil
+	case bk.IsInt64() && bk.Int64() == 1:
+		if first {
+			return Factorial(n-1, overflow...)
+		}
+		return 1, nil
+	}
+	x, y := float64(n), float64(k)
+	j := x - y
+	// both kinds are at least k^(n-k) and near the diagonal at least the first term of the Eulerian sum
+	log2 := j * math.Log2(y)
+	if j < y {
+		if first {
+			log2 = logBinomial(x, 2*j) / math.Ln2
+		} else {
+			log2 = logBinomial(x+j-1, 2*j) / math.Ln2
+		}
+	}
+	return fitCount[N](log2, func(wrap bool) *big.Int {
+		nn, kk := bn.Uint64(), bk.Uint64()
+		if nn-kk < kk {
+			if wrap && nn-kk > 1<<12 {
+				return nil
+			}
+			return stirlingDiagonal(first, nn, nn-kk, wrap)
+		}
+		if hi, lo := bits.Mul64(kk, nn-kk); wrap && (hi > 0 || lo > stirlingWrapSteps) {
+			return nil
+		}
+		return stirlingTriangle(first, nn, kk, wrap)
+	}, pickOverflowPolicy(overflow))
+}
+
+/*
+stirlingTriangle runs the recurrences S(m, c) = c*S(m-1, c) + S(m-1, c-1) and c(m, c) = (m-1)*c(m-1, c) +
+c(m-1, c-1), only over the band of the triangle that leads to (n, k), so it costs about k*(n-k) steps.
+*/
+func stirlingTriangle(first bool, n uint64, k uint64, wrap bool) *big.Int {
+	row := make([]*big.Int, k+1)
+	for c := range row {
+		row[c] = new(big.Int)
+	}
+	row[0].SetInt64(1)
+	t := new(big.Int)
+	for m := uint64(1); m <= n; m++ {
+		lo := uint64(1)
+		if m > n-k {
+			lo = m - (n - k)
+		}
+		for c := min(m, k); c >= lo; c-- {
+			w := c
+			if first {
+				w = m - 1
+			}
+			row[c].Mul(row[c], t.SetUint64(w))
+			row[c].Add(row[c], row[c-1])
+			if wrap {
+				row[c].And(row[c], lowBits)
+			}
+		}
+		row[0].SetInt64(0)
+	}
+	return row[k]
+}
+
+/*
+stirlingDiagonal is for k close to n, where the Stirling numbers are polynomials in n of degree 2(n-k). With
+j = n-k and the second order Eulerian numbers E(j, i) they are the sums over i of E(j, i)*(n+j-1-i choose 2j)
+for the second kind and E(j, i)*(n+i choose 2j) for the first, see Concrete Mathematics 6.43 and 6.44.
+*/
+func stirlingDiagonal(first bool, n uint64, j uint64, wrap bool) *big.Int {
+	// E(m, i) = (i+1)E(m-1, i) + (2m-1-i)E(m-1, i-1)
+	e := make([]*big.Int, j+1)
+	for i := range e {
+		e[i] = new(big.Int)
+	}
+	e[0].SetInt64(1)
+	t, u := new(big.Int), new(big.Int)
+	for m := uint64(1); m <= j; m++ {
+		for i := m; i >= 1; i-- {
+			e[i].Mul(e[i], t.SetUint64(i+1))
+			e[i].Add(e[i], u.Mul(e[i-1], t.SetUint64(2*m-1-i)))
+			if wrap {
+				e[i].And(e[i], lowBits)
+			}
+		}
+	}
+	sum := new(big.Int)
+	two := new(big.Int).SetUint64(2 * j)
+	for i := uint64(0); i < j; i++ {
+		top := n + j - 1 - i
+		if first {
+			top = n + i
+		}
+		sum.Add(sum, u.Mul(e[i], binomial(t.SetUint64(top), two, wrap)))
+		if wrap {
+			sum.And(sum, lowBits)
+		}
+	}
+	return sum
+}
+
+/*stirlingError is log(x!) minus Stirling's formula for it*/
+func stirlingError(x float64) float64 {
+	if x < 16 {
+		lg, _ := math.Lgamma(x + 1)
+		return lg - (x+0.5)*math.Log(x) + x - 0.5*math.Log(2*math.Pi)
+	}
+	f := 1 / (x * x)
+	return (1.0/12 - f*(1.0/360-f*(1.0/1260-f*(1.0/1680-f/1188)))) / x
+}
+
+func logBinomial(n float64, k float64) float64 {
+	m := n - k
+	switch {
+	case math.IsNaN(n) || math.IsNaN(k):
+		return math.NaN()
+	case k == 0 || m == 0:
+		return 0
+	case math.IsInf(n, 1) && !math.IsInf(k, 0):
+		return n
+	case math.IsInf(n, 0) || math.IsInf(k, 0):
+		return math.NaN()
+	case whole(n) && whole(k) && (n < 0):
+		return math.NaN()
+	case whole(n) && whole(k) && (k < 0 || m < 0):
+		return math.Inf(-1)
+	case k < 0 || m < 0:
+		return math.Log(math.Abs(gammaRatio([]float64{n + 1}, []float64{k + 1, m + 1})))
+	}
+	// n log n - k log k - m log m split so the smaller side goes through log1p
+	var a, b float64
+	if k <= m {
+		a, b = k*math.Log(n/k), -m*math.Log1p(-k/n)
+	} else {
+		a, b = -k*math.Log1p(-m/n), m*math.Log(n/m)
+	}
+	return stirlingError(n) - stirlingError(k) - stirlingError(m) + a + b + 0.5*(math.Log(n/k)-math.Log(2*math.Pi*m))
+}
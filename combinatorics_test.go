@@ -0,0 +1,328 @@
+package RUNK
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+/*lowInt64 is what Wrap should leave of b in an int64*/
+func lowInt64(b *big.Int) int64 {
+	return int64(new(big.Int).And(b, lowBits).Uint64())
+}
+
+/*checkCount compares a count in int64 against the big one under both policies*/
+func checkCount(t *testing.T, name string, count func(OverflowPolicy) (int64, error), want *big.Int) {
+	t.Helper()
+	fits := want.IsInt64()
+	got, err := count(Saturate)
+	if fits && (err != nil || got != want.Int64()) || !fits && (err != ErrOverflow || got != math.MaxInt64) {
+		t.Errorf("%s saturated = %d, %v, want %v", name, got, err, want)
+	}
+	got, err = count(Wrap)
+	if got != lowInt64(want) || fits != (err == nil) {
+		t.Errorf("%s wrapped = %d, %v, want %d", name, got, err, lowInt64(want))
+	}
+}
+
+func TestCountsMatchBig(t *testing.T) {
+	fact := big.NewInt(1)
+	for n := int64(0); n <= 70; n++ {
+		if n > 0 {
+			fact.Mul(fact, big.NewInt(n))
+		}
+		checkCount(t, "Factorial", func(p OverflowPolicy) (int64, error) { return Factorial(n, p) }, fact)
+		for k := int64(0); k <= n; k++ {
+			c := new(big.Int).Binomial(n, k)
+			checkCount(t, "Binomial", func(p OverflowPolicy) (int64, error) { return Binomial(n, k, p) }, c)
+			perm := new(big.Int).Quo(fact, new(big.Int).MulRange(1, n-k))
+			checkCount(t, "Permutations", func(p OverflowPolicy) (int64, error) { return Permutations(n, k, p) }, perm)
+		}
+		cat := new(big.Int).Binomial(2*n, n)
+		cat.Quo(cat, big.NewInt(n+1))
+		checkCount(t, "Catalan", func(p OverflowPolicy) (int64, error) { return Catalan(n, p) }, cat)
+	}
+	for _, c := range []struct {
+		n, k int64
+		want int64
+	}{{5, 7, 0}, {0, 0, 1}, {3, 4, 0}} {
+		if got, err := Binomial(c.n, c.k); err != nil || got != c.want {
+			t.Errorf("Binomial(%d, %d) = %d, %v", c.n, c.k, got, err)
+		}
+		if got, err := Permutations(c.n, c.k); err != nil || got != c.want {
+			t.Errorf("Permutations(%d, %d) = %d, %v", c.n, c.k, got, err)
+		}
+	}
+	if got, err := Binomial[uint8](10, 5); err != nil || got != 252 {
+		t.Errorf("Binomial[uint8](10, 5) = %d, %v", got, err)
+	}
+	if got, err := Binomial[uint8](12, 6, Wrap); err != ErrOverflow || got != 924-3*256 {
+		t.Errorf("Binomial[uint8](12, 6) wrapped = %d, %v", got, err)
+	}
+	if got, err := Factorial[int8](6, Wrap); err != ErrOverflow || got != int8(720-768) {
+		t.Errorf("Factorial[int8](6) wrapped = %d, %v", got, err)
+	}
+
+	// Multinomial is the product of binomials, checked on random groups
+	rng := rand.New(rand.NewSource(30))
+	for trial := 0; trial < 300; trial++ {
+		ks := make([]int64, rng.Intn(5))
+		want, total := big.NewInt(1), int64(0)
+		for i := range ks {
+			ks[i] = rng.Int63n(15)
+			total += ks[i]
+			want.Mul(want, new(big.Int).Binomial(total, ks[i]))
+		}
+		checkCount(t, "Multinomial", func(p OverflowPolicy) (int64, error) { return Multinomial(ks, p) }, want)
+	}
+
+	for _, f := range []func() (int64, error){
+		func() (int64, error) { return Factorial[int64](-1) },
+		func() (int64, error) { return Binomial[int64](-3, 1) },
+		func() (int64, error) { return Permutations[int64](4, -1) },
+		func() (int64, error) { return Multinomial([]int64{2, -1}) },
+		func() (int64, error) { return Catalan[int64](-2) },
+		func() (int64, error) { return Stirling2[int64](-2, 1) },
+	} {
+		if _, err := f(); err != ErrDomain {
+			t.Errorf("negative argument = %v", err)
+		}
+	}
+}
+
+func TestStirling(t *testing.T) {
+	// both triangles built the slow way in big, every entry to n = 40 covers both methods inside stirling
+	const size = 41
+	var s1, s2 [size][size]*big.Int
+	for n := 0; n < size; n++ {
+		for k := 0; k < size; k++ {
+			s1[n][k], s2[n][k] = new(big.Int), new(big.Int)
+		}
+	}
+	s1[0][0].SetInt64(1)
+	s2[0][0].SetInt64(1)
+	for n := 1; n < size; n++ {
+		for k := 1; k <= n; k++ {
+			s1[n][k].Mul(big.NewInt(int64(n-1)), s1[n-1][k])
+			s1[n][k].Add(s1[n][k], s1[n-1][k-1])
+			s2[n][k].Mul(big.NewInt(int64(k)), s2[n-1][k])
+			s2[n][k].Add(s2[n][k], s2[n-1][k-1])
+		}
+	}
+	for n := int64(0); n < size; n++ {
+		for k := int64(0); k <= n+1; k++ {
+			w1, w2 := big.NewInt(0), big.NewInt(0)
+			if k < size {
+				w1, w2 = s1[n][k], s2[n][k]
+			}
+			checkCount(t, "Stirling1", func(p OverflowPolicy) (int64, error) { return Stirling1(n, k, p) }, w1)
+			checkCount(t, "Stirling2", func(p OverflowPolicy) (int64, error) { return Stirling2(n, k, p) }, w2)
+		}
+	}
+	if got, _ := Stirling2(5, 2); got != 15 {
+		t.Errorf("Stirling2(5, 2) = %d", got)
+	}
+	if got, _ := Stirling1(5, 2); got != 50 {
+		t.Errorf("Stirling1(5, 2) = %d", got)
+	}
+
+	// past the cap Wrap gives up quickly rather than walking 2^40 rows
+	start := time.Now()
+	for _, f := range []func() (int64, error){
+		func() (int64, error) { return Stirling2[int64](1<<40, 5, Wrap) },
+		func() (int64, error) { return Stirling1[int64](1<<40, 1<<40-1<<20, Wrap) },
+	} {
+		if got, err := f(); err != ErrOverflow || got != math.MaxInt64 {
+			t.Errorf("capped Stirling = %d, %v", got, err)
+		}
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("capped Stirling took %v", d)
+	}
+	// just under it the low bits are still right
+	if got, err := Stirling2[int64](300, 3, Wrap); err != ErrOverflow || got != lowInt64(stirling2Three(300)) {
+		t.Errorf("Stirling2(300, 3) wrapped = %d, %v", got, err)
+	}
+}
+
+/*stirling2Three is S(n, 3) = (3^n - 3*2^n + 3)/6*/
+func stirling2Three(n int64) *big.Int {
+	r := new(big.Int).Exp(big.NewInt(3), big.NewInt(n), nil)
+	r.Sub(r, new(big.Int).Lsh(big.NewInt(3), uint(n)))
+	r.Add(r, big.NewInt(3))
+	return r.Quo(r, big.NewInt(6))
+}
+
+func TestBinomialWrapHuge(t *testing.T) {
+	// both ways of getting the low bits against big, the factorial way only kicks in past k = 1024
+	rng := rand.New(rand.NewSource(31))
+	for trial := 0; trial < 100; trial++ {
+		n := rng.Int63n(8000)
+		k := rng.Int63n(n + 1)
+		want := new(big.Int).Binomial(n, k)
+		if got := binomialLow(uint64(n), uint64(k)); got != new(big.Int).And(want, lowBits).Uint64() {
+			t.Fatalf("binomialLow(%d, %d) = %d", n, k, got)
+		}
+	}
+
+	// Pascal's rule holds mod 2^64 too, which checks the big blocks nothing else can
+	for trial := 0; trial < 200; trial++ {
+		n := rng.Uint64()
+		k := rng.Uint64() % n
+		if k == 0 {
+			continue
+		}
+		if binomialLow(n, k) != binomialLow(n-1, k-1)+binomialLow(n-1, k) {
+			t.Fatalf("Pascal's rule fails at %d, %d", n, k)
+		}
+	}
+	if got := binomialLow(1<<63, 1); got != 1<<63 {
+		t.Errorf("(2^63 choose 1) = %d", got)
+	}
+	if got := binomialLow(math.MaxUint64, 1<<40); got%2 != 1 {
+		t.Errorf("(2^64-1 choose k) should be odd, got %d", got)
+	}
+
+	start := time.Now()
+	got, err := Binomial[int64](1<<34, 1<<33, Wrap)
+	if err != ErrOverflow || got != lowInt64(new(big.Int).SetUint64(binomialLow(1<<34, 1<<33))) {
+		t.Errorf("Binomial(2^34, 2^33) wrapped = %d, %v", got, err)
+	}
+	if _, err := Catalan[uint64](1<<40, Wrap); err != ErrOverflow {
+		t.Errorf("Catalan(2^40) wrapped = %v", err)
+	}
+	if _, err := Multinomial([]int64{1 << 40, 1 << 41, 3}, Wrap); err != ErrOverflow {
+		t.Errorf("huge Multinomial wrapped = %v", err)
+	}
+	if got, err := Factorial[uint64](1<<62, Wrap); err != ErrOverflow || got != 0 {
+		t.Errorf("Factorial(2^62) wrapped = %d, %v", got, err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("huge wrapped counts took %v", d)
+	}
+	if got, err := Binomial[int64](1<<34, 1<<33); err != ErrOverflow || got != math.MaxInt64 {
+		t.Errorf("Binomial(2^34, 2^33) saturated = %d, %v", got, err)
+	}
+}
+
+func TestWrapPast64Bits(t *testing.T) {
+	// the running total of Multinomial passes 2^64, (2^64+1 choose 2) = (2^64+1) * 2^63
+	if got, err := Multinomial([]uint64{math.MaxUint64, 2}, Wrap); err != ErrOverflow || got != 1<<63 {
+		t.Errorf("Multinomial(2^64-1, 2) wrapped = %d, %v, want 2^63", got, err)
+	}
+	// small groups on top of a huge one against the product formula in big
+	rng := rand.New(rand.NewSource(33))
+	for trial := 0; trial < 100; trial++ {
+		ks := []uint64{math.MaxUint64 - rng.Uint64()%1000, uint64(rng.Intn(40)), uint64(rng.Intn(40))}
+		want, total := big.NewInt(1), new(big.Int).SetUint64(ks[0])
+		for _, k := range ks[1:] {
+			for i := uint64(1); i <= k; i++ {
+				total.Add(total, big.NewInt(1))
+				want.Mul(want, total)
+				want.Quo(want, new(big.Int).SetUint64(i))
+			}
+		}
+		if got, _ := Multinomial(ks, Wrap); got != new(big.Int).And(want, lowBits).Uint64() {
+			t.Fatalf("Multinomial%v wrapped = %d", ks, got)
+		}
+	}
+	// Pascal's rule past 64 bits with k big enough for the factorial way
+	for trial := 0; trial < 100; trial++ {
+		n := new(big.Int).Lsh(new(big.Int).SetUint64(rng.Uint64()), uint(rng.Intn(20)+1))
+		n.Add(n, new(big.Int).SetUint64(1<<64-1))
+		k := new(big.Int).SetUint64(rng.Uint64()>>1 + 1)
+		n1, k1 := new(big.Int).Sub(n, big.NewInt(1)), new(big.Int).Sub(k, big.NewInt(1))
+		sum := binomial(n1, k1, true).Uint64() + binomial(n1, k, true).Uint64()
+		if got := binomial(n, k, true).Uint64(); got != sum {
+			t.Fatalf("Pascal's rule fails at %v, %v: %d against %d", n, k, got, sum)
+		}
+	}
+	// 2n passes 2^64 from n = 2^63, (n+2) C(n+1) = 2(2n+1) C(n) holds mod 2^64 too
+	for _, n := range []uint64{1 << 63, 1<<63 + 1, math.MaxUint64 - 2, 3<<62 + 12345} {
+		c, _ := Catalan(n, Wrap)
+		next, _ := Catalan(n+1, Wrap)
+		if (n+2)*next != 2*(2*n+1)*c {
+			t.Errorf("Catalan(%d) and Catalan(%d) wrapped = %d %d don't follow the recurrence", n, n+1, c, next)
+		}
+	}
+}
+
+func TestCountsInFloats(t *testing.T) {
+	tests := []struct {
+		name string
+		got  float64
+		err  error
+		want float64
+	}{}
+	add := func(name string, want float64) func(float64, error) {
+		return func(got float64, err error) {
+			tests = append(tests, struct {
+				name string
+				got  float64
+				err  error
+				want float64
+			}{name, got, err, want})
+		}
+	}
+	add("Factorial(0.5)", math.Sqrt(math.Pi)/2)(Factorial(0.5))
+	add("Factorial(-0.5)", math.Sqrt(math.Pi))(Factorial(-0.5))
+	add("Factorial(170)", math.Gamma(171))(Factorial(170.0))
+	add("Binomial(5.5, 2)", 5.5*4.5/2)(Binomial(5.5, 2))
+	add("Binomial(60, 30)", 118264581564861424)(Binomial(60.0, 30))
+	add("Binomial(1e6, 2)", 1e6*999999/2)(Binomial(1e6, 2))
+	add("Permutations(4.5, 2)", 4.5*3.5)(Permutations(4.5, 2))
+	add("Catalan(0.5)", 8/(3*math.Pi))(Catalan(0.5))
+	add("Multinomial(1.5, 1)", math.Gamma(3.5)/math.Gamma(2.5))(Multinomial([]float64{1.5, 1}))
+	for _, tt := range tests {
+		if tt.err != nil || !relClose(tt.got, tt.want, 1e-14) {
+			t.Errorf("%s = %v, %v, want %v", tt.name, tt.got, tt.err, tt.want)
+		}
+	}
+	if got, err := Factorial(171.0); err != ErrOverflow || !math.IsInf(got, 1) {
+		t.Errorf("Factorial(171) = %v, %v", got, err)
+	}
+	if got, err := Factorial(float32(35)); err != ErrOverflow || !IsInf(got, 1) {
+		t.Errorf("Factorial[float32](35) = %v, %v", got, err)
+	}
+	if got, err := Factorial(math.NaN()); err != ErrDomain || got == got {
+		t.Errorf("Factorial(NaN) = %v, %v", got, err)
+	}
+	if _, err := Factorial(-1.0); err != ErrDomain {
+		t.Errorf("Factorial(-1) = %v", err)
+	}
+	if got, err := Multinomial([]float64{}); err != nil || got != 1 {
+		t.Errorf("Multinomial of nothing = %v, %v", got, err)
+	}
+}
+
+func TestLogBinomial(t *testing.T) {
+	rng := rand.New(rand.NewSource(32))
+	for trial := 0; trial < 1000; trial++ {
+		n := rng.ExpFloat64() * 200
+		k := rng.Float64() * n
+		want := lgammaOf(n+1) - lgammaOf(k+1) - lgammaOf(n-k+1)
+		if got := LogBinomial(n, k); !closeTo(got, want, 1e-12) {
+			t.Fatalf("LogBinomial(%v, %v) = %v, want %v", n, k, got, want)
+		}
+	}
+	// n far bigger than k is where subtracting Lgammas loses everything
+	if got, want := LogBinomial(1e15, 3), math.Log(1e15)+math.Log(1e15-1)+math.Log(1e15-2)-math.Log(6); !relClose(got, want, 1e-14) {
+		t.Errorf("LogBinomial(1e15, 3) = %v, want %v", got, want)
+	}
+	if got := LogBinomial(1e300, 1); !relClose(got, math.Log(1e300), 1e-14) {
+		t.Errorf("LogBinomial(1e300, 1) = %v", got)
+	}
+	for _, c := range []struct {
+		n, k float64
+		want float64
+	}{{5, 7, math.Inf(-1)}, {0, 0, 0}, {7, 0, 0}, {7, 7, 0}, {math.Inf(1), 3, math.Inf(1)}, {-3, 1, math.NaN()}, {math.NaN(), 1, math.NaN()}} {
+		if got := LogBinomial(c.n, c.k); !sameFloat(got, c.want) {
+			t.Errorf("LogBinomial(%v, %v) = %v, want %v", c.n, c.k, got, c.want)
+		}
+	}
+	if got := LogBinomial[int64](60, 30); !closeTo(got, math.Log(118264581564861424), 1e-14) {
+		t.Errorf("LogBinomial[int64](60, 30) = %v", got)
+	}
+}
@@ -0,0 +1,490 @@
+package RUNK
+
+import (
+	"math"
+	"math/big"
+)
+
+/*OverflowPolicy picks what a Context does with an integer result that doesn't fit in N*/
+type OverflowPolicy int
+
+const (
+	// Saturate pins the result to MaxNum/MinNum, the same as ConvertNumber
+	Saturate OverflowPolicy = iota
+	// Wrap keeps the low bits of the rounded result the way Go's own integer conversions do
+	Wrap
+)
+
+/*
+Context carries a rounding mode and an overflow policy and its methods are the wrappers with those applied to the
+result. The wrappers always round to nearest so Log2(7) is 3, with a floor context it is 2:
+
+	floor := NewContext[int](math.Floor, Saturate)
+	floor.Log2(7) // 2
+
+Rounding and overflow only matter when N is an integer type, a float N gets the float64 result converted the same
+way ConvertNumber does it. NaN results still become 0 and +-Inf still become MaxNum/MinNum since there are no bits
+to wrap.
+For an integer N the functions with exact integer answers don't go through float64 at all, a float64 can't hold
+every int64 and the rounding of the float result can land on the wrong side of a whole number. Sqrt, Cbrt, Log2,
+Log10 and Pow are worked out exactly and checked against the integers either side, Abs, Max and Min and the
+rounding functions never leave N. Exp2, Ldexp and Pow10 are already exact since their results are powers of 2 or
+small enough powers of 10.
+A Context is a small value so pass it around and copy it freely.
+*/
+type Context[N Number] struct {
+	round    RoundingMode
+	overflow OverflowPolicy
+}
+
+/*NewContext makes a Context, a nil roundMode means math.Round*/
+func NewContext[N Number](roundMode RoundingMode, overflow OverflowPolicy) Context[N] {
+	return Context[N]{round: pickRoundingMode([]RoundingMode{roundMode}), overflow: overflow}
+}
+
+func (c Context[N]) RoundingMode() RoundingMode {
+	return pickRoundingMode([]RoundingMode{c.round})
+}
+
+func (c Context[N]) OverflowPolicy() OverflowPolicy {
+	return c.overflow
+}
+
+/*Convert rounds and fits a float64 into N following the Context, every method finishes with this*/
+func (c Context[N]) Convert(f float64) N {
+	mode := c.RoundingMode()
+	if c.overflow != Wrap || isFloat[N]() || math.IsNaN(f) || math.IsInf(f, 0) {
+		return ConvertNumberBy[N](f, mode)
+	}
+	return wrapFloat[N](mode(f))
+}
+
+/*
+wrapFloat takes a whole number float down to its low 64 bits and then lets the Go conversion drop the rest.
+The subtraction is exact since floats past 2^63 are all multiples of 2048.
+*/
+func wrapFloat[N Number](f float64) N {
+	m := math.Mod(f, 1<<64)
+	if m >= 1<<63 {
+		m -= 1 << 64
+	} else if m < -(1 << 63) {
+		m += 1 << 64
+	}
+	return N(int64(m))
+}
+
+/*
+exactInt rounds a result v that is only known by q = floor(|v|), whether |v| is exactly q and which side of q+1/2
+it is on (half is the sign of |v| - q - 1/2). A float at q+1/4, q+1/2 or q+3/4 sits in the same place between the
+integers and half way points as v does so the rounding mode gives the same answer for it. neg is the sign of v.
+*/
+func (c Context[N]) exactInt(neg bool, q *big.Int, exact bool, half int) N {
+	if exact {
+		if neg {
+			q = new(big.Int).Neg(q)
+		}
+		return c.fit(q)
+	}
+	// inexact results are roots and logs, small enough for the float to hold q and the fraction
+	f := float64(q.Int64()) + 0.75
+	switch {
+	case half < 0:
+		f -= 0.5
+	case half == 0:
+		f -= 0.25
+	}
+	if neg {
+		f = -f
+	}
+	return c.Convert(f)
+}
+
+/*rootInt is the floor of the nth root of x >= 0 and where the root sits against the integers for exactInt*/
+func rootInt(x *big.Int, n int) (q *big.Int, exact bool, half int) {
+	f, _ := new(big.Float).SetInt(x).Float64()
+	q = big.NewInt(int64(math.Pow(f, 1/float64(n))))
+	pow := func(b *big.Int) *big.Int { return new(big.Int).Exp(b, big.NewInt(int64(n)), nil) }
+	one := big.NewInt(1)
+	// the float guess is within one or two of the root, step it onto q^n <= x < (q+1)^n
+	for pow(q).Cmp(x) > 0 {
+		q.Sub(q, one)
+	}
+	for pow(new(big.Int).Add(q, one)).Cmp(x) <= 0 {
+		q.Add(q, one)
+	}
+	// the root against q+1/2 is 2^n*x against (2q+1)^n, an odd number against an even one so never a tie
+	odd := new(big.Int).Lsh(q, 1)
+	half = new(big.Int).Lsh(x, uint(n)).Cmp(pow(odd.Add(odd, one)))
+	return q, pow(q).Cmp(x) == 0, half
+}
+
+/*logInt is the floor of the log of x > 0 in base and where the log sits against the integers for exactInt*/
+func logInt(x *big.Int, base int64) (q *big.Int, exact bool, half int) {
+	b := big.NewInt(base)
+	p, next, n := big.NewInt(1), big.NewInt(base), int64(0)
+	for next.Cmp(x) <= 0 {
+		p.Set(next)
+		next.Mul(next, b)
+		n++
+	}
+	// the log against n+1/2 is x^2 against base^(2n+1)
+	edge := new(big.Int).Mul(p, p)
+	half = new(big.Int).Mul(x, x).Cmp(edge.Mul(edge, b))
+	return big.NewInt(n), p.Cmp(x) == 0, half
+}
+
+/*fit puts an exact integer into N following the overflow policy*/
+func (c Context[N]) fit(b *big.Int) N {
+	n, _ := fitBig[N](b, c.overflow)
+	return n
+}
+
+func (c Context[N]) Abs(num N) N {
+	if isFloat[N]() || num >= 0 {
+		return c.Convert(math.Abs(float64(num)))
+	}
+	return c.fit(new(big.Int).Neg(bigFromInt(num)))
+}
+
+func (c Context[N]) Max(nums ...N) N {
+	return Max(nums...)
+}
+
+func (c Context[N]) Min(nums ...N) N {
+	return Min(nums...)
+}
+
+func (c Context[N]) Acos(num N) N {
+	return c.Convert(math.Acos(float64(num)))
+}
+
+func (c Context[N]) Acosh(num N) N {
+	return c.Convert(math.Acosh(float64(num)))
+}
+
+func (c Context[N]) Asin(num N) N {
+	return c.Convert(math.Asin(float64(num)))
+}
+
+func (c Context[N]) Asinh(num N) N {
+	return c.Convert(math.Asinh(float64(num)))
+}
+
+func (c Context[N]) Atan(num N) N {
+	return c.Convert(math.Atan(float64(num)))
+}
+
+func (c Context[N]) Atan2(y N, x N) N {
+	return c.Convert(math.Atan2(float64(y), float64(x)))
+}
+
+func (c Context[N]) Atanh(num N) N {
+	return c.Convert(math.Atanh(float64(num)))
+}
+
+func (c Context[N]) Cbrt(num N) N {
+	if isFloat[N]() {
+		return c.Convert(math.Cbrt(float64(num)))
+	}
+	x := bigFromInt(num)
+	q, exact, half := rootInt(new(big.Int).Abs(x), 3)
+	return c.exactInt(x.Sign() < 0, q, exact, half)
+}
+
+func (c Context[N]) Ceil(num N) N {
+	if !isFloat[N]() {
+		return num
+	}
+	return c.Convert(math.Ceil(float64(num)))
+}
+
+func (c Context[N]) Copysign(f N, sign N) N {
+	return c.Convert(math.Copysign(float64(f), float64(sign)))
+}
+
+func (c Context[N]) Cos(num N) N {
+	return c.Convert(math.Cos(float64(num)))
+}
+
+func (c Context[N]) Cosh(num N) N {
+	return c.Convert(math.Cosh(float64(num)))
+}
+
+func (c Context[N]) Dim(x N, y N) N {
+	return c.Convert(math.Dim(float64(x), float64(y)))
+}
+
+func (c Context[N]) Erf(num N) N {
+	return c.Convert(math.Erf(float64(num)))
+}
+
+func (c Context[N]) Erfc(num N) N {
+	return c.Convert(math.Erfc(float64(num)))
+}
+
+func (c Context[N]) Erfcinv(num N) N {
+	return c.Convert(math.Erfcinv(float64(num)))
+}
+
+func (c Context[N]) Erfinv(num N) N {
+	return c.Convert(math.Erfinv(float64(num)))
+}
+
+func (c Context[N]) Exp(num N) N {
+	return c.Convert(math.Exp(float64(num)))
+}
+
+func (c Context[N]) Exp2(num N) N {
+	return c.Convert(math.Exp2(float64(num)))
+}
+
+func (c Context[N]) Expm1(num N) N {
+	return c.Convert(math.Expm1(float64(num)))
+}
+
+func (c Context[N]) FMA(x N, y N, z N) N {
+	return c.Convert(math.FMA(float64(x), float64(y), float64(z)))
+}
+
+func (c Context[N]) Floor(num N) N {
+	if !isFloat[N]() {
+		return num
+	}
+	return c.Convert(math.Floor(float64(num)))
+}
+
+func (c Context[N]) Frexp(num N) (N, int) {
+	frac, exp := math.Frexp(float64(num))
+	return c.Convert(frac), exp
+}
+
+func (c Context[N]) Gamma(num N) N {
+	return c.Convert(math.Gamma(float64(num)))
+}
+
+func (c Context[N]) Hypot(x N, y N) N {
+	return c.Convert(math.Hypot(float64(x), float64(y)))
+}
+
+func (c Context[N]) Ilogb(num N) N {
+	return c.Convert(float64(math.Ilogb(float64(num))))
+}
+
+func (c Context[N]) J0(num N) N {
+	return c.Convert(math.J0(float64(num)))
+}
+
+func (c Context[N]) J1(num N) N {
+	return c.Convert(math.J1(float64(num)))
+}
+
+func (c Context[N]) Jn(n int, x N) N {
+	return c.Convert(math.Jn(n, float64(x)))
+}
+
+func (c Context[N]) Ldexp(frac N, exp int) N {
+	return c.Convert(math.Ldexp(float64(frac), exp))
+}
+
+func (c Context[N]) Lgamma(num N) (N, int) {
+	x, i := math.Lgamma(float64(num))
+	return c.Convert(x), i
+}
+
+/*Lgam is Lgamma with the sign multiplied back in like the Lgam wrapper*/
+func (c Context[N]) Lgam(num N) N {
+	x, i := math.Lgamma(float64(num))
+	return c.Convert(x * float64(i))
+}
+
+func (c Context[N]) Log(num N) N {
+	return c.Convert(math.Log(float64(num)))
+}
+
+func (c Context[N]) Log10(num N) N {
+	if isFloat[N]() || num <= 0 {
+		return c.Convert(math.Log10(float64(num)))
+	}
+	q, exact, half := logInt(bigFromInt(num), 10)
+	return c.exactInt(false, q, exact, half)
+}
+
+func (c Context[N]) Log1p(num N) N {
+	return c.Convert(math.Log1p(float64(num)))
+}
+
+func (c Context[N]) Log2(num N) N {
+	if isFloat[N]() || num <= 0 {
+		return c.Convert(math.Log2(float64(num)))
+	}
+	q, exact, half := logInt(bigFromInt(num), 2)
+	return c.exactInt(false, q, exact, half)
+}
+
+func (c Context[N]) Logb(num N) N {
+	return c.Convert(math.Logb(float64(num)))
+}
+
+func (c Context[N]) Mod(x N, y N) N {
+	return c.Convert(math.Mod(float64(x), float64(y)))
+}
+
+func (c Context[N]) Modf(num N) (N, float64) {
+	x, i := math.Modf(float64(num))
+	return c.Convert(x), i
+}
+
+func (c Context[N]) Nextafter(x N, y N) N {
+	return c.Convert(math.Nextafter(float64(x), float64(y)))
+}
+
+func (c Context[N]) Nextafter32(x N, y N) N {
+	return c.Convert(float64(math.Nextafter32(float32(x), float32(y))))
+}
+
+/*
+Pow with an integer N and y >= 0 is done exactly. A negative y gives a result under 1 in size that the float math
+gets right.
+*/
+func (c Context[N]) Pow(x N, y N) N {
+	if isFloat[N]() || y < 0 {
+		return c.Convert(math.Pow(float64(x), float64(y)))
+	}
+	bx, by := bigFromInt(x), bigFromInt(y)
+	if c.overflow == Wrap {
+		return c.fit(new(big.Int).Exp(bx, by, new(big.Int).Lsh(big.NewInt(1), 64)))
+	}
+	if bx.CmpAbs(big.NewInt(1)) > 0 && by.Cmp(big.NewInt(128)) > 0 {
+		// far past any N so only the sign matters
+		huge := new(big.Int).Lsh(big.NewInt(1), 200)
+		if bx.Sign() < 0 && by.Bit(0) == 1 {
+			huge.Neg(huge)
+		}
+		return c.fit(huge)
+	}
+	return c.fit(new(big.Int).Exp(bx, by, nil))
+}
+
+func (c Context[N]) Pow10(n int) N {
+	return c.Convert(math.Pow10(n))
+}
+
+func (c Context[N]) Remainder(x N, y N) N {
+	return c.Convert(math.Remainder(float64(x), float64(y)))
+}
+
+func (c Context[N]) Round(num N) N {
+	if !isFloat[N]() {
+		return num
+	}
+	return c.Convert(math.Round(float64(num)))
+}
+
+func (c Context[N]) RoundToEven(num N) N {
+	if !isFloat[N]() {
+		return num
+	}
+	return c.Convert(math.RoundToEven(float64(num)))
+}
+
+func (c Context[N]) Sin(num N) N {
+	return c.Convert(math.Sin(float64(num)))
+}
+
+func (c Context[N]) Sincos(num N) (N, N) {
+	x, y := math.Sincos(float64(num))
+	return c.Convert(x), c.Convert(y)
+}
+
+func (c Context[N]) Sinh(num N) N {
+	return c.Convert(math.Sinh(float64(num)))
+}
+
+func (c Context[N]) Sqrt(num N) N {
+	if isFloat[N]() || num < 0 {
+		return c.Convert(math.Sqrt(float64(num)))
+	}
+	q, exact, half := rootInt(bigFromInt(num), 2)
+	return c.exactInt(false, q, exact, half)
+}
+
+func (c Context[N]) Tan(num N) N {
+	return c.Convert(math.Tan(float64(num)))
+}
+
+func (c Context[N]) Tanh(num N) N {
+	return c.Convert(math.Tanh(float64(num)))
+}
+
+func (c Context[N]) Trunc(num N) N {
+	if !isFloat[N]() {
+		return num
+	}
+	return c.Convert(math.Trunc(float64(num)))
+}
+
+func (c Context[N]) Y0(num N) N {
+	return c.Convert(math.Y0(float64(num)))
+}
+
+func (c Context[N]) Y1(num N) N {
+	return c.Convert(math.Y1(float64(num)))
+}
+
+func (c Context[N]) Yn(n int, x N) N {
+	return c.Convert(math.Yn(n, float64(x)))
+}
+
+/*the special functions from special.go*/
+
+func (c Context[N]) Beta(a N, b N) N {
+	return c.Convert(beta(float64(a), float64(b)))
+}
+
+func (c Context[N]) LogBeta(a N, b N) N {
+	return c.Convert(logBeta(float64(a), float64(b)))
+}
+
+func (c Context[N]) GammaP(a N, x N) N {
+	return c.Convert(gammaP(float64(a), float64(x)))
+}
+
+func (c Context[N]) GammaQ(a N, x N) N {
+	return c.Convert(gammaQ(float64(a), float64(x)))
+}
+
+func (c Context[N]) BetaInc(a N, b N, x N) N {
+	return c.Convert(betaInc(float64(a), float64(b), float64(x)))
+}
+
+func (c Context[N]) Digamma(num N) N {
+	return c.Convert(digamma(float64(num)))
+}
+
+func (c Context[N]) Trigamma(num N) N {
+	return c.Convert(trigamma(float64(num)))
+}
+
+func (c Context[N]) Zeta(s N) N {
+	return c.Convert(zeta(float64(s)))
+}
+
+func (c Context[N]) LambertW(k int, x N) N {
+	return c.Convert(lambertW(k, float64(x)))
+}
+
+func (c Context[N]) Expint(num N) N {
+	return c.Convert(expint(float64(num)))
+}
+
+func (c Context[N]) Sinc(num N) N {
+	return c.Convert(sinc(float64(num)))
+}
+
+func (c Context[N]) Logit(p N) N {
+	return c.Convert(logit(float64(p)))
+}
+
+func (c Context[N]) Expit(num N) N {
+	return c.Convert(expit(float64(num)))
+}
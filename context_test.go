@@ -0,0 +1,212 @@
+package RUNK
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func TestContextReviewCases(t *testing.T) {
+	floor := NewContext[int64](math.Floor, Saturate)
+	tests := []struct {
+		name string
+		got  int64
+		want int64
+	}{
+		{"Log10(1e15)", floor.Log10(1e15), 15},
+		{"Log10(1e15-1)", floor.Log10(1e15 - 1), 14},
+		{"Sqrt(1<<62-1)", floor.Sqrt(1<<62 - 1), 2147483647},
+		{"Sqrt(999999999999999999)", floor.Sqrt(999999999999999999), 999999999},
+		{"Log2(7)", floor.Log2(7), 2},
+		{"Log2(1<<62)", floor.Log2(1 << 62), 62},
+		{"Cbrt(-28)", floor.Cbrt(-28), -4},
+		{"Pow(3, 39)", floor.Pow(3, 39), 4052555153018976267},
+		{"Pow(3, 40)", floor.Pow(3, 40), math.MaxInt64},
+		{"Pow(-2, 63)", floor.Pow(-2, 63), math.MinInt64},
+		{"Pow(2, -1)", floor.Pow(2, -1), 0},
+		{"Pow(0, 0)", floor.Pow(0, 0), 1},
+		{"Exp2(62)", floor.Exp2(62), 1 << 62},
+		{"Floor(MaxInt-1)", floor.Floor(math.MaxInt64 - 1), math.MaxInt64 - 1},
+		{"Abs(MinInt)", floor.Abs(math.MinInt64), math.MaxInt64},
+		{"Sqrt(-4)", floor.Sqrt(-4), 0},
+		{"Log2(0)", floor.Log2(0), math.MinInt64},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %d, want %d", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestContextExactAgainstBrute(t *testing.T) {
+	modes := []struct {
+		name string
+		f    RoundingMode
+	}{{"floor", math.Floor}, {"ceil", math.Ceil}, {"trunc", math.Trunc}, {"round", math.Round}}
+	rng := rand.New(rand.NewSource(7))
+	for i := 0; i < 3000; i++ {
+		x := rng.Int63() >> uint(rng.Intn(63))
+		if i%7 == 0 {
+			// the squares and powers and their neighbours are where float rounding goes wrong
+			r := x >> 32
+			x = r*r + int64(rng.Intn(3)) - 1
+		}
+		if x <= 0 {
+			continue
+		}
+		for _, m := range modes {
+			c := NewContext[int64](m.f, Saturate)
+			// the sqrt is never exactly half way so the floor and the half way check decide everything
+			q := new(big.Int).Sqrt(big.NewInt(x))
+			s := c.Sqrt(x)
+			lo := new(big.Int).Mul(q, q)
+			var want *big.Int
+			switch {
+			case lo.Cmp(big.NewInt(x)) == 0:
+				want = q
+			case m.name == "floor" || m.name == "trunc":
+				want = q
+			case m.name == "ceil":
+				want = new(big.Int).Add(q, big.NewInt(1))
+			default:
+				// 4x against (2q+1)^2
+				odd := new(big.Int).Lsh(q, 1)
+				odd.Add(odd, big.NewInt(1))
+				want = q
+				if new(big.Int).Lsh(big.NewInt(x), 2).Cmp(odd.Mul(odd, odd)) > 0 {
+					want = new(big.Int).Add(q, big.NewInt(1))
+				}
+			}
+			if s != want.Int64() {
+				t.Fatalf("%s Sqrt(%d) = %d, want %d", m.name, x, s, want)
+			}
+			l := c.Log10(x)
+			p := new(big.Int).Exp(big.NewInt(10), big.NewInt(l), nil)
+			if m.name == "floor" && (p.Cmp(big.NewInt(x)) > 0 || p.Mul(p, big.NewInt(10)).Cmp(big.NewInt(x)) <= 0) {
+				t.Fatalf("floor Log10(%d) = %d", x, l)
+			}
+		}
+	}
+	// small cube roots are far enough from the integers and half way points for the float to round right
+	for x := int64(-3000); x <= 3000; x++ {
+		for _, m := range modes {
+			if got, want := NewContext[int64](m.f, Saturate).Cbrt(x), int64(m.f(math.Cbrt(float64(x)))); got != want {
+				t.Fatalf("%s Cbrt(%d) = %d, want %d", m.name, x, got, want)
+			}
+		}
+	}
+	floor, ceil := NewContext[int64](math.Floor, Saturate), NewContext[int64](math.Ceil, Saturate)
+	if floor.Cbrt(1<<60) != 1<<20 || ceil.Cbrt(1<<60) != 1<<20 || ceil.Cbrt(1<<60+1) != 1<<20+1 {
+		t.Error("Cbrt around 2^60 is wrong")
+	}
+	if floor.Cbrt(math.MaxInt64) != 2097151 || ceil.Cbrt(math.MinInt64) != -2097152 {
+		t.Error("Cbrt at the ends of int64 is wrong")
+	}
+}
+
+func TestContextPowExact(t *testing.T) {
+	rng := rand.New(rand.NewSource(8))
+	two64 := new(big.Int).Lsh(big.NewInt(1), 64)
+	for i := 0; i < 2000; i++ {
+		x, y := int64(rng.Intn(41)-20), int64(rng.Intn(70))
+		exact := new(big.Int).Exp(big.NewInt(x), big.NewInt(y), nil)
+		want := bigIntTo[int64](exact)
+		if got := NewContext[int64](nil, Saturate).Pow(x, y); got != want {
+			t.Fatalf("Pow(%d, %d) = %d, want %d", x, y, got, want)
+		}
+		low := new(big.Int).Mod(exact, two64)
+		if got := NewContext[int64](nil, Wrap).Pow(x, y); got != int64(low.Uint64()) {
+			t.Fatalf("wrapped Pow(%d, %d) = %d, want %d", x, y, got, int64(low.Uint64()))
+		}
+		if got := NewContext[uint8](nil, Wrap).Pow(uint8(x), uint8(y)); got != uint8(new(big.Int).Exp(big.NewInt(int64(uint8(x))), big.NewInt(y), two64).Uint64()) {
+			t.Fatalf("wrapped uint8 Pow(%d, %d) = %d", uint8(x), y, got)
+		}
+	}
+	if got := NewContext[int](nil, Saturate).Pow(-3, math.MaxInt); got != math.MinInt {
+		t.Errorf("Pow(-3, MaxInt) = %d, want it to saturate low", got)
+	}
+	if got := NewContext[int](nil, Wrap).Pow(3, math.MaxInt); got == 0 {
+		t.Error("wrapped Pow(3, MaxInt) is 0 but 3 is odd")
+	}
+}
+
+func TestContextRoundingAndOverflow(t *testing.T) {
+	ceil := NewContext[int8](math.Ceil, Saturate)
+	if got := ceil.Log(2); got != 1 {
+		t.Errorf("ceil Log(2) = %d", got)
+	}
+	if got := ceil.Exp(10); got != math.MaxInt8 {
+		t.Errorf("saturated Exp(10) = %d", got)
+	}
+	wrap := NewContext[int8](nil, Wrap)
+	if got := wrap.Exp(5); got != int8(int64(math.Round(math.Exp(5)))) {
+		t.Errorf("wrapped Exp(5) = %d", got)
+	}
+	if got := wrap.Abs(math.MinInt8); got != math.MinInt8 {
+		t.Errorf("wrapped Abs(MinInt8) = %d", got)
+	}
+	if got := wrap.Log(0); got != math.MinInt8 {
+		t.Errorf("wrapped Log(0) = %d, -Inf has no bits to wrap", got)
+	}
+	if got := wrap.Sqrt(-1); got != 0 {
+		t.Errorf("wrapped Sqrt(-1) = %d", got)
+	}
+	if got := wrap.Convert(300); got != 44 {
+		t.Errorf("wrapped 300 = %d", got)
+	}
+	if got := NewContext[uint8](nil, Wrap).Convert(-1); got != 255 {
+		t.Errorf("wrapped -1 into uint8 = %d", got)
+	}
+	if got := NewContext[int64](nil, Wrap).Convert(0x1p64 + 0x1p12); got != 4096 {
+		t.Errorf("wrapped 2^64+2^12 = %d", got)
+	}
+	// nil means round to nearest
+	if NewContext[int](nil, Saturate).RoundingMode()(2.5) != 3 || NewContext[int](nil, Wrap).OverflowPolicy() != Wrap {
+		t.Error("the accessors are wrong")
+	}
+	if got := NewContext[uint](math.Floor, Saturate).Sqrt(8); got != 2 {
+		t.Errorf("unsigned floor Sqrt(8) = %d, the rounding mode has to reach unsigned types", got)
+	}
+	if got := ConvertNumber[uint](2.7); got != 3 {
+		t.Errorf("ConvertNumber[uint](2.7) = %d", got)
+	}
+	if got := ConvertNumberBy[uint](2.7, math.Floor); got != 2 {
+		t.Errorf("ConvertNumberBy[uint](2.7, Floor) = %d", got)
+	}
+}
+
+func TestContextFloatsAndNewMethods(t *testing.T) {
+	c := NewContext[float64](math.Floor, Wrap)
+	if got := c.Sqrt(2); got != math.Sqrt2 {
+		t.Errorf("float Sqrt(2) = %v, floats aren't rounded", got)
+	}
+	if got := c.Log(-1); !math.IsNaN(got) {
+		t.Errorf("float Log(-1) = %v, a float N keeps NaN", got)
+	}
+	if got := c.Pow(2, 0.5); got != math.Sqrt2 {
+		t.Errorf("float Pow(2, 0.5) = %v", got)
+	}
+	if frac, exp := c.Frexp(12); frac != 0.75 || exp != 4 {
+		t.Errorf("Frexp(12) = %v %d", frac, exp)
+	}
+	// Γ(-2.5) is about -0.945 so log|Γ| is negative and the sign flips it back
+	if l, sign := math.Lgamma(-2.5); c.Lgam(-2.5) != -l || sign != -1 {
+		t.Errorf("Lgam(-2.5) = %v", c.Lgam(-2.5))
+	}
+	if got := NewContext[float32](nil, Saturate).Nextafter32(1, 2); got != 1+0x1p-23 {
+		t.Errorf("Nextafter32(1, 2) = %v", got)
+	}
+	if got := c.Max(1, 5, -2); got != 5 {
+		t.Errorf("Max = %v", got)
+	}
+	if got := c.Min(); got != MaxNum[float64]() {
+		t.Errorf("Min() = %v, want what the Min wrapper gives", got)
+	}
+	if got := c.Abs(-3.5); got != 3.5 {
+		t.Errorf("Abs(-3.5) = %v", got)
+	}
+	if frac, exp := NewContext[int](math.Ceil, Saturate).Frexp(12); frac != 1 || exp != 4 {
+		t.Errorf("ceil int Frexp(12) = %d %d", frac, exp)
+	}
+}
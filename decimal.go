@@ -0,0 +1,374 @@
+package RUNK
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var ErrDivideByZero = errors.New("RUNK: division by zero")
+
+var ErrDecimalScale = errors.New("RUNK: decimal scale out of range")
+
+/*
+MaxDecimalScale bounds the scale of a Decimal both ways. Printing or aligning a Decimal costs a power of ten as
+long as its scale, so without a bound a short string like "1e-2147483647" from UnmarshalJSON would take gigabytes.
+*/
+const MaxDecimalScale = 10000
+
+func checkScale(scale int64) error {
+	if scale > MaxDecimalScale || scale < -MaxDecimalScale {
+		return ErrDecimalScale
+	}
+	return nil
+}
+
+var bigTen = big.NewInt(10)
+
+/*
+Decimal is an arbitrary precision base 10 number. The value is coef * 10^-scale so 1.50 is stored as 150 with a
+scale of 2 and 1e3 as 1 with a scale of -3. Add, Sub and Mul are always exact, only Div and Quantize ever round.
+The scale stays within +-MaxDecimalScale, anything that would leave that range is ErrDecimalScale. The zero value is 0.
+Decimals are values, none of the methods change the receiver.
+*/
+type Decimal struct {
+	coef  *big.Int
+	scale int32
+}
+
+/*
+NewDecimal converts any Number into a Decimal. Integers are exact. Floats use the shortest decimal that round trips
+so float64(0.1) becomes 0.1 rather than 0.1000000000000000055511151231257827. NaN becomes 0 and +-Inf becomes the
+largest finite value of the float type, the same way those values get pinned when converted to an integer.
+*/
+func NewDecimal[N Number](n N) Decimal {
+	if !isFloat[N]() {
+		return Decimal{coef: bigFromInt(n)}
+	}
+	f := float64(n)
+	bitSize := 64
+	if reflect.TypeOf(n).Kind() == reflect.Float32 {
+		bitSize = 32
+	}
+	switch {
+	case math.IsNaN(f):
+		return Decimal{}
+	case math.IsInf(f, 0):
+		f = float64(MaxNum[N]())
+		if math.Signbit(float64(n)) {
+			f = float64(MinNum[N]())
+		}
+	}
+	d, _ := ParseDecimal(strconv.FormatFloat(f, 'e', -1, bitSize))
+	return d
+}
+
+/*NewDecimalScaled builds a Decimal straight from a coefficient and scale, NewDecimalScaled(150, 2) is 1.50*/
+func NewDecimalScaled[N Int](coef N, scale int32) (Decimal, error) {
+	if err := checkScale(int64(scale)); err != nil {
+		return Decimal{}, err
+	}
+	d := NewDecimal(coef)
+	d.scale = scale
+	return d, nil
+}
+
+/*
+ParseDecimal reads plain or exponent notation ("-12.50", "1.5e-3") exactly. The number of digits after the point is
+kept as the scale so "1.50" stays 1.50. A scale past MaxDecimalScale either way is ErrDecimalScale.
+*/
+func ParseDecimal(s string) (Decimal, error) {
+	str := strings.TrimSpace(s)
+	invalid := errors.New("RUNK: invalid decimal " + strconv.Quote(s))
+	exp := int64(0)
+	if i := strings.IndexAny(str, "eE"); i >= 0 {
+		e, err := strconv.ParseInt(str[i+1:], 10, 32)
+		if err != nil {
+			return Decimal{}, invalid
+		}
+		exp = e
+		str = str[:i]
+	}
+	digits := str
+	frac := 0
+	if i := strings.IndexByte(str, '.'); i >= 0 {
+		frac = len(str) - i - 1
+		digits = str[:i] + str[i+1:]
+	}
+	unsigned := strings.TrimLeft(digits, "+-")
+	if len(digits)-len(unsigned) > 1 || unsigned == "" || strings.Trim(unsigned, "0123456789") != "" {
+		return Decimal{}, invalid
+	}
+	c, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return Decimal{}, invalid
+	}
+	scale := int64(frac) - exp
+	if err := checkScale(scale); err != nil {
+		return Decimal{}, err
+	}
+	return Decimal{coef: c, scale: int32(scale)}, nil
+}
+
+/*
+DecimalTo converts a Decimal into any Number type with the same saturation as ConvertNumber. Integer targets are
+rounded with roundMode (math.Round by default) and floats get the nearest representable value.
+*/
+func DecimalTo[To Number](d Decimal, roundMode ...RoundingMode) To {
+	if isFloat[To]() {
+		return ConvertNumber[To](d.Float64())
+	}
+	q, _ := d.Quantize(0, roundMode...)
+	return bigIntTo[To](q.coefficient())
+}
+
+func (d Decimal) coefficient() *big.Int {
+	if d.coef == nil {
+		return new(big.Int)
+	}
+	return d.coef
+}
+
+func pow10Big(n int64) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(n), nil)
+}
+
+/*Rat returns the exact value as a big.Rat*/
+func (d Decimal) Rat() *big.Rat {
+	if d.scale <= 0 {
+		return new(big.Rat).SetInt(new(big.Int).Mul(d.coefficient(), pow10Big(-int64(d.scale))))
+	}
+	return new(big.Rat).SetFrac(d.coefficient(), pow10Big(int64(d.scale)))
+}
+
+/*Float64 returns the nearest float64, overflowing to +-Inf*/
+func (d Decimal) Float64() float64 {
+	f, _ := d.Rat().Float64()
+	return f
+}
+
+/*Scale is the number of digits after the decimal point*/
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+func (d Decimal) Sign() int {
+	return d.coefficient().Sign()
+}
+
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+func (d Decimal) Neg() Decimal {
+	return Decimal{coef: new(big.Int).Neg(d.coefficient()), scale: d.scale}
+}
+
+func (d Decimal) Abs() Decimal {
+	return Decimal{coef: new(big.Int).Abs(d.coefficient()), scale: d.scale}
+}
+
+/*align returns both coefficients at the larger of the two scales*/
+func align(x Decimal, y Decimal) (*big.Int, *big.Int, int32) {
+	xc, yc := x.coefficient(), y.coefficient()
+	switch {
+	case x.scale > y.scale:
+		return xc, new(big.Int).Mul(yc, pow10Big(int64(x.scale)-int64(y.scale))), x.scale
+	case y.scale > x.scale:
+		return new(big.Int).Mul(xc, pow10Big(int64(y.scale)-int64(x.scale))), yc, y.scale
+	}
+	return xc, yc, x.scale
+}
+
+/*Add is exact, the result has the larger scale of the two*/
+func (x Decimal) Add(y Decimal) Decimal {
+	xc, yc, scale := align(x, y)
+	return Decimal{coef: new(big.Int).Add(xc, yc), scale: scale}
+}
+
+func (x Decimal) Sub(y Decimal) Decimal {
+	xc, yc, scale := align(x, y)
+	return Decimal{coef: new(big.Int).Sub(xc, yc), scale: scale}
+}
+
+/*Mul is exact, the scales add together. When the sum is past MaxDecimalScale it is ErrDecimalScale.*/
+func (x Decimal) Mul(y Decimal) (Decimal, error) {
+	scale := int64(x.scale) + int64(y.scale)
+	if err := checkScale(scale); err != nil {
+		return Decimal{}, err
+	}
+	return Decimal{coef: new(big.Int).Mul(x.coefficient(), y.coefficient()), scale: int32(scale)}, nil
+}
+
+/*Div divides to the given number of places, rounding the last place with roundMode (math.Round by default)*/
+func (x Decimal) Div(y Decimal, places int32, roundMode ...RoundingMode) (Decimal, error) {
+	if err := checkScale(int64(places)); err != nil {
+		return Decimal{}, err
+	}
+	if y.IsZero() {
+		return Decimal{scale: places}, ErrDivideByZero
+	}
+	// x/y * 10^places == xc * 10^(places + ys - xs) / yc
+	num := new(big.Int).Set(x.coefficient())
+	den := new(big.Int).Set(y.coefficient())
+	shift := int64(places) + int64(y.scale) - int64(x.scale)
+	if shift >= 0 {
+		num.Mul(num, pow10Big(shift))
+	} else {
+		den.Mul(den, pow10Big(-shift))
+	}
+	if den.Sign() < 0 {
+		num.Neg(num)
+		den.Neg(den)
+	}
+	return Decimal{coef: roundBigQuo(num, den, pickRoundingMode(roundMode)), scale: places}, nil
+}
+
+/*Quantize sets the number of decimal places, rounding with roundMode if digits have to be dropped*/
+func (d Decimal) Quantize(places int32, roundMode ...RoundingMode) (Decimal, error) {
+	if err := checkScale(int64(places)); err != nil {
+		return d, err
+	}
+	if places >= d.scale {
+		return Decimal{coef: new(big.Int).Mul(d.coefficient(), pow10Big(int64(places)-int64(d.scale))), scale: places}, nil
+	}
+	den := pow10Big(int64(d.scale) - int64(places))
+	return Decimal{coef: roundBigQuo(d.coefficient(), den, pickRoundingMode(roundMode)), scale: places}, nil
+}
+
+/*Cmp compares the values ignoring scale, so 1.5 and 1.50 are equal. It returns -1, 0 or +1.*/
+func (x Decimal) Cmp(y Decimal) int {
+	xc, yc, _ := align(x, y)
+	return xc.Cmp(yc)
+}
+
+/*Equal is value equality, use Cmp and Scale if the scale matters too*/
+func (x Decimal) Equal(y Decimal) bool {
+	return x.Cmp(y) == 0
+}
+
+/*
+Allocate splits d into parts proportional to the ratios that add back up to exactly d. Everything happens at the
+scale of d so the smallest unit is 10^-scale. The units left over after the proportional split go one each to the
+parts that lost the most to truncation. Negative ratios are treated as 0 and if every ratio is 0 the amount is
+split evenly.
+*/
+func (d Decimal) Allocate(ratios ...int64) []Decimal {
+	parts := make([]Decimal, len(ratios))
+	if len(ratios) == 0 {
+		return parts
+	}
+	total := new(big.Int)
+	weights := make([]*big.Int, len(ratios))
+	for i, r := range ratios {
+		if r < 0 {
+			r = 0
+		}
+		weights[i] = big.NewInt(r)
+		total.Add(total, weights[i])
+	}
+	if total.Sign() == 0 {
+		for i := range weights {
+			weights[i] = big.NewInt(1)
+		}
+		total.SetInt64(int64(len(weights)))
+	}
+	amount := d.coefficient()
+	neg := amount.Sign() < 0
+	mag := new(big.Int).Abs(amount)
+	left := new(big.Int).Set(mag)
+	rems := make([]*big.Int, len(weights))
+	for i, w := range weights {
+		q, r := new(big.Int).QuoRem(new(big.Int).Mul(mag, w), total, new(big.Int))
+		parts[i] = Decimal{coef: q, scale: d.scale}
+		rems[i] = r
+		left.Sub(left, q)
+	}
+	for left.Sign() > 0 {
+		best := 0
+		for i := range rems {
+			if rems[i].Cmp(rems[best]) > 0 {
+				best = i
+			}
+		}
+		parts[best].coef.Add(parts[best].coef, big.NewInt(1))
+		rems[best].SetInt64(-1)
+		left.Sub(left, big.NewInt(1))
+	}
+	if neg {
+		for i := range parts {
+			parts[i].coef.Neg(parts[i].coef)
+		}
+	}
+	return parts
+}
+
+/*Split divides d into n parts as even as possible that add back up to exactly d*/
+func (d Decimal) Split(n int) []Decimal {
+	if n <= 0 {
+		return []Decimal{}
+	}
+	ratios := make([]int64, n)
+	for i := range ratios {
+		ratios[i] = 1
+	}
+	return d.Allocate(ratios...)
+}
+
+/*
+String writes the value in plain notation with exactly Scale digits after the point. A negative scale is written
+as the coefficient with an exponent instead, so 1e3 comes back as "1e3" and parses to the same scale.
+*/
+func (d Decimal) String() string {
+	c := d.coefficient()
+	switch {
+	case d.scale == 0:
+		return c.String()
+	case d.scale < 0:
+		return c.String() + "e" + strconv.Itoa(-int(d.scale))
+	}
+	digits := new(big.Int).Abs(c).String()
+	scale := int(d.scale)
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+	s := digits[:len(digits)-scale] + "." + digits[len(digits)-scale:]
+	if c.Sign() < 0 {
+		return "-" + s
+	}
+	return s
+}
+
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Decimal) UnmarshalText(text []byte) error {
+	v, err := ParseDecimal(string(text))
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+/*MarshalJSON writes a bare JSON number so the trailing zeros of the scale survive, 1.50 stays 1.50*/
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+/*UnmarshalJSON accepts either a JSON number or a quoted string. null leaves the Decimal unchanged.*/
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if string(data) == "null" {
+		return nil
+	}
+	if len(data) >= 2 && data[0] == '"' && data[len(data)-1] == '"' {
+		data = data[1 : len(data)-1]
+	}
+	return d.UnmarshalText(data)
+}
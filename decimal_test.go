@@ -0,0 +1,238 @@
+package RUNK
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func dec(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		in    string
+		want  string
+		scale int32
+	}{
+		{"1.50", "1.50", 2},
+		{" -12.5 ", "-12.5", 1},
+		{"+7", "7", 0},
+		{"1.5e-3", "0.0015", 4},
+		{"1e3", "1e3", -3},
+		{"-15E2", "-15e2", -2},
+		{"12.5e1", "125", 0},
+		{".5", "0.5", 1},
+		{"0.000", "0.000", 3},
+	}
+	for _, tt := range tests {
+		d, err := ParseDecimal(tt.in)
+		if err != nil {
+			t.Errorf("ParseDecimal(%q) = %v", tt.in, err)
+			continue
+		}
+		if d.String() != tt.want || d.Scale() != tt.scale {
+			t.Errorf("ParseDecimal(%q) = %s scale %d, want %s scale %d", tt.in, d, d.Scale(), tt.want, tt.scale)
+		}
+		// what String writes parses back to the same value and scale
+		back, err := ParseDecimal(d.String())
+		if err != nil || back.Cmp(d) != 0 || back.Scale() != d.Scale() {
+			t.Errorf("%q did not round trip: %s scale %d", tt.in, back, back.Scale())
+		}
+	}
+	for _, in := range []string{"", "-", "1.2.3", "--1", "1e", "abc", "1e2147483648", "0x10"} {
+		if _, err := ParseDecimal(in); err == nil {
+			t.Errorf("ParseDecimal(%q) succeeded", in)
+		}
+	}
+	for _, in := range []string{"1e-2147483647", "1e10001", "1e-10001"} {
+		if _, err := ParseDecimal(in); err != ErrDecimalScale {
+			t.Errorf("ParseDecimal(%q) = %v, want ErrDecimalScale", in, err)
+		}
+	}
+	if _, err := ParseDecimal("1e-10000"); err != nil {
+		t.Errorf("scale 10000 rejected: %v", err)
+	}
+}
+
+func TestNewDecimal(t *testing.T) {
+	tests := []struct {
+		got  Decimal
+		want string
+	}{
+		{NewDecimal(0.1), "0.1"},
+		{NewDecimal(float32(0.1)), "0.1"},
+		{NewDecimal(-42), "-42"},
+		{NewDecimal(uint64(math.MaxUint64)), "18446744073709551615"},
+		{NewDecimal(math.NaN()), "0"},
+		{NewDecimal(float32(math.Inf(-1))), "-34028235e31"},
+		{NewDecimal(1e300), "1e300"},
+	}
+	for _, tt := range tests {
+		if got := tt.got.String(); got != tt.want {
+			t.Errorf("NewDecimal = %s, want %s", got, tt.want)
+		}
+	}
+	if d, err := NewDecimalScaled(150, 2); err != nil || d.String() != "1.50" {
+		t.Errorf("NewDecimalScaled(150, 2) = %s %v", d, err)
+	}
+	if _, err := NewDecimalScaled(1, math.MaxInt32); err != ErrDecimalScale {
+		t.Errorf("NewDecimalScaled past the bound = %v", err)
+	}
+	var zero Decimal
+	if zero.String() != "0" || !zero.IsZero() || zero.Cmp(dec("0.00")) != 0 {
+		t.Errorf("zero value = %s", zero)
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	a, b := dec("1.10"), dec("2.2")
+	if got := a.Add(b).String(); got != "3.30" {
+		t.Errorf("Add = %s", got)
+	}
+	if got := a.Sub(b).String(); got != "-1.10" {
+		t.Errorf("Sub = %s", got)
+	}
+	if got, err := a.Mul(b); err != nil || got.String() != "2.420" {
+		t.Errorf("Mul = %s %v", got, err)
+	}
+	// 0.1 + 0.2 is exactly 0.3 unlike in float64
+	if dec("0.1").Add(dec("0.2")).Cmp(dec("0.3")) != 0 {
+		t.Error("0.1 + 0.2 != 0.3")
+	}
+	if _, err := dec("1e-6000").Mul(dec("1e-6000")); err != ErrDecimalScale {
+		t.Errorf("Mul past the bound = %v", err)
+	}
+	big, _ := NewDecimalScaled(1, -MaxDecimalScale)
+	if _, err := big.Mul(big); err != ErrDecimalScale {
+		t.Errorf("Mul past the negative bound = %v", err)
+	}
+	q, err := dec("2").Div(dec("3"), 4)
+	if err != nil || q.String() != "0.6667" {
+		t.Errorf("2/3 = %s %v", q, err)
+	}
+	q, _ = dec("2").Div(dec("-3"), 4, math.Trunc)
+	if q.String() != "-0.6666" {
+		t.Errorf("2/-3 truncated = %s", q)
+	}
+	q, _ = dec("1e3").Div(dec("0.5"), 0)
+	if q.String() != "2000" {
+		t.Errorf("1e3/0.5 = %s", q)
+	}
+	if _, err := dec("1").Div(Decimal{}, 2); err != ErrDivideByZero {
+		t.Errorf("divide by zero = %v", err)
+	}
+	if _, err := dec("1").Div(dec("3"), MaxDecimalScale+1); err != ErrDecimalScale {
+		t.Errorf("Div past the bound = %v", err)
+	}
+}
+
+func TestDecimalQuantize(t *testing.T) {
+	tests := []struct {
+		in     string
+		places int32
+		mode   RoundingMode
+		want   string
+	}{
+		{"2.345", 2, nil, "2.35"},
+		{"2.345", 2, math.RoundToEven, "2.34"},
+		{"-2.345", 2, math.Floor, "-2.35"},
+		{"2.5", 0, math.RoundToEven, "2"},
+		{"1.5", 3, nil, "1.500"},
+		{"1234", -2, nil, "12e2"},
+	}
+	for _, tt := range tests {
+		got, err := dec(tt.in).Quantize(tt.places, tt.mode)
+		if err != nil || got.String() != tt.want {
+			t.Errorf("Quantize(%s, %d) = %s %v, want %s", tt.in, tt.places, got, err, tt.want)
+		}
+	}
+	if _, err := dec("1").Quantize(-MaxDecimalScale - 1); err != ErrDecimalScale {
+		t.Errorf("Quantize past the bound = %v", err)
+	}
+}
+
+func TestDecimalTo(t *testing.T) {
+	if got := DecimalTo[int](dec("2.5")); got != 3 {
+		t.Errorf("DecimalTo[int](2.5) = %d", got)
+	}
+	if got := DecimalTo[int](dec("-2.5"), math.Ceil); got != -2 {
+		t.Errorf("DecimalTo[int] ceil = %d", got)
+	}
+	if got := DecimalTo[int8](dec("1e5")); got != math.MaxInt8 {
+		t.Errorf("DecimalTo[int8](1e5) = %d", got)
+	}
+	if got := DecimalTo[uint](dec("-3")); got != 0 {
+		t.Errorf("DecimalTo[uint](-3) = %d", got)
+	}
+	if got := DecimalTo[float64](dec("0.1")); got != 0.1 {
+		t.Errorf("DecimalTo[float64] = %v", got)
+	}
+	if got := DecimalTo[float32](dec("1e400")); !math.IsInf(float64(got), 1) {
+		t.Errorf("DecimalTo[float32](1e400) = %v", got)
+	}
+}
+
+func TestDecimalAllocate(t *testing.T) {
+	parts := dec("100.00").Split(3)
+	want := []string{"33.34", "33.33", "33.33"}
+	sum := Decimal{}
+	for i, p := range parts {
+		if p.String() != want[i] {
+			t.Errorf("Split part %d = %s, want %s", i, p, want[i])
+		}
+		sum = sum.Add(p)
+	}
+	if sum.Cmp(dec("100")) != 0 {
+		t.Errorf("Split parts add to %s", sum)
+	}
+	parts = dec("-0.05").Allocate(1, 0, -4, 1)
+	got := []string{parts[0].String(), parts[1].String(), parts[2].String(), parts[3].String()}
+	if got[0] != "-0.03" || got[1] != "0.00" || got[2] != "0.00" || got[3] != "-0.02" {
+		t.Errorf("Allocate = %v", got)
+	}
+	parts = dec("1").Allocate(0, 0)
+	if parts[0].String() != "1" || parts[1].String() != "0" {
+		t.Errorf("Allocate with zero ratios = %s %s", parts[0], parts[1])
+	}
+	if len(dec("1").Split(0)) != 0 || len(dec("1").Allocate()) != 0 {
+		t.Error("empty split is not empty")
+	}
+}
+
+func TestDecimalJSON(t *testing.T) {
+	type price struct {
+		Amount Decimal
+		Exp    Decimal
+	}
+	in := price{Amount: dec("1.50"), Exp: dec("25e3")}
+	data, err := json.Marshal(in)
+	if err != nil || string(data) != `{"Amount":1.50,"Exp":25e3}` {
+		t.Fatalf("Marshal = %s %v", data, err)
+	}
+	var out price
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Amount.String() != "1.50" || out.Exp.Scale() != -3 {
+		t.Errorf("Unmarshal = %s %s", out.Amount, out.Exp)
+	}
+	if err := json.Unmarshal([]byte(`{"Amount":"-0.10","Exp":null}`), &out); err != nil || out.Amount.String() != "-0.10" {
+		t.Errorf("quoted Unmarshal = %s %v", out.Amount, err)
+	}
+	if out.Exp.String() != "25e3" {
+		t.Errorf("null changed the value to %s", out.Exp)
+	}
+	if err := json.Unmarshal([]byte(`{"Amount":1e-2147483647}`), &out); err == nil {
+		t.Error("Unmarshal accepted a huge scale")
+	}
+	var d Decimal
+	if err := d.UnmarshalText([]byte("3.14")); err != nil || d.String() != "3.14" {
+		t.Errorf("UnmarshalText = %s %v", d, err)
+	}
+}
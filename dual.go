@@ -0,0 +1,480 @@
+package RUNK
+
+import "math"
+
+/*
+Dual is a dual number Val + Der*ε where ε*ε = 0. Running a function on Variable(x) instead of x carries the exact
+derivative along with the value (forward mode automatic differentiation) so there is no step size to pick like
+there is with finite differences.
+The methods mirror the wrappers in RUNK.go. The value is the same math function and the derivative comes from
+the chain rule. Step functions like Floor and Round have a derivative of 0, and at the points where a function is
+not differentiable (Abs at 0, the corners of Dim) one side is picked.
+The math is done in float64 and then stored as N. A derivative that is exactly 0 stays 0 even when the function's
+own slope is Inf, so constants never turn into NaN.
+*/
+type Dual[N Float] struct {
+	Val N
+	Der N
+}
+
+func NewDual[N Float](val N, der N) Dual[N] {
+	return Dual[N]{Val: val, Der: der}
+}
+
+/*Variable is x with a derivative of 1, the thing being differentiated with respect to*/
+func Variable[N Float](x N) Dual[N] {
+	return Dual[N]{Val: x, Der: 1}
+}
+
+/*Constant is x with a derivative of 0*/
+func Constant[N Float](x N) Dual[N] {
+	return Dual[N]{Val: x}
+}
+
+/*Derivative returns f(x) and f'(x)*/
+func Derivative[N Float](f func(Dual[N]) Dual[N], x N) (N, N) {
+	d := f(Variable(x))
+	return d.Val, d.Der
+}
+
+/*
+Gradient returns f(xs) and the partial derivative for each of xs. Forward mode needs one pass of f per input so
+this calls f len(xs) times.
+*/
+func Gradient[N Float](f func([]Dual[N]) Dual[N], xs []N) (N, []N) {
+	grad := make([]N, len(xs))
+	args := make([]Dual[N], len(xs))
+	for i := range xs {
+		args[i] = Constant(xs[i])
+	}
+	var val N
+	if len(xs) == 0 {
+		val = f(args).Val
+	}
+	for i := range xs {
+		args[i].Der = 1
+		d := f(args)
+		args[i].Der = 0
+		val = d.Val
+		grad[i] = d.Der
+	}
+	return val, grad
+}
+
+/*chain builds f(d) from the value of f and its slope f'(d.Val)*/
+func (d Dual[N]) chain(val float64, slope float64) Dual[N] {
+	if d.Der == 0 {
+		return Dual[N]{Val: N(val)}
+	}
+	return Dual[N]{Val: N(val), Der: N(slope * float64(d.Der))}
+}
+
+/*chain2 builds f(a, b) from the value of f and its partial slopes*/
+func chain2[N Float](a Dual[N], b Dual[N], val float64, da float64, db float64) Dual[N] {
+	der := 0.0
+	if a.Der != 0 {
+		der += da * float64(a.Der)
+	}
+	if b.Der != 0 {
+		der += db * float64(b.Der)
+	}
+	return Dual[N]{Val: N(val), Der: N(der)}
+}
+
+func (a Dual[N]) Add(b Dual[N]) Dual[N] {
+	return Dual[N]{Val: a.Val + b.Val, Der: a.Der + b.Der}
+}
+
+func (a Dual[N]) Sub(b Dual[N]) Dual[N] {
+	return Dual[N]{Val: a.Val - b.Val, Der: a.Der - b.Der}
+}
+
+func (a Dual[N]) Mul(b Dual[N]) Dual[N] {
+	return Dual[N]{Val: a.Val * b.Val, Der: a.Der*b.Val + a.Val*b.Der}
+}
+
+func (a Dual[N]) Div(b Dual[N]) Dual[N] {
+	return Dual[N]{Val: a.Val / b.Val, Der: (a.Der*b.Val - a.Val*b.Der) / (b.Val * b.Val)}
+}
+
+/*Scale multiplies by a plain number*/
+func (a Dual[N]) Scale(k N) Dual[N] {
+	return Dual[N]{Val: a.Val * k, Der: a.Der * k}
+}
+
+func (a Dual[N]) Neg() Dual[N] {
+	return Dual[N]{Val: -a.Val, Der: -a.Der}
+}
+
+func (a Dual[N]) Inv() Dual[N] {
+	return Dual[N]{Val: 1 / a.Val, Der: -a.Der / (a.Val * a.Val)}
+}
+
+func (d Dual[N]) Abs() Dual[N] {
+	if d.Val < 0 {
+		return d.Neg()
+	}
+	return d
+}
+
+/*Max returns whichever of the duals has the larger value, the first one wins ties*/
+func (d Dual[N]) Max(others ...Dual[N]) Dual[N] {
+	for _, o := range others {
+		if o.Val > d.Val {
+			d = o
+		}
+	}
+	return d
+}
+
+func (d Dual[N]) Min(others ...Dual[N]) Dual[N] {
+	for _, o := range others {
+		if o.Val < d.Val {
+			d = o
+		}
+	}
+	return d
+}
+
+func (d Dual[N]) Acos() Dual[N] {
+	x := float64(d.Val)
+	return d.chain(math.Acos(x), -1/math.Sqrt(1-x*x))
+}
+
+func (d Dual[N]) Acosh() Dual[N] {
+	x := float64(d.Val)
+	return d.chain(math.Acosh(x), 1/math.Sqrt(x*x-1))
+}
+
+func (d Dual[N]) Asin() Dual[N] {
+	x := float64(d.Val)
+	return d.chain(math.Asin(x), 1/math.Sqrt(1-x*x))
+}
+
+func (d Dual[N]) Asinh() Dual[N] {
+	x := float64(d.Val)
+	return d.chain(math.Asinh(x), 1/math.Hypot(x, 1))
+}
+
+func (d Dual[N]) Atan() Dual[N] {
+	x := float64(d.Val)
+	return d.chain(math.Atan(x), 1/(1+x*x))
+}
+
+/*Atan2 is atan(y/x) in the right quadrant with y as the receiver, the same argument order as the Atan2 wrapper*/
+func (y Dual[N]) Atan2(x Dual[N]) Dual[N] {
+	yv, xv := float64(y.Val), float64(x.Val)
+	r2 := xv*xv + yv*yv
+	return chain2(y, x, math.Atan2(yv, xv), xv/r2, -yv/r2)
+}
+
+func (d Dual[N]) Atanh() Dual[N] {
+	x := float64(d.Val)
+	return d.chain(math.Atanh(x), 1/(1-x*x))
+}
+
+func (d Dual[N]) Cbrt() Dual[N] {
+	c := math.Cbrt(float64(d.Val))
+	return d.chain(c, 1/(3*c*c))
+}
+
+func (d Dual[N]) Ceil() Dual[N] {
+	return Dual[N]{Val: N(math.Ceil(float64(d.Val)))}
+}
+
+/*Copysign gives d the sign of sign, the derivative flips with the value*/
+func (d Dual[N]) Copysign(sign Dual[N]) Dual[N] {
+	v := math.Copysign(float64(d.Val), float64(sign.Val))
+	if math.Signbit(v) != math.Signbit(float64(d.Val)) {
+		return d.chain(v, -1)
+	}
+	return d.chain(v, 1)
+}
+
+func (d Dual[N]) Cos() Dual[N] {
+	s, c := math.Sincos(float64(d.Val))
+	return d.chain(c, -s)
+}
+
+func (d Dual[N]) Cosh() Dual[N] {
+	x := float64(d.Val)
+	return d.chain(math.Cosh(x), math.Sinh(x))
+}
+
+/*Dim is max(x-y, 0)*/
+func (x Dual[N]) Dim(y Dual[N]) Dual[N] {
+	if x.Val > y.Val {
+		return x.Sub(y)
+	}
+	return Dual[N]{Val: N(math.Dim(float64(x.Val), float64(y.Val)))}
+}
+
+func (d Dual[N]) Erf() Dual[N] {
+	x := float64(d.Val)
+	return d.chain(math.Erf(x), 2/math.SqrtPi*math.Exp(-x*x))
+}
+
+func (d Dual[N]) Erfc() Dual[N] {
+	x := float64(d.Val)
+	return d.chain(math.Erfc(x), -2/math.SqrtPi*math.Exp(-x*x))
+}
+
+/*Erfinv uses the inverse function rule, 1/erf'(erfinv(x))*/
+func (d Dual[N]) Erfinv() Dual[N] {
+	y := math.Erfinv(float64(d.Val))
+	return d.chain(y, math.SqrtPi/2*math.Exp(y*y))
+}
+
+func (d Dual[N]) Erfcinv() Dual[N] {
+	y := math.Erfcinv(float64(d.Val))
+	return d.chain(y, -math.SqrtPi/2*math.Exp(y*y))
+}
+
+func (d Dual[N]) Exp() Dual[N] {
+	e := math.Exp(float64(d.Val))
+	return d.chain(e, e)
+}
+
+func (d Dual[N]) Exp2() Dual[N] {
+	e := math.Exp2(float64(d.Val))
+	return d.chain(e, e*math.Ln2)
+}
+
+func (d Dual[N]) Expm1() Dual[N] {
+	x := float64(d.Val)
+	return d.chain(math.Expm1(x), math.Exp(x))
+}
+
+/*FMA is x*y + z with x as the receiver*/
+func (x Dual[N]) FMA(y Dual[N], z Dual[N]) Dual[N] {
+	xv, yv := float64(x.Val), float64(y.Val)
+	xy := chain2(x, y, 0, yv, xv)
+	return Dual[N]{Val: N(math.FMA(xv, yv, float64(z.Val))), Der: xy.Der + z.Der}
+}
+
+func (d Dual[N]) Floor() Dual[N] {
+	return Dual[N]{Val: N(math.Floor(float64(d.Val)))}
+}
+
+/*Frexp splits d into frac * 2^exp, the exponent is a step so only frac carries the derivative*/
+func (d Dual[N]) Frexp() (Dual[N], int) {
+	frac, exp := math.Frexp(float64(d.Val))
+	return d.chain(frac, math.Ldexp(1, -exp)), exp
+}
+
+/*Gamma uses Γ'(x) = Γ(x)ψ(x) where ψ is the digamma function*/
+func (d Dual[N]) Gamma() Dual[N] {
+	x := float64(d.Val)
+	g := math.Gamma(x)
+	return d.chain(g, g*digamma(x))
+}
+
+func (x Dual[N]) Hypot(y Dual[N]) Dual[N] {
+	xv, yv := float64(x.Val), float64(y.Val)
+	h := math.Hypot(xv, yv)
+	return chain2(x, y, h, xv/h, yv/h)
+}
+
+/*Ilogb is the binary exponent as a number like Logb*/
+func (d Dual[N]) Ilogb() Dual[N] {
+	return Dual[N]{Val: N(math.Ilogb(float64(d.Val)))}
+}
+
+/*
+The Bessel derivatives come from J'n = (Jn-1 - Jn+1)/2 which also covers J'0 = -J1 since J-1 = -J1.
+The order n is a plain int like it is for math.Jn.
+*/
+
+func (d Dual[N]) J0() Dual[N] {
+	x := float64(d.Val)
+	return d.chain(math.J0(x), -math.J1(x))
+}
+
+func (d Dual[N]) J1() Dual[N] {
+	x := float64(d.Val)
+	return d.chain(math.J1(x), (math.J0(x)-math.Jn(2, x))/2)
+}
+
+func (d Dual[N]) Jn(n int) Dual[N] {
+	x := float64(d.Val)
+	return d.chain(math.Jn(n, x), (math.Jn(n-1, x)-math.Jn(n+1, x))/2)
+}
+
+func (d Dual[N]) Y0() Dual[N] {
+	x := float64(d.Val)
+	return d.chain(math.Y0(x), -math.Y1(x))
+}
+
+func (d Dual[N]) Y1() Dual[N] {
+	x := float64(d.Val)
+	return d.chain(math.Y1(x), (math.Y0(x)-math.Yn(2, x))/2)
+}
+
+func (d Dual[N]) Yn(n int) Dual[N] {
+	x := float64(d.Val)
+	return d.chain(math.Yn(n, x), (math.Yn(n-1, x)-math.Yn(n+1, x))/2)
+}
+
+func (d Dual[N]) Ldexp(exp int) Dual[N] {
+	return d.chain(math.Ldexp(float64(d.Val), exp), math.Ldexp(1, exp))
+}
+
+/*Lgamma is log|Γ(x)|, its derivative is ψ(x) whatever the sign of Γ*/
+func (d Dual[N]) Lgamma() (Dual[N], int) {
+	x := float64(d.Val)
+	l, sign := math.Lgamma(x)
+	return d.chain(l, digamma(x)), sign
+}
+
+/*Lgam is Lgamma with the sign multiplied back in, sign*log|Γ(x)|*/
+func (d Dual[N]) Lgam() Dual[N] {
+	x := float64(d.Val)
+	l, sign := math.Lgamma(x)
+	s := float64(sign)
+	return d.chain(l*s, digamma(x)*s)
+}
+
+func (d Dual[N]) Log() Dual[N] {
+	x := float64(d.Val)
+	return d.chain(math.Log(x), 1/x)
+}
+
+func (d Dual[N]) Log10() Dual[N] {
+	x := float64(d.Val)
+	return d.chain(math.Log10(x), 1/(x*math.Ln10))
+}
+
+func (d Dual[N]) Log1p() Dual[N] {
+	x := float64(d.Val)
+	return d.chain(math.Log1p(x), 1/(1+x))
+}
+
+func (d Dual[N]) Log2() Dual[N] {
+	x := float64(d.Val)
+	return d.chain(math.Log2(x), 1/(x*math.Ln2))
+}
+
+func (d Dual[N]) Logb() Dual[N] {
+	return Dual[N]{Val: N(math.Logb(float64(d.Val)))}
+}
+
+/*Mod is x - y*trunc(x/y) so the derivative is dx - trunc(x/y)*dy*/
+func (x Dual[N]) Mod(y Dual[N]) Dual[N] {
+	xv, yv := float64(x.Val), float64(y.Val)
+	return chain2(x, y, math.Mod(xv, yv), 1, -math.Trunc(xv/yv))
+}
+
+/*Modf returns the integer part, which is a step, and the fractional part which moves with d*/
+func (d Dual[N]) Modf() (Dual[N], Dual[N]) {
+	i, frac := math.Modf(float64(d.Val))
+	return Dual[N]{Val: N(i)}, d.chain(frac, 1)
+}
+
+/*
+Nextafter is the next N after x towards y. It is x moved by one ulp so it follows x with a slope of 1 and y only
+picks the direction. float32 steps by a float32 ulp since a float64 one would round straight back to x.
+*/
+func (x Dual[N]) Nextafter(y Dual[N]) Dual[N] {
+	var v float64
+	if epsilonOf[N]() == 0x1p-23 {
+		v = float64(math.Nextafter32(float32(x.Val), float32(y.Val)))
+	} else {
+		v = math.Nextafter(float64(x.Val), float64(y.Val))
+	}
+	return x.chain(v, 1)
+}
+
+/*Remainder is x - y*n with n the nearest even integer to x/y*/
+func (x Dual[N]) Remainder(y Dual[N]) Dual[N] {
+	xv, yv := float64(x.Val), float64(y.Val)
+	r := math.Remainder(xv, yv)
+	return chain2(x, y, r, 1, -math.Round((xv-r)/yv))
+}
+
+/*
+Pow is x^y. The y*x^(y-1) part is only used when x has a derivative and the log(x)*x^y part only when y does, so a
+negative base with a constant exponent works the same as it does for math.Pow.
+*/
+func (x Dual[N]) Pow(y Dual[N]) Dual[N] {
+	xv, yv := float64(x.Val), float64(y.Val)
+	p := math.Pow(xv, yv)
+	var dx, dy float64
+	if x.Der != 0 {
+		dx = yv * math.Pow(xv, yv-1)
+	}
+	if y.Der != 0 {
+		dy = p * math.Log(xv)
+	}
+	return chain2(x, y, p, dx, dy)
+}
+
+/*Pow10 is 10^n for d rounded to the integer n like the wrapper does, so it is a step with a derivative of 0*/
+func (d Dual[N]) Pow10() Dual[N] {
+	return Dual[N]{Val: N(math.Pow10(ConvertNumber[int](d.Val)))}
+}
+
+func (d Dual[N]) Round() Dual[N] {
+	return Dual[N]{Val: N(math.Round(float64(d.Val)))}
+}
+
+func (d Dual[N]) RoundToEven() Dual[N] {
+	return Dual[N]{Val: N(math.RoundToEven(float64(d.Val)))}
+}
+
+func (d Dual[N]) Sin() Dual[N] {
+	s, c := math.Sincos(float64(d.Val))
+	return d.chain(s, c)
+}
+
+func (d Dual[N]) Sincos() (Dual[N], Dual[N]) {
+	s, c := math.Sincos(float64(d.Val))
+	return d.chain(s, c), d.chain(c, -s)
+}
+
+func (d Dual[N]) Sinh() Dual[N] {
+	x := float64(d.Val)
+	return d.chain(math.Sinh(x), math.Cosh(x))
+}
+
+func (d Dual[N]) Sqrt() Dual[N] {
+	s := math.Sqrt(float64(d.Val))
+	return d.chain(s, 1/(2*s))
+}
+
+func (d Dual[N]) Tan() Dual[N] {
+	t := math.Tan(float64(d.Val))
+	return d.chain(t, 1+t*t)
+}
+
+func (d Dual[N]) Tanh() Dual[N] {
+	t := math.Tanh(float64(d.Val))
+	return d.chain(t, 1-t*t)
+}
+
+func (d Dual[N]) Trunc() Dual[N] {
+	return Dual[N]{Val: N(math.Trunc(float64(d.Val)))}
+}
+
+/*
+digamma is ψ(x) = Γ'(x)/Γ(x). Small x is pushed up with ψ(x) = ψ(x+1) - 1/x until the asymptotic series is
+accurate and negative x uses the reflection ψ(1-x) - ψ(x) = π/tan(πx). It is NaN at 0 and the negative integers.
+*/
+func digamma(x float64) float64 {
+	switch {
+	case math.IsNaN(x) || math.IsInf(x, -1):
+		return math.NaN()
+	case math.IsInf(x, 1):
+		return x
+	case x <= 0 && x == math.Floor(x):
+		return math.NaN()
+	case x < 0:
+		return digamma(1-x) - math.Pi/math.Tan(math.Pi*x)
+	}
+	r := 0.0
+	for x < 10 {
+		r -= 1 / x
+		x++
+	}
+	f := 1 / (x * x)
+	return r + math.Log(x) - 0.5/x - f*(1.0/12-f*(1.0/120-f*(1.0/252-f*(1.0/240-f/132))))
+}
@@ -0,0 +1,170 @@
+package RUNK
+
+import (
+	"math"
+	"testing"
+)
+
+/*central is a central difference with a step scaled to x, good to about 1e-7 for smooth functions*/
+func central(f func(float64) float64, x float64) float64 {
+	h := 1e-6 * math.Max(1, math.Abs(x))
+	return (f(x+h) - f(x-h)) / (2 * h)
+}
+
+func TestDualAgreesWithFiniteDifferences(t *testing.T) {
+	fns := []struct {
+		name string
+		dual func(Dual[float64]) Dual[float64]
+		f    func(float64) float64
+		xs   []float64
+	}{
+		{"sin", Dual[float64].Sin, math.Sin, []float64{-2, 0, 0.5, 3}},
+		{"cos", Dual[float64].Cos, math.Cos, []float64{-2, 0.5, 3}},
+		{"tan", Dual[float64].Tan, math.Tan, []float64{-1, 0.3, 1.2}},
+		{"exp", Dual[float64].Exp, math.Exp, []float64{-3, 0, 2}},
+		{"exp2", Dual[float64].Exp2, math.Exp2, []float64{-3, 0, 2}},
+		{"expm1", Dual[float64].Expm1, math.Expm1, []float64{-1, 1e-3, 2}},
+		{"log", Dual[float64].Log, math.Log, []float64{0.1, 1, 50}},
+		{"log2", Dual[float64].Log2, math.Log2, []float64{0.1, 1, 50}},
+		{"log10", Dual[float64].Log10, math.Log10, []float64{0.1, 1, 50}},
+		{"log1p", Dual[float64].Log1p, math.Log1p, []float64{-0.5, 0, 3}},
+		{"sqrt", Dual[float64].Sqrt, math.Sqrt, []float64{0.25, 2, 100}},
+		{"cbrt", Dual[float64].Cbrt, math.Cbrt, []float64{-8, 0.5, 27}},
+		{"asin", Dual[float64].Asin, math.Asin, []float64{-0.5, 0, 0.9}},
+		{"acos", Dual[float64].Acos, math.Acos, []float64{-0.5, 0, 0.9}},
+		{"atan", Dual[float64].Atan, math.Atan, []float64{-5, 0, 2}},
+		{"sinh", Dual[float64].Sinh, math.Sinh, []float64{-2, 0, 1}},
+		{"cosh", Dual[float64].Cosh, math.Cosh, []float64{-2, 0, 1}},
+		{"tanh", Dual[float64].Tanh, math.Tanh, []float64{-2, 0, 1}},
+		{"asinh", Dual[float64].Asinh, math.Asinh, []float64{-2, 0, 1}},
+		{"acosh", Dual[float64].Acosh, math.Acosh, []float64{1.5, 3}},
+		{"atanh", Dual[float64].Atanh, math.Atanh, []float64{-0.5, 0, 0.7}},
+		{"erf", Dual[float64].Erf, math.Erf, []float64{-1, 0, 2}},
+		{"erfc", Dual[float64].Erfc, math.Erfc, []float64{-1, 0, 2}},
+		{"erfinv", Dual[float64].Erfinv, math.Erfinv, []float64{-0.5, 0, 0.9}},
+		{"erfcinv", Dual[float64].Erfcinv, math.Erfcinv, []float64{0.2, 1, 1.5}},
+		{"gamma", Dual[float64].Gamma, math.Gamma, []float64{-2.5, 0.5, 1, 4.2}},
+		{"lgam", Dual[float64].Lgam, func(x float64) float64 { l, s := math.Lgamma(x); return l * float64(s) }, []float64{-2.5, 0.5, 4.2}},
+		{"j0", Dual[float64].J0, math.J0, []float64{0, 1, 7}},
+		{"j1", Dual[float64].J1, math.J1, []float64{0, 1, 7}},
+		{"y0", Dual[float64].Y0, math.Y0, []float64{0.5, 7}},
+		{"y1", Dual[float64].Y1, math.Y1, []float64{0.5, 7}},
+		{"jn", func(d Dual[float64]) Dual[float64] { return d.Jn(3) }, func(x float64) float64 { return math.Jn(3, x) }, []float64{1, 7}},
+		{"ldexp", func(d Dual[float64]) Dual[float64] { return d.Ldexp(3) }, func(x float64) float64 { return math.Ldexp(x, 3) }, []float64{-1, 2}},
+	}
+	for _, fn := range fns {
+		for _, x := range fn.xs {
+			val, der := Derivative(fn.dual, x)
+			if want := fn.f(x); val != want {
+				t.Errorf("%s(%v) value = %v, want %v", fn.name, x, val, want)
+			}
+			want := central(fn.f, x)
+			if math.Abs(der-want) > 1e-5*math.Max(1, math.Abs(want)) {
+				t.Errorf("%s'(%v) = %v, want about %v", fn.name, x, der, want)
+			}
+		}
+	}
+}
+
+func TestDualTwoArguments(t *testing.T) {
+	x, y := 1.7, 0.6
+	f := func(v []Dual[float64]) Dual[float64] {
+		return v[0].Pow(v[1]).Add(v[0].Hypot(v[1])).Add(v[1].Atan2(v[0])).Add(v[0].Mul(v[1]).Div(v[0].Sub(v[1])))
+	}
+	plain := func(a, b float64) float64 {
+		return math.Pow(a, b) + math.Hypot(a, b) + math.Atan2(b, a) + a*b/(a-b)
+	}
+	val, grad := Gradient(f, []float64{x, y})
+	if want := plain(x, y); math.Abs(val-want) > 1e-15 {
+		t.Errorf("value = %v, want %v", val, want)
+	}
+	dx := central(func(a float64) float64 { return plain(a, y) }, x)
+	dy := central(func(b float64) float64 { return plain(x, b) }, y)
+	if math.Abs(grad[0]-dx) > 1e-6 || math.Abs(grad[1]-dy) > 1e-6 {
+		t.Errorf("gradient = %v, want about [%v %v]", grad, dx, dy)
+	}
+	if val, grad := Gradient(func([]Dual[float64]) Dual[float64] { return Constant(3.0) }, nil); val != 3 || len(grad) != 0 {
+		t.Errorf("Gradient with no inputs = %v %v", val, grad)
+	}
+	if _, d := Derivative(func(d Dual[float64]) Dual[float64] { return d.FMA(d, Constant(2.0)) }, 3); d != 6 {
+		t.Errorf("d/dx x*x+2 at 3 = %v", d)
+	}
+	if _, d := Derivative(func(d Dual[float64]) Dual[float64] { return Constant(7.0).Mod(d) }, 2); d != -3 {
+		t.Errorf("d/dy 7 mod y at 2 = %v, want -trunc(7/2)", d)
+	}
+	// a negative base with a constant exponent must not pull in log(x)
+	if v, d := Derivative(func(d Dual[float64]) Dual[float64] { return d.Pow(Constant(3.0)) }, -2); v != -8 || d != 12 {
+		t.Errorf("d/dx x^3 at -2 = %v %v", v, d)
+	}
+}
+
+func TestDualSteps(t *testing.T) {
+	steps := []func(Dual[float64]) Dual[float64]{
+		Dual[float64].Floor, Dual[float64].Ceil, Dual[float64].Round, Dual[float64].RoundToEven, Dual[float64].Trunc,
+		Dual[float64].Logb, Dual[float64].Ilogb, Dual[float64].Pow10,
+	}
+	for i, f := range steps {
+		if _, d := Derivative(f, 2.7); d != 0 {
+			t.Errorf("step %d has derivative %v", i, d)
+		}
+	}
+	if v, _ := Derivative(Dual[float64].Pow10, 2.7); v != 1000 {
+		t.Errorf("Pow10(2.7) = %v, want 10^3 like the wrapper", v)
+	}
+	if v, _ := Derivative(Dual[float64].Ilogb, 10); v != 3 {
+		t.Errorf("Ilogb(10) = %v", v)
+	}
+	i, frac := Variable(-3.25).Modf()
+	if i.Val != -3 || i.Der != 0 || frac.Val != -0.25 || frac.Der != 1 {
+		t.Errorf("Modf(-3.25) = %v %v", i, frac)
+	}
+	fr, exp := Variable(12.0).Frexp()
+	if fr.Val != 0.75 || exp != 4 || fr.Der != 1.0/16 {
+		t.Errorf("Frexp(12) = %v %d", fr, exp)
+	}
+	if _, d := Derivative(Dual[float64].Abs, -3); d != -1 {
+		t.Errorf("d/dx |x| at -3 = %v", d)
+	}
+	if _, d := Derivative(func(d Dual[float64]) Dual[float64] { return d.Dim(Constant(5.0)) }, 2); d != 0 {
+		t.Errorf("Dim below y has derivative %v", d)
+	}
+}
+
+func TestDualNextafter(t *testing.T) {
+	n := Variable(1.0).Nextafter(Constant(2.0))
+	if n.Val != 1+0x1p-52 || n.Der != 1 {
+		t.Errorf("float64 Nextafter = %v", n)
+	}
+	m := Variable(float32(1)).Nextafter(Constant(float32(0)))
+	if m.Val != 1-0x1p-24 || m.Der != 1 {
+		t.Errorf("float32 Nextafter = %v, want a float32 ulp", m)
+	}
+	if s := Variable(3.0).Nextafter(Constant(3.0)); s.Val != 3 {
+		t.Errorf("Nextafter to itself = %v", s)
+	}
+}
+
+func TestDualSpecialValues(t *testing.T) {
+	// a constant stays a constant even where the slope is infinite
+	if d := Constant(0.0).Sqrt(); d.Der != 0 {
+		t.Errorf("Sqrt of constant 0 has derivative %v", d.Der)
+	}
+	if d := Variable(0.0).Sqrt(); !math.IsInf(float64(d.Der), 1) {
+		t.Errorf("d/dx sqrt(x) at 0 = %v", d.Der)
+	}
+	if d := Variable(math.NaN()).Exp(); !math.IsNaN(d.Val) || !math.IsNaN(d.Der) {
+		t.Errorf("Exp(NaN) = %v", d)
+	}
+	if d := Variable(-1.0).Log(); !math.IsNaN(d.Val) {
+		t.Errorf("Log(-1) = %v", d)
+	}
+	if d := Variable(float32(2)).Exp(); d.Val != float32(math.Exp(2)) || d.Der != d.Val {
+		t.Errorf("float32 Exp = %v", d)
+	}
+	if d := Variable(2.0).Max(Variable(5.0).Scale(2), Constant(9.0)); d.Val != 10 || d.Der != 2 {
+		t.Errorf("Max = %v", d)
+	}
+	if d := Variable(2.0).Min(Constant(2.0)); d.Der != 1 {
+		t.Errorf("Min tie = %v, want the first to win", d)
+	}
+}
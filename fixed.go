@@ -0,0 +1,558 @@
+package RUNK
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+/*
+RoundingMode is the same kind of rounding function that ConvertNumberBy accepts. Anything with the shape of
+math.Round, math.Floor, math.Ceil, math.Trunc or math.RoundToEven can be used. It is an alias so that the std math
+functions can be passed in directly.
+*/
+type RoundingMode = func(float64) float64
+
+/*
+FixedScale describes how the raw integer of a Fixed is scaled. Radix is 2 for binary (Q format) scaling and 10 for
+decimal scaling. Places is the number of fractional bits or digits. Q16.16 would be BinaryScale(16) and a 4 place
+money amount would be DecimalScale(4). Places go up to 63 bits or 19 digits so that the factor fits in a uint64,
+anything past that or any other radix is ErrScale from the constructors and from everything else that takes a scale.
+*/
+type FixedScale struct {
+	Radix  uint8
+	Places uint8
+}
+
+var ErrScale = errors.New("RUNK: fixed-point scale out of range")
+
+func BinaryScale(places uint8) (FixedScale, error) {
+	s := FixedScale{Radix: 2, Places: places}
+	return s, s.Check()
+}
+
+func DecimalScale(places uint8) (FixedScale, error) {
+	s := FixedScale{Radix: 10, Places: places}
+	return s, s.Check()
+}
+
+/*Check returns ErrScale unless the radix is 2 or 10 and the places fit in a uint64 factor*/
+func (s FixedScale) Check() error {
+	switch {
+	case s.Radix == 2 && s.Places <= 63:
+		return nil
+	case s.Radix == 10 && s.Places <= 19:
+		return nil
+	}
+	return ErrScale
+}
+
+/*
+Factor is the number that one whole unit is multiplied by to get the raw value. A hand built scale that fails
+Check gets 1, so the raw value reads as whole units instead of dividing by nothing.
+*/
+func (s FixedScale) Factor() uint64 {
+	if s.Check() != nil {
+		return 1
+	}
+	if s.Radix == 10 {
+		f := uint64(1)
+		for i := uint8(0); i < s.Places; i++ {
+			f *= 10
+		}
+		return f
+	}
+	return 1 << s.Places
+}
+
+/*
+Fixed is a fixed-point number backed by any of the integer types. The value is Raw / Scale.Factor().
+All the arithmetic is done on the integers so it is deterministic and never touches a float unless you ask for one.
+Overflow saturates the same way ConvertNumber does: too big becomes MaxNum, too small becomes MinNum, and a
+result that would be NaN becomes 0.
+*/
+type Fixed[N Int] struct {
+	Raw   N
+	Scale FixedScale
+}
+
+/*
+NewFixed converts any Number into a Fixed with the given scale. Integers are scaled exactly and floats are rounded
+with roundMode, which defaults to math.Round like ConvertNumber. The only error is ErrScale.
+*/
+func NewFixed[N Int, M Number](m M, scale FixedScale, roundMode ...RoundingMode) (Fixed[N], error) {
+	if err := scale.Check(); err != nil {
+		return Fixed[N]{Scale: scale}, err
+	}
+	mode := pickRoundingMode(roundMode)
+	f := scale.Factor()
+	if isFloat[M]() {
+		return Fixed[N]{Raw: fixedFromFloat[N](float64(m), f, mode), Scale: scale}, nil
+	}
+	neg, mag := magnitude(m)
+	hi, lo := bits.Mul64(mag, f)
+	if hi != 0 {
+		return Fixed[N]{Raw: fromMagnitude[N](neg, math.MaxUint64), Scale: scale}, nil
+	}
+	return Fixed[N]{Raw: fromMagnitude[N](neg, lo), Scale: scale}, nil
+}
+
+func fixedFromFloat[N Int](fl float64, f uint64, mode RoundingMode) N {
+	if math.IsNaN(fl) || math.IsInf(fl, 0) {
+		return ConvertNumberBy[N](fl, mode)
+	}
+	r := new(big.Rat)
+	r.SetFloat64(fl)
+	return ratToRaw[N](r, f, mode)
+}
+
+/*
+FixedTo converts a Fixed back into any Number type. Integer targets are rounded with roundMode (math.Round by
+default) and saturated like ConvertNumber.
+*/
+func FixedTo[To Number, N Int](x Fixed[N], roundMode ...RoundingMode) To {
+	if isFloat[To]() {
+		return ConvertNumber[To](x.Float64())
+	}
+	f := x.Scale.Factor()
+	neg, mag := magnitude(x.Raw)
+	mag, overflow := roundMagnitude(neg, mag/f, fracOf(mag%f, f), pickRoundingMode(roundMode))
+	if overflow {
+		mag = math.MaxUint64
+	}
+	return fromMagnitude[To](neg, mag)
+}
+
+/*
+ParseFixed reads a decimal string like "-12.345" (anything big.Rat.SetString accepts works) into a Fixed.
+Digits beyond the scale are rounded with roundMode and out of range values saturate.
+*/
+func ParseFixed[N Int](s string, scale FixedScale, roundMode ...RoundingMode) (Fixed[N], error) {
+	if err := scale.Check(); err != nil {
+		return Fixed[N]{Scale: scale}, err
+	}
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok {
+		return Fixed[N]{Scale: scale}, errors.New("RUNK: invalid fixed-point number " + strconv.Quote(s))
+	}
+	return Fixed[N]{Raw: ratToRaw[N](r, scale.Factor(), pickRoundingMode(roundMode)), Scale: scale}, nil
+}
+
+/*String formats the exact value. Decimal scales keep all of their places so 1.5 at DecimalScale(2) is "1.50".*/
+func (x Fixed[N]) String() string {
+	places := int(x.Scale.Places)
+	if x.Scale.Check() != nil {
+		places = 0
+	}
+	if x.Scale.Radix == 10 {
+		return x.Rat().FloatString(places)
+	}
+	s := x.Rat().FloatString(places)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(s, "0")
+		s = strings.TrimSuffix(s, ".")
+	}
+	return s
+}
+
+/*Rat returns the exact value as a big.Rat*/
+func (x Fixed[N]) Rat() *big.Rat {
+	neg, mag := magnitude(x.Raw)
+	num := new(big.Int).SetUint64(mag)
+	if neg {
+		num.Neg(num)
+	}
+	return new(big.Rat).SetFrac(num, new(big.Int).SetUint64(x.Scale.Factor()))
+}
+
+/*Float64 returns the nearest float64 to the value*/
+func (x Fixed[N]) Float64() float64 {
+	neg, mag := magnitude(x.Raw)
+	f := x.Scale.Factor()
+	if mag <= 1<<53 && (f <= 1<<53 || f&(f-1) == 0) {
+		v := float64(mag) / float64(f)
+		if neg {
+			v = -v
+		}
+		return v
+	}
+	v, _ := x.Rat().Float64()
+	return v
+}
+
+/*
+Rescale changes the scale, rounding away any places that no longer fit. A bad scale is ErrScale and x comes back
+as it was.
+*/
+func (x Fixed[N]) Rescale(scale FixedScale, roundMode ...RoundingMode) (Fixed[N], error) {
+	if err := scale.Check(); err != nil {
+		return x, err
+	}
+	return Fixed[N]{Raw: rescaleRaw(x.Raw, x.Scale.Factor(), scale.Factor(), pickRoundingMode(roundMode)), Scale: scale}, nil
+}
+
+func rescaleRaw[N Int](raw N, from uint64, to uint64, mode RoundingMode) N {
+	if from == to {
+		return raw
+	}
+	neg, mag := magnitude(raw)
+	mag, overflow := mulDivRound(neg, mag, to, from, mode)
+	if overflow {
+		mag = math.MaxUint64
+	}
+	return fromMagnitude[N](neg, mag)
+}
+
+/*Add returns x + y in the scale of x. y is rescaled first if the scales differ.*/
+func (x Fixed[N]) Add(y Fixed[N], roundMode ...RoundingMode) Fixed[N] {
+	yr := rescaleRaw(y.Raw, y.Scale.Factor(), x.Scale.Factor(), pickRoundingMode(roundMode))
+	return Fixed[N]{Raw: addSaturate(x.Raw, yr), Scale: x.Scale}
+}
+
+/*Sub returns x - y in the scale of x*/
+func (x Fixed[N]) Sub(y Fixed[N], roundMode ...RoundingMode) Fixed[N] {
+	yr := rescaleRaw(y.Raw, y.Scale.Factor(), x.Scale.Factor(), pickRoundingMode(roundMode))
+	neg, mag := magnitude(yr)
+	xneg, xmag := magnitude(x.Raw)
+	neg, mag = addMagnitudes(xneg, xmag, !neg && mag != 0, mag)
+	return Fixed[N]{Raw: fromMagnitude[N](neg, mag), Scale: x.Scale}
+}
+
+/*Mul returns x * y in the scale of x*/
+func (x Fixed[N]) Mul(y Fixed[N], roundMode ...RoundingMode) Fixed[N] {
+	xneg, xmag := magnitude(x.Raw)
+	yneg, ymag := magnitude(y.Raw)
+	neg := xneg != yneg
+	mag, overflow := mulDivRound(neg, xmag, ymag, y.Scale.Factor(), pickRoundingMode(roundMode))
+	if overflow {
+		mag = math.MaxUint64
+	}
+	return Fixed[N]{Raw: fromMagnitude[N](neg, mag), Scale: x.Scale}
+}
+
+/*
+Div returns x / y in the scale of x. Dividing by zero saturates toward the sign of x like converting +-Inf would
+and 0/0 is 0 like converting NaN would.
+*/
+func (x Fixed[N]) Div(y Fixed[N], roundMode ...RoundingMode) Fixed[N] {
+	xneg, xmag := magnitude(x.Raw)
+	yneg, ymag := magnitude(y.Raw)
+	if ymag == 0 {
+		if xmag == 0 {
+			return Fixed[N]{Scale: x.Scale}
+		}
+		return Fixed[N]{Raw: fromMagnitude[N](xneg, math.MaxUint64), Scale: x.Scale}
+	}
+	neg := xneg != yneg
+	mag, overflow := mulDivRound(neg, xmag, y.Scale.Factor(), ymag, pickRoundingMode(roundMode))
+	if overflow {
+		mag = math.MaxUint64
+	}
+	return Fixed[N]{Raw: fromMagnitude[N](neg, mag), Scale: x.Scale}
+}
+
+func (x Fixed[N]) Neg() Fixed[N] {
+	neg, mag := magnitude(x.Raw)
+	return Fixed[N]{Raw: fromMagnitude[N](!neg && mag != 0, mag), Scale: x.Scale}
+}
+
+func (x Fixed[N]) Abs() Fixed[N] {
+	_, mag := magnitude(x.Raw)
+	return Fixed[N]{Raw: fromMagnitude[N](false, mag), Scale: x.Scale}
+}
+
+/*Cmp compares the exact values of x and y even when their scales differ. It returns -1, 0 or +1.*/
+func (x Fixed[N]) Cmp(y Fixed[N]) int {
+	xneg, xmag := magnitude(x.Raw)
+	yneg, ymag := magnitude(y.Raw)
+	if xmag == 0 && ymag == 0 {
+		return 0
+	}
+	if xneg != yneg {
+		if xneg {
+			return -1
+		}
+		return 1
+	}
+	xhi, xlo := bits.Mul64(xmag, y.Scale.Factor())
+	yhi, ylo := bits.Mul64(ymag, x.Scale.Factor())
+	c := 0
+	switch {
+	case xhi < yhi || (xhi == yhi && xlo < ylo):
+		c = -1
+	case xhi > yhi || (xhi == yhi && xlo > ylo):
+		c = 1
+	}
+	if xneg {
+		return -c
+	}
+	return c
+}
+
+/*
+Sqrt is computed exactly on the integers so it does not depend on floating point at all. Negative values give 0
+the same way converting NaN does.
+*/
+func (x Fixed[N]) Sqrt(roundMode ...RoundingMode) Fixed[N] {
+	neg, mag := magnitude(x.Raw)
+	if neg || mag == 0 {
+		return Fixed[N]{Scale: x.Scale}
+	}
+	// sqrt(raw/f)*f == sqrt(raw*f)
+	hi, lo := bits.Mul64(mag, x.Scale.Factor())
+	n := new(big.Int).Lsh(new(big.Int).SetUint64(hi), 64)
+	n.Or(n, new(big.Int).SetUint64(lo))
+	s := new(big.Int).Sqrt(n)
+	rem := new(big.Int).Sub(n, new(big.Int).Mul(s, s))
+	// The square root of an integer is never exactly halfway so only the side of 0 and 0.5 matters for rounding.
+	frac := 0.0
+	if rem.Sign() > 0 {
+		frac = 0.25
+		if rem.Cmp(s) > 0 {
+			frac = 0.75
+		}
+	}
+	root, overflow := roundMagnitude(false, s.Uint64(), frac, pickRoundingMode(roundMode))
+	if overflow {
+		root = math.MaxUint64
+	}
+	return Fixed[N]{Raw: fromMagnitude[N](false, root), Scale: x.Scale}
+}
+
+/*
+Apply runs any float64 function on the value and converts the result back into the same scale. This is how the
+rest of the wrappers can be used, for example `x.Apply(Atan[float64])`.
+*/
+func (x Fixed[N]) Apply(fn func(float64) float64, roundMode ...RoundingMode) Fixed[N] {
+	return Fixed[N]{Raw: fixedFromFloat[N](fn(x.Float64()), x.Scale.Factor(), pickRoundingMode(roundMode)), Scale: x.Scale}
+}
+
+func (x Fixed[N]) Sin(roundMode ...RoundingMode) Fixed[N] {
+	return x.Apply(math.Sin, roundMode...)
+}
+
+func (x Fixed[N]) Cos(roundMode ...RoundingMode) Fixed[N] {
+	return x.Apply(math.Cos, roundMode...)
+}
+
+func (x Fixed[N]) Exp(roundMode ...RoundingMode) Fixed[N] {
+	return x.Apply(math.Exp, roundMode...)
+}
+
+func (x Fixed[N]) Log(roundMode ...RoundingMode) Fixed[N] {
+	return x.Apply(math.Log, roundMode...)
+}
+
+/*
+Below are the integer helpers shared by the exact types. Everything is done on a sign and a uint64 magnitude so
+the same code works for every integer width without overflowing in the middle.
+*/
+
+func pickRoundingMode(roundMode []RoundingMode) RoundingMode {
+	if len(roundMode) > 0 && roundMode[0] != nil {
+		return roundMode[0]
+	}
+	return math.Round
+}
+
+/*isFloat reports whether N is one of the float types. 1/2 is only nonzero for floats.*/
+func isFloat[N Number]() bool {
+	n := N(1)
+	n /= 2
+	return n != 0
+}
+
+/*isSigned reports whether N can go below zero. Unsigned types wrap around instead.*/
+func isSigned[N Number]() bool {
+	var n N
+	return n-1 < n
+}
+
+/*magnitude splits an integer into its sign and absolute value. MinInt64 works because the negation wraps to 1<<63.*/
+func magnitude[N Number](n N) (neg bool, mag uint64) {
+	if isSigned[N]() && n < 0 {
+		return true, uint64(-int64(n))
+	}
+	return false, uint64(n)
+}
+
+/*fromMagnitude puts a sign and magnitude back together, saturating the same way ConvertNumber does*/
+func fromMagnitude[N Number](neg bool, mag uint64) N {
+	if !neg || mag == 0 {
+		return ConvertNumber[N](mag)
+	}
+	if mag > 1<<63 {
+		if isFloat[N]() {
+			return ConvertNumber[N](-float64(mag))
+		}
+		return MinNum[N]()
+	}
+	return ConvertNumber[N](int64(-mag))
+}
+
+/*addMagnitudes adds two signed magnitudes. A carry out of 64 bits saturates the magnitude.*/
+func addMagnitudes(aneg bool, amag uint64, bneg bool, bmag uint64) (bool, uint64) {
+	if aneg == bneg {
+		sum, carry := bits.Add64(amag, bmag, 0)
+		if carry != 0 {
+			sum = math.MaxUint64
+		}
+		return aneg, sum
+	}
+	if amag >= bmag {
+		return aneg && amag != bmag, amag - bmag
+	}
+	return bneg, bmag - amag
+}
+
+func addSaturate[N Int](a N, b N) N {
+	aneg, amag := magnitude(a)
+	bneg, bmag := magnitude(b)
+	neg, mag := addMagnitudes(aneg, amag, bneg, bmag)
+	return fromMagnitude[N](neg, mag)
+}
+
+/*
+fracOf returns r/d as a float64 for 0 <= r < d. The float division can round a value that is just below or above
+one half onto exactly 0.5, so it is nudged back to the correct side to keep half-way rounding exact.
+*/
+func fracOf(r uint64, d uint64) float64 {
+	if r == 0 {
+		return 0
+	}
+	twice, carry := bits.Add64(r, r, 0)
+	c := 1
+	if carry == 0 {
+		switch {
+		case twice < d:
+			c = -1
+		case twice == d:
+			c = 0
+		}
+	}
+	return nudgeHalf(float64(r)/float64(d), c)
+}
+
+/*nudgeHalf keeps a fraction on the same side of one half as the exact value, c is the sign of 2r-d*/
+func nudgeHalf(f float64, c int) float64 {
+	switch {
+	case c == 0:
+		return 0.5
+	case c < 0:
+		return math.Min(f, math.Nextafter(0.5, 0))
+	}
+	return math.Max(f, math.Nextafter(0.5, 1))
+}
+
+/*
+roundStep applies a rounding function to +-(odd + frac) and returns how much to add to the even part of the
+magnitude. Only the lowest bit of the quotient is handed to the rounding function so that large magnitudes don't
+lose precision in a float64. Shifting by an even number keeps floor, ceil, trunc, round and round-to-even all
+behaving the same.
+*/
+func roundStep(neg bool, odd bool, frac float64, mode RoundingMode) uint64 {
+	x := frac
+	if odd {
+		x++
+	}
+	if neg {
+		x = -x
+	}
+	r := math.Abs(mode(x))
+	if math.IsNaN(r) || r > 2 {
+		r = math.Round(math.Abs(x))
+	}
+	return uint64(r)
+}
+
+/*roundMagnitude rounds the value +-(q + frac) to a whole magnitude. The bool reports a uint64 overflow.*/
+func roundMagnitude(neg bool, q uint64, frac float64, mode RoundingMode) (uint64, bool) {
+	if frac == 0 {
+		return q, false
+	}
+	base := q &^ 1
+	add := roundStep(neg, q&1 == 1, frac, mode)
+	if base > math.MaxUint64-add {
+		return math.MaxUint64, true
+	}
+	return base + add, false
+}
+
+/*mulDivRound computes a*b/d with a 128 bit intermediate and rounds the result with mode*/
+func mulDivRound(neg bool, a uint64, b uint64, d uint64, mode RoundingMode) (uint64, bool) {
+	if d == 0 {
+		return math.MaxUint64, true
+	}
+	hi, lo := bits.Mul64(a, b)
+	if hi >= d {
+		return math.MaxUint64, true
+	}
+	q, r := bits.Div64(hi, lo, d)
+	return roundMagnitude(neg, q, fracOf(r, d), mode)
+}
+
+/*roundBigQuo divides num by a positive den and rounds the quotient with mode*/
+func roundBigQuo(num *big.Int, den *big.Int, mode RoundingMode) *big.Int {
+	q, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Sign() == 0 {
+		return q
+	}
+	neg := num.Sign() < 0
+	rem.Abs(rem)
+	frac, _ := new(big.Rat).SetFrac(rem, den).Float64()
+	frac = nudgeHalf(frac, new(big.Int).Lsh(rem, 1).Cmp(den))
+	q.Abs(q)
+	add := roundStep(neg, q.Bit(0) == 1, frac, mode)
+	q.SetBit(q, 0, 0)
+	q.Add(q, new(big.Int).SetUint64(add))
+	if neg {
+		q.Neg(q)
+	}
+	return q
+}
+
+/*ratToRaw scales an exact rational by f and rounds it into N*/
+func ratToRaw[N Int](r *big.Rat, f uint64, mode RoundingMode) N {
+	num := new(big.Int).Mul(r.Num(), new(big.Int).SetUint64(f))
+	return bigIntTo[N](roundBigQuo(num, r.Denom(), mode))
+}
+
+/*bigIntTo converts a big.Int into any Number, saturating at MinNum and MaxNum like ConvertNumber*/
+func bigIntTo[To Number](b *big.Int) To {
+	switch {
+	case b.IsInt64():
+		return ConvertNumber[To](b.Int64())
+	case b.IsUint64():
+		return ConvertNumber[To](b.Uint64())
+	case isFloat[To]():
+		f, _ := new(big.Float).SetInt(b).Float64()
+		return ConvertNumber[To](f)
+	case b.Sign() < 0:
+		return MinNum[To]()
+	}
+	return MaxNum[To]()
+}
+
+/*fitBig puts an exact integer into N with the overflow policy, the error is ErrOverflow if it didn't fit*/
+func fitBig[N Number](b *big.Int, policy OverflowPolicy) (N, error) {
+	n := bigIntTo[N](b)
+	if bigFromInt(n).Cmp(b) == 0 {
+		return n, nil
+	}
+	if policy == Wrap {
+		low := new(big.Int).And(b, new(big.Int).SetUint64(math.MaxUint64))
+		n = N(low.Uint64())
+	}
+	return n, ErrOverflow
+}
+
+/*fitWide converts an exact result with the overflow policy and records ErrOverflow in err if it didn't fit*/
+func fitWide[N Number](w wide, policy OverflowPolicy, err *error) N {
+	if w.hi != 0 || wideOf(fromMagnitude[N](w.neg, w.lo)) != w {
+		*err = ErrOverflow
+	}
+	return wideTo[N](w, policy, false)
+}
@@ -0,0 +1,179 @@
+package RUNK
+
+import (
+	"math"
+	"testing"
+)
+
+/*scaleOf drops the error of a scale constructor, TestFixedScaleCheck covers the ones that fail*/
+func scaleOf(s FixedScale, _ error) FixedScale {
+	return s
+}
+
+func TestFixedScaleCheck(t *testing.T) {
+	if _, err := BinaryScale(63); err != nil {
+		t.Errorf("BinaryScale(63) = %v", err)
+	}
+	if _, err := BinaryScale(64); err != ErrScale {
+		t.Errorf("BinaryScale(64) = %v, want ErrScale", err)
+	}
+	if _, err := DecimalScale(19); err != nil {
+		t.Errorf("DecimalScale(19) = %v", err)
+	}
+	if _, err := DecimalScale(20); err != ErrScale {
+		t.Errorf("DecimalScale(20) = %v, want ErrScale", err)
+	}
+	bad := FixedScale{Radix: 16, Places: 2}
+	if _, err := NewFixed[int64](1.5, bad); err != ErrScale {
+		t.Errorf("NewFixed with radix 16 = %v, want ErrScale", err)
+	}
+	if _, err := ParseFixed[int64]("1.5", bad); err != ErrScale {
+		t.Errorf("ParseFixed with radix 16 = %v, want ErrScale", err)
+	}
+	x, _ := NewFixed[int64](3, scaleOf(DecimalScale(2)))
+	if y, err := x.Rescale(FixedScale{Radix: 10, Places: 25}); err != ErrScale || y != x {
+		t.Errorf("Rescale to 25 places = %v %v, want x and ErrScale", y, err)
+	}
+	// a hand built bad scale reads as whole units rather than dividing by zero
+	if got := (Fixed[int64]{Raw: 7, Scale: bad}).String(); got != "7" {
+		t.Errorf("String with bad scale = %q", got)
+	}
+	if got := bad.Factor(); got != 1 {
+		t.Errorf("Factor of bad scale = %d", got)
+	}
+}
+
+func TestFixedConvert(t *testing.T) {
+	q16 := scaleOf(BinaryScale(16))
+	d4 := scaleOf(DecimalScale(4))
+	x, _ := NewFixed[int32](1.5, q16)
+	if x.Raw != 3<<15 || x.String() != "1.5" {
+		t.Errorf("Q16.16 1.5 = %d %q", x.Raw, x)
+	}
+	y, _ := NewFixed[int64](-12.34567, d4)
+	if y.Raw != -123457 || y.String() != "-12.3457" {
+		t.Errorf("4 places -12.34567 = %d %q", y.Raw, y)
+	}
+	y, _ = NewFixed[int64](-12.34567, d4, math.Trunc)
+	if y.Raw != -123456 {
+		t.Errorf("truncated -12.34567 = %d", y.Raw)
+	}
+	// saturation and NaN follow ConvertNumber
+	z, _ := NewFixed[int8](100, scaleOf(BinaryScale(4)))
+	if z.Raw != math.MaxInt8 {
+		t.Errorf("100 in int8 Q3.4 = %d, want saturated", z.Raw)
+	}
+	z, _ = NewFixed[int8](math.Inf(-1), scaleOf(BinaryScale(4)))
+	if z.Raw != math.MinInt8 {
+		t.Errorf("-Inf in int8 = %d", z.Raw)
+	}
+	z, _ = NewFixed[int8](math.NaN(), scaleOf(BinaryScale(4)))
+	if z.Raw != 0 {
+		t.Errorf("NaN in int8 = %d", z.Raw)
+	}
+	if got := FixedTo[int](y); got != -12 {
+		t.Errorf("FixedTo[int](-12.3456) = %d", got)
+	}
+	if got := FixedTo[int](y, math.Floor); got != -13 {
+		t.Errorf("FixedTo[int] floor = %d", got)
+	}
+	if got := FixedTo[uint8](y); got != 0 {
+		t.Errorf("FixedTo[uint8] of a negative = %d", got)
+	}
+	if got := FixedTo[float64](x); got != 1.5 {
+		t.Errorf("FixedTo[float64] = %v", got)
+	}
+}
+
+func TestFixedArithmetic(t *testing.T) {
+	d2 := scaleOf(DecimalScale(2))
+	a, _ := ParseFixed[int64]("10.00", d2)
+	b, _ := ParseFixed[int64]("3", d2)
+	tests := []struct {
+		name string
+		got  Fixed[int64]
+		want string
+	}{
+		{"add", a.Add(b), "13.00"},
+		{"sub", b.Sub(a), "-7.00"},
+		{"mul", a.Mul(b), "30.00"},
+		{"div", a.Div(b), "3.33"},
+		{"div ceil", a.Div(b, math.Ceil), "3.34"},
+		{"neg div floor", a.Neg().Div(b, math.Floor), "-3.34"},
+		{"div by zero", a.Div(Fixed[int64]{Scale: d2}), "92233720368547758.07"},
+		{"zero by zero", Fixed[int64]{Scale: d2}.Div(Fixed[int64]{Scale: d2}), "0.00"},
+		{"sqrt", a.Sqrt(), "3.16"},
+		{"sqrt negative", a.Neg().Sqrt(), "0.00"},
+	}
+	for _, tt := range tests {
+		if got := tt.got.String(); got != tt.want {
+			t.Errorf("%s = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+	// half way cases round exactly, not through a float
+	h, _ := ParseFixed[int64]("0.05", d2)
+	half, _ := ParseFixed[int64]("0.5", d2)
+	if got := h.Mul(half, math.RoundToEven).String(); got != "0.02" {
+		t.Errorf("0.05*0.5 to even = %s", got)
+	}
+	if got := h.Mul(half).String(); got != "0.03" {
+		t.Errorf("0.05*0.5 = %s", got)
+	}
+	big, _ := NewFixed[int16](200, scaleOf(BinaryScale(8)))
+	if got := big.Add(big).Raw; got != math.MaxInt16 {
+		t.Errorf("saturating add = %d", got)
+	}
+	if got := big.Mul(big).Raw; got != math.MaxInt16 {
+		t.Errorf("saturating mul = %d", got)
+	}
+}
+
+func TestFixedMixedScales(t *testing.T) {
+	q8 := scaleOf(BinaryScale(8))
+	d3 := scaleOf(DecimalScale(3))
+	a, _ := NewFixed[int64](0.5, q8)
+	b, _ := ParseFixed[int64]("0.500", d3)
+	if a.Cmp(b) != 0 || b.Cmp(a) != 0 {
+		t.Errorf("0.5 in binary and decimal compare %d %d", a.Cmp(b), b.Cmp(a))
+	}
+	c, _ := ParseFixed[int64]("0.501", d3)
+	if a.Cmp(c) != -1 || c.Cmp(a) != 1 {
+		t.Errorf("0.5 vs 0.501 compare %d %d", a.Cmp(c), c.Cmp(a))
+	}
+	if got := b.Add(a).String(); got != "1.000" {
+		t.Errorf("mixed add = %s", got)
+	}
+	r, err := c.Rescale(q8)
+	if err != nil || r.Raw != 128 {
+		t.Errorf("0.501 to Q8 = %d %v", r.Raw, err)
+	}
+}
+
+func TestFixedParse(t *testing.T) {
+	d2 := scaleOf(DecimalScale(2))
+	if _, err := ParseFixed[int64]("abc", d2); err == nil {
+		t.Error("ParseFixed accepted abc")
+	}
+	x, err := ParseFixed[int32](" 1e20 ", d2)
+	if err != nil || x.Raw != math.MaxInt32 {
+		t.Errorf("1e20 in int32 = %d %v", x.Raw, err)
+	}
+	x, _ = ParseFixed[int32]("1/3", d2)
+	if x.String() != "0.33" {
+		t.Errorf("1/3 = %s", x)
+	}
+}
+
+func TestFixedFunctions(t *testing.T) {
+	q16 := scaleOf(BinaryScale(16))
+	x, _ := NewFixed[int32](1, q16)
+	if got := x.Exp().Float64(); math.Abs(got-math.E) > 1.0/(1<<16) {
+		t.Errorf("Exp(1) = %v", got)
+	}
+	if got := x.Sin().Float64(); math.Abs(got-math.Sin(1)) > 1.0/(1<<16) {
+		t.Errorf("Sin(1) = %v", got)
+	}
+	if got := x.Log().Raw; got != 0 {
+		t.Errorf("Log(1) raw = %d", got)
+	}
+}
@@ -0,0 +1,343 @@
+package RUNK
+
+import (
+	"math"
+	"math/bits"
+	"strconv"
+)
+
+/*
+Float16 is an IEEE 754 half precision float and BFloat16 is the brain float (the top half of a float32). They are
+stored as their raw bits so they line up with the uint16 buffers that tensors and GPU data come in.
+They are structs rather than named uint16s on purpose. A named uint16 would satisfy Number and then something like
+MaxNum or Sqrt would happily treat the bit pattern as an integer.
+Conversions round to nearest even straight from the source value so there is no double rounding, and values too
+big for the format become +-Inf the same way a float64 that is too big for a float32 does in floatToFloat.
+*/
+type Float16 struct {
+	bits uint16
+}
+
+type BFloat16 struct {
+	bits uint16
+}
+
+const (
+	MaxFloat16              float32 = 0x1p15 * (1 + (1 - 0x1p-10)) // 65504
+	SmallestNormalFloat16   float32 = 0x1p-14                      // 6.103515625e-05
+	SmallestNonzeroFloat16  float32 = 0x1p-24                      // 5.960464477539063e-08
+	MaxBFloat16             float32 = 0x1p127 * (1 + (1 - 0x1p-7)) // 3.3895313892515355e+38
+	SmallestNormalBFloat16  float32 = 0x1p-126                     // 1.1754943508222875e-38
+	SmallestNonzeroBFloat16 float32 = 0x1p-133                     // 9.183549615799121e-41
+)
+
+const (
+	float16ExpBits   = 5
+	float16MantBits  = 10
+	bfloat16ExpBits  = 8
+	bfloat16MantBits = 7
+)
+
+func Float16FromBits(b uint16) Float16 {
+	return Float16{bits: b}
+}
+
+func BFloat16FromBits(b uint16) BFloat16 {
+	return BFloat16{bits: b}
+}
+
+func (h Float16) Bits() uint16 {
+	return h.bits
+}
+
+func (h BFloat16) Bits() uint16 {
+	return h.bits
+}
+
+/*NewFloat16 converts any Number to the nearest Float16*/
+func NewFloat16[N Number](n N) Float16 {
+	return Float16{bits: narrowNumber(n, float16ExpBits, float16MantBits)}
+}
+
+/*NewBFloat16 converts any Number to the nearest BFloat16*/
+func NewBFloat16[N Number](n N) BFloat16 {
+	return BFloat16{bits: narrowNumber(n, bfloat16ExpBits, bfloat16MantBits)}
+}
+
+/*Float16To converts to any Number. The float32 value is exact so this saturates exactly like ConvertNumber does.*/
+func Float16To[To Number](h Float16) To {
+	return ConvertNumber[To](h.Float32())
+}
+
+func BFloat16To[To Number](h BFloat16) To {
+	return ConvertNumber[To](h.Float32())
+}
+
+/*Float32 widens exactly, every Float16 is representable as a float32*/
+func (h Float16) Float32() float32 {
+	return math.Float32frombits(widenBits(h.bits, float16ExpBits, float16MantBits))
+}
+
+/*Float32 widens exactly, a BFloat16 is just the high half of a float32*/
+func (h BFloat16) Float32() float32 {
+	return math.Float32frombits(uint32(h.bits) << 16)
+}
+
+func (h Float16) String() string {
+	return strconv.FormatFloat(float64(h.Float32()), 'g', -1, 32)
+}
+
+func (h BFloat16) String() string {
+	return strconv.FormatFloat(float64(h.Float32()), 'g', -1, 32)
+}
+
+/*
+The arithmetic is done in float32 and rounded once more. float32 has more than twice the precision plus two bits
+of either format, which is enough that the double rounding can never change the answer for + - * / and so the
+results are the correctly rounded half precision values.
+*/
+
+func (h Float16) Add(g Float16) Float16 {
+	return NewFloat16(h.Float32() + g.Float32())
+}
+
+func (h Float16) Sub(g Float16) Float16 {
+	return NewFloat16(h.Float32() - g.Float32())
+}
+
+func (h Float16) Mul(g Float16) Float16 {
+	return NewFloat16(h.Float32() * g.Float32())
+}
+
+func (h Float16) Div(g Float16) Float16 {
+	return NewFloat16(h.Float32() / g.Float32())
+}
+
+func (h Float16) Neg() Float16 {
+	return Float16{bits: h.bits ^ 0x8000}
+}
+
+func (h BFloat16) Add(g BFloat16) BFloat16 {
+	return NewBFloat16(h.Float32() + g.Float32())
+}
+
+func (h BFloat16) Sub(g BFloat16) BFloat16 {
+	return NewBFloat16(h.Float32() - g.Float32())
+}
+
+func (h BFloat16) Mul(g BFloat16) BFloat16 {
+	return NewBFloat16(h.Float32() * g.Float32())
+}
+
+func (h BFloat16) Div(g BFloat16) BFloat16 {
+	return NewBFloat16(h.Float32() / g.Float32())
+}
+
+func (h BFloat16) Neg() BFloat16 {
+	return BFloat16{bits: h.bits ^ 0x8000}
+}
+
+func (h Float16) IsNaN() bool {
+	return halfClass(h.bits, float16ExpBits, float16MantBits) == halfNaN
+}
+
+/*IsInf reports whether h is an infinity, sign works the same as math.IsInf*/
+func (h Float16) IsInf(sign int) bool {
+	return halfClass(h.bits, float16ExpBits, float16MantBits) == halfInf && signMatches(h.bits, sign)
+}
+
+func (h Float16) IsSubnormal() bool {
+	return halfClass(h.bits, float16ExpBits, float16MantBits) == halfSubnormal
+}
+
+func (h Float16) IsZero() bool {
+	return h.bits&0x7fff == 0
+}
+
+func (h Float16) Signbit() bool {
+	return h.bits&0x8000 != 0
+}
+
+func (h BFloat16) IsNaN() bool {
+	return halfClass(h.bits, bfloat16ExpBits, bfloat16MantBits) == halfNaN
+}
+
+func (h BFloat16) IsInf(sign int) bool {
+	return halfClass(h.bits, bfloat16ExpBits, bfloat16MantBits) == halfInf && signMatches(h.bits, sign)
+}
+
+func (h BFloat16) IsSubnormal() bool {
+	return halfClass(h.bits, bfloat16ExpBits, bfloat16MantBits) == halfSubnormal
+}
+
+func (h BFloat16) IsZero() bool {
+	return h.bits&0x7fff == 0
+}
+
+func (h BFloat16) Signbit() bool {
+	return h.bits&0x8000 != 0
+}
+
+/*
+The bulk converters work on raw uint16 buffers. They convert min(len(dst), len(src)) values and return how many
+that was, the same as copy.
+*/
+
+func Float16sToFloat32s(dst []float32, src []uint16) int {
+	n := min(len(dst), len(src))
+	for i := 0; i < n; i++ {
+		dst[i] = Float16{bits: src[i]}.Float32()
+	}
+	return n
+}
+
+func Float32sToFloat16s(dst []uint16, src []float32) int {
+	n := min(len(dst), len(src))
+	for i := 0; i < n; i++ {
+		dst[i] = narrowFloat(float64(src[i]), float16ExpBits, float16MantBits)
+	}
+	return n
+}
+
+func BFloat16sToFloat32s(dst []float32, src []uint16) int {
+	n := min(len(dst), len(src))
+	for i := 0; i < n; i++ {
+		dst[i] = math.Float32frombits(uint32(src[i]) << 16)
+	}
+	return n
+}
+
+func Float32sToBFloat16s(dst []uint16, src []float32) int {
+	n := min(len(dst), len(src))
+	for i := 0; i < n; i++ {
+		dst[i] = narrowFloat(float64(src[i]), bfloat16ExpBits, bfloat16MantBits)
+	}
+	return n
+}
+
+const (
+	halfZero = iota
+	halfSubnormal
+	halfNormal
+	halfInf
+	halfNaN
+)
+
+func halfClass(b uint16, expBits uint, mantBits uint) int {
+	expMask := uint16(1)<<expBits - 1
+	e := (b >> mantBits) & expMask
+	m := b & (1<<mantBits - 1)
+	switch {
+	case e == expMask && m != 0:
+		return halfNaN
+	case e == expMask:
+		return halfInf
+	case e == 0 && m != 0:
+		return halfSubnormal
+	case e == 0:
+		return halfZero
+	}
+	return halfNormal
+}
+
+func signMatches(b uint16, sign int) bool {
+	neg := b&0x8000 != 0
+	return sign == 0 || (sign > 0 && !neg) || (sign < 0 && neg)
+}
+
+/*widenBits turns a small IEEE float into float32 bits, exactly*/
+func widenBits(b uint16, expBits uint, mantBits uint) uint32 {
+	sign := uint32(b>>15) << 31
+	expMask := uint32(1)<<expBits - 1
+	bias := int32(expMask >> 1)
+	e := uint32(b>>mantBits) & expMask
+	m := uint32(b) & (1<<mantBits - 1)
+	switch {
+	case e == expMask:
+		return sign | 0x7f800000 | m<<(23-mantBits)
+	case e == 0 && m == 0:
+		return sign
+	case e == 0:
+		// subnormal, normalise it since it is a normal number in float32
+		shift := uint32(bits.LeadingZeros32(m)) - (32 - uint32(mantBits))
+		m = (m << (shift + 1)) & (1<<mantBits - 1)
+		return sign | uint32(-bias-int32(shift)+127)<<23 | m<<(23-mantBits)
+	}
+	return sign | uint32(int32(e)-bias+127)<<23 | m<<(23-mantBits)
+}
+
+/*
+narrowNumber rounds any Number into the small format. Integers past 2^53 would get rounded once going into a
+float64 and then again here so they are rounded to the target precision straight from the integer instead.
+*/
+func narrowNumber[N Number](n N, expBits uint, mantBits uint) uint16 {
+	if isFloat[N]() {
+		return narrowFloat(float64(n), expBits, mantBits)
+	}
+	neg, mag := magnitude(n)
+	if l := bits.Len64(mag); l > 53 {
+		shift := uint(l) - (mantBits + 1)
+		keep := mag >> shift
+		rem := mag & (1<<shift - 1)
+		half := uint64(1) << (shift - 1)
+		if rem > half || (rem == half && keep&1 == 1) {
+			keep++
+		}
+		f := math.Ldexp(float64(keep), int(shift))
+		if neg {
+			f = -f
+		}
+		return narrowFloat(f, expBits, mantBits)
+	}
+	f := float64(mag)
+	if neg {
+		f = -f
+	}
+	return narrowFloat(f, expBits, mantBits)
+}
+
+/*
+narrowFloat rounds a float64 to nearest even in a format with expBits of exponent and mantBits of fraction.
+The value is scaled so that one unit is the spacing of the target format at its exponent, rounded as an integer
+and then packed. A carry out of the mantissa rolls into the exponent on its own which also handles the step from
+subnormal to normal and from the largest finite value to Inf.
+*/
+func narrowFloat(f float64, expBits uint, mantBits uint) uint16 {
+	sign := uint16(0)
+	if math.Signbit(f) {
+		sign = 0x8000
+	}
+	expMask := uint16(1)<<expBits - 1
+	infBits := sign | expMask<<mantBits
+	switch {
+	case math.IsNaN(f):
+		return infBits | 1<<(mantBits-1)
+	case math.IsInf(f, 0):
+		return infBits
+	case f == 0:
+		return sign
+	}
+	bias := int(expMask >> 1)
+	a := math.Abs(f)
+	_, e := math.Frexp(a)
+	e--
+	emin := 1 - bias
+	if e < emin {
+		e = emin
+	}
+	if e > bias+1 {
+		return infBits
+	}
+	q := math.RoundToEven(math.Ldexp(a, int(mantBits)-e))
+	n := uint64(q)
+	var packed uint64
+	if n < 1<<mantBits {
+		packed = n
+	} else {
+		packed = uint64(e+bias)<<mantBits + (n - 1<<mantBits)
+	}
+	if packed >= uint64(expMask)<<mantBits {
+		return infBits
+	}
+	return sign | uint16(packed)
+}
@@ -0,0 +1,183 @@
+package RUNK
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestFloat16RoundTripsEveryPattern(t *testing.T) {
+	for b := 0; b < 1<<16; b++ {
+		h := Float16FromBits(uint16(b))
+		back := NewFloat16(h.Float32())
+		if h.IsNaN() {
+			if !back.IsNaN() || back.Signbit() != h.Signbit() {
+				t.Fatalf("NaN %#04x came back as %#04x", b, back.Bits())
+			}
+			continue
+		}
+		if back.Bits() != h.Bits() {
+			t.Fatalf("Float16 %#04x came back as %#04x", b, back.Bits())
+		}
+		g := BFloat16FromBits(uint16(b))
+		if !g.IsNaN() && NewBFloat16(g.Float32()).Bits() != g.Bits() {
+			t.Fatalf("BFloat16 %#04x did not round trip", b)
+		}
+	}
+}
+
+func TestFloat16RoundsToNearestEven(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	for i := 0; i < 100000; i++ {
+		x := math.Float32frombits(rng.Uint32())
+		if x != x || math.Abs(float64(x)) > float64(MaxFloat16) {
+			continue
+		}
+		h := NewFloat16(x)
+		got := float64(h.Float32())
+		// the neighbours of h are one bit pattern either side of it, neither may be closer to x
+		for _, d := range []int{-1, 1} {
+			n := Float16FromBits(uint16(int(h.Bits()) + d))
+			if n.IsNaN() || n.IsInf(0) || n.Signbit() != h.Signbit() {
+				continue
+			}
+			e, ne := math.Abs(got-float64(x)), math.Abs(float64(n.Float32())-float64(x))
+			if ne < e || (ne == e && h.Bits()&1 == 1) {
+				t.Fatalf("NewFloat16(%v) = %v but %v is nearer or even", x, h, n)
+			}
+		}
+	}
+	for i := 0; i < 100000; i++ {
+		b := rng.Uint32()
+		x := math.Float32frombits(b)
+		if x != x {
+			continue
+		}
+		want := uint16((b + 0x7fff + (b>>16)&1) >> 16)
+		if got := NewBFloat16(x).Bits(); got != want && !math.IsInf(float64(NewBFloat16(x).Float32()), 0) {
+			t.Fatalf("NewBFloat16(%v) = %#04x, want %#04x", x, got, want)
+		}
+	}
+}
+
+func TestFloat16Values(t *testing.T) {
+	tests := []struct {
+		in   float64
+		bits uint16
+	}{
+		{1, 0x3c00},
+		{-2, 0xc000},
+		{65504, 0x7bff},
+		{65519.99, 0x7bff},
+		{65520, 0x7c00},
+		{1e10, 0x7c00},
+		{math.Inf(-1), 0xfc00},
+		{1.0 / 3, 0x3555},
+		{0x1p-24, 0x0001},
+		{0x1p-25, 0x0000},
+		{0x1.8p-25, 0x0001},
+		{math.Copysign(0, -1), 0x8000},
+		{0x1p-14, 0x0400},
+	}
+	for _, tt := range tests {
+		if got := NewFloat16(tt.in).Bits(); got != tt.bits {
+			t.Errorf("NewFloat16(%v) = %#04x, want %#04x", tt.in, got, tt.bits)
+		}
+	}
+	if h := Float16FromBits(0x0001); !h.IsSubnormal() || h.Float32() != SmallestNonzeroFloat16 {
+		t.Errorf("smallest subnormal = %v", h)
+	}
+	if h := NewFloat16(math.NaN()); !h.IsNaN() || h.IsInf(0) {
+		t.Errorf("NaN = %#04x", h.Bits())
+	}
+	if !NewFloat16(1e9).IsInf(1) || NewFloat16(1e9).IsInf(-1) {
+		t.Error("IsInf sign is wrong")
+	}
+	if s := NewFloat16(0.1).String(); s != "0.099975586" {
+		t.Errorf("String(0.1) = %s", s)
+	}
+	if got := NewBFloat16(1e38).Float32(); got != 0x1.2cp126 {
+		t.Errorf("NewBFloat16(1e38) = %v", got)
+	}
+	if got := NewBFloat16(float64(MaxBFloat16) * 1.01); !got.IsInf(1) {
+		t.Errorf("past MaxBFloat16 = %v", got)
+	}
+}
+
+func TestFloat16FromIntegers(t *testing.T) {
+	if got := NewFloat16(int64(2049)).Float32(); got != 2048 {
+		t.Errorf("NewFloat16(2049) = %v, want the tie to go to even", got)
+	}
+	if got := NewFloat16(uint64(math.MaxUint64)); !got.IsInf(1) {
+		t.Errorf("NewFloat16(MaxUint64) = %v", got)
+	}
+	// 2^60 + 2^52 + 1 is just over the half way point, going through float64 first would lose the +1 and round down
+	n := uint64(1)<<60 | 1<<52 | 1
+	if got := NewBFloat16(n).Float32(); got != 0x1.02p60 {
+		t.Errorf("NewBFloat16(2^60+2^52+1) = %v, want %v", got, float32(0x1.02p60))
+	}
+	if got := NewBFloat16(-int64(n)).Float32(); got != -0x1.02p60 {
+		t.Errorf("NewBFloat16(-(2^60+2^52+1)) = %v", got)
+	}
+	if got := Float16To[int8](NewFloat16(300)); got != math.MaxInt8 {
+		t.Errorf("Float16To[int8](300) = %d", got)
+	}
+	if got := Float16To[int](NewFloat16(math.NaN())); got != 0 {
+		t.Errorf("Float16To[int](NaN) = %d", got)
+	}
+	if got := BFloat16To[uint16](NewBFloat16(-5)); got != 0 {
+		t.Errorf("BFloat16To[uint16](-5) = %d", got)
+	}
+}
+
+func TestFloat16Arithmetic(t *testing.T) {
+	a, b := NewFloat16(1), NewFloat16(0x1p-11)
+	if got := a.Add(b); got.Bits() != 0x3c00 {
+		t.Errorf("1 + 2^-11 = %v, want the tie to round to even", got)
+	}
+	if got := a.Add(b).Add(b); got.Bits() != 0x3c00 {
+		t.Errorf("(1 + 2^-11) + 2^-11 = %v", got)
+	}
+	if got := NewFloat16(60000).Mul(NewFloat16(2)); !got.IsInf(1) {
+		t.Errorf("60000 * 2 = %v", got)
+	}
+	if got := NewFloat16(1).Div(NewFloat16(3)).Bits(); got != 0x3555 {
+		t.Errorf("1/3 = %#04x", got)
+	}
+	if got := NewFloat16(0).Div(NewFloat16(0)); !got.IsNaN() {
+		t.Errorf("0/0 = %v", got)
+	}
+	if got := NewBFloat16(3).Sub(NewBFloat16(5)).Neg().Float32(); got != 2 {
+		t.Errorf("-(3 - 5) = %v", got)
+	}
+	if got := NewFloat16(0).Neg(); !got.Signbit() || !got.IsZero() {
+		t.Errorf("-0 = %#04x", got.Bits())
+	}
+}
+
+func TestFloat16Bulk(t *testing.T) {
+	src := []float32{1, -0.5, 65504, 1e6, float32(math.NaN())}
+	halves := make([]uint16, 4)
+	if n := Float32sToFloat16s(halves, src); n != 4 {
+		t.Errorf("converted %d, want the shorter length 4", n)
+	}
+	want := []uint16{0x3c00, 0xb800, 0x7bff, 0x7c00}
+	for i := range want {
+		if halves[i] != want[i] {
+			t.Errorf("half %d = %#04x, want %#04x", i, halves[i], want[i])
+		}
+	}
+	back := make([]float32, 8)
+	if n := Float16sToFloat32s(back, halves); n != 4 || back[1] != -0.5 || !math.IsInf(float64(back[3]), 1) {
+		t.Errorf("back = %v", back[:n])
+	}
+	brains := make([]uint16, len(src))
+	Float32sToBFloat16s(brains, src)
+	BFloat16sToFloat32s(back, brains)
+	if back[0] != 1 || back[1] != -0.5 || back[2] != 65536 || back[3] != 999424 || back[4] == back[4] {
+		t.Errorf("bfloat16 round trip = %v", back[:len(src)])
+	}
+	if n := Float32sToFloat16s(nil, src); n != 0 {
+		t.Errorf("converted %d into nil", n)
+	}
+}
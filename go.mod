@@ -1,7 +1,7 @@
-module main
+module RUNK
 
 go 1.21.5
 
 toolchain go1.21.10
 
-require github.com/Patrick-ring-motive/utils v0.0.0-20240818195207-cd3b4aa5c45f // indirect
+require github.com/Patrick-ring-motive/utils v0.0.0-20240818195207-cd3b4aa5c45f
@@ -0,0 +1,513 @@
+package RUNK
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+/*
+Uint128 and Int128 are 128 bit integers made from two uint64 halves. Int128 is two's complement so the bits of
+the halves are the same as a Uint128 and only comparison, division, right shift and the conversions care about the
+sign.
+The arithmetic wraps around just like the built in integer types do. Conversions to and from the Number types
+saturate like ConvertNumber instead, and dividing by zero saturates toward the sign of the dividend (0/0 is 0) the
+same way converting +-Inf and NaN would.
+*/
+type Uint128 struct {
+	Hi uint64
+	Lo uint64
+}
+
+type Int128 struct {
+	Hi int64
+	Lo uint64
+}
+
+var (
+	MaxUint128 = Uint128{Hi: math.MaxUint64, Lo: math.MaxUint64} // 340282366920938463463374607431768211455
+	MinUint128 = Uint128{}
+	MaxInt128  = Int128{Hi: math.MaxInt64, Lo: math.MaxUint64} // 170141183460469231731687303715884105727
+	MinInt128  = Int128{Hi: math.MinInt64}                     // -170141183460469231731687303715884105728
+)
+
+/*Int128s is either of the 128 bit types, for the queries that MinNum and MaxNum answer for the Number types*/
+type Int128s interface {
+	Int128 | Uint128
+}
+
+/*MinNum128 is MinInt128 or MinUint128. Like MinNum it can be called with a value to infer the type, MinNum128(x).*/
+func MinNum128[W Int128s](w ...W) W {
+	var n W
+	if _, ok := any(n).(Int128); ok {
+		return any(MinInt128).(W)
+	}
+	return n
+}
+
+/*MaxNum128 is MaxInt128 or MaxUint128*/
+func MaxNum128[W Int128s](w ...W) W {
+	var n W
+	if _, ok := any(n).(Int128); ok {
+		return any(MaxInt128).(W)
+	}
+	return any(MaxUint128).(W)
+}
+
+/*IsSigned128 reports whether W is Int128*/
+func IsSigned128[W Int128s](w ...W) bool {
+	var n W
+	_, ok := any(n).(Int128)
+	return ok
+}
+
+/*
+Uint128From converts any Number into a Uint128. Negative values become 0, NaN becomes 0, and values that are too big
+(including +Inf) become MaxUint128. Floats are rounded with roundMode, math.Round by default.
+*/
+func Uint128From[N Number](n N, roundMode ...RoundingMode) Uint128 {
+	if isFloat[N]() {
+		return uint128FromBig(floatToBig(float64(n), pickRoundingMode(roundMode)))
+	}
+	neg, mag := magnitude(n)
+	if neg {
+		return Uint128{}
+	}
+	return Uint128{Lo: mag}
+}
+
+/*Int128From converts any Number into an Int128, saturating at MinInt128 and MaxInt128*/
+func Int128From[N Number](n N, roundMode ...RoundingMode) Int128 {
+	if isFloat[N]() {
+		return int128FromBig(floatToBig(float64(n), pickRoundingMode(roundMode)))
+	}
+	neg, mag := magnitude(n)
+	if neg {
+		return Uint128{Lo: mag}.Neg().signed()
+	}
+	return Int128{Lo: mag}
+}
+
+/*Uint128To converts into any Number with the same saturation as ConvertNumber*/
+func Uint128To[To Number](u Uint128) To {
+	if u.Hi == 0 {
+		return ConvertNumber[To](u.Lo)
+	}
+	return bigIntTo[To](u.Big())
+}
+
+/*Int128To converts into any Number with the same saturation as ConvertNumber*/
+func Int128To[To Number](i Int128) To {
+	if i.IsInt64() {
+		return ConvertNumber[To](int64(i.Lo))
+	}
+	return bigIntTo[To](i.Big())
+}
+
+/*floatToBig rounds a float to a big.Int. NaN is 0 and +-Inf land just outside 128 bits so they saturate.*/
+func floatToBig(f float64, mode RoundingMode) *big.Int {
+	switch {
+	case math.IsNaN(f):
+		return new(big.Int)
+	case math.IsInf(f, 1):
+		return new(big.Int).Lsh(big.NewInt(1), 128)
+	case math.IsInf(f, -1):
+		return new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 128))
+	}
+	r := mode(f)
+	if math.IsNaN(r) || math.IsInf(r, 0) {
+		r = math.Round(f)
+	}
+	b, _ := big.NewFloat(r).Int(nil)
+	return b
+}
+
+var (
+	bigMaxUint128 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+	bigMaxInt128  = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 127), big.NewInt(1))
+	bigMinInt128  = new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 127))
+)
+
+func uint128FromBig(b *big.Int) Uint128 {
+	switch {
+	case b.Sign() < 0:
+		return Uint128{}
+	case b.Cmp(bigMaxUint128) > 0:
+		return MaxUint128
+	}
+	lo := new(big.Int).And(b, new(big.Int).SetUint64(math.MaxUint64))
+	return Uint128{Hi: new(big.Int).Rsh(b, 64).Uint64(), Lo: lo.Uint64()}
+}
+
+func int128FromBig(b *big.Int) Int128 {
+	switch {
+	case b.Cmp(bigMinInt128) < 0:
+		return MinInt128
+	case b.Cmp(bigMaxInt128) > 0:
+		return MaxInt128
+	case b.Sign() < 0:
+		return uint128FromBig(new(big.Int).Neg(b)).Neg().signed()
+	}
+	return uint128FromBig(b).signed()
+}
+
+func (u Uint128) signed() Int128 {
+	return Int128{Hi: int64(u.Hi), Lo: u.Lo}
+}
+
+func (i Int128) unsigned() Uint128 {
+	return Uint128{Hi: uint64(i.Hi), Lo: i.Lo}
+}
+
+/*Big returns the value as a big.Int*/
+func (u Uint128) Big() *big.Int {
+	b := new(big.Int).SetUint64(u.Hi)
+	b.Lsh(b, 64)
+	return b.Or(b, new(big.Int).SetUint64(u.Lo))
+}
+
+func (i Int128) Big() *big.Int {
+	if i.Hi >= 0 {
+		return i.unsigned().Big()
+	}
+	b := i.unsigned().Neg().Big()
+	return b.Neg(b)
+}
+
+/*IsUint64 reports whether u fits in a uint64*/
+func (u Uint128) IsUint64() bool {
+	return u.Hi == 0
+}
+
+/*IsInt64 reports whether i fits in an int64*/
+func (i Int128) IsInt64() bool {
+	return (i.Hi == 0 && i.Lo <= math.MaxInt64) || (i.Hi == -1 && i.Lo > math.MaxInt64)
+}
+
+func (u Uint128) Add(v Uint128) Uint128 {
+	lo, carry := bits.Add64(u.Lo, v.Lo, 0)
+	hi, _ := bits.Add64(u.Hi, v.Hi, carry)
+	return Uint128{Hi: hi, Lo: lo}
+}
+
+func (u Uint128) Sub(v Uint128) Uint128 {
+	lo, borrow := bits.Sub64(u.Lo, v.Lo, 0)
+	hi, _ := bits.Sub64(u.Hi, v.Hi, borrow)
+	return Uint128{Hi: hi, Lo: lo}
+}
+
+func (u Uint128) Mul(v Uint128) Uint128 {
+	hi, lo := bits.Mul64(u.Lo, v.Lo)
+	hi += u.Hi*v.Lo + u.Lo*v.Hi
+	return Uint128{Hi: hi, Lo: lo}
+}
+
+/*Neg is the two's complement negation, 0 - u*/
+func (u Uint128) Neg() Uint128 {
+	return Uint128{}.Sub(u)
+}
+
+/*QuoRem returns u/v and u%v. Dividing by zero gives MaxUint128 (or 0 for 0/0) and a remainder of 0.*/
+func (u Uint128) QuoRem(v Uint128) (Uint128, Uint128) {
+	if v.IsZero() {
+		if u.IsZero() {
+			return Uint128{}, Uint128{}
+		}
+		return MaxUint128, Uint128{}
+	}
+	if v.Hi == 0 {
+		if u.Hi < v.Lo {
+			q, r := bits.Div64(u.Hi, u.Lo, v.Lo)
+			return Uint128{Lo: q}, Uint128{Lo: r}
+		}
+		qhi, rhi := u.Hi/v.Lo, u.Hi%v.Lo
+		qlo, r := bits.Div64(rhi, u.Lo, v.Lo)
+		return Uint128{Hi: qhi, Lo: qlo}, Uint128{Lo: r}
+	}
+	// normalise so the top bit of the divisor is set, estimate the quotient from the top words and correct it
+	n := uint(bits.LeadingZeros64(v.Hi))
+	v1 := v.Lsh(n)
+	u1 := u.Rsh(1)
+	tq, _ := bits.Div64(u1.Hi, u1.Lo, v1.Hi)
+	tq >>= 63 - n
+	if tq != 0 {
+		tq--
+	}
+	q := Uint128{Lo: tq}
+	r := u.Sub(q.Mul(v))
+	if r.Cmp(v) >= 0 {
+		q = q.Add(Uint128{Lo: 1})
+		r = r.Sub(v)
+	}
+	return q, r
+}
+
+func (u Uint128) Div(v Uint128) Uint128 {
+	q, _ := u.QuoRem(v)
+	return q
+}
+
+func (u Uint128) Mod(v Uint128) Uint128 {
+	_, r := u.QuoRem(v)
+	return r
+}
+
+func (u Uint128) Lsh(n uint) Uint128 {
+	switch {
+	case n >= 128:
+		return Uint128{}
+	case n >= 64:
+		return Uint128{Hi: u.Lo << (n - 64)}
+	case n == 0:
+		return u
+	}
+	return Uint128{Hi: u.Hi<<n | u.Lo>>(64-n), Lo: u.Lo << n}
+}
+
+func (u Uint128) Rsh(n uint) Uint128 {
+	switch {
+	case n >= 128:
+		return Uint128{}
+	case n >= 64:
+		return Uint128{Lo: u.Hi >> (n - 64)}
+	case n == 0:
+		return u
+	}
+	return Uint128{Hi: u.Hi >> n, Lo: u.Lo>>n | u.Hi<<(64-n)}
+}
+
+func (u Uint128) And(v Uint128) Uint128 {
+	return Uint128{Hi: u.Hi & v.Hi, Lo: u.Lo & v.Lo}
+}
+
+func (u Uint128) Or(v Uint128) Uint128 {
+	return Uint128{Hi: u.Hi | v.Hi, Lo: u.Lo | v.Lo}
+}
+
+func (u Uint128) Xor(v Uint128) Uint128 {
+	return Uint128{Hi: u.Hi ^ v.Hi, Lo: u.Lo ^ v.Lo}
+}
+
+func (u Uint128) AndNot(v Uint128) Uint128 {
+	return Uint128{Hi: u.Hi &^ v.Hi, Lo: u.Lo &^ v.Lo}
+}
+
+func (u Uint128) Not() Uint128 {
+	return Uint128{Hi: ^u.Hi, Lo: ^u.Lo}
+}
+
+func (u Uint128) LeadingZeros() int {
+	if u.Hi != 0 {
+		return bits.LeadingZeros64(u.Hi)
+	}
+	return 64 + bits.LeadingZeros64(u.Lo)
+}
+
+func (u Uint128) TrailingZeros() int {
+	if u.Lo != 0 {
+		return bits.TrailingZeros64(u.Lo)
+	}
+	return 64 + bits.TrailingZeros64(u.Hi)
+}
+
+func (u Uint128) OnesCount() int {
+	return bits.OnesCount64(u.Hi) + bits.OnesCount64(u.Lo)
+}
+
+/*Len is the number of bits needed to represent u*/
+func (u Uint128) Len() int {
+	return 128 - u.LeadingZeros()
+}
+
+func (u Uint128) IsZero() bool {
+	return u.Hi == 0 && u.Lo == 0
+}
+
+/*Cmp returns -1, 0 or +1*/
+func (u Uint128) Cmp(v Uint128) int {
+	switch {
+	case u.Hi < v.Hi || (u.Hi == v.Hi && u.Lo < v.Lo):
+		return -1
+	case u == v:
+		return 0
+	}
+	return 1
+}
+
+func (i Int128) Add(j Int128) Int128 {
+	return i.unsigned().Add(j.unsigned()).signed()
+}
+
+func (i Int128) Sub(j Int128) Int128 {
+	return i.unsigned().Sub(j.unsigned()).signed()
+}
+
+func (i Int128) Mul(j Int128) Int128 {
+	return i.unsigned().Mul(j.unsigned()).signed()
+}
+
+/*Neg wraps for MinInt128 the same way -math.MinInt64 does*/
+func (i Int128) Neg() Int128 {
+	return i.unsigned().Neg().signed()
+}
+
+func (i Int128) Abs() Uint128 {
+	if i.Hi < 0 {
+		return i.unsigned().Neg()
+	}
+	return i.unsigned()
+}
+
+func (i Int128) Sign() int {
+	switch {
+	case i.Hi < 0:
+		return -1
+	case i.Hi == 0 && i.Lo == 0:
+		return 0
+	}
+	return 1
+}
+
+/*
+QuoRem truncates toward zero like Go's / and %. MinInt128 / -1 wraps back to MinInt128 like it does for int64.
+Dividing by zero saturates toward the sign of i with a remainder of 0.
+*/
+func (i Int128) QuoRem(j Int128) (Int128, Int128) {
+	if j.Sign() == 0 {
+		switch i.Sign() {
+		case 1:
+			return MaxInt128, Int128{}
+		case -1:
+			return MinInt128, Int128{}
+		}
+		return Int128{}, Int128{}
+	}
+	q, r := i.Abs().QuoRem(j.Abs())
+	if (i.Hi < 0) != (j.Hi < 0) {
+		q = q.Neg()
+	}
+	if i.Hi < 0 {
+		r = r.Neg()
+	}
+	return q.signed(), r.signed()
+}
+
+func (i Int128) Div(j Int128) Int128 {
+	q, _ := i.QuoRem(j)
+	return q
+}
+
+func (i Int128) Mod(j Int128) Int128 {
+	_, r := i.QuoRem(j)
+	return r
+}
+
+func (i Int128) Lsh(n uint) Int128 {
+	return i.unsigned().Lsh(n).signed()
+}
+
+/*Rsh is an arithmetic shift, the sign bit is copied in from the left*/
+func (i Int128) Rsh(n uint) Int128 {
+	if i.Hi >= 0 {
+		return i.unsigned().Rsh(n).signed()
+	}
+	return i.unsigned().Not().Rsh(n).Not().signed()
+}
+
+func (i Int128) And(j Int128) Int128 {
+	return i.unsigned().And(j.unsigned()).signed()
+}
+
+func (i Int128) Or(j Int128) Int128 {
+	return i.unsigned().Or(j.unsigned()).signed()
+}
+
+func (i Int128) Xor(j Int128) Int128 {
+	return i.unsigned().Xor(j.unsigned()).signed()
+}
+
+func (i Int128) AndNot(j Int128) Int128 {
+	return i.unsigned().AndNot(j.unsigned()).signed()
+}
+
+func (i Int128) Not() Int128 {
+	return i.unsigned().Not().signed()
+}
+
+func (i Int128) IsZero() bool {
+	return i.Hi == 0 && i.Lo == 0
+}
+
+/*Cmp returns -1, 0 or +1*/
+func (i Int128) Cmp(j Int128) int {
+	switch {
+	case i.Hi < j.Hi || (i.Hi == j.Hi && i.Lo < j.Lo):
+		return -1
+	case i == j:
+		return 0
+	}
+	return 1
+}
+
+func (u Uint128) String() string {
+	return u.Text(10)
+}
+
+/*Text formats u in any base from 2 to 62 like big.Int.Text*/
+func (u Uint128) Text(base int) string {
+	if u.Hi == 0 && base >= 2 && base <= 36 {
+		return strconv.FormatUint(u.Lo, base)
+	}
+	return u.Big().Text(base)
+}
+
+func (i Int128) String() string {
+	return i.Text(10)
+}
+
+func (i Int128) Text(base int) string {
+	if i.IsInt64() && base >= 2 && base <= 36 {
+		return strconv.FormatInt(int64(i.Lo), base)
+	}
+	return i.Big().Text(base)
+}
+
+/*
+ParseUint128 reads s in the given base (2 to 62, or 0 to go by a 0x/0o/0b prefix like big.Int.SetString).
+Values out of range come back saturated along with ErrOverflow, the same way strconv hands back the max with
+ErrRange.
+*/
+func ParseUint128(s string, base int) (Uint128, error) {
+	b, err := parseBig128(s, base)
+	if err != nil {
+		return Uint128{}, err
+	}
+	u := uint128FromBig(b)
+	if b.Sign() < 0 || b.Cmp(bigMaxUint128) > 0 {
+		return u, ErrOverflow
+	}
+	return u, nil
+}
+
+func ParseInt128(s string, base int) (Int128, error) {
+	b, err := parseBig128(s, base)
+	if err != nil {
+		return Int128{}, err
+	}
+	i := int128FromBig(b)
+	if b.Cmp(bigMinInt128) < 0 || b.Cmp(bigMaxInt128) > 0 {
+		return i, ErrOverflow
+	}
+	return i, nil
+}
+
+func parseBig128(s string, base int) (*big.Int, error) {
+	b, ok := new(big.Int).SetString(strings.TrimSpace(s), base)
+	if !ok {
+		return nil, errors.New("RUNK: invalid integer " + strconv.Quote(s) + " in base " + strconv.Itoa(base))
+	}
+	return b, nil
+}
@@ -0,0 +1,232 @@
+package RUNK
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+var two128 = new(big.Int).Lsh(big.NewInt(1), 128)
+
+/*wrapU and wrapI reduce an exact result to 128 bits the way the types wrap*/
+func wrapU(b *big.Int) *big.Int {
+	return new(big.Int).Mod(b, two128)
+}
+
+func wrapI(b *big.Int) *big.Int {
+	r := wrapU(b)
+	if r.Cmp(bigMaxInt128) > 0 {
+		r.Sub(r, two128)
+	}
+	return r
+}
+
+type check128[T any] struct {
+	name string
+	got  T
+	want *big.Int
+}
+
+func randUint128(rng *rand.Rand) Uint128 {
+	// mix in small and edge values so the single word paths get used too
+	switch rng.Intn(6) {
+	case 0:
+		return Uint128{Lo: rng.Uint64() >> uint(rng.Intn(64))}
+	case 1:
+		return Uint128{Hi: rng.Uint64() >> uint(rng.Intn(64)), Lo: rng.Uint64()}
+	case 2:
+		return MaxUint128.Rsh(uint(rng.Intn(128)))
+	}
+	return Uint128{Hi: rng.Uint64(), Lo: rng.Uint64()}
+}
+
+func TestUint128MatchesBig(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+	for i := 0; i < 20000; i++ {
+		u, v := randUint128(rng), randUint128(rng)
+		bu, bv := u.Big(), v.Big()
+		n := uint(rng.Intn(140))
+		checks := []check128[Uint128]{
+			{"add", u.Add(v), wrapU(new(big.Int).Add(bu, bv))},
+			{"sub", u.Sub(v), wrapU(new(big.Int).Sub(bu, bv))},
+			{"mul", u.Mul(v), wrapU(new(big.Int).Mul(bu, bv))},
+			{"neg", u.Neg(), wrapU(new(big.Int).Neg(bu))},
+			{"lsh", u.Lsh(n), wrapU(new(big.Int).Lsh(bu, n))},
+			{"rsh", u.Rsh(n), new(big.Int).Rsh(bu, n)},
+			{"and", u.And(v), new(big.Int).And(bu, bv)},
+			{"or", u.Or(v), new(big.Int).Or(bu, bv)},
+			{"xor", u.Xor(v), new(big.Int).Xor(bu, bv)},
+			{"andnot", u.AndNot(v), new(big.Int).AndNot(bu, bv)},
+		}
+		if !v.IsZero() {
+			q, r := new(big.Int).QuoRem(bu, bv, new(big.Int))
+			gq, gr := u.QuoRem(v)
+			checks = append(checks, check128[Uint128]{"quo", gq, q}, check128[Uint128]{"rem", gr, r})
+		}
+		for _, c := range checks {
+			if c.got.Big().Cmp(c.want) != 0 {
+				t.Fatalf("%s of %s and %s (n=%d) = %s, want %s", c.name, u, v, n, c.got, c.want)
+			}
+		}
+		if u.Cmp(v) != bu.Cmp(bv) {
+			t.Fatalf("Cmp(%s, %s) = %d", u, v, u.Cmp(v))
+		}
+		if u.Len() != bu.BitLen() || u.OnesCount() != onesBig(bu) {
+			t.Fatalf("bit counts of %s are wrong", u)
+		}
+	}
+}
+
+func onesBig(b *big.Int) int {
+	n := 0
+	for i := 0; i < b.BitLen(); i++ {
+		n += int(b.Bit(i))
+	}
+	return n
+}
+
+func TestInt128MatchesBig(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	for i := 0; i < 20000; i++ {
+		x, y := randUint128(rng).signed(), randUint128(rng).signed()
+		bx, by := x.Big(), y.Big()
+		n := uint(rng.Intn(140))
+		checks := []check128[Int128]{
+			{"add", x.Add(y), wrapI(new(big.Int).Add(bx, by))},
+			{"sub", x.Sub(y), wrapI(new(big.Int).Sub(bx, by))},
+			{"mul", x.Mul(y), wrapI(new(big.Int).Mul(bx, by))},
+			{"neg", x.Neg(), wrapI(new(big.Int).Neg(bx))},
+			{"lsh", x.Lsh(n), wrapI(new(big.Int).Lsh(bx, n))},
+			// big.Int.Rsh rounds toward -Inf which is exactly an arithmetic shift
+			{"rsh", x.Rsh(n), new(big.Int).Rsh(bx, n)},
+			{"and", x.And(y), new(big.Int).And(bx, by)},
+			{"xor", x.Xor(y), new(big.Int).Xor(bx, by)},
+		}
+		if y.Sign() != 0 {
+			// big.Int.QuoRem truncates toward zero like Go does
+			q, r := new(big.Int).QuoRem(bx, by, new(big.Int))
+			checks = append(checks, check128[Int128]{"quo", x.Div(y), wrapI(q)}, check128[Int128]{"rem", x.Mod(y), r})
+		}
+		for _, c := range checks {
+			if c.got.Big().Cmp(c.want) != 0 {
+				t.Fatalf("%s of %s and %s (n=%d) = %s, want %s", c.name, x, y, n, c.got, c.want)
+			}
+		}
+		if x.Cmp(y) != bx.Cmp(by) || x.Sign() != bx.Sign() {
+			t.Fatalf("Cmp or Sign of %s and %s is wrong", x, y)
+		}
+	}
+}
+
+func TestInt128EdgeCases(t *testing.T) {
+	if q := MinInt128.Div(Int128From(-1)); q != MinInt128 {
+		t.Errorf("MinInt128 / -1 = %s, want it to wrap like int64", q)
+	}
+	if q, r := Int128From(5).QuoRem(Int128{}); q != MaxInt128 || !r.IsZero() {
+		t.Errorf("5 / 0 = %s r %s", q, r)
+	}
+	if q := Int128From(-5).Div(Int128{}); q != MinInt128 {
+		t.Errorf("-5 / 0 = %s", q)
+	}
+	if q := (Int128{}).Div(Int128{}); !q.IsZero() {
+		t.Errorf("0 / 0 = %s", q)
+	}
+	if q := Uint128From(7).Div(Uint128{}); q != MaxUint128 {
+		t.Errorf("unsigned 7 / 0 = %s", q)
+	}
+	if a := MinInt128.Abs(); a.String() != "170141183460469231731687303715884105728" {
+		t.Errorf("Abs(MinInt128) = %s", a)
+	}
+	if got := MaxUint128.Add(Uint128From(1)); !got.IsZero() {
+		t.Errorf("MaxUint128 + 1 = %s", got)
+	}
+	if z := (Uint128{}); z.LeadingZeros() != 128 || z.TrailingZeros() != 128 || z.Len() != 0 {
+		t.Error("bit counts of 0 are wrong")
+	}
+}
+
+func TestInt128Convert(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"uint64 max", Uint128From(uint64(math.MaxUint64)).String(), "18446744073709551615"},
+		{"negative to unsigned", Uint128From(-3).String(), "0"},
+		{"NaN", Int128From(math.NaN()).String(), "0"},
+		{"+Inf", Int128From(math.Inf(1)).String(), MaxInt128.String()},
+		{"-Inf", Int128From(math.Inf(-1)).String(), MinInt128.String()},
+		{"1e38", Uint128From(1e38).String(), "99999999999999997748809823456034029568"},
+		{"1e39 saturates", Uint128From(1e39).String(), MaxUint128.String()},
+		{"float rounding", Int128From(-2.5).String(), "-3"},
+		{"float floor", Int128From(2.7, math.Floor).String(), "2"},
+		{"MinInt64", Int128From(int64(math.MinInt64)).String(), "-9223372036854775808"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %s, want %s", tt.name, tt.got, tt.want)
+		}
+	}
+	if got := Int128To[int64](MaxInt128); got != math.MaxInt64 {
+		t.Errorf("Int128To[int64](MaxInt128) = %d", got)
+	}
+	if got := Int128To[uint8](Int128From(-1)); got != 0 {
+		t.Errorf("Int128To[uint8](-1) = %d", got)
+	}
+	if got := Uint128To[float64](MaxUint128); got != 0x1p128 {
+		t.Errorf("Uint128To[float64](MaxUint128) = %v", got)
+	}
+	if got := Uint128To[float32](MaxUint128); !math.IsInf(float64(got), 1) {
+		t.Errorf("Uint128To[float32](MaxUint128) = %v", got)
+	}
+	if got := Uint128To[int](Uint128From(42)); got != 42 {
+		t.Errorf("Uint128To[int](42) = %d", got)
+	}
+}
+
+func TestInt128Parse(t *testing.T) {
+	u, err := ParseUint128("0xffffffffffffffffffffffffffffffff", 0)
+	if err != nil || u != MaxUint128 {
+		t.Errorf("parse max hex = %s %v", u, err)
+	}
+	if got := u.Text(16); got != "ffffffffffffffffffffffffffffffff" {
+		t.Errorf("Text(16) = %s", got)
+	}
+	if _, err := ParseUint128("340282366920938463463374607431768211456", 10); err != ErrOverflow {
+		t.Errorf("2^128 = %v, want ErrOverflow", err)
+	}
+	i, err := ParseInt128("-170141183460469231731687303715884105729", 10)
+	if err != ErrOverflow || i != MinInt128 {
+		t.Errorf("below MinInt128 = %s %v", i, err)
+	}
+	i, err = ParseInt128(" -zz ", 36)
+	if err != nil || i.String() != "-1295" || i.Text(36) != "-zz" {
+		t.Errorf("parse base 36 = %s %v", i, err)
+	}
+	if _, err := ParseInt128("12x", 10); err == nil {
+		t.Error("ParseInt128 accepted 12x")
+	}
+	if got := MaxInt128.Text(62); got == "" {
+		t.Error("Text(62) is empty")
+	}
+}
+
+func TestInt128Queries(t *testing.T) {
+	if MinNum128[Int128]() != MinInt128 || MaxNum128[Int128]() != MaxInt128 {
+		t.Error("Int128 limits are wrong")
+	}
+	if MinNum128[Uint128]() != MinUint128 || MaxNum128[Uint128]() != MaxUint128 {
+		t.Error("Uint128 limits are wrong")
+	}
+	x := Uint128From(5)
+	if MaxNum128(x) != MaxUint128 || IsSigned128(x) {
+		t.Error("inferring the type from a value doesn't work")
+	}
+	if !IsSigned128[Int128]() || IsSigned128[Uint128]() {
+		t.Error("IsSigned128 is wrong")
+	}
+	if MaxNum128[Int128]().Big().Cmp(bigMaxInt128) != 0 || MinNum128[Int128]().Big().Cmp(bigMinInt128) != 0 {
+		t.Error("the limits don't match their big.Int values")
+	}
+}
@@ -0,0 +1,390 @@
+package RUNK
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+var ErrUnsorted = errors.New("RUNK: x values are not strictly increasing")
+
+/*
+Lerp is a + t*(b-a). It gives exactly a at t = 0 and exactly b at t = 1 and t outside [0, 1] extrapolates.
+For integers the difference is worked out exactly and only the step t*(b-a) is rounded (math.Round by default),
+so Lerp[uint8](200, 250, 0.5) is 225 with no overflow in between and results past the range saturate.
+*/
+func Lerp[N Number](a N, b N, t float64, roundMode ...RoundingMode) N {
+	switch {
+	case t == 0:
+		return a
+	case t == 1:
+		return b
+	case isFloat[N]():
+		return N(lerpFloat(float64(a), float64(b), t))
+	}
+	d := wideOf(b).add(negWide(wideOf(a)))
+	step := t * wideTo[float64](d, Saturate, false)
+	if step != step {
+		return 0
+	}
+	return wideTo[N](wideOf(a).add(wideFromFloat(pickRoundingMode(roundMode)(step))), Saturate, false)
+}
+
+/*
+lerpFloat is exact at both ends and monotone in t. Working from the nearer end does that, and when b-a
+overflows the weighted form is used instead.
+*/
+func lerpFloat(a float64, b float64, t float64) float64 {
+	d := b - a
+	switch {
+	case math.IsInf(d, 0) && !math.IsInf(a, 0) && !math.IsInf(b, 0):
+		return a*(1-t) + b*t
+	case t < 0.5:
+		return a + t*d
+	}
+	return b - (1-t)*d
+}
+
+func negWide(a wide) wide {
+	a.neg = !a.neg && !a.isZero()
+	return a
+}
+
+/*wideFromFloat takes a whole number float into a wide, saturating past 128 bits*/
+func wideFromFloat(f float64) wide {
+	neg := f < 0
+	f = math.Abs(f)
+	if f >= 0x1p128 {
+		return wide{neg: neg, hi: math.MaxUint64, lo: math.MaxUint64}
+	}
+	hi := math.Floor(f / 0x1p64)
+	w := wide{neg: neg, hi: uint64(hi), lo: uint64(f - hi*0x1p64)}
+	w.neg = w.neg && !w.isZero()
+	return w
+}
+
+/*
+InvLerp is the t that Lerp(a, b, t) would need to give v, (v-a)/(b-a). The differences are exact for integers so
+it is exactly 0 at a and exactly 1 at b. a == b has no answer and gives NaN.
+*/
+func InvLerp[N Number](a N, b N, v N) float64 {
+	if isFloat[N]() {
+		fa, fb, fv := float64(a), float64(b), float64(v)
+		switch {
+		case fa == fb:
+			return math.NaN()
+		case fv == fb:
+			return 1
+		case math.IsInf(fb-fa, 0) && !math.IsInf(fa, 0) && !math.IsInf(fb, 0):
+			return (fv/2 - fa/2) / (fb/2 - fa/2)
+		}
+		return (fv - fa) / (fb - fa)
+	}
+	num, den := wideOf(v).add(negWide(wideOf(a))), wideOf(b).add(negWide(wideOf(a)))
+	switch {
+	case den.isZero():
+		return math.NaN()
+	case num == den:
+		return 1
+	}
+	return wideTo[float64](num, Saturate, false) / wideTo[float64](den, Saturate, false)
+}
+
+/*
+Remap moves v from the range inLo..inHi to the range outLo..outHi, Lerp(outLo, outHi, InvLerp(inLo, inHi, v)). The
+ends map exactly, so Remap[uint8](1023, 0, 1023, 0, 255) is 255, and values outside extrapolate and saturate.
+*/
+func Remap[To Number, N Number](v N, inLo N, inHi N, outLo To, outHi To, roundMode ...RoundingMode) To {
+	t := InvLerp(inLo, inHi, v)
+	if t != t && !isFloat[To]() {
+		return 0
+	}
+	return Lerp(outLo, outHi, t, roundMode...)
+}
+
+/*
+Bilerp interpolates inside a cell from its corners, q00 at (0, 0), q10 at (1, 0), q01 at (0, 1) and q11 at (1, 1).
+The corners come back exactly and integer results are rounded once at the end.
+*/
+func Bilerp[N Number](q00 N, q10 N, q01 N, q11 N, x float64, y float64, roundMode ...RoundingMode) N {
+	f := lerpFloat(lerpFloat(float64(q00), float64(q10), x), lerpFloat(float64(q01), float64(q11), x), y)
+	if isFloat[N]() {
+		return N(f)
+	}
+	switch {
+	case x == 0 && y == 0:
+		return q00
+	case x == 1 && y == 0:
+		return q10
+	case x == 0 && y == 1:
+		return q01
+	case x == 1 && y == 1:
+		return q11
+	}
+	return ConvertNumberBy[N](f, roundMode...)
+}
+
+/*knots converts the x values to float64 and checks they go strictly up*/
+func knots[X Number](xs []X) ([]float64, error) {
+	if len(xs) == 0 {
+		return nil, ErrShape
+	}
+	fx := float64sOf(xs)
+	for i := 1; i < len(fx); i++ {
+		if !(fx[i] > fx[i-1]) {
+			return nil, ErrUnsorted
+		}
+	}
+	return fx, nil
+}
+
+/*segment finds the i with xs[i] <= x < xs[i+1], clipped to the first and last segment*/
+func segment(xs []float64, x float64) int {
+	i := sort.SearchFloat64s(xs, x)
+	if i < len(xs) && xs[i] == x {
+		return min(i, max(len(xs)-2, 0))
+	}
+	return max(0, min(i-1, len(xs)-2))
+}
+
+/*
+LinearTable is a piecewise linear lookup table, the usual sensor calibration or gamma curve. Inside the table it
+joins the points with straight lines (using Lerp so the points themselves are exact) and outside it holds the end
+values instead of extrapolating.
+*/
+type LinearTable[X Number, Y Number] struct {
+	xs []float64
+	ys []Y
+}
+
+/*NewLinearTable needs matching slices with the xs strictly increasing*/
+func NewLinearTable[X Number, Y Number](xs []X, ys []Y) (LinearTable[X, Y], error) {
+	if len(xs) != len(ys) {
+		return LinearTable[X, Y]{}, ErrShape
+	}
+	fx, err := knots(xs)
+	if err != nil {
+		return LinearTable[X, Y]{}, err
+	}
+	return LinearTable[X, Y]{xs: fx, ys: append([]Y(nil), ys...)}, nil
+}
+
+func (lt LinearTable[X, Y]) At(x X, roundMode ...RoundingMode) Y {
+	if len(lt.xs) == 0 {
+		return 0
+	}
+	f, last := float64(x), len(lt.xs)-1
+	switch {
+	case f <= lt.xs[0]:
+		return lt.ys[0]
+	case f >= lt.xs[last]:
+		return lt.ys[last]
+	}
+	i := segment(lt.xs, f)
+	return Lerp(lt.ys[i], lt.ys[i+1], (f-lt.xs[i])/(lt.xs[i+1]-lt.xs[i]), roundMode...)
+}
+
+/*
+Spline is a piecewise cubic through the points, kept as the values and slopes at each point (cubic Hermite form).
+NewNaturalSpline and NewClampedSpline give the usual C2 cubic spline with zero curvature or the given slopes at the
+ends. NewMonotoneSpline uses the Fritsch-Carlson slopes, which is only C1 but never overshoots, so monotone data
+gives a monotone curve. Past the ends the end cubics carry on like scipy does.
+*/
+type Spline[X Number, Y Number] struct {
+	xs []float64
+	ys []float64
+	m  []float64
+}
+
+func splineData[X Number, Y Number](xs []X, ys []Y) ([]float64, []float64, error) {
+	if len(xs) != len(ys) {
+		return nil, nil, ErrShape
+	}
+	fx, err := knots(xs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return fx, float64sOf(ys), nil
+}
+
+func NewNaturalSpline[X Number, Y Number](xs []X, ys []Y) (Spline[X, Y], error) {
+	fx, fy, err := splineData(xs, ys)
+	if err != nil {
+		return Spline[X, Y]{}, err
+	}
+	return Spline[X, Y]{xs: fx, ys: fy, m: splineSlopes(fx, fy, nil)}, nil
+}
+
+/*NewClampedSpline takes the slopes dy/dx wanted at the first and last point*/
+func NewClampedSpline[X Number, Y Number](xs []X, ys []Y, start float64, end float64) (Spline[X, Y], error) {
+	fx, fy, err := splineData(xs, ys)
+	if err != nil {
+		return Spline[X, Y]{}, err
+	}
+	return Spline[X, Y]{xs: fx, ys: fy, m: splineSlopes(fx, fy, []float64{start, end})}, nil
+}
+
+/*
+splineSlopes solves the tridiagonal system for the slopes of a C2 spline. The rows come from matching the second
+derivatives at the inner points, the end rows are either zero curvature or the clamped slopes.
+*/
+func splineSlopes(x []float64, y []float64, ends []float64) []float64 {
+	n := len(x) - 1
+	m := make([]float64, n+1)
+	if n == 0 {
+		return m
+	}
+	sub, diag, sup, rhs := make([]float64, n+1), make([]float64, n+1), make([]float64, n+1), make([]float64, n+1)
+	h := func(i int) float64 { return x[i+1] - x[i] }
+	delta := func(i int) float64 { return (y[i+1] - y[i]) / h(i) }
+	if ends == nil {
+		diag[0], sup[0], rhs[0] = 2, 1, 3*delta(0)
+		sub[n], diag[n], rhs[n] = 1, 2, 3*delta(n-1)
+	} else {
+		diag[0], rhs[0] = 1, ends[0]
+		diag[n], rhs[n] = 1, ends[1]
+	}
+	for i := 1; i < n; i++ {
+		sub[i], diag[i], sup[i] = h(i), 2*(h(i-1)+h(i)), h(i-1)
+		rhs[i] = 3 * (h(i)*delta(i-1) + h(i-1)*delta(i))
+	}
+	// Thomas algorithm, the system is diagonally dominant so no pivoting is needed
+	for i := 1; i <= n; i++ {
+		w := sub[i] / diag[i-1]
+		diag[i] -= w * sup[i-1]
+		rhs[i] -= w * rhs[i-1]
+	}
+	m[n] = rhs[n] / diag[n]
+	for i := n - 1; i >= 0; i-- {
+		m[i] = (rhs[i] - sup[i]*m[i+1]) / diag[i]
+	}
+	return m
+}
+
+func NewMonotoneSpline[X Number, Y Number](xs []X, ys []Y) (Spline[X, Y], error) {
+	fx, fy, err := splineData(xs, ys)
+	if err != nil {
+		return Spline[X, Y]{}, err
+	}
+	n := len(fx) - 1
+	m := make([]float64, n+1)
+	if n == 0 {
+		return Spline[X, Y]{xs: fx, ys: fy, m: m}, nil
+	}
+	delta := make([]float64, n)
+	for i := range delta {
+		delta[i] = (fy[i+1] - fy[i]) / (fx[i+1] - fx[i])
+	}
+	m[0], m[n] = delta[0], delta[n-1]
+	for i := 1; i < n; i++ {
+		if delta[i-1]*delta[i] > 0 {
+			m[i] = (delta[i-1] + delta[i]) / 2
+		}
+	}
+	for i, d := range delta {
+		if d == 0 {
+			m[i], m[i+1] = 0, 0
+			continue
+		}
+		a, b := m[i]/d, m[i+1]/d
+		if r := math.Hypot(a, b); r > 3 {
+			m[i], m[i+1] = 3*a/r*d, 3*b/r*d
+		}
+	}
+	return Spline[X, Y]{xs: fx, ys: fy, m: m}, nil
+}
+
+/*hermite returns the Hermite basis weights at t and their derivatives*/
+func hermite(t float64) (h00, h10, h01, h11, d00, d10, d01, d11 float64) {
+	t2, t3 := t*t, t*t*t
+	return 2*t3 - 3*t2 + 1, t3 - 2*t2 + t, -2*t3 + 3*t2, t3 - t2,
+		6*t2 - 6*t, 3*t2 - 4*t + 1, -6*t2 + 6*t, 3*t2 - 2*t
+}
+
+/*AtFloat is the spline value as a float64 without rounding into Y*/
+func (s Spline[X, Y]) AtFloat(x float64) float64 {
+	if len(s.xs) < 2 {
+		if len(s.ys) == 0 {
+			return 0
+		}
+		return s.ys[0]
+	}
+	i := segment(s.xs, x)
+	h := s.xs[i+1] - s.xs[i]
+	t := (x - s.xs[i]) / h
+	switch t {
+	case 0:
+		return s.ys[i]
+	case 1:
+		return s.ys[i+1]
+	}
+	// h00 + h01 = 1 so this is the usual Hermite sum, written so a flat piece stays exactly flat
+	_, h10, h01, h11, _, _, _, _ := hermite(t)
+	return s.ys[i] + h01*(s.ys[i+1]-s.ys[i]) + h*(h10*s.m[i]+h11*s.m[i+1])
+}
+
+/*At is the spline value converted into Y with ConvertNumberBy*/
+func (s Spline[X, Y]) At(x X, roundMode ...RoundingMode) Y {
+	f := s.AtFloat(float64(x))
+	if isFloat[Y]() {
+		return Y(f)
+	}
+	return ConvertNumberBy[Y](f, roundMode...)
+}
+
+/*Derivative is the slope dy/dx of the spline at x*/
+func (s Spline[X, Y]) Derivative(x X) float64 {
+	if len(s.xs) < 2 {
+		return 0
+	}
+	f := float64(x)
+	i := segment(s.xs, f)
+	h := s.xs[i+1] - s.xs[i]
+	_, _, _, _, d00, d10, d01, d11 := hermite((f - s.xs[i]) / h)
+	return (d00*s.ys[i]+d01*s.ys[i+1])/h + d10*s.m[i] + d11*s.m[i+1]
+}
+
+/*
+BilinearTable interpolates on a rectangular grid, zs.At(i, j) is the value at (xs[i], ys[j]). Like LinearTable it
+holds the edge values outside the grid.
+*/
+type BilinearTable[X Number, Z Number] struct {
+	xs []float64
+	ys []float64
+	zs Mat[Z]
+}
+
+func NewBilinearTable[X Number, Z Number](xs []X, ys []X, zs Mat[Z]) (BilinearTable[X, Z], error) {
+	if zs.Rows() != len(xs) || zs.Cols() != len(ys) {
+		return BilinearTable[X, Z]{}, ErrShape
+	}
+	fx, err := knots(xs)
+	if err != nil {
+		return BilinearTable[X, Z]{}, err
+	}
+	fy, err := knots(ys)
+	if err != nil {
+		return BilinearTable[X, Z]{}, err
+	}
+	return BilinearTable[X, Z]{xs: fx, ys: fy, zs: zs.Clone()}, nil
+}
+
+/*cell finds the cell x falls in and how far across it, clamped to the grid*/
+func cell(xs []float64, x float64) (int, float64) {
+	if len(xs) == 1 {
+		return 0, 0
+	}
+	i := segment(xs, x)
+	t := (x - xs[i]) / (xs[i+1] - xs[i])
+	return i, max(0, min(t, 1))
+}
+
+func (bt BilinearTable[X, Z]) At(x X, y X, roundMode ...RoundingMode) Z {
+	if len(bt.xs) == 0 {
+		return 0
+	}
+	i, tx := cell(bt.xs, float64(x))
+	j, ty := cell(bt.ys, float64(y))
+	i1, j1 := min(i+1, len(bt.xs)-1), min(j+1, len(bt.ys)-1)
+	return Bilerp(bt.zs.At(i, j), bt.zs.At(i1, j), bt.zs.At(i, j1), bt.zs.At(i1, j1), tx, ty, roundMode...)
+}
@@ -0,0 +1,286 @@
+package RUNK
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestLerpIntegers(t *testing.T) {
+	tests := []struct {
+		name string
+		got  int64
+		want int64
+	}{
+		{"uint8 middle", int64(Lerp[uint8](200, 250, 0.5)), 225},
+		{"uint8 step rounded", int64(Lerp[uint8](250, 200, 0.25)), 237}, // 250 + round(-12.5)
+		{"uint8 past the end", int64(Lerp[uint8](0, 200, 2)), 255},
+		{"uint8 before the start", int64(Lerp[uint8](10, 200, -1)), 0},
+		{"int8 full range", int64(Lerp[int8](-128, 127, 0.5)), 0},
+		{"int64 full range", Lerp[int64](math.MinInt64, math.MaxInt64, 0.5), 0},
+		{"int64 end", Lerp[int64](math.MinInt64, math.MaxInt64, 1), math.MaxInt64},
+		{"int64 start", Lerp[int64](math.MaxInt64, math.MinInt64, 0), math.MaxInt64},
+		{"floor", int64(Lerp[int](0, 3, 0.5, math.Floor)), 1},
+		{"NaN", int64(Lerp[int](5, 10, math.NaN())), 0},
+		{"Inf", int64(Lerp[int16](5, 10, math.Inf(1))), math.MaxInt16},
+		{"uint64 high", int64(Lerp[uint64](math.MaxUint64-10, math.MaxUint64, 0.5) - (math.MaxUint64 - 5)), 0},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %d, want %d", tt.name, tt.got, tt.want)
+		}
+	}
+	// every t in [0, 1] stays between the ends
+	rng := rand.New(rand.NewSource(24))
+	for trial := 0; trial < 10000; trial++ {
+		a, b := int16(rng.Intn(65536)-32768), int16(rng.Intn(65536)-32768)
+		v := Lerp(a, b, rng.Float64())
+		if v < min(a, b) || v > max(a, b) {
+			t.Fatalf("Lerp(%d, %d) = %d is outside", a, b, v)
+		}
+	}
+}
+
+func TestLerpFloats(t *testing.T) {
+	if got := Lerp(-math.MaxFloat64, math.MaxFloat64, 0.5); got != 0 {
+		t.Errorf("Lerp across the whole float range = %v", got)
+	}
+	if got := Lerp(-math.MaxFloat64, math.MaxFloat64, 0.75); math.Abs(got/(math.MaxFloat64/2)-1) > 1e-15 {
+		t.Errorf("Lerp at 0.75 across the range = %v", got)
+	}
+	if got := Lerp(0.1, 0.3, 1); got != 0.3 {
+		t.Errorf("Lerp at 1 = %v, want exactly b", got)
+	}
+	if got := Lerp(1.0, 2.0, math.NaN()); got == got {
+		t.Errorf("Lerp at NaN = %v", got)
+	}
+	if got := Lerp(float32(1), float32(3), 0.5); got != 2 {
+		t.Errorf("float32 Lerp = %v", got)
+	}
+	// monotone in t, which a + t*(b-a) alone is not near t = 1
+	rng := rand.New(rand.NewSource(25))
+	for trial := 0; trial < 200; trial++ {
+		a, b := rng.NormFloat64()*1e3, rng.NormFloat64()*1e-3
+		prev := Lerp(a, b, 0)
+		for t0 := 0.40; t0 <= 0.6; t0 += 0x1p-10 {
+			v := Lerp(a, b, t0)
+			if (a < b && v < prev) || (a > b && v > prev) {
+				t.Fatalf("Lerp(%v, %v) goes backwards at t = %v", a, b, t0)
+			}
+			prev = v
+		}
+	}
+}
+
+func TestInvLerpAndRemap(t *testing.T) {
+	tests := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"int start", InvLerp[int64](math.MinInt64, math.MaxInt64, math.MinInt64), 0},
+		{"int end", InvLerp[int64](math.MinInt64, math.MaxInt64, math.MaxInt64), 1},
+		{"uint8 middle", InvLerp[uint8](0, 200, 50), 0.25},
+		{"uint8 below", InvLerp[uint8](100, 200, 0), -1},
+		{"float end", InvLerp(0.1, 0.7, 0.7), 1},
+		{"float whole range", InvLerp(-math.MaxFloat64, math.MaxFloat64, 0), 0.5},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("InvLerp %s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+	if got := InvLerp(3, 3, 3); got == got {
+		t.Errorf("InvLerp with a == b = %v", got)
+	}
+	if got := InvLerp(2.0, 2.0, 1); got == got {
+		t.Errorf("float InvLerp with a == b = %v", got)
+	}
+	if got := Remap[uint8](1023, 0, 1023, 0, 255); got != 255 {
+		t.Errorf("Remap 10 bit to 8 bit = %d", got)
+	}
+	if got := Remap[uint8](512, 0, 1023, 0, 255); got != 128 {
+		t.Errorf("Remap of the middle = %d", got)
+	}
+	if got := Remap[int8](2000, 0, 1000, 0.0, 100.0); got != 127 {
+		t.Errorf("Remap past the end into int8 = %d", got)
+	}
+	if got := Remap(-1.0, 0, 1, 10.0, 20.0); got != 0 {
+		t.Errorf("Remap extrapolating = %v", got)
+	}
+	if got := Remap[int](5, 5, 5, 0, 10); got != 0 {
+		t.Errorf("Remap with an empty range = %d", got)
+	}
+	if got := Remap(5, 5, 5, 0.0, 10.0); got == got {
+		t.Errorf("float Remap with an empty range = %v", got)
+	}
+}
+
+func TestBilerp(t *testing.T) {
+	corners := [4]uint8{0, 255, 255, 0}
+	for _, c := range []struct {
+		x, y float64
+		want uint8
+	}{{0, 0, 0}, {1, 0, 255}, {0, 1, 255}, {1, 1, 0}, {0.5, 0.5, 128}, {0.5, 0, 128}, {2, 0, 255}} {
+		if got := Bilerp(corners[0], corners[1], corners[2], corners[3], c.x, c.y); got != c.want {
+			t.Errorf("Bilerp(%v, %v) = %d, want %d", c.x, c.y, got, c.want)
+		}
+	}
+	if got := Bilerp[int64](math.MaxInt64, 0, 0, 0, 0, 0); got != math.MaxInt64 {
+		t.Errorf("Bilerp corner = %d, want it exact even past float64", got)
+	}
+	if got := Bilerp(1.0, 2, 3, 4, 0.25, 0.5); got != 2.25 {
+		t.Errorf("float Bilerp = %v", got)
+	}
+}
+
+func TestLinearTable(t *testing.T) {
+	lt, err := NewLinearTable([]int{0, 10, 20}, []uint8{0, 200, 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		x    int
+		want uint8
+	}{{-5, 0}, {0, 0}, {5, 100}, {10, 200}, {15, 150}, {20, 100}, {99, 100}} {
+		if got := lt.At(c.x); got != c.want {
+			t.Errorf("At(%d) = %d, want %d", c.x, got, c.want)
+		}
+	}
+	if got := lt.At(3, math.Floor); got != 60 {
+		t.Errorf("At(3) floored = %d", got)
+	}
+	ft, _ := NewLinearTable([]float64{0, 1}, []float64{0, 1})
+	if got := ft.At(math.NaN()); got == got {
+		t.Errorf("At(NaN) = %v", got)
+	}
+	if _, err := NewLinearTable([]int{0, 0}, []int{1, 2}); err != ErrUnsorted {
+		t.Errorf("repeated x = %v", err)
+	}
+	if _, err := NewLinearTable([]float64{0, math.NaN()}, []int{1, 2}); err != ErrUnsorted {
+		t.Errorf("NaN x = %v", err)
+	}
+	if _, err := NewLinearTable([]int{0, 1}, []int{1}); err != ErrShape {
+		t.Errorf("short ys = %v", err)
+	}
+	if _, err := NewLinearTable([]int{}, []int{}); err != ErrShape {
+		t.Errorf("empty table = %v", err)
+	}
+	one, _ := NewLinearTable([]int{3}, []int{7})
+	if one.At(-100) != 7 || one.At(100) != 7 {
+		t.Error("one point table should be constant")
+	}
+	var zero LinearTable[int, int]
+	if zero.At(1) != 0 {
+		t.Error("zero table should give 0")
+	}
+}
+
+func TestSplines(t *testing.T) {
+	// a clamped spline with the right end slopes reproduces a cubic exactly
+	cubic := func(x float64) float64 { return x*x*x - 2*x*x + 0.5*x + 3 }
+	slope := func(x float64) float64 { return 3*x*x - 4*x + 0.5 }
+	xs := []float64{-2, -1.5, 0, 0.3, 1, 2.5, 4}
+	ys := make([]float64, len(xs))
+	for i, x := range xs {
+		ys[i] = cubic(x)
+	}
+	cl, err := NewClampedSpline(xs, ys, slope(-2), slope(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for x := -3.0; x <= 5; x += 0.1 {
+		if got := cl.AtFloat(x); math.Abs(got-cubic(x)) > 1e-10*math.Max(1, math.Abs(cubic(x))) {
+			t.Errorf("clamped spline at %v = %v, want %v", x, got, cubic(x))
+		}
+		if got := cl.Derivative(x); math.Abs(got-slope(x)) > 1e-9*math.Max(1, math.Abs(slope(x))) {
+			t.Errorf("clamped spline slope at %v = %v, want %v", x, got, slope(x))
+		}
+	}
+
+	// natural: through every point, C1 at the knots and zero curvature at the ends
+	nat, _ := NewNaturalSpline(xs, ys)
+	const h = 1e-6
+	curvature := func(x float64) float64 { return (nat.Derivative(x+h) - nat.Derivative(x-h)) / (2 * h) }
+	for i, x := range xs {
+		if nat.AtFloat(x) != ys[i] {
+			t.Errorf("natural spline misses point %d: %v", i, nat.AtFloat(x))
+		}
+		if l, r := nat.Derivative(x-1e-9), nat.Derivative(x+1e-9); math.Abs(l-r) > 1e-6 {
+			t.Errorf("natural spline slope jumps at %v: %v %v", x, l, r)
+		}
+	}
+	if c0, c1 := (nat.Derivative(xs[0]+h)-nat.Derivative(xs[0]))/h, curvature(xs[len(xs)-1]-h); math.Abs(c0) > 1e-4 || math.Abs(c1) > 1e-3 {
+		t.Errorf("natural spline end curvature = %v %v", c0, c1)
+	}
+
+	// monotone data with a cliff, the C2 spline overshoots and the monotone one must not
+	mx := []int{0, 1, 2, 3, 4, 5}
+	my := []uint8{0, 0, 0, 255, 255, 255}
+	mono, _ := NewMonotoneSpline(mx, my)
+	nat2, _ := NewNaturalSpline(mx, my)
+	prev, overshoot := -1.0, false
+	for x := 0.0; x <= 5; x += 0.01 {
+		v := mono.AtFloat(x)
+		if v < prev || v < 0 || v > 255 {
+			t.Fatalf("monotone spline at %v = %v after %v", x, v, prev)
+		}
+		prev = v
+		overshoot = overshoot || nat2.AtFloat(x) > 255
+	}
+	if !overshoot {
+		t.Error("the natural spline should overshoot this data, the test data is too tame")
+	}
+	if got := nat2.At(4); got != 255 {
+		t.Errorf("natural spline At(4) = %d", got)
+	}
+	if got := mono.At(2); got != 0 || mono.Derivative(1) != 0 {
+		t.Errorf("monotone spline on the flat part = %d slope %v", got, mono.Derivative(1))
+	}
+
+	one, _ := NewMonotoneSpline([]int{1}, []float64{4})
+	if one.AtFloat(-10) != 4 || one.Derivative(3) != 0 {
+		t.Error("one point spline should be constant")
+	}
+	if _, err := NewNaturalSpline([]int{2, 1}, []int{0, 0}); err != ErrUnsorted {
+		t.Errorf("unsorted spline = %v", err)
+	}
+	if _, err := NewClampedSpline([]int{1, 2}, []int{0}, 0, 0); err != ErrShape {
+		t.Errorf("mismatched spline = %v", err)
+	}
+	var zero Spline[int, int]
+	if zero.At(1) != 0 || zero.Derivative(1) != 0 {
+		t.Error("zero spline should give 0")
+	}
+}
+
+func TestBilinearTable(t *testing.T) {
+	zs, _ := MatFromRows([][]int16{{0, 100, 200}, {1000, 1100, 1200}})
+	bt, err := NewBilinearTable([]float64{0, 10}, []float64{0, 1, 2}, zs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		x, y float64
+		want int16
+	}{{0, 0, 0}, {10, 2, 1200}, {5, 0.5, 550}, {-5, -5, 0}, {50, 1.5, 1150}, {2.5, 9, 450}} {
+		if got := bt.At(c.x, c.y); got != c.want {
+			t.Errorf("At(%v, %v) = %d, want %d", c.x, c.y, got, c.want)
+		}
+	}
+	zs.Set(0, 0, 77)
+	if bt.At(0, 0) != 0 {
+		t.Error("the table should keep its own copy of zs")
+	}
+	if _, err := NewBilinearTable([]float64{0, 1}, []float64{0, 1}, zs); err != ErrShape {
+		t.Errorf("wrong grid shape = %v", err)
+	}
+	if _, err := NewBilinearTable([]float64{0, 1}, []float64{0, 2, 1}, zs); err != ErrUnsorted {
+		t.Errorf("unsorted ys = %v", err)
+	}
+	row, _ := MatFromRows([][]float32{{1, 3}})
+	line, _ := NewBilinearTable([]int{5}, []int{0, 2}, row)
+	if got := line.At(100, 1); got != 2 {
+		t.Errorf("single row grid = %v", got)
+	}
+}
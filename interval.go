@@ -0,0 +1,448 @@
+package RUNK
+
+import (
+	"math"
+	"math/big"
+)
+
+/*
+Interval is a closed range [Lo, Hi] that is guaranteed to contain the true result of every operation done on it.
+For the float types the bounds are rounded outward: the basic arithmetic uses error free transforms (TwoSum and FMA)
+to find out which way the float result was rounded and steps one ulp outward only when it needs to. The elementary
+functions in math are not correctly rounded so their bounds are padded by a few ulps instead.
+For the integer types the arithmetic is exact and the bounds are floored/ceiled and then saturate at MinNum/MaxNum.
+An operation that has no real result at all (Sqrt of an all negative interval) gives NaN bounds, which become 0
+for the integers the same way ConvertNumber treats NaN.
+*/
+type Interval[N Number] struct {
+	Lo N
+	Hi N
+}
+
+/*how many ulps the results of the std math functions get widened by*/
+const intervalPad = 4
+
+/*NewInterval makes [lo, hi], swapping them if they come in backwards*/
+func NewInterval[N Number](lo N, hi N) Interval[N] {
+	if hi < lo {
+		lo, hi = hi, lo
+	}
+	return Interval[N]{Lo: lo, Hi: hi}
+}
+
+/*PointInterval is the interval that contains only x*/
+func PointInterval[N Number](x N) Interval[N] {
+	return Interval[N]{Lo: x, Hi: x}
+}
+
+func (a Interval[N]) Contains(x N) bool {
+	return a.Lo <= x && x <= a.Hi
+}
+
+/*Width is Hi - Lo as a float64 so that it can't overflow the integer types*/
+func (a Interval[N]) Width() float64 {
+	if !isFloat[N]() {
+		w := new(big.Int).Sub(bigFromInt(a.Hi), bigFromInt(a.Lo))
+		f, _ := new(big.Float).SetMode(big.ToPositiveInf).SetInt(w).Float64()
+		return f
+	}
+	lo, hi := a.bounds()
+	return addUp(hi, -lo)
+}
+
+func (a Interval[N]) Add(b Interval[N]) Interval[N] {
+	if !isFloat[N]() {
+		return intervalFromBig[N](new(big.Int).Add(bigFromInt(a.Lo), bigFromInt(b.Lo)), new(big.Int).Add(bigFromInt(a.Hi), bigFromInt(b.Hi)))
+	}
+	alo, ahi := a.bounds()
+	blo, bhi := b.bounds()
+	return intervalFromFloats[N](addDown(alo, blo), addUp(ahi, bhi))
+}
+
+func (a Interval[N]) Sub(b Interval[N]) Interval[N] {
+	if !isFloat[N]() {
+		return intervalFromBig[N](new(big.Int).Sub(bigFromInt(a.Lo), bigFromInt(b.Hi)), new(big.Int).Sub(bigFromInt(a.Hi), bigFromInt(b.Lo)))
+	}
+	alo, ahi := a.bounds()
+	blo, bhi := b.bounds()
+	return intervalFromFloats[N](addDown(alo, -bhi), addUp(ahi, -blo))
+}
+
+func (a Interval[N]) Mul(b Interval[N]) Interval[N] {
+	if !isFloat[N]() {
+		lo, hi := bigMinMax(
+			new(big.Int).Mul(bigFromInt(a.Lo), bigFromInt(b.Lo)),
+			new(big.Int).Mul(bigFromInt(a.Lo), bigFromInt(b.Hi)),
+			new(big.Int).Mul(bigFromInt(a.Hi), bigFromInt(b.Lo)),
+			new(big.Int).Mul(bigFromInt(a.Hi), bigFromInt(b.Hi)),
+		)
+		return intervalFromBig[N](lo, hi)
+	}
+	alo, ahi := a.bounds()
+	blo, bhi := b.bounds()
+	lo := math.Min(math.Min(mulDown(alo, blo), mulDown(alo, bhi)), math.Min(mulDown(ahi, blo), mulDown(ahi, bhi)))
+	hi := math.Max(math.Max(mulUp(alo, blo), mulUp(alo, bhi)), math.Max(mulUp(ahi, blo), mulUp(ahi, bhi)))
+	return intervalFromFloats[N](lo, hi)
+}
+
+/*Div returns the whole number line when b contains 0 since the quotient is unbounded*/
+func (a Interval[N]) Div(b Interval[N]) Interval[N] {
+	if b.Contains(0) {
+		return intervalFromFloats[N](math.Inf(-1), math.Inf(1))
+	}
+	if !isFloat[N]() {
+		qs := []*big.Rat{
+			new(big.Rat).SetFrac(bigFromInt(a.Lo), bigFromInt(b.Lo)),
+			new(big.Rat).SetFrac(bigFromInt(a.Lo), bigFromInt(b.Hi)),
+			new(big.Rat).SetFrac(bigFromInt(a.Hi), bigFromInt(b.Lo)),
+			new(big.Rat).SetFrac(bigFromInt(a.Hi), bigFromInt(b.Hi)),
+		}
+		lo, hi := qs[0], qs[0]
+		for _, q := range qs[1:] {
+			if q.Cmp(lo) < 0 {
+				lo = q
+			}
+			if q.Cmp(hi) > 0 {
+				hi = q
+			}
+		}
+		return intervalFromBig[N](roundBigQuo(lo.Num(), lo.Denom(), math.Floor), roundBigQuo(hi.Num(), hi.Denom(), math.Ceil))
+	}
+	alo, ahi := a.bounds()
+	blo, bhi := b.bounds()
+	lo := math.Min(math.Min(divDown(alo, blo), divDown(alo, bhi)), math.Min(divDown(ahi, blo), divDown(ahi, bhi)))
+	hi := math.Max(math.Max(divUp(alo, blo), divUp(alo, bhi)), math.Max(divUp(ahi, blo), divUp(ahi, bhi)))
+	return intervalFromFloats[N](lo, hi)
+}
+
+/*Sqrt ignores the negative part of the interval since it has no real square root*/
+func (a Interval[N]) Sqrt() Interval[N] {
+	lo, hi := a.bounds()
+	if hi < 0 {
+		return intervalFromFloats[N](math.NaN(), math.NaN())
+	}
+	lo = math.Max(lo, 0)
+	return intervalFromFloats[N](sqrtDown(lo), sqrtUp(hi))
+}
+
+func (a Interval[N]) Exp() Interval[N] {
+	lo, hi := a.bounds()
+	return intervalFromFloats[N](math.Max(padDown(math.Exp(lo)), 0), padUp(math.Exp(hi)))
+}
+
+/*Log ignores the part of the interval at or below 0, if 0 is included the low bound is -Inf*/
+func (a Interval[N]) Log() Interval[N] {
+	lo, hi := a.bounds()
+	if hi < 0 {
+		return intervalFromFloats[N](math.NaN(), math.NaN())
+	}
+	return intervalFromFloats[N](padDown(math.Log(math.Max(lo, 0))), padUp(math.Log(hi)))
+}
+
+func (a Interval[N]) Sin() Interval[N] {
+	lo, hi := a.bounds()
+	return intervalFromFloats[N](periodicBounds(lo, hi, math.Sin, math.Pi/2))
+}
+
+func (a Interval[N]) Cos() Interval[N] {
+	lo, hi := a.bounds()
+	return intervalFromFloats[N](periodicBounds(lo, hi, math.Cos, 0))
+}
+
+/*
+Pow raises a to the powers in b. When b is a single integer the negative part of a is handled exactly like the
+scalar Pow would. Otherwise only the non-negative part of a is used since a negative base to a fractional power
+has no real result.
+*/
+func (a Interval[N]) Pow(b Interval[N]) Interval[N] {
+	lo, hi := a.bounds()
+	blo, bhi := b.bounds()
+	if blo == bhi && blo == math.Trunc(blo) && lo < 0 {
+		n := blo
+		switch {
+		case n == 0:
+			return intervalFromFloats[N](1, 1)
+		case math.Mod(n, 2) != 0 && n > 0:
+			return intervalFromFloats[N](padDown(math.Pow(lo, n)), padUp(math.Pow(hi, n)))
+		case n > 0:
+			top := padUp(math.Max(math.Pow(lo, n), math.Pow(hi, n)))
+			if hi >= 0 {
+				return intervalFromFloats[N](0, top)
+			}
+			return intervalFromFloats[N](padDown(math.Pow(hi, n)), top)
+		case hi >= 0:
+			return intervalFromFloats[N](math.Inf(-1), math.Inf(1))
+		case math.Mod(n, 2) != 0:
+			return intervalFromFloats[N](padDown(math.Pow(hi, n)), padUp(math.Pow(lo, n)))
+		}
+		return intervalFromFloats[N](padDown(math.Pow(lo, n)), padUp(math.Pow(hi, n)))
+	}
+	if hi < 0 {
+		return intervalFromFloats[N](math.NaN(), math.NaN())
+	}
+	lo = math.Max(lo, 0)
+	// x^y is monotone in each argument for x >= 0 so the corners hold the extremes
+	c := []float64{math.Pow(lo, blo), math.Pow(lo, bhi), math.Pow(hi, blo), math.Pow(hi, bhi)}
+	min, max := c[0], c[0]
+	for _, v := range c[1:] {
+		min = math.Min(min, v)
+		max = math.Max(max, v)
+	}
+	return intervalFromFloats[N](math.Max(padDown(min), 0), padUp(max))
+}
+
+/*
+bounds widens the interval into float64. The floats convert exactly, integers past 2^53 can round inward when
+converted so they get pushed out one more step.
+*/
+func (a Interval[N]) bounds() (float64, float64) {
+	lo, hi := float64(a.Lo), float64(a.Hi)
+	if !isFloat[N]() {
+		if math.Abs(lo) > 1<<53 {
+			lo = math.Nextafter(lo, math.Inf(-1))
+		}
+		if math.Abs(hi) > 1<<53 {
+			hi = math.Nextafter(hi, math.Inf(1))
+		}
+	}
+	return lo, hi
+}
+
+/*intervalFromFloats narrows a float64 interval into N, rounding the low bound down and the high bound up*/
+func intervalFromFloats[N Number](lo float64, hi float64) Interval[N] {
+	if isFloat[N]() {
+		l, h := N(lo), N(hi)
+		if float64(l) > lo {
+			l = N(math.Nextafter32(float32(l), float32(math.Inf(-1))))
+		}
+		if float64(h) < hi {
+			h = N(math.Nextafter32(float32(h), float32(math.Inf(1))))
+		}
+		return Interval[N]{Lo: l, Hi: h}
+	}
+	return Interval[N]{Lo: ConvertNumberBy[N](math.Floor(lo), math.Floor), Hi: ConvertNumberBy[N](math.Ceil(hi), math.Ceil)}
+}
+
+func intervalFromBig[N Number](lo *big.Int, hi *big.Int) Interval[N] {
+	return Interval[N]{Lo: bigIntTo[N](lo), Hi: bigIntTo[N](hi)}
+}
+
+func bigMinMax(vals ...*big.Int) (*big.Int, *big.Int) {
+	min, max := vals[0], vals[0]
+	for _, v := range vals[1:] {
+		if v.Cmp(min) < 0 {
+			min = v
+		}
+		if v.Cmp(max) > 0 {
+			max = v
+		}
+	}
+	return min, max
+}
+
+/*
+periodicBounds bounds fn over [lo, hi] where fn has a period of 2π, a peak at peak and a trough at peak+π.
+The test for whether a peak is inside is done on a slightly wider range so that rounding can only ever include an
+extreme that wasn't needed, never miss one.
+*/
+func periodicBounds(lo float64, hi float64, fn func(float64) float64, peak float64) (float64, float64) {
+	if math.IsNaN(lo) || math.IsNaN(hi) {
+		return math.NaN(), math.NaN()
+	}
+	if hi-lo >= 2*math.Pi || math.IsInf(lo, 0) || math.IsInf(hi, 0) {
+		return -1, 1
+	}
+	a, b := fn(lo), fn(hi)
+	min := math.Max(padDown(math.Min(a, b)), -1)
+	max := math.Min(padUp(math.Max(a, b)), 1)
+	wlo, whi := padDown(lo), padUp(hi)
+	has := func(at float64) bool {
+		k := math.Ceil((wlo - at) / (2 * math.Pi))
+		return at+2*math.Pi*k <= whi+1e-12*math.Max(1, math.Abs(whi))
+	}
+	if has(peak) {
+		max = 1
+	}
+	if has(peak + math.Pi) {
+		min = -1
+	}
+	return min, max
+}
+
+func padDown(x float64) float64 {
+	for i := 0; i < intervalPad; i++ {
+		x = math.Nextafter(x, math.Inf(-1))
+	}
+	return x
+}
+
+func padUp(x float64) float64 {
+	for i := 0; i < intervalPad; i++ {
+		x = math.Nextafter(x, math.Inf(1))
+	}
+	return x
+}
+
+/*
+The directed rounding helpers. Each one computes the nearest float result and then uses the exact error term to
+see which side of the true value it landed on.
+*/
+
+func roundedDown(r float64, err float64, finite bool) float64 {
+	if math.IsNaN(r) {
+		return r
+	}
+	if math.IsInf(r, 1) && finite {
+		return math.MaxFloat64
+	}
+	if err < 0 && !math.IsInf(r, 0) {
+		return math.Nextafter(r, math.Inf(-1))
+	}
+	return r
+}
+
+func roundedUp(r float64, err float64, finite bool) float64 {
+	if math.IsNaN(r) {
+		return r
+	}
+	if math.IsInf(r, -1) && finite {
+		return -math.MaxFloat64
+	}
+	if err > 0 && !math.IsInf(r, 0) {
+		return math.Nextafter(r, math.Inf(1))
+	}
+	return r
+}
+
+func finite(vals ...float64) bool {
+	for _, v := range vals {
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+underflowed reports a result whose residual may have been rounded. The exact residual needs about 104 bits below the
+leading bit of x, the product or the dividend, so once x is under 2^-969 it can fall off the subnormal range and a
+zero residual no longer means the result was exact. Then the bound has to step outward anyway, the sign of a zero
+result still says which side of zero the true value is on.
+*/
+func underflowed(x float64, err float64, nonzero bool) bool {
+	return err == 0 && nonzero && math.Abs(x) < 0x1p-969
+}
+
+func stepDown(r float64) float64 {
+	if r == 0 && !math.Signbit(r) {
+		return r
+	}
+	return math.Nextafter(r, math.Inf(-1))
+}
+
+func stepUp(r float64) float64 {
+	if r == 0 && math.Signbit(r) {
+		return r
+	}
+	return math.Nextafter(r, math.Inf(1))
+}
+
+/*twoSum returns a+b and the exact error of that sum*/
+func twoSum(a float64, b float64) (float64, float64) {
+	s := a + b
+	if math.IsInf(s, 0) || math.IsNaN(s) {
+		return s, 0
+	}
+	bb := s - a
+	return s, (a - (s - bb)) + (b - bb)
+}
+
+func addDown(a float64, b float64) float64 {
+	s, err := twoSum(a, b)
+	return roundedDown(s, err, finite(a, b))
+}
+
+func addUp(a float64, b float64) float64 {
+	s, err := twoSum(a, b)
+	return roundedUp(s, err, finite(a, b))
+}
+
+func mulErr(a float64, b float64) (float64, float64) {
+	p := a * b
+	if !finite(p) {
+		if math.IsNaN(p) {
+			// 0 * Inf, the only way to get here from a real interval is a zero bound
+			return 0, 0
+		}
+		return p, 0
+	}
+	return p, math.FMA(a, b, -p)
+}
+
+func mulDown(a float64, b float64) float64 {
+	p, err := mulErr(a, b)
+	if underflowed(p, err, a != 0 && b != 0) {
+		return stepDown(p)
+	}
+	return roundedDown(p, err, finite(a, b))
+}
+
+func mulUp(a float64, b float64) float64 {
+	p, err := mulErr(a, b)
+	if underflowed(p, err, a != 0 && b != 0) {
+		return stepUp(p)
+	}
+	return roundedUp(p, err, finite(a, b))
+}
+
+/*divErr uses a - q*b to find out if q is above or below a/b*/
+func divErr(a float64, b float64) (float64, float64) {
+	q := a / b
+	if !finite(q) || !finite(a, b) {
+		return q, 0
+	}
+	rem := math.FMA(-q, b, a)
+	if b < 0 {
+		rem = -rem
+	}
+	return q, rem
+}
+
+func divDown(a float64, b float64) float64 {
+	q, err := divErr(a, b)
+	if underflowed(a, err, a != 0 && finite(a, b)) {
+		return stepDown(q)
+	}
+	return roundedDown(q, err, finite(a, b))
+}
+
+func divUp(a float64, b float64) float64 {
+	q, err := divErr(a, b)
+	if underflowed(a, err, a != 0 && finite(a, b)) {
+		return stepUp(q)
+	}
+	return roundedUp(q, err, finite(a, b))
+}
+
+func sqrtErr(a float64) (float64, float64) {
+	if a > 0 && a < 0x1p-969 {
+		// scaled up the residual is exact again, and both scalings are exact
+		r, err := sqrtErr(a * 0x1p108)
+		return r * 0x1p-54, err
+	}
+	r := math.Sqrt(a)
+	if !finite(r) {
+		return r, 0
+	}
+	return r, math.FMA(-r, r, a)
+}
+
+func sqrtDown(a float64) float64 {
+	r, err := sqrtErr(a)
+	return roundedDown(r, err, true)
+}
+
+func sqrtUp(a float64) float64 {
+	r, err := sqrtErr(a)
+	return roundedUp(r, err, true)
+}
@@ -0,0 +1,191 @@
+package RUNK
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+/*containsExact checks the exact value r is inside the float64 interval a*/
+func containsExact(a Interval[float64], r *big.Float) bool {
+	lo, hi := new(big.Float).SetFloat64(a.Lo), new(big.Float).SetFloat64(a.Hi)
+	return lo.Cmp(r) <= 0 && r.Cmp(hi) <= 0
+}
+
+func TestIntervalArithmeticEncloses(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	prec := uint(2000)
+	for i := 0; i < 2000; i++ {
+		x := (rng.Float64() - 0.5) * math.Pow(2, float64(rng.Intn(80)-40))
+		y := (rng.Float64() - 0.5) * math.Pow(2, float64(rng.Intn(80)-40))
+		a, b := PointInterval(x), PointInterval(y)
+		bx, by := new(big.Float).SetPrec(prec).SetFloat64(x), new(big.Float).SetPrec(prec).SetFloat64(y)
+		checks := []struct {
+			name  string
+			got   Interval[float64]
+			exact *big.Float
+		}{
+			{"add", a.Add(b), new(big.Float).SetPrec(prec).Add(bx, by)},
+			{"sub", a.Sub(b), new(big.Float).SetPrec(prec).Sub(bx, by)},
+			{"mul", a.Mul(b), new(big.Float).SetPrec(prec).Mul(bx, by)},
+			{"div", a.Div(b), new(big.Float).SetPrec(prec).Quo(bx, by)},
+		}
+		for _, c := range checks {
+			if !containsExact(c.got, c.exact) {
+				t.Fatalf("%s of %v and %v = %v misses the exact result", c.name, x, y, c.got)
+			}
+			// outward rounding adds at most one ulp on each side
+			if c.got.Hi > math.Nextafter(math.Nextafter(c.got.Lo, math.Inf(1)), math.Inf(1)) {
+				t.Fatalf("%s of %v and %v = %v is wider than it needs to be", c.name, x, y, c.got)
+			}
+		}
+		if x > 0 {
+			s := a.Sqrt()
+			exact := new(big.Float).SetPrec(prec).Sqrt(bx)
+			if !containsExact(s, exact) {
+				t.Fatalf("Sqrt(%v) = %v misses", x, s)
+			}
+		}
+	}
+}
+
+func TestIntervalUnderflow(t *testing.T) {
+	prec := uint(4000)
+	exact := func(op string, x, y float64) *big.Float {
+		bx, by := new(big.Float).SetPrec(prec).SetFloat64(x), new(big.Float).SetPrec(prec).SetFloat64(y)
+		if op == "mul" {
+			return bx.Mul(bx, by)
+		}
+		return bx.Quo(bx, by)
+	}
+	cases := []struct {
+		op   string
+		x, y float64
+	}{
+		{"mul", 1e-200, 1e-200},
+		{"mul", -1e-200, 1e-200},
+		{"mul", 5e-324, 0.5},
+		{"mul", -5e-324, 0.75},
+		{"mul", 3e-310, 0.3},
+		{"mul", 0x1p-600, 0x1p-460},
+		{"div", 5e-324, 3},
+		{"div", 1e-300, -1e20},
+		{"div", 3e-310, 7},
+	}
+	rng := rand.New(rand.NewSource(33))
+	for i := 0; i < 2000; i++ {
+		x := (rng.Float64() - 0.5) * math.Pow(2, float64(-rng.Intn(600)))
+		y := (rng.Float64() - 0.5) * math.Pow(2, float64(-rng.Intn(600)-430))
+		cases = append(cases, struct {
+			op   string
+			x, y float64
+		}{"mul", x, y}, struct {
+			op   string
+			x, y float64
+		}{"div", y, 1 / x})
+	}
+	for _, c := range cases {
+		a, b := PointInterval(c.x), PointInterval(c.y)
+		got := a.Mul(b)
+		if c.op == "div" {
+			got = a.Div(b)
+		}
+		if !containsExact(got, exact(c.op, c.x, c.y)) {
+			t.Fatalf("%s of %v and %v = %v misses the exact result", c.op, c.x, c.y, got)
+		}
+	}
+	for _, x := range []float64{5e-324, 3e-310, 0x1p-1023 + 0x1p-1074, 2.2e-308} {
+		s := PointInterval(x).Sqrt()
+		if !containsExact(s, new(big.Float).SetPrec(prec).Sqrt(new(big.Float).SetPrec(prec).SetFloat64(x))) || s.Lo == s.Hi && x != 0x1p-1074 {
+			t.Errorf("Sqrt(%v) = %v", x, s)
+		}
+	}
+	// zero times anything is still exactly zero
+	if z := PointInterval(0.0).Mul(PointInterval(1e-300)); z.Lo != 0 || z.Hi != 0 {
+		t.Errorf("0 * 1e-300 = %v", z)
+	}
+}
+
+func TestIntervalExactResultsStayPoints(t *testing.T) {
+	a := PointInterval(1.5).Add(PointInterval(2.25))
+	if a.Lo != 3.75 || a.Hi != 3.75 {
+		t.Errorf("1.5 + 2.25 = %v, the sum is exact so it should not widen", a)
+	}
+	s := PointInterval(16.0).Sqrt()
+	if s.Lo != 4 || s.Hi != 4 {
+		t.Errorf("Sqrt(16) = %v", s)
+	}
+}
+
+func TestIntervalFloat32(t *testing.T) {
+	a := PointInterval(float32(0.1)).Add(PointInterval(float32(0.2)))
+	exact := float64(float32(0.1)) + float64(float32(0.2))
+	if float64(a.Lo) > exact || float64(a.Hi) < exact || a.Lo == a.Hi {
+		t.Errorf("float32 0.1 + 0.2 = %v does not enclose %v", a, exact)
+	}
+}
+
+func TestIntervalIntegers(t *testing.T) {
+	a := NewInterval[int8](100, 50)
+	if a.Lo != 50 || a.Hi != 100 {
+		t.Errorf("NewInterval did not swap: %v", a)
+	}
+	if got := a.Add(a); got.Lo != 100 || got.Hi != math.MaxInt8 {
+		t.Errorf("int8 add = %v, want saturated high bound", got)
+	}
+	if got := a.Mul(NewInterval[int8](-3, 2)); got.Lo != math.MinInt8 || got.Hi != math.MaxInt8 {
+		t.Errorf("int8 mul = %v", got)
+	}
+	if got := NewInterval(7, 8).Div(PointInterval(2)); got.Lo != 3 || got.Hi != 4 {
+		t.Errorf("[7, 8]/2 = %v, want floor and ceil of 3.5 and 4", got)
+	}
+	if got := NewInterval[uint](1, 2).Sub(NewInterval[uint](3, 4)); got.Lo != 0 || got.Hi != 0 {
+		t.Errorf("unsigned sub below zero = %v", got)
+	}
+	if got := NewInterval(2, 3).Sqrt(); got.Lo != 1 || got.Hi != 2 {
+		t.Errorf("int Sqrt([2, 3]) = %v", got)
+	}
+	if w := NewInterval[int64](math.MinInt64, math.MaxInt64).Width(); w < math.Exp2(64)-1 {
+		t.Errorf("Width overflowed: %v", w)
+	}
+}
+
+func TestIntervalSpecialCases(t *testing.T) {
+	d := PointInterval(1.0).Div(NewInterval(-1.0, 1.0))
+	if !math.IsInf(d.Lo, -1) || !math.IsInf(d.Hi, 1) {
+		t.Errorf("divide by an interval holding 0 = %v", d)
+	}
+	if s := NewInterval(-4.0, -1.0).Sqrt(); !math.IsNaN(s.Lo) || !math.IsNaN(s.Hi) {
+		t.Errorf("Sqrt of all negative = %v", s)
+	}
+	if s := NewInterval(-4, -1).Sqrt(); s.Lo != 0 || s.Hi != 0 {
+		t.Errorf("int Sqrt of all negative = %v, want NaN as 0", s)
+	}
+	if l := NewInterval(0.0, 1.0).Log(); !math.IsInf(l.Lo, -1) || l.Hi < 0 {
+		t.Errorf("Log([0, 1]) = %v", l)
+	}
+	s := NewInterval(0.0, 3.0).Sin()
+	if s.Hi != 1 || s.Lo > 0 || s.Lo < -1e-15 {
+		t.Errorf("Sin([0, 3]) = %v, want the peak at pi/2 as the high bound", s)
+	}
+	c := NewInterval(-1.0, 4.0).Cos()
+	if c.Lo != -1 || c.Hi != 1 {
+		t.Errorf("Cos([-1, 4]) = %v", c)
+	}
+	p := NewInterval(-3.0, 2.0).Pow(PointInterval(2.0))
+	if p.Lo != 0 || p.Hi < 9 || p.Hi > 9.000001 {
+		t.Errorf("[-3, 2]^2 = %v", p)
+	}
+	p = NewInterval(-3.0, -2.0).Pow(PointInterval(3.0))
+	if p.Lo > -27 || p.Hi < -8 || p.Hi > -7.99999 {
+		t.Errorf("[-3, -2]^3 = %v", p)
+	}
+	if p := NewInterval(-3.0, 2.0).Pow(PointInterval(-1.0)); !math.IsInf(p.Lo, -1) || !math.IsInf(p.Hi, 1) {
+		t.Errorf("[-3, 2]^-1 = %v", p)
+	}
+	e := NewInterval(0.0, 1.0).Exp()
+	if e.Lo > 1 || e.Hi < math.E {
+		t.Errorf("Exp([0, 1]) = %v", e)
+	}
+}
@@ -0,0 +1,682 @@
+package RUNK
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrShape               = errors.New("RUNK: shape mismatch")
+	ErrSingular            = errors.New("RUNK: singular matrix")
+	ErrNotPositiveDefinite = errors.New("RUNK: matrix is not positive definite")
+)
+
+/*
+Mat is a small dense matrix stored row by row. It is meant for the 3x3 and 6x6 sized problems of robotics and
+geometry code, not for big linear algebra.
+The arithmetic methods return a new matrix. When N is an integer type the math is done exactly (sums of products
+in 128 bits, or big integers past that) and then fitted into N with the overflow policy, Saturate by default like
+ConvertNumber. If any element didn't fit you still get the whole saturated (or wrapped) matrix back but along
+with ErrOverflow, the same way Int128 parsing does it. Floats are plain IEEE math, products are summed in float64.
+The decompositions (LU, QR, Cholesky) and Solve/Inverse only make sense for floats and work in float64 inside.
+Det works for every type and is exact for integers.
+A Mat shares its storage when copied, use Clone for an independent copy.
+*/
+type Mat[N Number] struct {
+	rows int
+	cols int
+	data []N
+}
+
+/*NewMat makes a rows x cols matrix from data in row order. nil data gives a zero matrix.*/
+func NewMat[N Number](rows int, cols int, data []N) (Mat[N], error) {
+	if rows < 0 || cols < 0 || (data != nil && len(data) != rows*cols) {
+		return Mat[N]{}, ErrShape
+	}
+	m := Mat[N]{rows: rows, cols: cols, data: make([]N, rows*cols)}
+	copy(m.data, data)
+	return m, nil
+}
+
+/*MatFromRows makes a matrix out of a slice of rows which all have to be the same length*/
+func MatFromRows[N Number](rows [][]N) (Mat[N], error) {
+	cols := 0
+	if len(rows) > 0 {
+		cols = len(rows[0])
+	}
+	m := Mat[N]{rows: len(rows), cols: cols, data: make([]N, 0, len(rows)*cols)}
+	for _, r := range rows {
+		if len(r) != cols {
+			return Mat[N]{}, ErrShape
+		}
+		m.data = append(m.data, r...)
+	}
+	return m, nil
+}
+
+func Identity[N Number](n int) Mat[N] {
+	n = max(n, 0)
+	m := Mat[N]{rows: n, cols: n, data: make([]N, n*n)}
+	for i := 0; i < n; i++ {
+		m.data[i*n+i] = 1
+	}
+	return m
+}
+
+/*MatFrom converts every element with ConvertSlice, so the ConvertNumber rules apply*/
+func MatFrom[To Number, N Number](m Mat[N], roundMode ...RoundingMode) Mat[To] {
+	out := Mat[To]{rows: m.rows, cols: m.cols, data: make([]To, len(m.data))}
+	ConvertSlice(out.data, m.data, roundMode...)
+	return out
+}
+
+func (m Mat[N]) Rows() int {
+	return m.rows
+}
+
+func (m Mat[N]) Cols() int {
+	return m.cols
+}
+
+/*At returns the element at row i and column j, 0 when that is out of range*/
+func (m Mat[N]) At(i int, j int) N {
+	if !m.inRange(i, j) {
+		return 0
+	}
+	return m.data[i*m.cols+j]
+}
+
+/*Set stores v at row i and column j and reports false if that is out of range*/
+func (m Mat[N]) Set(i int, j int, v N) bool {
+	if !m.inRange(i, j) {
+		return false
+	}
+	m.data[i*m.cols+j] = v
+	return true
+}
+
+func (m Mat[N]) inRange(i int, j int) bool {
+	return uint(i) < uint(m.rows) && uint(j) < uint(m.cols)
+}
+
+/*Row returns a copy of row i, nil when there is no such row*/
+func (m Mat[N]) Row(i int) []N {
+	if uint(i) >= uint(m.rows) {
+		return nil
+	}
+	return append([]N(nil), m.data[i*m.cols:(i+1)*m.cols]...)
+}
+
+/*Col returns a copy of column j, nil when there is no such column*/
+func (m Mat[N]) Col(j int) []N {
+	if uint(j) >= uint(m.cols) {
+		return nil
+	}
+	col := make([]N, m.rows)
+	for i := range col {
+		col[i] = m.At(i, j)
+	}
+	return col
+}
+
+/*Data returns a copy of the elements in row order*/
+func (m Mat[N]) Data() []N {
+	return append([]N(nil), m.data...)
+}
+
+func (m Mat[N]) Clone() Mat[N] {
+	return Mat[N]{rows: m.rows, cols: m.cols, data: m.Data()}
+}
+
+/*Equal compares shapes and elements with ==, so a NaN is never equal*/
+func (m Mat[N]) Equal(b Mat[N]) bool {
+	if m.rows != b.rows || m.cols != b.cols {
+		return false
+	}
+	for i := range m.data {
+		if m.data[i] != b.data[i] {
+			return false
+		}
+	}
+	return true
+}
+
+/*String prints the rows like [[1 2] [3 4]]*/
+func (m Mat[N]) String() string {
+	var sb strings.Builder
+	sb.WriteByte('[')
+	for i := 0; i < m.rows; i++ {
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+		sb.WriteByte('[')
+		for j := 0; j < m.cols; j++ {
+			if j > 0 {
+				sb.WriteByte(' ')
+			}
+			sb.WriteString(formatNumber(m.data[i*m.cols+j]))
+		}
+		sb.WriteByte(']')
+	}
+	sb.WriteByte(']')
+	return sb.String()
+}
+
+/*formatNumber prints any Number the shortest way that reads back the same*/
+func formatNumber[N Number](n N) string {
+	switch {
+	case isFloat[N]():
+		bitSize := 64
+		if huge := math.MaxFloat64; float64(N(huge)) != huge {
+			bitSize = 32
+		}
+		return strconv.FormatFloat(float64(n), 'g', -1, bitSize)
+	case isSigned[N]():
+		return strconv.FormatInt(int64(n), 10)
+	}
+	return strconv.FormatUint(uint64(n), 10)
+}
+
+/*T is the transpose*/
+func (m Mat[N]) T() Mat[N] {
+	t := Mat[N]{rows: m.cols, cols: m.rows, data: make([]N, len(m.data))}
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			t.data[j*t.cols+i] = m.data[i*m.cols+j]
+		}
+	}
+	return t
+}
+
+/*zip runs an element wise operation, exactly for integers and in N for floats*/
+func (m Mat[N]) zip(b Mat[N], overflow []OverflowPolicy, intOp func(x wide, y wide) wide,
+	floatOp func(x N, y N) N) (Mat[N], error) {
+	if m.rows != b.rows || m.cols != b.cols {
+		return Mat[N]{}, ErrShape
+	}
+	out := Mat[N]{rows: m.rows, cols: m.cols, data: make([]N, len(m.data))}
+	policy := pickOverflowPolicy(overflow)
+	var err error
+	for i := range out.data {
+		if isFloat[N]() {
+			out.data[i] = floatOp(m.data[i], b.data[i])
+			continue
+		}
+		out.data[i] = fitWide[N](intOp(wideOf(m.data[i]), wideOf(b.data[i])), policy, &err)
+	}
+	return out, err
+}
+
+func (m Mat[N]) Add(b Mat[N], overflow ...OverflowPolicy) (Mat[N], error) {
+	return m.zip(b, overflow,
+		func(x, y wide) wide { return x.add(y) },
+		func(x, y N) N { return x + y })
+}
+
+func (m Mat[N]) Sub(b Mat[N], overflow ...OverflowPolicy) (Mat[N], error) {
+	return m.zip(b, overflow,
+		func(x, y wide) wide { y.neg = !y.neg && !y.isZero(); return x.add(y) },
+		func(x, y N) N { return x - y })
+}
+
+/*MulElem is the element wise (Hadamard) product, Mul is the matrix product*/
+func (m Mat[N]) MulElem(b Mat[N], overflow ...OverflowPolicy) (Mat[N], error) {
+	return m.zip(b, overflow,
+		func(x, y wide) wide { return x.mul(y) },
+		func(x, y N) N { return x * y })
+}
+
+func (m Mat[N]) Scale(k N, overflow ...OverflowPolicy) (Mat[N], error) {
+	kw := wideOf(k)
+	return m.zip(m, overflow,
+		func(x, _ wide) wide { return kw.mul(x) },
+		func(x, _ N) N { return k * x })
+}
+
+/*Mul is the matrix product, m.Cols() has to match b.Rows()*/
+func (m Mat[N]) Mul(b Mat[N], overflow ...OverflowPolicy) (Mat[N], error) {
+	if m.cols != b.rows {
+		return Mat[N]{}, ErrShape
+	}
+	out := Mat[N]{rows: m.rows, cols: b.cols, data: make([]N, m.rows*b.cols)}
+	policy := pickOverflowPolicy(overflow)
+	var err error
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < b.cols; j++ {
+			if isFloat[N]() {
+				s := 0.0
+				for k := 0; k < m.cols; k++ {
+					s = math.FMA(float64(m.data[i*m.cols+k]), float64(b.data[k*b.cols+j]), s)
+				}
+				out.data[i*out.cols+j] = N(s)
+				continue
+			}
+			var s wideSum
+			for k := 0; k < m.cols; k++ {
+				s.add(wideOf(m.data[i*m.cols+k]).mul(wideOf(b.data[k*b.cols+j])))
+			}
+			out.data[i*out.cols+j] = wideSumTo[N](&s, policy, &err)
+		}
+	}
+	return out, err
+}
+
+/*
+Det is the determinant of a square matrix. Floats go through LU. Integers use the fraction free Bareiss
+elimination on big integers so the answer is exact, then it is fitted with the overflow policy like the other
+methods. The empty matrix has determinant 1.
+*/
+func Det[N Number](m Mat[N], overflow ...OverflowPolicy) (N, error) {
+	if m.rows != m.cols {
+		return 0, ErrShape
+	}
+	if isFloat[N]() {
+		return N(newLU(m).det()), nil
+	}
+	n := m.rows
+	a := make([]*big.Int, len(m.data))
+	for i, v := range m.data {
+		a[i] = bigFromInt(v)
+	}
+	det, prev := big.NewInt(1), big.NewInt(1)
+	for k := 0; k < n; k++ {
+		if a[k*n+k].Sign() == 0 {
+			p := k + 1
+			for p < n && a[p*n+k].Sign() == 0 {
+				p++
+			}
+			if p == n {
+				return 0, nil
+			}
+			for j := 0; j < n; j++ {
+				a[k*n+j], a[p*n+j] = a[p*n+j], a[k*n+j]
+			}
+			det.Neg(det)
+		}
+		for i := k + 1; i < n; i++ {
+			for j := k + 1; j < n; j++ {
+				t := new(big.Int).Mul(a[i*n+j], a[k*n+k])
+				t.Sub(t, new(big.Int).Mul(a[i*n+k], a[k*n+j]))
+				a[i*n+j] = t.Quo(t, prev)
+			}
+		}
+		prev = a[k*n+k]
+	}
+	det.Mul(det, prev)
+	return fitBig[N](det, pickOverflowPolicy(overflow))
+}
+
+func float64sOf[N Number](xs []N) []float64 {
+	out := make([]float64, len(xs))
+	for i, x := range xs {
+		out[i] = float64(x)
+	}
+	return out
+}
+
+func matOf[N Number](rows int, cols int, data []float64) Mat[N] {
+	m := Mat[N]{rows: rows, cols: cols, data: make([]N, len(data))}
+	for i, x := range data {
+		m.data[i] = N(x)
+	}
+	return m
+}
+
+/*
+LU is the factorization P*A = L*U with partial pivoting, L has ones on the diagonal. A singular matrix still
+factors, its Det is 0 and Solve returns ErrSingular.
+*/
+type LU[N Float] struct {
+	lu luFactors
+}
+
+/*luFactors keeps L and U packed in one float64 matrix like LAPACK does*/
+type luFactors struct {
+	n    int
+	lu   []float64
+	perm []int
+	sign float64
+}
+
+func newLU[N Number](m Mat[N]) luFactors {
+	n := m.rows
+	f := luFactors{n: n, lu: float64sOf(m.data), perm: make([]int, n), sign: 1}
+	lu := f.lu
+	for i := range f.perm {
+		f.perm[i] = i
+	}
+	for k := 0; k < n; k++ {
+		p := k
+		for i := k + 1; i < n; i++ {
+			if math.Abs(lu[i*n+k]) > math.Abs(lu[p*n+k]) {
+				p = i
+			}
+		}
+		if p != k {
+			for j := 0; j < n; j++ {
+				lu[k*n+j], lu[p*n+j] = lu[p*n+j], lu[k*n+j]
+			}
+			f.perm[k], f.perm[p] = f.perm[p], f.perm[k]
+			f.sign = -f.sign
+		}
+		piv := lu[k*n+k]
+		if piv == 0 {
+			continue
+		}
+		for i := k + 1; i < n; i++ {
+			l := lu[i*n+k] / piv
+			lu[i*n+k] = l
+			for j := k + 1; j < n; j++ {
+				lu[i*n+j] -= l * lu[k*n+j]
+			}
+		}
+	}
+	return f
+}
+
+func (f luFactors) det() float64 {
+	d := f.sign
+	for i := 0; i < f.n; i++ {
+		d *= f.lu[i*f.n+i]
+	}
+	return d
+}
+
+func (f luFactors) solve(b []float64, cols int) ([]float64, error) {
+	n := f.n
+	for i := 0; i < n; i++ {
+		if f.lu[i*n+i] == 0 {
+			return nil, ErrSingular
+		}
+	}
+	x := make([]float64, len(b))
+	for i, p := range f.perm {
+		copy(x[i*cols:(i+1)*cols], b[p*cols:(p+1)*cols])
+	}
+	for c := 0; c < cols; c++ {
+		for i := 0; i < n; i++ {
+			for k := 0; k < i; k++ {
+				x[i*cols+c] -= f.lu[i*n+k] * x[k*cols+c]
+			}
+		}
+		for i := n - 1; i >= 0; i-- {
+			for k := i + 1; k < n; k++ {
+				x[i*cols+c] -= f.lu[i*n+k] * x[k*cols+c]
+			}
+			x[i*cols+c] /= f.lu[i*n+i]
+		}
+	}
+	return x, nil
+}
+
+func NewLU[N Float](m Mat[N]) (LU[N], error) {
+	if m.rows != m.cols {
+		return LU[N]{}, ErrShape
+	}
+	return LU[N]{lu: newLU(m)}, nil
+}
+
+func (f LU[N]) L() Mat[N] {
+	n := f.lu.n
+	l := make([]float64, n*n)
+	for i := 0; i < n; i++ {
+		copy(l[i*n:i*n+i], f.lu.lu[i*n:i*n+i])
+		l[i*n+i] = 1
+	}
+	return matOf[N](n, n, l)
+}
+
+func (f LU[N]) U() Mat[N] {
+	n := f.lu.n
+	u := make([]float64, n*n)
+	for i := 0; i < n; i++ {
+		copy(u[i*n+i:(i+1)*n], f.lu.lu[i*n+i:(i+1)*n])
+	}
+	return matOf[N](n, n, u)
+}
+
+/*Pivot is the row permutation, row i of P*A is row Pivot()[i] of A*/
+func (f LU[N]) Pivot() []int {
+	return append([]int(nil), f.lu.perm...)
+}
+
+func (f LU[N]) Det() N {
+	return N(f.lu.det())
+}
+
+/*Solve finds X with A*X = b, b can have any number of columns*/
+func (f LU[N]) Solve(b Mat[N]) (Mat[N], error) {
+	if b.rows != f.lu.n {
+		return Mat[N]{}, ErrShape
+	}
+	x, err := f.lu.solve(float64sOf(b.data), b.cols)
+	if err != nil {
+		return Mat[N]{}, err
+	}
+	return matOf[N](b.rows, b.cols, x), nil
+}
+
+func (f LU[N]) Inverse() (Mat[N], error) {
+	return f.Solve(Identity[N](f.lu.n))
+}
+
+/*
+QR is the Householder factorization A = Q*R of a matrix with at least as many rows as columns. Q is the thin
+rows x cols part with orthonormal columns and R is cols x cols upper triangular. Solve gives the least squares
+answer, which is the exact one for a square matrix.
+*/
+type QR[N Float] struct {
+	rows  int
+	cols  int
+	qr    []float64
+	rdiag []float64
+}
+
+func NewQR[N Float](m Mat[N]) (QR[N], error) {
+	if m.rows < m.cols {
+		return QR[N]{}, ErrShape
+	}
+	rows, cols := m.rows, m.cols
+	f := QR[N]{rows: rows, cols: cols, qr: float64sOf(m.data), rdiag: make([]float64, cols)}
+	qr := f.qr
+	for k := 0; k < cols; k++ {
+		nrm := 0.0
+		for i := k; i < rows; i++ {
+			nrm = math.Hypot(nrm, qr[i*cols+k])
+		}
+		if nrm != 0 {
+			if qr[k*cols+k] < 0 {
+				nrm = -nrm
+			}
+			for i := k; i < rows; i++ {
+				qr[i*cols+k] /= nrm
+			}
+			qr[k*cols+k]++
+			for j := k + 1; j < cols; j++ {
+				s := 0.0
+				for i := k; i < rows; i++ {
+					s += qr[i*cols+k] * qr[i*cols+j]
+				}
+				s = -s / qr[k*cols+k]
+				for i := k; i < rows; i++ {
+					qr[i*cols+j] += s * qr[i*cols+k]
+				}
+			}
+		}
+		f.rdiag[k] = -nrm
+	}
+	return f, nil
+}
+
+func (f QR[N]) Q() Mat[N] {
+	rows, cols, qr := f.rows, f.cols, f.qr
+	q := make([]float64, rows*cols)
+	for k := cols - 1; k >= 0; k-- {
+		q[k*cols+k] = 1
+		for j := k; j < cols; j++ {
+			if qr[k*cols+k] == 0 {
+				continue
+			}
+			s := 0.0
+			for i := k; i < rows; i++ {
+				s += qr[i*cols+k] * q[i*cols+j]
+			}
+			s = -s / qr[k*cols+k]
+			for i := k; i < rows; i++ {
+				q[i*cols+j] += s * qr[i*cols+k]
+			}
+		}
+	}
+	return matOf[N](rows, cols, q)
+}
+
+func (f QR[N]) R() Mat[N] {
+	cols := f.cols
+	r := make([]float64, cols*cols)
+	for i := 0; i < cols; i++ {
+		r[i*cols+i] = f.rdiag[i]
+		copy(r[i*cols+i+1:(i+1)*cols], f.qr[i*cols+i+1:(i+1)*cols])
+	}
+	return matOf[N](cols, cols, r)
+}
+
+/*
+Solve finds the X that minimizes ||A*X - b||, ErrSingular if A doesn't have full column rank. A column that is a
+combination of the others leaves rounding noise on the diagonal of R rather than an exact 0, so anything within
+rows*eps of the largest diagonal element counts as 0, like the rank cutoff of LAPACK and NumPy.
+*/
+func (f QR[N]) Solve(b Mat[N]) (Mat[N], error) {
+	if b.rows != f.rows {
+		return Mat[N]{}, ErrShape
+	}
+	tol := 0.0
+	for _, d := range f.rdiag {
+		tol = math.Max(tol, math.Abs(d))
+	}
+	tol *= float64(f.rows) * 0x1p-52
+	for _, d := range f.rdiag {
+		if math.Abs(d) <= tol {
+			return Mat[N]{}, ErrSingular
+		}
+	}
+	rows, cols, nx, qr := f.rows, f.cols, b.cols, f.qr
+	x := float64sOf(b.data)
+	for k := 0; k < cols; k++ {
+		for j := 0; j < nx; j++ {
+			s := 0.0
+			for i := k; i < rows; i++ {
+				s += qr[i*cols+k] * x[i*nx+j]
+			}
+			s = -s / qr[k*cols+k]
+			for i := k; i < rows; i++ {
+				x[i*nx+j] += s * qr[i*cols+k]
+			}
+		}
+	}
+	for k := cols - 1; k >= 0; k-- {
+		for j := 0; j < nx; j++ {
+			x[k*nx+j] /= f.rdiag[k]
+			for i := 0; i < k; i++ {
+				x[i*nx+j] -= x[k*nx+j] * qr[i*cols+k]
+			}
+		}
+	}
+	return matOf[N](cols, nx, x[:cols*nx]), nil
+}
+
+/*
+Cholesky is A = L*L^T for a symmetric positive definite A. Only the lower triangle of A is read so a matrix that is
+off from symmetric by rounding is fine. Anything that isn't positive definite returns ErrNotPositiveDefinite.
+*/
+type Cholesky[N Float] struct {
+	n int
+	l []float64
+}
+
+func NewCholesky[N Float](m Mat[N]) (Cholesky[N], error) {
+	if m.rows != m.cols {
+		return Cholesky[N]{}, ErrShape
+	}
+	n := m.rows
+	a := float64sOf(m.data)
+	l := make([]float64, n*n)
+	for j := 0; j < n; j++ {
+		d := a[j*n+j]
+		for k := 0; k < j; k++ {
+			d -= l[j*n+k] * l[j*n+k]
+		}
+		if !(d > 0) {
+			return Cholesky[N]{}, ErrNotPositiveDefinite
+		}
+		l[j*n+j] = math.Sqrt(d)
+		for i := j + 1; i < n; i++ {
+			s := a[i*n+j]
+			for k := 0; k < j; k++ {
+				s -= l[i*n+k] * l[j*n+k]
+			}
+			l[i*n+j] = s / l[j*n+j]
+		}
+	}
+	return Cholesky[N]{n: n, l: l}, nil
+}
+
+func (c Cholesky[N]) L() Mat[N] {
+	return matOf[N](c.n, c.n, c.l)
+}
+
+func (c Cholesky[N]) Det() N {
+	d := 1.0
+	for i := 0; i < c.n; i++ {
+		d *= c.l[i*c.n+i]
+	}
+	return N(d * d)
+}
+
+func (c Cholesky[N]) Solve(b Mat[N]) (Mat[N], error) {
+	if b.rows != c.n {
+		return Mat[N]{}, ErrShape
+	}
+	n, nx, l := c.n, b.cols, c.l
+	x := float64sOf(b.data)
+	for j := 0; j < nx; j++ {
+		for i := 0; i < n; i++ {
+			for k := 0; k < i; k++ {
+				x[i*nx+j] -= l[i*n+k] * x[k*nx+j]
+			}
+			x[i*nx+j] /= l[i*n+i]
+		}
+		for i := n - 1; i >= 0; i-- {
+			for k := i + 1; k < n; k++ {
+				x[i*nx+j] -= l[k*n+i] * x[k*nx+j]
+			}
+			x[i*nx+j] /= l[i*n+i]
+		}
+	}
+	return matOf[N](n, nx, x), nil
+}
+
+/*
+Solve finds X with A*X = b. A square A goes through LU, a tall one through QR and gets the least squares answer.
+*/
+func Solve[N Float](a Mat[N], b Mat[N]) (Mat[N], error) {
+	if a.rows == a.cols {
+		f, _ := NewLU(a)
+		return f.Solve(b)
+	}
+	f, err := NewQR(a)
+	if err != nil {
+		return Mat[N]{}, err
+	}
+	return f.Solve(b)
+}
+
+func Inverse[N Float](m Mat[N]) (Mat[N], error) {
+	f, err := NewLU(m)
+	if err != nil {
+		return Mat[N]{}, err
+	}
+	return f.Inverse()
+}
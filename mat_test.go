@@ -0,0 +1,327 @@
+package RUNK
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func randMat[N Number](rng *rand.Rand, rows int, cols int, gen func() N) Mat[N] {
+	m, _ := NewMat[N](rows, cols, nil)
+	for i := range m.data {
+		m.data[i] = gen()
+	}
+	return m
+}
+
+/*closeMat checks every element of a against b to within tol times the largest element of b*/
+func closeMat(a Mat[float64], b Mat[float64], tol float64) bool {
+	if a.rows != b.rows || a.cols != b.cols {
+		return false
+	}
+	scale := 1.0
+	for _, x := range b.data {
+		scale = math.Max(scale, math.Abs(x))
+	}
+	for i := range a.data {
+		if !(math.Abs(a.data[i]-b.data[i]) <= tol*scale) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestMatIntegerMatchesBig(t *testing.T) {
+	rng := rand.New(rand.NewSource(18))
+	gen := func() int8 { return int8(rng.Intn(256) - 128) }
+	a, b := randMat(rng, 4, 4, gen), randMat(rng, 4, 4, gen)
+	a.data[0], b.data[0] = math.MinInt8, math.MinInt8
+	bigAt := func(m Mat[int8], i int) *big.Int { return big.NewInt(int64(m.data[i])) }
+	for _, policy := range []OverflowPolicy{Saturate, Wrap} {
+		ops := []struct {
+			name string
+			got  Mat[int8]
+			err  error
+			ref  func(i int) *big.Int
+		}{
+			{"Add", nil2(a.Add(b, policy)), second(a.Add(b, policy)), func(i int) *big.Int { return new(big.Int).Add(bigAt(a, i), bigAt(b, i)) }},
+			{"Sub", nil2(a.Sub(b, policy)), second(a.Sub(b, policy)), func(i int) *big.Int { return new(big.Int).Sub(bigAt(a, i), bigAt(b, i)) }},
+			{"MulElem", nil2(a.MulElem(b, policy)), second(a.MulElem(b, policy)), func(i int) *big.Int { return new(big.Int).Mul(bigAt(a, i), bigAt(b, i)) }},
+			{"Scale", nil2(a.Scale(-3, policy)), second(a.Scale(-3, policy)), func(i int) *big.Int { return new(big.Int).Mul(bigAt(a, i), big.NewInt(-3)) }},
+			{"Mul", nil2(a.Mul(b, policy)), second(a.Mul(b, policy)), func(i int) *big.Int {
+				s := new(big.Int)
+				for k := 0; k < 4; k++ {
+					s.Add(s, new(big.Int).Mul(bigAt(a, i/4*4+k), bigAt(b, k*4+i%4)))
+				}
+				return s
+			}},
+		}
+		for _, op := range ops {
+			var wantErr error
+			for i, got := range op.got.data {
+				want, err := fitBig[int8](op.ref(i), policy)
+				if err != nil {
+					wantErr = err
+				}
+				if got != want {
+					t.Fatalf("%s policy %d element %d = %d, want %d", op.name, policy, i, got, want)
+				}
+			}
+			if op.err != wantErr {
+				t.Errorf("%s policy %d error = %v, want %v", op.name, policy, op.err, wantErr)
+			}
+		}
+	}
+	// the products of int64s are summed in 128 bits so only the total has to fit
+	m := Mat[int64]{rows: 1, cols: 2, data: []int64{math.MaxInt64, math.MaxInt64}}
+	v := Mat[int64]{rows: 2, cols: 1, data: []int64{2, -2}}
+	if p, err := m.Mul(v); err != nil || p.data[0] != 0 {
+		t.Errorf("int64 Mul = %v %v", p, err)
+	}
+	if p, err := m.Mul(m.T(), Wrap); err != ErrOverflow || p.data[0] != 2 {
+		t.Errorf("wrapping int64 Mul = %v %v, want 2*(2^63-1)^2 mod 2^64", p, err)
+	}
+	// a partial sum past 128 bits still takes the later terms of the other sign exactly
+	row := Mat[int64]{rows: 1, cols: 8, data: []int64{math.MinInt64, math.MinInt64, math.MinInt64, math.MinInt64,
+		math.MaxInt64, math.MaxInt64, math.MaxInt64, math.MaxInt64}}
+	col := Mat[int64]{rows: 8, cols: 1, data: make([]int64, 8)}
+	for i := range col.data {
+		col.data[i] = math.MinInt64
+	}
+	if p, err := row.Mul(col, Wrap); err != ErrOverflow || p.data[0] != 0 {
+		t.Errorf("wrapping Mul past 128 bits = %v %v, want 2^65 mod 2^64", p, err)
+	}
+	if p, err := row.Mul(col); err != ErrOverflow || p.data[0] != math.MaxInt64 {
+		t.Errorf("saturating Mul past 128 bits = %v %v", p, err)
+	}
+	row.data, col.data = append(row.data, math.MinInt64, 7), append(col.data, 4, 1)
+	row.cols, col.rows = 10, 10
+	if p, err := row.Mul(col); err != nil || p.data[0] != 7 {
+		t.Errorf("Mul past 128 bits and back = %v %v, want 7", p, err)
+	}
+}
+
+func nil2[N Number](m Mat[N], _ error) Mat[N] { return m }
+
+func second[N Number](_ Mat[N], err error) error { return err }
+
+func TestDetExact(t *testing.T) {
+	const big = math.MaxInt64
+	tests := []struct {
+		name   string
+		m      Mat[int64]
+		policy OverflowPolicy
+		want   int64
+		err    error
+	}{
+		{"cancelling", Mat[int64]{2, 2, []int64{big, big - 1, big - 1, big - 2}}, Saturate, -1, nil},
+		{"pivot swap", Mat[int64]{2, 2, []int64{0, 1, 1, 0}}, Saturate, -1, nil},
+		{"singular", Mat[int64]{3, 3, []int64{1, 2, 3, 2, 4, 6, 7, 8, 9}}, Saturate, 0, nil},
+		{"zero column", Mat[int64]{2, 2, []int64{0, 1, 0, 5}}, Saturate, 0, nil},
+		{"saturated", Mat[int64]{2, 2, []int64{big, 0, 0, big}}, Saturate, big, ErrOverflow},
+		{"wrapped", Mat[int64]{2, 2, []int64{big, 0, 0, big}}, Wrap, 1, ErrOverflow},
+		{"empty", Mat[int64]{}, Saturate, 1, nil},
+		{"3x3", Mat[int64]{3, 3, []int64{2, -3, 1, 2, 0, -1, 1, 4, 5}}, Saturate, 49, nil},
+	}
+	for _, tt := range tests {
+		if got, err := Det(tt.m, tt.policy); got != tt.want || err != tt.err {
+			t.Errorf("%s Det = %d %v, want %d %v", tt.name, got, err, tt.want, tt.err)
+		}
+	}
+	swap := Mat[uint8]{2, 2, []uint8{0, 1, 1, 0}}
+	if d, err := Det(swap); d != 0 || err != ErrOverflow {
+		t.Errorf("uint8 Det of -1 = %d %v", d, err)
+	}
+	if d, err := Det(swap, Wrap); d != 255 || err != ErrOverflow {
+		t.Errorf("wrapping uint8 Det of -1 = %d %v", d, err)
+	}
+	if _, err := Det(Mat[int]{2, 3, make([]int, 6)}); err != ErrShape {
+		t.Errorf("Det of 2x3 = %v", err)
+	}
+	// exact for integers means Det of an integer matrix matches the float one rounded
+	rng := rand.New(rand.NewSource(19))
+	for trial := 0; trial < 50; trial++ {
+		m := randMat(rng, 5, 5, func() int32 { return int32(rng.Intn(41) - 20) })
+		exact, _ := Det(m)
+		if f, _ := Det(MatFrom[float64](m)); math.Abs(f-float64(exact)) > 1e-6*math.Max(1, math.Abs(f)) {
+			t.Fatalf("Det of %v = %d, float says %v", m, exact, f)
+		}
+	}
+	if d, _ := Det(Mat[float64]{2, 2, []float64{1, math.NaN(), 3, 4}}); d == d {
+		t.Errorf("Det with NaN = %v", d)
+	}
+}
+
+func TestMatDecompositions(t *testing.T) {
+	rng := rand.New(rand.NewSource(20))
+	gen := func() float64 { return rng.NormFloat64() }
+	a := randMat(rng, 6, 6, gen)
+	lu, err := NewLU(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pa, _ := NewMat[float64](6, 6, nil)
+	for i, p := range lu.Pivot() {
+		copy(pa.data[i*6:], a.Row(p))
+	}
+	if l, u := lu.L(), lu.U(); !closeMat(nil2(l.Mul(u)), pa, 1e-14) {
+		t.Errorf("L*U = %v, want P*A %v", nil2(l.Mul(u)), pa)
+	}
+	if d, _ := Det(a); math.Abs(lu.Det()-d) > 1e-12*math.Abs(d) {
+		t.Errorf("LU Det = %v, Det = %v", lu.Det(), d)
+	}
+	b := randMat(rng, 6, 2, gen)
+	x, err := Solve(a, b)
+	if err != nil || !closeMat(nil2(a.Mul(x)), b, 1e-12) {
+		t.Errorf("Solve residual too big: %v %v", nil2(a.Mul(x)), err)
+	}
+	inv, err := Inverse(a)
+	if err != nil || !closeMat(nil2(inv.Mul(a)), Identity[float64](6), 1e-12) {
+		t.Errorf("Inverse*A = %v %v", nil2(inv.Mul(a)), err)
+	}
+
+	tall := randMat(rng, 8, 3, gen)
+	qr, err := NewQR(tall)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q, r := qr.Q(), qr.R()
+	if !closeMat(nil2(q.Mul(r)), tall, 1e-14) || !closeMat(nil2(q.T().Mul(q)), Identity[float64](3), 1e-14) {
+		t.Errorf("Q*R or Q^T*Q is off: %v %v", nil2(q.Mul(r)), nil2(q.T().Mul(q)))
+	}
+	for i := 1; i < 3; i++ {
+		for j := 0; j < i; j++ {
+			if r.At(i, j) != 0 {
+				t.Errorf("R is not upper triangular: %v", r)
+			}
+		}
+	}
+	// least squares solves the normal equations A^T*A*x = A^T*y
+	y := randMat(rng, 8, 1, gen)
+	ls, err := Solve(tall, y)
+	ata, aty := nil2(tall.T().Mul(tall)), nil2(tall.T().Mul(y))
+	if normal, _ := Solve(ata, aty); err != nil || !closeMat(ls, normal, 1e-12) {
+		t.Errorf("least squares = %v %v, normal equations give %v", ls, err, normal)
+	}
+
+	spd, _ := ata.Add(Identity[float64](3))
+	ch, err := NewCholesky(spd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l := ch.L(); !closeMat(nil2(l.Mul(l.T())), spd, 1e-14) {
+		t.Errorf("L*L^T = %v, want %v", nil2(l.Mul(l.T())), spd)
+	}
+	if d, _ := Det(spd); math.Abs(ch.Det()-d) > 1e-12*d {
+		t.Errorf("Cholesky Det = %v, want %v", ch.Det(), d)
+	}
+	if cx, err := ch.Solve(aty); err != nil || !closeMat(nil2(spd.Mul(cx)), aty, 1e-12) {
+		t.Errorf("Cholesky Solve = %v %v", cx, err)
+	}
+
+	// float32 goes through float64 inside
+	a32 := MatFrom[float32](a)
+	if x32, err := Solve(a32, MatFrom[float32](b)); err != nil || !closeMat(MatFrom[float64](x32), x, 1e-4) {
+		t.Errorf("float32 Solve = %v %v", x32, err)
+	}
+}
+
+func TestMatFailures(t *testing.T) {
+	singular := Mat[float64]{3, 3, []float64{1, 2, 3, 2, 4, 6, 1, 1, 1}}
+	lu, _ := NewLU(singular)
+	if lu.Det() != 0 {
+		t.Errorf("singular LU Det = %v", lu.Det())
+	}
+	if _, err := lu.Solve(Identity[float64](3)); err != ErrSingular {
+		t.Errorf("singular Solve = %v", err)
+	}
+	if _, err := Inverse(singular); err != ErrSingular {
+		t.Errorf("singular Inverse = %v", err)
+	}
+	if _, err := Inverse(Mat[float64]{2, 3, make([]float64, 6)}); err != ErrShape {
+		t.Errorf("Inverse of 2x3 = %v", err)
+	}
+	rankDeficient := Mat[float64]{3, 2, []float64{1, 2, 2, 4, 3, 6}}
+	if _, err := Solve(rankDeficient, Mat[float64]{3, 1, []float64{1, 2, 3}}); err != ErrSingular {
+		t.Errorf("rank deficient least squares = %v", err)
+	}
+	if _, err := NewQR(Mat[float64]{2, 3, make([]float64, 6)}); err != ErrShape {
+		t.Errorf("wide QR = %v", err)
+	}
+	for _, m := range []Mat[float64]{
+		{2, 2, []float64{1, 2, 2, 1}},
+		{2, 2, []float64{0, 0, 0, 0}},
+		{2, 2, []float64{math.NaN(), 0, 0, 1}},
+	} {
+		if _, err := NewCholesky(m); err != ErrNotPositiveDefinite {
+			t.Errorf("Cholesky of %v = %v", m, err)
+		}
+	}
+	if _, err := Solve(Identity[float64](2), Identity[float64](3)); err != ErrShape {
+		t.Errorf("Solve with the wrong b = %v", err)
+	}
+	if _, err := Identity[int](2).Mul(Identity[int](3)); err != ErrShape {
+		t.Errorf("Mul of 2x2 by 3x3 = %v", err)
+	}
+	if _, err := Identity[int](2).Add(Identity[int](3)); err != ErrShape {
+		t.Errorf("Add of 2x2 and 3x3 = %v", err)
+	}
+	x, err := Solve(Mat[float64]{2, 2, []float64{1, 0, 0, math.Inf(1)}}, Mat[float64]{2, 1, []float64{1, 1}})
+	if err != nil || x.At(0, 0) != 1 || x.At(1, 0) != 0 {
+		t.Errorf("Solve with an Inf pivot = %v %v", x, err)
+	}
+}
+
+func TestMatBasics(t *testing.T) {
+	if _, err := NewMat(2, 2, []int{1, 2, 3}); err != ErrShape {
+		t.Errorf("NewMat with 3 elements = %v", err)
+	}
+	if _, err := NewMat[int](-1, 2, nil); err != ErrShape {
+		t.Errorf("NewMat with -1 rows = %v", err)
+	}
+	if _, err := MatFromRows([][]int{{1, 2}, {3}}); err != ErrShape {
+		t.Errorf("ragged MatFromRows = %v", err)
+	}
+	data := []int16{1, 2, 3, 4, 5, 6}
+	m, _ := NewMat(2, 3, data)
+	data[0] = 99
+	if m.At(0, 0) != 1 {
+		t.Error("NewMat kept the caller's slice")
+	}
+	if m.At(2, 0) != 0 || m.At(0, -1) != 0 || m.Set(0, 3, 1) || m.Row(2) != nil || m.Col(-1) != nil {
+		t.Error("out of range access should be 0, false or nil")
+	}
+	if s := m.String(); s != "[[1 2 3] [4 5 6]]" {
+		t.Errorf("String = %s", s)
+	}
+	if s := m.T().String(); s != "[[1 4] [2 5] [3 6]]" {
+		t.Errorf("T = %s", s)
+	}
+	c := m.Clone()
+	c.Set(1, 2, -6)
+	if m.At(1, 2) != 6 || c.Equal(m) || !m.Equal(m.T().T()) {
+		t.Error("Clone shares storage or Equal is wrong")
+	}
+	if f := MatFrom[int8](Mat[float64]{1, 4, []float64{math.NaN(), math.Inf(1), -1e9, 2.5}}); f.String() != "[[0 127 -128 3]]" {
+		t.Errorf("MatFrom[int8] = %v", f)
+	}
+	if s := (Mat[float32]{1, 2, []float32{0.1, float32(math.Inf(-1))}}).String(); s != "[[0.1 -Inf]]" {
+		t.Errorf("float32 String = %s", s)
+	}
+	if e := Identity[int](-3); e.Rows() != 0 || e.String() != "[]" {
+		t.Errorf("Identity(-3) = %v", e)
+	}
+	var zero Mat[float64]
+	if p, err := zero.Mul(zero); err != nil || p.Rows() != 0 {
+		t.Errorf("empty Mul = %v %v", p, err)
+	}
+	if inv, err := Inverse(zero); err != nil || inv.Rows() != 0 {
+		t.Errorf("empty Inverse = %v %v", inv, err)
+	}
+	// float products are summed in float64 with FMA, float32 only rounds once at the end
+	r := Mat[float32]{1, 3, []float32{1e8, 1, -1e8}}
+	if p, _ := r.Mul(Mat[float32]{3, 1, []float32{1, 1, 1}}); p.data[0] != 1 {
+		t.Errorf("float32 Mul = %v", p)
+	}
+}
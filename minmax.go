@@ -0,0 +1,135 @@
+package RUNK
+
+import "errors"
+
+var ErrEmpty = errors.New("RUNK: empty input")
+
+/*
+NaNPolicy picks how the checked min/max functions treat NaN. It only matters for the float types, integers are
+never NaN.
+*/
+type NaNPolicy int
+
+const (
+	// NaNPropagate makes the result NaN if any input is NaN, like math.Max and IEEE 754 maximum
+	NaNPropagate NaNPolicy = iota
+	// NaNIgnore skips NaNs as if they weren't there, so all NaN input counts as empty
+	NaNIgnore
+	// NaNMaximumNumber is IEEE 754 maximumNumber/minimumNumber, numbers win over NaN and only all NaN input is NaN
+	NaNMaximumNumber
+)
+
+func pickNaNPolicy(nanPolicy []NaNPolicy) NaNPolicy {
+	if len(nanPolicy) > 0 {
+		return nanPolicy[0]
+	}
+	return NaNPropagate
+}
+
+/*
+less orders the way IEEE 754 minimum and maximum do, which is < except that -0 comes before +0. math.Max gets
+this right but a plain < can't tell the zeros apart.
+*/
+func less[N Number](a N, b N) bool {
+	if a == b && a == 0 && isFloat[N]() {
+		return Signbit(a) && !Signbit(b)
+	}
+	return a < b
+}
+
+/*
+extremes finds the index of the smallest and largest value in one pass. A NaN index is returned for both when the
+policy says the result is NaN.
+*/
+func extremes[N Number](nums []N, policy NaNPolicy) (int, int, error) {
+	lo, hi, nan := -1, -1, -1
+	for i, x := range nums {
+		if x != x {
+			if policy == NaNPropagate {
+				return i, i, nil
+			}
+			if nan < 0 {
+				nan = i
+			}
+			continue
+		}
+		if lo < 0 {
+			lo, hi = i, i
+			continue
+		}
+		if less(x, nums[lo]) {
+			lo = i
+		}
+		if less(nums[hi], x) {
+			hi = i
+		}
+	}
+	switch {
+	case lo >= 0:
+		return lo, hi, nil
+	case nan >= 0 && policy == NaNMaximumNumber:
+		return nan, nan, nil
+	}
+	return -1, -1, ErrEmpty
+}
+
+/*
+MaxChecked is Max that returns ErrEmpty instead of MinNum when there is nothing to compare. nanPolicy defaults to
+NaNPropagate and +0 is bigger than -0.
+*/
+func MaxChecked[N Number](nums []N, nanPolicy ...NaNPolicy) (N, error) {
+	_, hi, err := extremes(nums, pickNaNPolicy(nanPolicy))
+	if err != nil {
+		return 0, err
+	}
+	return nums[hi], nil
+}
+
+/*MinChecked is Min that returns ErrEmpty instead of MaxNum when there is nothing to compare*/
+func MinChecked[N Number](nums []N, nanPolicy ...NaNPolicy) (N, error) {
+	lo, _, err := extremes(nums, pickNaNPolicy(nanPolicy))
+	if err != nil {
+		return 0, err
+	}
+	return nums[lo], nil
+}
+
+/*MinMax finds both in a single pass over nums*/
+func MinMax[N Number](nums []N, nanPolicy ...NaNPolicy) (min N, max N, err error) {
+	lo, hi, err := extremes(nums, pickNaNPolicy(nanPolicy))
+	if err != nil {
+		return 0, 0, err
+	}
+	return nums[lo], nums[hi], nil
+}
+
+/*
+ArgMax is the index of the largest value, the first one if there are ties. It is -1 when there isn't one, the same
+as strings.Index. Under NaNPropagate it is the index of the first NaN.
+*/
+func ArgMax[N Number](nums []N, nanPolicy ...NaNPolicy) int {
+	_, hi, _ := extremes(nums, pickNaNPolicy(nanPolicy))
+	return hi
+}
+
+func ArgMin[N Number](nums []N, nanPolicy ...NaNPolicy) int {
+	lo, _, _ := extremes(nums, pickNaNPolicy(nanPolicy))
+	return lo
+}
+
+/*
+Clamp limits x to [lo, hi], swapping the bounds if they come in backwards. A NaN x stays NaN and a NaN bound is
+ignored. Signed zeros are ordered so Clamp(-0.0, 0, 1) is +0.
+*/
+func Clamp[N Number](x N, lo N, hi N) N {
+	if less(hi, lo) {
+		lo, hi = hi, lo
+	}
+	if less(x, lo) {
+		return lo
+	}
+	if less(hi, x) {
+		return hi
+	}
+	return x
+}
@@ -0,0 +1,92 @@
+package RUNK
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMinMaxChecked(t *testing.T) {
+	nan := math.NaN()
+	xs := []float64{3, nan, -1, 7, 7, -1}
+	tests := []struct {
+		name   string
+		policy NaNPolicy
+		lo, hi float64
+		argLo  int
+		argHi  int
+	}{
+		{"propagate", NaNPropagate, nan, nan, 1, 1},
+		{"ignore", NaNIgnore, -1, 7, 2, 3},
+		{"maximumNumber", NaNMaximumNumber, -1, 7, 2, 3},
+	}
+	for _, tt := range tests {
+		lo, hi, err := MinMax(xs, tt.policy)
+		if err != nil || !sameFloat(lo, tt.lo) || !sameFloat(hi, tt.hi) {
+			t.Errorf("%s MinMax = %v %v %v", tt.name, lo, hi, err)
+		}
+		if a, b := ArgMin(xs, tt.policy), ArgMax(xs, tt.policy); a != tt.argLo || b != tt.argHi {
+			t.Errorf("%s ArgMin, ArgMax = %d %d, want %d %d", tt.name, a, b, tt.argLo, tt.argHi)
+		}
+	}
+	allNaN := []float64{nan, nan}
+	if _, err := MaxChecked(allNaN, NaNIgnore); err != ErrEmpty {
+		t.Errorf("all NaN ignored = %v, want ErrEmpty", err)
+	}
+	if m, err := MaxChecked(allNaN, NaNMaximumNumber); err != nil || m == m {
+		t.Errorf("all NaN maximumNumber = %v %v, want NaN", m, err)
+	}
+	if _, err := MinChecked([]int{}); err != ErrEmpty {
+		t.Errorf("MinChecked of nothing = %v", err)
+	}
+	if _, _, err := MinMax[int8](nil); err != ErrEmpty {
+		t.Errorf("MinMax of nil = %v", err)
+	}
+	if ArgMax[int](nil) != -1 || ArgMin([]float32{float32(nan)}, NaNIgnore) != -1 {
+		t.Error("ArgMax and ArgMin of nothing should be -1")
+	}
+}
+
+func sameFloat(a float64, b float64) bool {
+	return a == b && math.Signbit(a) == math.Signbit(b) || a != a && b != b
+}
+
+func TestMinMaxSignedZerosAndIntegers(t *testing.T) {
+	neg := math.Copysign(0, -1)
+	if m, _ := MaxChecked([]float64{neg, 0}); math.Signbit(m) {
+		t.Error("Max(-0, +0) is -0")
+	}
+	if m, _ := MinChecked([]float64{0, neg}); !math.Signbit(m) {
+		t.Error("Min(+0, -0) is +0")
+	}
+	if got := Clamp(neg, 0, 1); math.Signbit(got) {
+		t.Error("Clamp(-0, 0, 1) is -0")
+	}
+	if lo, hi, _ := MinMax([]int64{math.MaxInt64, math.MinInt64, 0}); lo != math.MinInt64 || hi != math.MaxInt64 {
+		t.Errorf("int64 MinMax = %d %d", lo, hi)
+	}
+	if ArgMax([]uint8{1, 9, 9, 2}) != 1 || ArgMin([]uint8{4, 0, 0}) != 1 {
+		t.Error("ties should go to the first index")
+	}
+	tests := []struct {
+		x, lo, hi, want float64
+	}{
+		{5, 0, 1, 1},
+		{-5, 0, 1, 0},
+		{0.5, 1, 0, 0.5},
+		{5, 1, 0, 1},
+		{5, math.NaN(), 3, 3},
+		{-5, math.NaN(), 3, -5},
+		{math.Inf(1), 0, 10, 10},
+	}
+	for _, tt := range tests {
+		if got := Clamp(tt.x, tt.lo, tt.hi); got != tt.want {
+			t.Errorf("Clamp(%v, %v, %v) = %v, want %v", tt.x, tt.lo, tt.hi, got, tt.want)
+		}
+	}
+	if got := Clamp(math.NaN(), 0, 1); got == got {
+		t.Errorf("Clamp(NaN) = %v", got)
+	}
+	if got := Clamp[uint](7, 9, 3); got != 7 {
+		t.Errorf("Clamp[uint](7, 9, 3) = %d", got)
+	}
+}
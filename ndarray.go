@@ -0,0 +1,524 @@
+package RUNK
+
+import (
+	"strings"
+)
+
+/*
+NDArray is an n-dimensional array laid over a flat slice with a shape and strides, the same model as NumPy. Slice,
+Index, Transpose, BroadcastTo and (when it can) Reshape return views that share the data, everything else returns
+a fresh array. Write through a view with Set and the original sees it.
+Element wise math broadcasts the NumPy way: shapes are lined up from the right and a size 1 axis stretches to match.
+Like Mat, integer math is exact and then fitted with the overflow policy, reporting ErrOverflow when a value didn't
+fit, and float math is plain IEEE.
+Reductions take the axes to reduce, nil reduces everything into a 0-d array whose value is At().
+*/
+type NDArray[N Number] struct {
+	data    []N
+	shape   []int
+	strides []int
+	offset  int
+}
+
+/*NewNDArray makes an array of the given shape from data in row order, nil data gives zeros*/
+func NewNDArray[N Number](shape []int, data []N) (NDArray[N], error) {
+	size := 1
+	for _, s := range shape {
+		if s < 0 {
+			return NDArray[N]{}, ErrShape
+		}
+		size *= s
+	}
+	if data != nil && len(data) != size {
+		return NDArray[N]{}, ErrShape
+	}
+	a := NDArray[N]{data: make([]N, size), shape: append([]int{}, shape...)}
+	copy(a.data, data)
+	a.strides = rowStrides(a.shape)
+	return a, nil
+}
+
+/*NDArrayFromMat is a 2-d array with a copy of the matrix*/
+func NDArrayFromMat[N Number](m Mat[N]) NDArray[N] {
+	a, _ := NewNDArray([]int{m.rows, m.cols}, m.data)
+	return a
+}
+
+func rowStrides(shape []int) []int {
+	strides := make([]int, len(shape))
+	step := 1
+	for k := len(shape) - 1; k >= 0; k-- {
+		strides[k] = step
+		step *= shape[k]
+	}
+	return strides
+}
+
+/*AsType converts every element through ConvertNumber into a new array, an empty array stays empty*/
+func AsType[To Number, N Number](a NDArray[N], roundMode ...RoundingMode) NDArray[To] {
+	if a.Size() == 0 {
+		// the zero NDArray has no shape, NewNDArray would make that a 0-d array with one element
+		return NDArray[To]{shape: a.Shape(), strides: a.Strides()}
+	}
+	out, _ := NewNDArray[To](a.shape, nil)
+	ConvertSlice(out.data, a.Data(), roundMode...)
+	return out
+}
+
+func (a NDArray[N]) Shape() []int {
+	return append([]int{}, a.shape...)
+}
+
+/*Strides are counted in elements, not bytes like NumPy*/
+func (a NDArray[N]) Strides() []int {
+	return append([]int{}, a.strides...)
+}
+
+func (a NDArray[N]) Ndim() int {
+	return len(a.shape)
+}
+
+/*Size is the number of elements, the zero NDArray has none*/
+func (a NDArray[N]) Size() int {
+	if len(a.data) == 0 {
+		return 0
+	}
+	size := 1
+	for _, s := range a.shape {
+		size *= s
+	}
+	return size
+}
+
+/*offsetOf finds where idx lives in data, negative indexes count from the end*/
+func (a NDArray[N]) offsetOf(idx []int) (int, bool) {
+	if len(idx) != len(a.shape) || len(a.data) == 0 {
+		return 0, false
+	}
+	off := a.offset
+	for k, i := range idx {
+		if i < 0 {
+			i += a.shape[k]
+		}
+		if i < 0 || i >= a.shape[k] {
+			return 0, false
+		}
+		off += i * a.strides[k]
+	}
+	return off, true
+}
+
+/*At returns the element at idx, 0 when idx doesn't name an element*/
+func (a NDArray[N]) At(idx ...int) N {
+	off, ok := a.offsetOf(idx)
+	if !ok {
+		return 0
+	}
+	return a.data[off]
+}
+
+/*Set stores v at idx and reports false if idx doesn't name an element*/
+func (a NDArray[N]) Set(v N, idx ...int) bool {
+	off, ok := a.offsetOf(idx)
+	if ok {
+		a.data[off] = v
+	}
+	return ok
+}
+
+/*walk calls fn with every index and data offset in row order. The index slice is reused between calls.*/
+func (a NDArray[N]) walk(fn func(idx []int, off int)) {
+	if a.Size() == 0 {
+		return
+	}
+	idx := make([]int, len(a.shape))
+	off := a.offset
+	for {
+		fn(idx, off)
+		k := len(idx) - 1
+		for ; k >= 0; k-- {
+			idx[k]++
+			off += a.strides[k]
+			if idx[k] < a.shape[k] {
+				break
+			}
+			off -= a.strides[k] * a.shape[k]
+			idx[k] = 0
+		}
+		if k < 0 {
+			return
+		}
+	}
+}
+
+/*Data returns a copy of the elements in row order*/
+func (a NDArray[N]) Data() []N {
+	out := make([]N, 0, a.Size())
+	a.walk(func(_ []int, off int) {
+		out = append(out, a.data[off])
+	})
+	return out
+}
+
+/*Clone is a compact copy that shares nothing with a*/
+func (a NDArray[N]) Clone() NDArray[N] {
+	out, _ := NewNDArray(a.shape, a.Data())
+	return out
+}
+
+func (a NDArray[N]) contiguous() bool {
+	want := rowStrides(a.shape)
+	for k := range a.shape {
+		if a.shape[k] > 1 && a.strides[k] != want[k] {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+Slice is a view of the elements start, start+step, ... before stop along one axis, the same as a[start:stop:step]
+in Python. Negative start and stop count from the end and are clipped to the axis, a negative step walks backwards.
+There is no None so use math.MaxInt or math.MinInt for an open end, a[::-1] is Slice(axis, -1, math.MinInt, -1).
+*/
+func (a NDArray[N]) Slice(axis int, start int, stop int, step int) (NDArray[N], error) {
+	if axis < 0 || axis >= len(a.shape) || step == 0 {
+		return NDArray[N]{}, ErrShape
+	}
+	n := a.shape[axis]
+	clip := func(i int, lo int, hi int) int {
+		if i < 0 {
+			i += n
+		}
+		return max(lo, min(i, hi))
+	}
+	count := 0
+	if step > 0 {
+		start, stop = clip(start, 0, n), clip(stop, 0, n)
+		if stop > start {
+			count = (stop - start + step - 1) / step
+		}
+	} else {
+		start, stop = clip(start, -1, n-1), clip(stop, -1, n-1)
+		if start > stop {
+			count = (start - stop - step - 1) / -step
+		}
+	}
+	v := a.view()
+	if count > 0 {
+		v.offset += start * a.strides[axis]
+	}
+	v.shape[axis] = count
+	v.strides[axis] *= step
+	return v, nil
+}
+
+/*Index is the view at position i along axis with that axis dropped, a[:, i] in Python*/
+func (a NDArray[N]) Index(axis int, i int) (NDArray[N], error) {
+	if axis < 0 || axis >= len(a.shape) {
+		return NDArray[N]{}, ErrShape
+	}
+	if i < 0 {
+		i += a.shape[axis]
+	}
+	if i < 0 || i >= a.shape[axis] {
+		return NDArray[N]{}, ErrShape
+	}
+	v := a.view()
+	v.offset += i * a.strides[axis]
+	v.shape = append(v.shape[:axis], v.shape[axis+1:]...)
+	v.strides = append(v.strides[:axis], v.strides[axis+1:]...)
+	return v, nil
+}
+
+func (a NDArray[N]) view() NDArray[N] {
+	return NDArray[N]{data: a.data, shape: a.Shape(), strides: a.Strides(), offset: a.offset}
+}
+
+/*
+Reshape gives the same elements in row order with a new shape, one size can be -1 to have it worked out. It is a
+view when the array is laid out in row order and a copy otherwise.
+*/
+func (a NDArray[N]) Reshape(shape ...int) (NDArray[N], error) {
+	size, unknown := 1, -1
+	for k, s := range shape {
+		switch {
+		case s == -1 && unknown < 0:
+			unknown = k
+		case s < 0:
+			return NDArray[N]{}, ErrShape
+		default:
+			size *= s
+		}
+	}
+	shape = append([]int{}, shape...)
+	if unknown >= 0 {
+		if size == 0 || a.Size()%size != 0 {
+			return NDArray[N]{}, ErrShape
+		}
+		shape[unknown] = a.Size() / size
+		size = a.Size()
+	}
+	if size != a.Size() {
+		return NDArray[N]{}, ErrShape
+	}
+	if !a.contiguous() {
+		a = a.Clone()
+	}
+	return NDArray[N]{data: a.data, shape: shape, strides: rowStrides(shape), offset: a.offset}, nil
+}
+
+/*Transpose permutes the axes, with no axes it reverses them like .T*/
+func (a NDArray[N]) Transpose(axes ...int) (NDArray[N], error) {
+	n := len(a.shape)
+	if len(axes) == 0 {
+		for k := n - 1; k >= 0; k-- {
+			axes = append(axes, k)
+		}
+	}
+	if len(axes) != n {
+		return NDArray[N]{}, ErrShape
+	}
+	seen := make([]bool, n)
+	v := a.view()
+	for k, ax := range axes {
+		if ax < 0 || ax >= n || seen[ax] {
+			return NDArray[N]{}, ErrShape
+		}
+		seen[ax] = true
+		v.shape[k], v.strides[k] = a.shape[ax], a.strides[ax]
+	}
+	return v, nil
+}
+
+/*broadcastShape lines the shapes up from the right, a size 1 axis stretches to match*/
+func broadcastShape(x []int, y []int) ([]int, error) {
+	n := max(len(x), len(y))
+	out := make([]int, n)
+	for k := 1; k <= n; k++ {
+		dx, dy := 1, 1
+		if k <= len(x) {
+			dx = x[len(x)-k]
+		}
+		if k <= len(y) {
+			dy = y[len(y)-k]
+		}
+		switch {
+		case dx == dy || dy == 1:
+			out[n-k] = dx
+		case dx == 1:
+			out[n-k] = dy
+		default:
+			return nil, ErrShape
+		}
+	}
+	return out, nil
+}
+
+/*BroadcastTo is a view of a stretched to shape. The stretched axes have stride 0 so they all share one element.*/
+func (a NDArray[N]) BroadcastTo(shape ...int) (NDArray[N], error) {
+	if len(shape) < len(a.shape) {
+		return NDArray[N]{}, ErrShape
+	}
+	v := NDArray[N]{data: a.data, shape: append([]int{}, shape...), strides: make([]int, len(shape)), offset: a.offset}
+	lead := len(shape) - len(a.shape)
+	for k, s := range a.shape {
+		switch s {
+		case shape[lead+k]:
+			v.strides[lead+k] = a.strides[k]
+		case 1:
+		default:
+			return NDArray[N]{}, ErrShape
+		}
+	}
+	return v, nil
+}
+
+/*zip broadcasts a against b and runs an element wise operation into a new array*/
+func (a NDArray[N]) zip(b NDArray[N], overflow []OverflowPolicy, intOp func(x wide, y wide) wide,
+	floatOp func(x N, y N) N) (NDArray[N], error) {
+	shape, err := broadcastShape(a.shape, b.shape)
+	if err != nil {
+		return NDArray[N]{}, err
+	}
+	a, _ = a.BroadcastTo(shape...)
+	b, _ = b.BroadcastTo(shape...)
+	out, _ := NewNDArray[N](shape, nil)
+	xs, ys := a.Data(), b.Data()
+	if len(xs) != len(out.data) || len(ys) != len(out.data) {
+		// only the zero NDArray gets here, it has no element to stretch
+		return NDArray[N]{}, ErrShape
+	}
+	policy := pickOverflowPolicy(overflow)
+	for i := range out.data {
+		if isFloat[N]() {
+			out.data[i] = floatOp(xs[i], ys[i])
+			continue
+		}
+		out.data[i] = fitWide[N](intOp(wideOf(xs[i]), wideOf(ys[i])), policy, &err)
+	}
+	return out, err
+}
+
+func (a NDArray[N]) Add(b NDArray[N], overflow ...OverflowPolicy) (NDArray[N], error) {
+	return a.zip(b, overflow,
+		func(x, y wide) wide { return x.add(y) },
+		func(x, y N) N { return x + y })
+}
+
+func (a NDArray[N]) Sub(b NDArray[N], overflow ...OverflowPolicy) (NDArray[N], error) {
+	return a.zip(b, overflow,
+		func(x, y wide) wide { y.neg = !y.neg && !y.isZero(); return x.add(y) },
+		func(x, y N) N { return x - y })
+}
+
+func (a NDArray[N]) Mul(b NDArray[N], overflow ...OverflowPolicy) (NDArray[N], error) {
+	return a.zip(b, overflow,
+		func(x, y wide) wide { return x.mul(y) },
+		func(x, y N) N { return x * y })
+}
+
+/*Div rounds integer quotients to nearest like DivVec, x/0 saturates toward the sign of x and 0/0 is 0*/
+func (a NDArray[N]) Div(b NDArray[N], overflow ...OverflowPolicy) (NDArray[N], error) {
+	return a.zip(b, overflow,
+		func(x, y wide) wide { q, _ := x.quo(y); return q },
+		func(x, y N) N { return x / y })
+}
+
+/*Map applies fn to every element into a new array of the same shape*/
+func (a NDArray[N]) Map(fn func(N) N) NDArray[N] {
+	out := a.Clone()
+	for i, x := range out.data {
+		out.data[i] = fn(x)
+	}
+	return out
+}
+
+/*
+groups splits the elements into the groups that reduce together along axes and returns them with the shape of the
+result. No axes is every axis.
+*/
+func (a NDArray[N]) groups(axes []int) ([][]N, []int, error) {
+	reduce := make([]bool, len(a.shape))
+	for _, ax := range axes {
+		if ax < 0 {
+			ax += len(a.shape)
+		}
+		if ax < 0 || ax >= len(a.shape) || reduce[ax] {
+			return nil, nil, ErrShape
+		}
+		reduce[ax] = true
+	}
+	var shape []int
+	for k, s := range a.shape {
+		if !reduce[k] && len(axes) > 0 {
+			shape = append(shape, s)
+		}
+	}
+	outStrides := make([]int, len(a.shape))
+	step := 1
+	for k := len(a.shape) - 1; k >= 0; k-- {
+		if !reduce[k] && len(axes) > 0 {
+			outStrides[k] = step
+			step *= a.shape[k]
+		}
+	}
+	groups := make([][]N, step)
+	a.walk(func(idx []int, off int) {
+		g := 0
+		for k, i := range idx {
+			g += i * outStrides[k]
+		}
+		groups[g] = append(groups[g], a.data[off])
+	})
+	return groups, shape, nil
+}
+
+/*
+Sum adds up along axes. Integers are summed exactly and fitted with the overflow policy, floats use the compensated
+sum that Sum uses.
+*/
+func (a NDArray[N]) Sum(axes []int, overflow ...OverflowPolicy) (NDArray[N], error) {
+	groups, shape, err := a.groups(axes)
+	if err != nil {
+		return NDArray[N]{}, err
+	}
+	out, _ := NewNDArray[N](shape, nil)
+	policy := pickOverflowPolicy(overflow)
+	for i, g := range groups {
+		if isFloat[N]() {
+			out.data[i] = compensatedSum[N](g)
+			continue
+		}
+		var total wide
+		for _, x := range g {
+			total = total.add(wideOf(x))
+		}
+		out.data[i] = fitWide[N](total, policy, &err)
+	}
+	return out, err
+}
+
+/*Max is the largest value along axes with MaxChecked rules, so NaN propagates by default and empty is ErrEmpty*/
+func (a NDArray[N]) Max(axes []int, nanPolicy ...NaNPolicy) (NDArray[N], error) {
+	return a.reduceChecked(nanPolicy, axes, MaxChecked[N])
+}
+
+func (a NDArray[N]) Min(axes []int, nanPolicy ...NaNPolicy) (NDArray[N], error) {
+	return a.reduceChecked(nanPolicy, axes, MinChecked[N])
+}
+
+func (a NDArray[N]) reduceChecked(nanPolicy []NaNPolicy, axes []int,
+	fn func(nums []N, nanPolicy ...NaNPolicy) (N, error)) (NDArray[N], error) {
+	groups, shape, err := a.groups(axes)
+	if err != nil {
+		return NDArray[N]{}, err
+	}
+	out, _ := NewNDArray[N](shape, nil)
+	for i, g := range groups {
+		if out.data[i], err = fn(g, nanPolicy...); err != nil {
+			return NDArray[N]{}, err
+		}
+	}
+	return out, nil
+}
+
+/*Mean is the average along axes as float64 like NumPy, an empty group is NaN*/
+func (a NDArray[N]) Mean(axes []int) (NDArray[float64], error) {
+	groups, shape, err := a.groups(axes)
+	if err != nil {
+		return NDArray[float64]{}, err
+	}
+	out, _ := NewNDArray[float64](shape, nil)
+	for i, g := range groups {
+		out.data[i] = Mean(g)
+	}
+	return out, nil
+}
+
+/*String nests brackets like NumPy without the line breaks, [[1 2] [3 4]]*/
+func (a NDArray[N]) String() string {
+	var sb strings.Builder
+	a.format(&sb)
+	return sb.String()
+}
+
+func (a NDArray[N]) format(sb *strings.Builder) {
+	if len(a.shape) == 0 {
+		if len(a.data) == 0 {
+			sb.WriteString("[]")
+			return
+		}
+		sb.WriteString(formatNumber(a.data[a.offset]))
+		return
+	}
+	sb.WriteByte('[')
+	for i := 0; i < a.shape[0]; i++ {
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+		sub, _ := a.Index(0, i)
+		sub.format(sb)
+	}
+	sb.WriteByte(']')
+}
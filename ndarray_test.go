@@ -0,0 +1,292 @@
+package RUNK
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func arange[N Number](shape ...int) NDArray[N] {
+	a, _ := NewNDArray[N](shape, nil)
+	for i := range a.data {
+		a.data[i] = N(i)
+	}
+	return a
+}
+
+/*pySlice is the list of indexes Python's a[start:stop:step] picks, with math.MinInt and math.MaxInt for None*/
+func pySlice(n int, start int, stop int, step int) []int {
+	adjust := func(i int) int {
+		switch {
+		case i < 0 && i+n < 0:
+			if step < 0 {
+				return -1
+			}
+			return 0
+		case i < 0:
+			return i + n
+		case i >= n:
+			if step < 0 {
+				return n - 1
+			}
+			return n
+		}
+		return i
+	}
+	var out []int
+	for i := adjust(start); (step > 0 && i < adjust(stop)) || (step < 0 && i > adjust(stop)); i += step {
+		out = append(out, i)
+	}
+	return out
+}
+
+func TestNDArraySliceMatchesPython(t *testing.T) {
+	rng := rand.New(rand.NewSource(21))
+	ends := []int{math.MinInt, math.MaxInt}
+	for trial := 0; trial < 2000; trial++ {
+		n := rng.Intn(7)
+		a := arange[int](n)
+		pick := func() int {
+			if rng.Intn(5) == 0 {
+				return ends[rng.Intn(2)]
+			}
+			return rng.Intn(19) - 9
+		}
+		start, stop, step := pick(), pick(), rng.Intn(7)-3
+		if step == 0 {
+			if _, err := a.Slice(0, start, stop, step); err != ErrShape {
+				t.Fatalf("step 0 = %v", err)
+			}
+			continue
+		}
+		v, err := a.Slice(0, start, stop, step)
+		want := pySlice(n, start, stop, step)
+		if got := v.Data(); err != nil || !slices.Equal(got, want) && len(got)+len(want) > 0 {
+			t.Fatalf("a[%d:%d:%d] of %d = %v %v, want %v", start, stop, step, n, got, err, want)
+		}
+	}
+}
+
+func TestNDArrayViews(t *testing.T) {
+	a := arange[int16](2, 3, 4)
+	if a.Ndim() != 3 || a.Size() != 24 || !slices.Equal(a.Strides(), []int{12, 4, 1}) {
+		t.Fatalf("shape %v strides %v size %d", a.Shape(), a.Strides(), a.Size())
+	}
+	if a.At(1, 2, 3) != 23 || a.At(-1, -1, -1) != 23 || a.At(2, 0, 0) != 0 || a.At(0, 0) != 0 {
+		t.Error("At with negative or bad indexes is wrong")
+	}
+	// a[:, 1, ::-2] in NumPy
+	row, _ := a.Index(1, 1)
+	v, _ := row.Slice(1, -1, math.MinInt, -2)
+	if s := v.String(); s != "[[7 5] [19 17]]" {
+		t.Errorf("a[:, 1, ::-2] = %s", s)
+	}
+	v.Set(-7, 0, 0)
+	if a.At(0, 1, 3) != -7 {
+		t.Error("Set through a view didn't reach the array")
+	}
+	tr, _ := a.Transpose()
+	if !slices.Equal(tr.Shape(), []int{4, 3, 2}) || tr.At(3, 2, 1) != a.At(1, 2, 3) {
+		t.Errorf("Transpose = %v", tr.Shape())
+	}
+	// reshaping a transposed view has to copy, reshaping a contiguous one must not
+	flat, err := tr.Reshape(-1)
+	if err != nil || flat.At(1) != a.At(1, 0, 0) || flat.At(2) != a.At(0, 1, 0) {
+		t.Errorf("Reshape of the transpose = %v %v", flat, err)
+	}
+	r, _ := a.Reshape(4, -1)
+	r.Set(100, 0, 0)
+	if a.At(0, 0, 0) != 100 || !slices.Equal(r.Shape(), []int{4, 6}) {
+		t.Error("Reshape of a contiguous array should be a view")
+	}
+	for _, bad := range [][]int{{5, -1}, {-1, -1}, {25}, {-2, 12}} {
+		if _, err := a.Reshape(bad...); err != ErrShape {
+			t.Errorf("Reshape%v = %v", bad, err)
+		}
+	}
+	for _, bad := range [][]int{{0, 1}, {0, 0, 1}, {0, 1, 3}} {
+		if _, err := a.Transpose(bad...); err != ErrShape {
+			t.Errorf("Transpose%v = %v", bad, err)
+		}
+	}
+	if _, err := a.Index(3, 0); err != ErrShape {
+		t.Errorf("Index on axis 3 = %v", err)
+	}
+	if _, err := a.Index(0, 2); err != ErrShape {
+		t.Errorf("Index past the end = %v", err)
+	}
+	c := a.Clone()
+	c.Set(1, 0, 0, 0)
+	if a.At(0, 0, 0) == 1 {
+		t.Error("Clone shares data")
+	}
+	if _, err := NewNDArray([]int{2, -1}, []int{}); err != ErrShape {
+		t.Errorf("negative size = %v", err)
+	}
+	if _, err := NewNDArray([]int{2, 2}, []int{1}); err != ErrShape {
+		t.Errorf("short data = %v", err)
+	}
+	if m := NDArrayFromMat(Identity[uint8](2)); m.String() != "[[1 0] [0 1]]" {
+		t.Errorf("NDArrayFromMat = %v", m)
+	}
+}
+
+func TestNDArrayBroadcasting(t *testing.T) {
+	col := arange[int32](3, 1)
+	row := arange[int32](4)
+	sum, err := col.Add(row)
+	if err != nil || !slices.Equal(sum.Shape(), []int{3, 4}) || sum.At(2, 3) != 5 {
+		t.Fatalf("(3,1) + (4,) = %v %v", sum, err)
+	}
+	scalar, _ := NewNDArray([]int{}, []int32{10})
+	if p, _ := scalar.Mul(sum); p.At(2, 3) != 50 || !slices.Equal(p.Shape(), []int{3, 4}) {
+		t.Errorf("scalar * (3,4) = %v", p)
+	}
+	if _, err := arange[int32](3).Add(row); err != ErrShape {
+		t.Errorf("(3,) + (4,) = %v", err)
+	}
+	b, _ := row.BroadcastTo(2, 4)
+	if !slices.Equal(b.Strides(), []int{0, 1}) || b.At(1, 3) != 3 {
+		t.Errorf("BroadcastTo strides %v", b.Strides())
+	}
+	if _, err := row.BroadcastTo(2, 3); err != ErrShape {
+		t.Errorf("BroadcastTo a mismatched shape = %v", err)
+	}
+	if _, err := arange[int](2, 2).BroadcastTo(4); err != ErrShape {
+		t.Errorf("BroadcastTo fewer axes = %v", err)
+	}
+
+	x, _ := NewNDArray([]int{4}, []int8{100, -100, 7, -7})
+	y, _ := NewNDArray([]int{1}, []int8{100})
+	tests := []struct {
+		name   string
+		op     func(NDArray[int8], ...OverflowPolicy) (NDArray[int8], error)
+		policy OverflowPolicy
+		want   []int8
+		err    error
+	}{
+		{"add", x.Add, Saturate, []int8{127, 0, 107, 93}, ErrOverflow},
+		{"add", x.Add, Wrap, []int8{-56, 0, 107, 93}, ErrOverflow},
+		{"sub", x.Sub, Saturate, []int8{0, -128, -93, -107}, ErrOverflow},
+		{"sub", x.Sub, Wrap, []int8{0, 56, -93, -107}, ErrOverflow},
+		{"mul", x.Mul, Wrap, []int8{16, -16, -68, 68}, ErrOverflow},
+		{"div", x.Div, Saturate, []int8{1, -1, 0, 0}, nil},
+	}
+	for _, tt := range tests {
+		got, err := tt.op(y, tt.policy)
+		if !slices.Equal(got.Data(), tt.want) || err != tt.err {
+			t.Errorf("%s policy %d = %v %v, want %v %v", tt.name, tt.policy, got.Data(), err, tt.want, tt.err)
+		}
+	}
+	zeros, _ := NewNDArray[int8]([]int{1}, nil)
+	if q, _ := x.Div(zeros); !slices.Equal(q.Data(), []int8{127, -128, 127, -128}) {
+		t.Errorf("x / 0 = %v", q.Data())
+	}
+	if q, _ := x.Div(x); !slices.Equal(q.Data(), []int8{1, 1, 1, 1}) {
+		t.Errorf("x / x = %v", q.Data())
+	}
+	f, _ := NewNDArray([]int{3}, []float64{1, -1, 0})
+	fz, _ := NewNDArray([]int{1}, []float64{0})
+	if q, _ := f.Div(fz); !math.IsInf(q.At(0), 1) || !math.IsInf(q.At(1), -1) || q.At(2) == q.At(2) {
+		t.Errorf("float x / 0 = %v", q)
+	}
+	if m := f.Map(func(x float64) float64 { return x * 2 }); m.String() != "[2 -2 0]" || f.At(0) != 1 {
+		t.Errorf("Map = %v", m)
+	}
+}
+
+func TestNDArrayReductions(t *testing.T) {
+	a := arange[int64](2, 3)
+	tests := []struct {
+		axes []int
+		want string
+	}{
+		{nil, "15"},
+		{[]int{0}, "[3 5 7]"},
+		{[]int{1}, "[3 12]"},
+		{[]int{-1}, "[3 12]"},
+		{[]int{0, 1}, "15"},
+	}
+	for _, tt := range tests {
+		s, err := a.Sum(tt.axes)
+		if err != nil || s.String() != tt.want {
+			t.Errorf("Sum(%v) = %v %v, want %s", tt.axes, s, err, tt.want)
+		}
+	}
+	for _, bad := range [][]int{{2}, {-3}, {0, 0}} {
+		if _, err := a.Sum(bad); err != ErrShape {
+			t.Errorf("Sum(%v) = %v", bad, err)
+		}
+	}
+	// the int64 sum is exact, so it only overflows if the total does
+	big, _ := NewNDArray([]int{2, 2}, []int64{math.MaxInt64, math.MaxInt64, math.MinInt64, -1})
+	if s, err := big.Sum([]int{1}); err != ErrOverflow || s.String() != "[9223372036854775807 -9223372036854775808]" {
+		t.Errorf("saturated Sum = %v %v", s, err)
+	}
+	if s, err := big.Sum([]int{1}, Wrap); err != ErrOverflow || s.String() != "[-2 9223372036854775807]" {
+		t.Errorf("wrapped Sum = %v %v", s, err)
+	}
+	if s, err := big.Sum(nil); err != nil || s.At() != math.MaxInt64-2 {
+		t.Errorf("Sum of everything = %v %v", s, err)
+	}
+	f, _ := NewNDArray([]int{2, 3}, []float64{1e16, 1, -1e16, math.NaN(), 1, 2})
+	if s, _ := f.Sum([]int{1}); s.At(0) != 1 || s.At(1) == s.At(1) {
+		t.Errorf("float Sum = %v", s)
+	}
+	if m, err := f.Max([]int{1}); err != nil || m.At(0) != 1e16 || m.At(1) == m.At(1) {
+		t.Errorf("Max = %v %v", m, err)
+	}
+	if m, err := f.Min([]int{1}, NaNIgnore); err != nil || m.String() != "[-1e+16 1]" {
+		t.Errorf("Min ignoring NaN = %v %v", m, err)
+	}
+	if m, err := a.Mean([]int{0}); err != nil || m.String() != "[1.5 2.5 3.5]" {
+		t.Errorf("Mean = %v %v", m, err)
+	}
+
+	empty, _ := NewNDArray[int]([]int{0, 3}, nil)
+	if s, err := empty.Sum([]int{0}); err != nil || s.String() != "[0 0 0]" {
+		t.Errorf("Sum over an empty axis = %v %v", s, err)
+	}
+	if _, err := empty.Max([]int{0}); err != ErrEmpty {
+		t.Errorf("Max over an empty axis = %v", err)
+	}
+	if m, _ := empty.Mean(nil); m.At() == m.At() {
+		t.Errorf("Mean of nothing = %v", m)
+	}
+	if s, err := empty.Sum([]int{1}); err != nil || s.Size() != 0 || !slices.Equal(s.Shape(), []int{0}) {
+		t.Errorf("Sum keeping the empty axis = %v %v", s, err)
+	}
+}
+
+func TestNDArrayAsTypeAndEmpty(t *testing.T) {
+	f, _ := NewNDArray([]int{2, 3}, []float64{math.NaN(), math.Inf(1), math.Inf(-1), 2.5, -2.5, 300})
+	u, _ := f.Transpose()
+	if got := AsType[uint8](u).String(); got != "[[0 3] [255 0] [0 255]]" {
+		t.Errorf("AsType[uint8] of the transpose = %s", got)
+	}
+	if got := AsType[int8](f, math.Trunc).String(); got != "[[0 127 -128] [2 -2 127]]" {
+		t.Errorf("AsType[int8] toward zero = %s", got)
+	}
+	var zero NDArray[float64]
+	if z := AsType[int](zero); z.Size() != 0 || z.Ndim() != 0 || z.String() != "[]" || z.At() != 0 {
+		t.Errorf("AsType of the zero NDArray = %v size %d", z, z.Size())
+	}
+	empty, _ := NewNDArray[float32]([]int{0, 3}, nil)
+	if z := AsType[int16](empty); z.Size() != 0 || !slices.Equal(z.Shape(), []int{0, 3}) {
+		t.Errorf("AsType of a (0,3) array = %v %v", z, z.Shape())
+	}
+	one := arange[float64](3)
+	if _, err := zero.Add(one); err != ErrShape {
+		t.Errorf("zero NDArray + (3,) = %v", err)
+	}
+	if _, err := one.Mul(zero); err != ErrShape {
+		t.Errorf("(3,) * zero NDArray = %v", err)
+	}
+	if zero.Set(1) || zero.Data() == nil || len(zero.Data()) != 0 || zero.Clone().Size() != 0 {
+		t.Error("the zero NDArray should hold nothing")
+	}
+	if _, err := zero.Max(nil); err != ErrEmpty {
+		t.Errorf("Max of the zero NDArray = %v", err)
+	}
+}
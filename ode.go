@@ -0,0 +1,231 @@
+package RUNK
+
+import (
+	"math"
+)
+
+/*
+The ODE solvers take y' = f(t, y) for a vector y and step it from t0 to t1, which may be before t0 to go
+backwards. f gets the state and returns the derivative as a new slice of the same length. Like the quadrature
+everything is done in N so a float32 system stays float32. They return every accepted time and state starting
+with t0 and y0, the last pair is the answer at t1. On an error the path so far is returned with it.
+*/
+
+/*
+StepControl tunes the adaptive ODE solvers. Tolerance is per component, a step is kept when its error estimate
+is within Abs + Rel*|y| for every component in the root mean square sense, and MaxIter caps the number of steps.
+Left empty both Abs and Rel get the quadrature default.
+Initial is the first step size, zero lets the solver pick one. Min and Max bound the step size, zero Max means
+the whole range and when the step has to shrink below Min, or below what the float spacing of t allows, it is
+ErrNoConvergence.
+*/
+type StepControl struct {
+	Tolerance
+	Initial float64
+	Min     float64
+	Max     float64
+}
+
+func pickStepControl[N Float](ctl []StepControl) StepControl {
+	var c StepControl
+	if len(ctl) > 0 {
+		c = ctl[0]
+	}
+	// an empty tolerance gets the quadrature default for both parts, Rel alone would ask the impossible near y = 0
+	if c.Abs <= 0 && c.Rel <= 0 {
+		c.Rel = math.Pow(epsilonOf[N](), 0.75)
+		c.Abs = c.Rel
+	}
+	c.Tolerance = pickTolerance[N]([]Tolerance{c.Tolerance}, 100000)
+	return c
+}
+
+/*axpy returns y + h*(sum of c[i]*k[i]), the building block of every Runge-Kutta stage*/
+func axpy[N Float](y []N, h N, c []float64, k [][]N) []N {
+	out := make([]N, len(y))
+	for i := range y {
+		var s N
+		for j, cj := range c {
+			if cj != 0 {
+				s += N(cj) * k[j][i]
+			}
+		}
+		out[i] = y[i] + h*s
+	}
+	return out
+}
+
+/*derivative calls f and checks it kept the shape of the state*/
+func derivative[N Float](f func(N, []N) []N, t N, y []N) ([]N, error) {
+	dy := f(t, y)
+	if len(dy) != len(y) {
+		return nil, ErrShape
+	}
+	return dy, nil
+}
+
+func finiteState[N Float](y []N) bool {
+	for _, v := range y {
+		if IsNaN(v) || IsInf(v, 0) {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+RK4 steps from t0 to t1 in a fixed number of equal steps with the classic fourth order Runge-Kutta method. It has
+no error control, so it is for when the step size is known to be small enough. It returns ErrNoConvergence if
+the solution stops being finite.
+*/
+func RK4[N Float](f func(t N, y []N) []N, t0 N, y0 []N, t1 N, steps int) ([]N, [][]N, error) {
+	steps = max(steps, 1)
+	y := append([]N(nil), y0...)
+	ts, ys := []N{t0}, [][]N{y}
+	h := (t1 - t0) / N(steps)
+	t := t0
+	for i := 0; i < steps; i++ {
+		k1, err := derivative(f, t, y)
+		if err != nil {
+			return ts, ys, err
+		}
+		k2, err := derivative(f, t+h/2, axpy(y, h, []float64{0.5}, [][]N{k1}))
+		if err != nil {
+			return ts, ys, err
+		}
+		k3, err := derivative(f, t+h/2, axpy(y, h, []float64{0, 0.5}, [][]N{k1, k2}))
+		if err != nil {
+			return ts, ys, err
+		}
+		k4, err := derivative(f, t+h, axpy(y, h, []float64{0, 0, 1}, [][]N{k1, k2, k3}))
+		if err != nil {
+			return ts, ys, err
+		}
+		y = axpy(y, h, []float64{1.0 / 6, 1.0 / 3, 1.0 / 3, 1.0 / 6}, [][]N{k1, k2, k3, k4})
+		// the last step lands on t1 exactly rather than on the rounded sum of the steps
+		t = t0 + N(i+1)*h
+		if i == steps-1 {
+			t = t1
+		}
+		ts, ys = append(ts, t), append(ys, y)
+		if !finiteState(y) {
+			return ts, ys, ErrNoConvergence
+		}
+	}
+	return ts, ys, nil
+}
+
+/*the Dormand-Prince 5(4) tableau, the last row of dpA is the fifth order solution and dpE its difference to the fourth*/
+var (
+	dpC = [6]float64{0, 1.0 / 5, 3.0 / 10, 4.0 / 5, 8.0 / 9, 1}
+	dpA = [7][]float64{
+		{},
+		{1.0 / 5},
+		{3.0 / 40, 9.0 / 40},
+		{44.0 / 45, -56.0 / 15, 32.0 / 9},
+		{19372.0 / 6561, -25360.0 / 2187, 64448.0 / 6561, -212.0 / 729},
+		{9017.0 / 3168, -355.0 / 33, 46732.0 / 5247, 49.0 / 176, -5103.0 / 18656},
+		{35.0 / 384, 0, 500.0 / 1113, 125.0 / 192, -2187.0 / 6784, 11.0 / 84},
+	}
+	dpE = []float64{71.0 / 57600, 0, -71.0 / 16695, 71.0 / 1920, -17253.0 / 339200, 22.0 / 525, -1.0 / 40}
+)
+
+/*
+DormandPrince steps from t0 to t1 with the adaptive Dormand-Prince 5(4) method, the one behind ode45 and
+RK45. Each step is checked against a fourth order solution and redone smaller when the difference is over
+tolerance, and the next step is grown or shrunk to match. The last stage is reused as the first of the next
+step so an accepted step costs 6 calls of f.
+*/
+func DormandPrince[N Float](f func(t N, y []N) []N, t0 N, y0 []N, t1 N, ctl ...StepControl) ([]N, [][]N, error) {
+	c := pickStepControl[N](ctl)
+	y := append([]N(nil), y0...)
+	ts, ys := []N{t0}, [][]N{y}
+	if t0 == t1 {
+		return ts, ys, nil
+	}
+	span := float64(t1 - t0)
+	dir := math.Copysign(1, span)
+	maxStep := math.Abs(span)
+	if c.Max > 0 {
+		maxStep = min(maxStep, c.Max)
+	}
+	k := make([][]N, 7)
+	var err error
+	if k[0], err = derivative(f, t0, y); err != nil {
+		return ts, ys, err
+	}
+	// ratio is how big d is against what is allowed for a component that is at a and b
+	ratio := func(d N, a N, b N) float64 {
+		if d == 0 {
+			return 0
+		}
+		return float64(d) / (c.Abs + c.Rel*math.Max(math.Abs(float64(a)), math.Abs(float64(b))))
+	}
+	h := math.Abs(c.Initial)
+	if h == 0 {
+		// the step that moves y by about 1% of its own size, from Hairer, Norsett and Wanner
+		var d0, d1 float64
+		for i := range y {
+			d0 += math.Pow(ratio(y[i], y[i], y[i]), 2)
+			d1 += math.Pow(ratio(k[0][i], y[i], y[i]), 2)
+		}
+		h = 1e-6
+		if d0 > 1e-10 && d1 > 1e-10 {
+			h = 0.01 * math.Sqrt(d0/d1)
+		}
+	}
+	h = min(h, maxStep)
+	t := t0
+	for step := 0; ; step++ {
+		if step >= c.MaxIter {
+			return ts, ys, ErrNoConvergence
+		}
+		last := false
+		if h >= math.Abs(float64(t1-t)) {
+			h, last = math.Abs(float64(t1-t)), true
+		}
+		hn := N(dir * h)
+		if t+hn == t || h < c.Min {
+			return ts, ys, ErrNoConvergence
+		}
+		for s := 1; s < 6; s++ {
+			if k[s], err = derivative(f, t+N(dpC[s])*hn, axpy(y, hn, dpA[s], k[:s])); err != nil {
+				return ts, ys, err
+			}
+		}
+		next := axpy(y, hn, dpA[6], k[:6])
+		if k[6], err = derivative(f, t+hn, next); err != nil {
+			return ts, ys, err
+		}
+		diff := axpy(make([]N, len(y)), hn, dpE, k)
+		var e float64
+		for i := range y {
+			e += math.Pow(ratio(diff[i], y[i], next[i]), 2)
+		}
+		e = math.Sqrt(e / float64(max(len(y), 1)))
+		if math.IsNaN(e) || !finiteState(next) {
+			// the step was too big to even give numbers back, try a much smaller one
+			h /= 10
+			continue
+		}
+		// the classic 0.9 safety factor, never shrinking more than 5 times or growing more than 5 times at once
+		factor := 5.0
+		if e > 0 {
+			factor = min(5, max(0.2, 0.9*math.Pow(e, -0.2)))
+		}
+		if e > 1 {
+			h *= factor
+			continue
+		}
+		t, y = t+hn, next
+		if last {
+			t = t1
+		}
+		ts, ys = append(ts, t), append(ys, y)
+		if last {
+			return ts, ys, nil
+		}
+		k[0] = k[6]
+		h = min(h*factor, maxStep)
+	}
+}
@@ -0,0 +1,119 @@
+package RUNK
+
+import (
+	"math"
+	"testing"
+)
+
+/*oscillator is y'' = -y as a first order system, starting at (1, 0) it is (cos t, -sin t)*/
+func oscillator[N Float](t N, y []N) []N {
+	return []N{y[1], -y[0]}
+}
+
+func TestRK4(t *testing.T) {
+	grow := func(t float64, y []float64) []float64 { return []float64{y[0]} }
+	errAt := func(steps int) float64 {
+		ts, ys, err := RK4(grow, 0, []float64{1}, 1, steps)
+		if err != nil || len(ts) != steps+1 || ts[steps] != 1 {
+			t.Fatalf("RK4 with %d steps: %d points ending at %v, %v", steps, len(ts), ts[len(ts)-1], err)
+		}
+		return math.Abs(ys[steps][0] - math.E)
+	}
+	if e := errAt(100); e > 1e-9 {
+		t.Errorf("RK4 error with 100 steps = %v", e)
+	}
+	// fourth order, so halving the step cuts the error about 16 times
+	if r := errAt(10) / errAt(20); r < 14 || r > 18 {
+		t.Errorf("RK4 error ratio = %v", r)
+	}
+
+	ts, ys, err := RK4(oscillator[float64], 2*math.Pi, []float64{1, 0}, 0, 1000)
+	if err != nil || ts[len(ts)-1] != 0 || math.Abs(ys[len(ys)-1][0]-1) > 1e-10 || math.Abs(ys[len(ys)-1][1]) > 1e-10 {
+		t.Errorf("RK4 backwards = %v, %v", ys[len(ys)-1], err)
+	}
+	if ys[0][0] != 1 || len(ys[0]) != 2 {
+		t.Errorf("RK4 should start with y0, got %v", ys[0])
+	}
+	ts32, ys32, err := RK4(oscillator[float32], 0, []float32{1, 0}, math.Pi, 200)
+	if err != nil || Abs(ys32[len(ys32)-1][0]+1) > 1e-5 || ts32[len(ts32)-1] != math.Pi {
+		t.Errorf("float32 RK4 = %v, %v", ys32[len(ys32)-1], err)
+	}
+	if _, ys, err := RK4(grow, 0, []float64{1}, 1, 0); err != nil || len(ys) != 2 {
+		t.Errorf("RK4 with 0 steps takes 1: %d points, %v", len(ys), err)
+	}
+
+	y0 := []float64{1}
+	ts, ys, err = RK4(func(t float64, y []float64) []float64 { return []float64{y[0] * y[0]} }, 0, y0, 2, 10)
+	if err != ErrNoConvergence || len(ts) != len(ys) || len(ts) > 10 {
+		t.Errorf("RK4 through a blow up = %d points, %v", len(ts), err)
+	}
+	if y0[0] != 1 {
+		t.Error("RK4 changed y0")
+	}
+	if _, _, err := RK4(func(t float64, y []float64) []float64 { return nil }, 0, y0, 1, 10); err != ErrShape {
+		t.Errorf("RK4 with a bad f = %v", err)
+	}
+}
+
+func TestDormandPrince(t *testing.T) {
+	calls := 0
+	f := func(t float64, y []float64) []float64 {
+		calls++
+		return oscillator(t, y)
+	}
+	ts, ys, err := DormandPrince(f, 0, []float64{1, 0}, 10*math.Pi)
+	end := ys[len(ys)-1]
+	if err != nil || ts[len(ts)-1] != 10*math.Pi || math.Abs(end[0]-1) > 1e-8 || math.Abs(end[1]) > 1e-8 {
+		t.Errorf("DormandPrince oscillator = %v at %v, %v", end, ts[len(ts)-1], err)
+	}
+	for i := 1; i < len(ts); i++ {
+		if ts[i] <= ts[i-1] {
+			t.Fatalf("times go backwards at %d: %v %v", i, ts[i-1], ts[i])
+		}
+		if d := math.Abs(ys[i][0] - math.Cos(ts[i])); d > 1e-8 {
+			t.Fatalf("y(%v) is off by %v", ts[i], d)
+		}
+	}
+	// each accepted step reuses its last stage, so a smooth run costs about 6 calls a step
+	if calls > 8*len(ts) {
+		t.Errorf("%d calls for %d steps", calls, len(ts))
+	}
+
+	// looser tolerance, fewer steps
+	loose, _, err := DormandPrince(oscillator[float64], 0, []float64{1, 0}, 10*math.Pi, StepControl{Tolerance: Tolerance{Rel: 1e-4, Abs: 1e-4}})
+	if err != nil || len(loose) >= len(ts) {
+		t.Errorf("a loose tolerance took %d steps against %d, %v", len(loose), len(ts), err)
+	}
+	capped, _, err := DormandPrince(oscillator[float64], 0, []float64{1, 0}, 1, StepControl{Max: 0.01})
+	if err != nil || len(capped) < 101 {
+		t.Errorf("Max 0.01 over 1 took %d steps, %v", len(capped)-1, err)
+	}
+
+	ts, ys, err = DormandPrince(func(t float64, y []float64) []float64 { return []float64{-2 * t * y[0]} }, 2, []float64{math.Exp(-4)}, -1)
+	if err != nil || ts[len(ts)-1] != -1 || math.Abs(ys[len(ys)-1][0]-math.Exp(-1)) > 1e-9 {
+		t.Errorf("DormandPrince backwards = %v at %v, %v", ys[len(ys)-1], ts[len(ts)-1], err)
+	}
+	ts32, ys32, err := DormandPrince(oscillator[float32], 0, []float32{1, 0}, math.Pi)
+	if err != nil || Abs(ys32[len(ys32)-1][0]+1) > 1e-4 || ts32[len(ts32)-1] != math.Pi {
+		t.Errorf("float32 DormandPrince = %v, %v", ys32[len(ys32)-1], err)
+	}
+	if ts, ys, err := DormandPrince(oscillator[float64], 1, []float64{1, 0}, 1); err != nil || len(ts) != 1 || len(ys) != 1 {
+		t.Errorf("empty range = %v %v, %v", ts, ys, err)
+	}
+
+	// y' = y^2 from 1 blows up at t = 1, the step can't get past it
+	blow := func(t float64, y []float64) []float64 { return []float64{y[0] * y[0]} }
+	ts, _, err = DormandPrince(blow, 0, []float64{1}, 2)
+	if err != ErrNoConvergence || ts[len(ts)-1] >= 1 || ts[len(ts)-1] < 0.99 {
+		t.Errorf("DormandPrince through a blow up stopped at %v, %v", ts[len(ts)-1], err)
+	}
+	if _, _, err := DormandPrince(blow, 0, []float64{1}, 2, StepControl{Min: 1e-3}); err != ErrNoConvergence {
+		t.Errorf("DormandPrince under Min = %v", err)
+	}
+	if _, _, err := DormandPrince(oscillator[float64], 0, []float64{1, 0}, 100, StepControl{Tolerance: Tolerance{MaxIter: 5}}); err != ErrNoConvergence {
+		t.Errorf("DormandPrince out of steps = %v", err)
+	}
+	if _, _, err := DormandPrince(func(t float64, y []float64) []float64 { return y[:1] }, 0, []float64{1, 0}, 1); err != ErrShape {
+		t.Errorf("DormandPrince with a bad f = %v", err)
+	}
+}
@@ -0,0 +1,513 @@
+package RUNK
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"math/cmplx"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrInexact       = errors.New("RUNK: result is not exact in this type")
+	ErrNoConvergence = errors.New("RUNK: did not converge")
+)
+
+/*
+Poly is a polynomial with coefficients of type N, stored lowest power first so NewPoly(1, 2, 3) is 1 + 2x + 3x^2
+(the numpy.polynomial order, not the old polyval one). Trailing zero coefficients are dropped, the zero polynomial
+has no coefficients and degree -1.
+With integer coefficients the arithmetic is exact on big integers and the result is fitted into N with the overflow
+policy, if a coefficient didn't fit you get the saturated (or wrapped) polynomial back along with ErrOverflow, the
+same as Mat. Float coefficients are worked in float64 and rounded into N once at the end.
+*/
+type Poly[N Number] struct {
+	c []N
+}
+
+func NewPoly[N Number](coeffs ...N) Poly[N] {
+	return Poly[N]{c: trimPoly(append([]N(nil), coeffs...))}
+}
+
+func trimPoly[N Number](c []N) []N {
+	for len(c) > 0 && c[len(c)-1] == 0 {
+		c = c[:len(c)-1]
+	}
+	return c
+}
+
+/*Coeffs returns a copy of the coefficients, lowest power first*/
+func (p Poly[N]) Coeffs() []N {
+	return append([]N(nil), p.c...)
+}
+
+func (p Poly[N]) Degree() int {
+	return len(p.c) - 1
+}
+
+/*Coeff is the coefficient of x^i, 0 past the degree*/
+func (p Poly[N]) Coeff(i int) N {
+	if i < 0 || i >= len(p.c) {
+		return 0
+	}
+	return p.c[i]
+}
+
+func (p Poly[N]) Equal(q Poly[N]) bool {
+	if len(p.c) != len(q.c) {
+		return false
+	}
+	for i := range p.c {
+		if p.c[i] != q.c[i] {
+			return false
+		}
+	}
+	return true
+}
+
+/*String prints the highest power first, like 3x^2 - x + 1*/
+func (p Poly[N]) String() string {
+	if len(p.c) == 0 {
+		return "0"
+	}
+	var sb strings.Builder
+	for i := len(p.c) - 1; i >= 0; i-- {
+		c := p.c[i]
+		if c == 0 {
+			continue
+		}
+		s := formatNumber(c)
+		neg := strings.HasPrefix(s, "-")
+		s = strings.TrimPrefix(s, "-")
+		switch {
+		case sb.Len() == 0 && neg:
+			sb.WriteString("-")
+		case sb.Len() > 0 && neg:
+			sb.WriteString(" - ")
+		case sb.Len() > 0:
+			sb.WriteString(" + ")
+		}
+		if s != "1" || i == 0 {
+			sb.WriteString(s)
+		}
+		if i > 0 {
+			sb.WriteString("x")
+		}
+		if i > 1 {
+			sb.WriteString("^" + formatNumber(i))
+		}
+	}
+	return sb.String()
+}
+
+/*
+Eval works out p(x) with Horner's rule. Floats use a fused multiply add for every step, integers are exact and
+saturate like ConvertNumber, use EvalChecked to find out when that happened.
+*/
+func (p Poly[N]) Eval(x N) N {
+	y, _ := p.EvalChecked(x)
+	return y
+}
+
+/*EvalChecked is Eval that reports ErrOverflow when an integer result had to be saturated*/
+func (p Poly[N]) EvalChecked(x N, overflow ...OverflowPolicy) (N, error) {
+	if isFloat[N]() {
+		return N(p.EvalFloat(float64(x))), nil
+	}
+	bx, y := bigFromInt(x), new(big.Int)
+	for i := len(p.c) - 1; i >= 0; i-- {
+		y.Mul(y, bx).Add(y, bigFromInt(p.c[i]))
+	}
+	return fitBig[N](y, pickOverflowPolicy(overflow))
+}
+
+/*EvalFloat evaluates at a float64 point whatever N is, handy for integer calibration tables*/
+func (p Poly[N]) EvalFloat(x float64) float64 {
+	y := 0.0
+	for i := len(p.c) - 1; i >= 0; i-- {
+		y = math.FMA(y, x, float64(p.c[i]))
+	}
+	return y
+}
+
+/*EvalComplex evaluates at a complex point, which is what the root finder needs*/
+func (p Poly[N]) EvalComplex(z complex128) complex128 {
+	var y complex128
+	for i := len(p.c) - 1; i >= 0; i-- {
+		y = y*z + complex(float64(p.c[i]), 0)
+	}
+	return y
+}
+
+func (p Poly[N]) bigs() []*big.Int {
+	b := make([]*big.Int, len(p.c))
+	for i, c := range p.c {
+		b[i] = bigFromInt(c)
+	}
+	return b
+}
+
+func (p Poly[N]) floats() []float64 {
+	return float64sOf(p.c)
+}
+
+/*polyFromBigs fits exact coefficients into N keeping the first error*/
+func polyFromBigs[N Number](b []*big.Int, policy OverflowPolicy) (Poly[N], error) {
+	var err error
+	c := make([]N, len(b))
+	for i, x := range b {
+		var e error
+		if c[i], e = fitBig[N](x, policy); e != nil && err == nil {
+			err = e
+		}
+	}
+	return Poly[N]{c: trimPoly(c)}, err
+}
+
+func polyFromFloats[N Number](f []float64) Poly[N] {
+	c := make([]N, len(f))
+	for i, x := range f {
+		c[i] = N(x)
+	}
+	return Poly[N]{c: trimPoly(c)}
+}
+
+func (p Poly[N]) Add(q Poly[N], overflow ...OverflowPolicy) (Poly[N], error) {
+	return p.combine(q, 1, overflow)
+}
+
+func (p Poly[N]) Sub(q Poly[N], overflow ...OverflowPolicy) (Poly[N], error) {
+	return p.combine(q, -1, overflow)
+}
+
+/*combine is p + sign*q*/
+func (p Poly[N]) combine(q Poly[N], sign int64, overflow []OverflowPolicy) (Poly[N], error) {
+	n := max(len(p.c), len(q.c))
+	if isFloat[N]() {
+		f := make([]float64, n)
+		for i := range f {
+			f[i] = float64(p.Coeff(i)) + float64(sign)*float64(q.Coeff(i))
+		}
+		return polyFromFloats[N](f), nil
+	}
+	b := make([]*big.Int, n)
+	for i := range b {
+		t := new(big.Int).Mul(bigFromInt(q.Coeff(i)), big.NewInt(sign))
+		b[i] = t.Add(t, bigFromInt(p.Coeff(i)))
+	}
+	return polyFromBigs[N](b, pickOverflowPolicy(overflow))
+}
+
+func (p Poly[N]) Mul(q Poly[N], overflow ...OverflowPolicy) (Poly[N], error) {
+	if len(p.c) == 0 || len(q.c) == 0 {
+		return Poly[N]{}, nil
+	}
+	n := len(p.c) + len(q.c) - 1
+	if isFloat[N]() {
+		f := make([]float64, n)
+		for i, a := range p.c {
+			for j, b := range q.c {
+				f[i+j] = math.FMA(float64(a), float64(b), f[i+j])
+			}
+		}
+		return polyFromFloats[N](f), nil
+	}
+	b := make([]*big.Int, n)
+	for i := range b {
+		b[i] = new(big.Int)
+	}
+	pb, qb := p.bigs(), q.bigs()
+	for i := range pb {
+		for j := range qb {
+			b[i+j].Add(b[i+j], new(big.Int).Mul(pb[i], qb[j]))
+		}
+	}
+	return polyFromBigs[N](b, pickOverflowPolicy(overflow))
+}
+
+/*
+DivMod divides p by d and returns the quotient and remainder with p = q*d + r. Floats always bring the remainder
+below the degree of d. Integers can only do that when the leading coefficient of d keeps dividing exactly (always
+true when it is 1 or -1), when it stops dividing the division ends there and ErrInexact comes back with a q and r
+that still satisfy p = q*d + r. Dividing by the zero polynomial is ErrDivideByZero.
+*/
+func (p Poly[N]) DivMod(d Poly[N], overflow ...OverflowPolicy) (Poly[N], Poly[N], error) {
+	if len(d.c) == 0 {
+		return Poly[N]{}, Poly[N]{}, ErrDivideByZero
+	}
+	if len(p.c) < len(d.c) {
+		return Poly[N]{}, p, nil
+	}
+	dn := len(d.c) - 1
+	if isFloat[N]() {
+		r, df := p.floats(), d.floats()
+		q := make([]float64, len(r)-dn)
+		for k := len(q) - 1; k >= 0; k-- {
+			q[k] = r[k+dn] / df[dn]
+			for j := 0; j <= dn; j++ {
+				r[k+j] -= q[k] * df[j]
+			}
+		}
+		return polyFromFloats[N](q), polyFromFloats[N](r[:dn]), nil
+	}
+	r, db := p.bigs(), d.bigs()
+	q := make([]*big.Int, len(r)-dn)
+	for i := range q {
+		q[i] = new(big.Int)
+	}
+	var err error
+	for k := len(q) - 1; k >= 0; k-- {
+		quo, rem := new(big.Int).QuoRem(r[k+dn], db[dn], new(big.Int))
+		if rem.Sign() != 0 {
+			err = ErrInexact
+			break
+		}
+		q[k] = quo
+		for j := 0; j <= dn; j++ {
+			r[k+j] = new(big.Int).Sub(r[k+j], new(big.Int).Mul(quo, db[j]))
+		}
+	}
+	policy := pickOverflowPolicy(overflow)
+	qp, qerr := polyFromBigs[N](q, policy)
+	rp, rerr := polyFromBigs[N](r, policy)
+	return qp, rp, errors.Join(err, qerr, rerr)
+}
+
+/*Derivative is p'*/
+func (p Poly[N]) Derivative(overflow ...OverflowPolicy) (Poly[N], error) {
+	if len(p.c) <= 1 {
+		return Poly[N]{}, nil
+	}
+	if isFloat[N]() {
+		f := make([]float64, len(p.c)-1)
+		for i := range f {
+			f[i] = float64(i+1) * float64(p.c[i+1])
+		}
+		return polyFromFloats[N](f), nil
+	}
+	b := make([]*big.Int, len(p.c)-1)
+	for i := range b {
+		b[i] = new(big.Int).Mul(big.NewInt(int64(i+1)), bigFromInt(p.c[i+1]))
+	}
+	return polyFromBigs[N](b, pickOverflowPolicy(overflow))
+}
+
+/*
+Integral is the antiderivative with constant term c. With integer coefficients c_i/(i+1) is rounded with roundMode
+(math.Round by default) and ErrInexact is returned if any of them wasn't a whole number.
+*/
+func (p Poly[N]) Integral(c N, roundMode ...RoundingMode) (Poly[N], error) {
+	if isFloat[N]() {
+		f := make([]float64, len(p.c)+1)
+		f[0] = float64(c)
+		for i, x := range p.c {
+			f[i+1] = float64(x) / float64(i+1)
+		}
+		return polyFromFloats[N](f), nil
+	}
+	var err error
+	b := make([]*big.Int, len(p.c)+1)
+	b[0] = bigFromInt(c)
+	mode := pickRoundingMode(roundMode)
+	for i, x := range p.c {
+		num, den := bigFromInt(x), big.NewInt(int64(i+1))
+		if new(big.Int).Rem(num, den).Sign() != 0 {
+			err = ErrInexact
+		}
+		b[i+1] = roundBigQuo(num, den, mode)
+	}
+	q, ferr := polyFromBigs[N](b, Saturate)
+	return q, errors.Join(err, ferr)
+}
+
+/*
+Compose is p(q(x)). The Horner steps are all done exactly (in float64 for floats) and only the result is fitted,
+saturating a step on the way would leave the rest of the composition wrong.
+*/
+func (p Poly[N]) Compose(q Poly[N], overflow ...OverflowPolicy) (Poly[N], error) {
+	if isFloat[N]() {
+		var out []float64
+		qf := q.floats()
+		for i := len(p.c) - 1; i >= 0; i-- {
+			next := make([]float64, max(len(out)+len(qf)-1, 1))
+			for a, x := range out {
+				for b, y := range qf {
+					next[a+b] = math.FMA(x, y, next[a+b])
+				}
+			}
+			next[0] += float64(p.c[i])
+			out = next
+		}
+		return polyFromFloats[N](out), nil
+	}
+	var out []*big.Int
+	qb := q.bigs()
+	for i := len(p.c) - 1; i >= 0; i-- {
+		next := make([]*big.Int, max(len(out)+len(qb)-1, 1))
+		for k := range next {
+			next[k] = new(big.Int)
+		}
+		for a, x := range out {
+			for b, y := range qb {
+				next[a+b].Add(next[a+b], new(big.Int).Mul(x, y))
+			}
+		}
+		next[0].Add(next[0], bigFromInt(p.c[i]))
+		out = next
+	}
+	return polyFromBigs[N](out, pickOverflowPolicy(overflow))
+}
+
+/*
+Roots finds all the complex roots with the Aberth-Ehrlich method, which improves every root at once and converges
+cubically for simple roots. Roots at 0 are split off exactly first. The roots come back sorted by real and then
+imaginary part, with a multiple root repeated. A constant has no roots and the zero polynomial returns ErrShape.
+Roots that don't settle in 500 rounds return ErrNoConvergence along with the best guesses.
+*/
+func (p Poly[N]) Roots() ([]complex128, error) {
+	if len(p.c) == 0 {
+		return nil, ErrShape
+	}
+	c := p.floats()
+	var roots []complex128
+	for len(c) > 1 && c[0] == 0 {
+		roots = append(roots, 0)
+		c = c[1:]
+	}
+	n := len(c) - 1
+	if n == 0 {
+		return roots, nil
+	}
+	// monic and scaled so the roots are near the unit circle
+	lead := c[n]
+	for i := range c {
+		c[i] /= lead
+	}
+	scale := 0.0
+	for i := 0; i < n; i++ {
+		scale = max(scale, math.Pow(math.Abs(c[i]), 1/float64(n-i)))
+	}
+	if scale == 0 || math.IsInf(scale, 0) || math.IsNaN(scale) {
+		scale = 1
+	}
+	cs := make([]complex128, n+1)
+	for i := range c {
+		cs[i] = complex(c[i]/math.Pow(scale, float64(n-i)), 0)
+	}
+	z := make([]complex128, n)
+	for k := range z {
+		// off the axes so conjugate pairs don't start out symmetric
+		z[k] = cmplx.Rect(1, 2*math.Pi*float64(k)/float64(n)+0.4)
+	}
+	done := make([]bool, n)
+	var err error = ErrNoConvergence
+	for iter := 0; iter < 500; iter++ {
+		moved := false
+		for k := range z {
+			if done[k] {
+				continue
+			}
+			pv, dv, _ := hornerWithDerivative(cs, z[k])
+			if pv == 0 {
+				done[k] = true
+				continue
+			}
+			w := pv / dv
+			var sum complex128
+			for j := range z {
+				if j != k {
+					sum += 1 / (z[k] - z[j])
+				}
+			}
+			step := w / (1 - w*sum)
+			if cmplx.IsNaN(step) || cmplx.IsInf(step) {
+				step = w
+			}
+			z[k] -= step
+			if cmplx.Abs(step) <= 4*epsilon*cmplx.Abs(z[k]) {
+				done[k] = true
+			}
+			moved = true
+		}
+		if !moved {
+			err = nil
+			break
+		}
+	}
+	if err != nil {
+		// the steps of an ill conditioned root wander in the rounding noise, if p(z) is down in its own
+		// rounding error there is nothing better to find
+		err = nil
+		for _, r := range z {
+			if pv, _, bound := hornerWithDerivative(cs, r); cmplx.Abs(pv) > 8*epsilon*bound {
+				err = ErrNoConvergence
+			}
+		}
+	}
+	for _, r := range z {
+		if math.Abs(imag(r)) <= 4*epsilon*cmplx.Abs(r) {
+			r = complex(real(r), 0)
+		}
+		roots = append(roots, r*complex(scale, 0))
+	}
+	sort.Slice(roots, func(i, j int) bool {
+		if real(roots[i]) != real(roots[j]) {
+			return real(roots[i]) < real(roots[j])
+		}
+		return imag(roots[i]) < imag(roots[j])
+	})
+	return roots, err
+}
+
+const epsilon = 0x1p-52
+
+/*hornerWithDerivative returns p(z), p'(z) and a bound on the rounding error in p(z)*/
+func hornerWithDerivative(c []complex128, z complex128) (complex128, complex128, float64) {
+	var p, d complex128
+	bound, az := 0.0, cmplx.Abs(z)
+	for i := len(c) - 1; i >= 0; i-- {
+		d = d*z + p
+		p = p*z + c[i]
+		bound = bound*az + cmplx.Abs(p)
+	}
+	return p, d, bound
+}
+
+/*
+PolyFit is the least squares polynomial of the given degree through the points, solved with QR on the Vandermonde
+matrix with x scaled into [-1, 1] to keep it well conditioned.
+*/
+func PolyFit[N Float](xs []N, ys []N, degree int) (Poly[N], error) {
+	n := min(len(xs), len(ys))
+	if degree < 0 || n <= degree {
+		return Poly[N]{}, ErrShape
+	}
+	lo, hi := float64(xs[0]), float64(xs[0])
+	for _, x := range xs[:n] {
+		lo, hi = min(lo, float64(x)), max(hi, float64(x))
+	}
+	mid, half := (hi+lo)/2, (hi-lo)/2
+	if half == 0 {
+		half = 1
+	}
+	v := make([]float64, n*(degree+1))
+	for i, x := range xs[:n] {
+		t, pw := (float64(x)-mid)/half, 1.0
+		for j := 0; j <= degree; j++ {
+			v[i*(degree+1)+j] = pw
+			pw *= t
+		}
+	}
+	vm, _ := NewMat(n, degree+1, v)
+	qr, _ := NewQR(vm)
+	b, _ := NewMat(n, 1, float64sOf(ys[:n]))
+	sol, err := qr.Solve(b)
+	if err != nil {
+		return Poly[N]{}, err
+	}
+	// undo the scaling, p(x) = s((x - mid)/half)
+	s := Poly[float64]{c: trimPoly(sol.Data())}
+	p, _ := s.Compose(NewPoly(-mid/half, 1/half))
+	return polyFromFloats[N](p.c), nil
+}
@@ -0,0 +1,274 @@
+package RUNK
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"math/cmplx"
+	"math/rand"
+	"testing"
+)
+
+/*bigPoly helpers work on exact coefficient slices, lowest power first*/
+func bigPolyOf[N Number](p Poly[N]) []*big.Int {
+	return p.bigs()
+}
+
+func bigPolyMul(a []*big.Int, b []*big.Int) []*big.Int {
+	if len(a) == 0 || len(b) == 0 {
+		return nil
+	}
+	out := make([]*big.Int, len(a)+len(b)-1)
+	for i := range out {
+		out[i] = new(big.Int)
+	}
+	for i := range a {
+		for j := range b {
+			out[i+j].Add(out[i+j], new(big.Int).Mul(a[i], b[j]))
+		}
+	}
+	return out
+}
+
+func bigPolyAdd(a []*big.Int, b []*big.Int, sign int64) []*big.Int {
+	out := make([]*big.Int, max(len(a), len(b)))
+	for i := range out {
+		out[i] = new(big.Int)
+		if i < len(a) {
+			out[i].Add(out[i], a[i])
+		}
+		if i < len(b) {
+			out[i].Add(out[i], new(big.Int).Mul(b[i], big.NewInt(sign)))
+		}
+	}
+	return out
+}
+
+func bigPolyCompose(p []*big.Int, q []*big.Int) []*big.Int {
+	var out []*big.Int
+	for i := len(p) - 1; i >= 0; i-- {
+		out = bigPolyAdd(bigPolyMul(out, q), []*big.Int{p[i]}, 1)
+	}
+	return out
+}
+
+func randPoly(rng *rand.Rand, maxDegree int) Poly[int8] {
+	c := make([]int8, rng.Intn(maxDegree+2))
+	for i := range c {
+		c[i] = int8(rng.Intn(256) - 128)
+	}
+	return NewPoly(c...)
+}
+
+func TestPolyIntegerMatchesBig(t *testing.T) {
+	rng := rand.New(rand.NewSource(22))
+	for trial := 0; trial < 300; trial++ {
+		p, q := randPoly(rng, 4), randPoly(rng, 2)
+		pb, qb := bigPolyOf(p), bigPolyOf(q)
+		deriv := make([]*big.Int, max(len(pb)-1, 0))
+		for i := range deriv {
+			deriv[i] = new(big.Int).Mul(pb[i+1], big.NewInt(int64(i+1)))
+		}
+		for _, policy := range []OverflowPolicy{Saturate, Wrap} {
+			ops := []struct {
+				name string
+				got  func() (Poly[int8], error)
+				want []*big.Int
+			}{
+				{"Add", func() (Poly[int8], error) { return p.Add(q, policy) }, bigPolyAdd(pb, qb, 1)},
+				{"Sub", func() (Poly[int8], error) { return p.Sub(q, policy) }, bigPolyAdd(pb, qb, -1)},
+				{"Mul", func() (Poly[int8], error) { return p.Mul(q, policy) }, bigPolyMul(pb, qb)},
+				{"Compose", func() (Poly[int8], error) { return p.Compose(q, policy) }, bigPolyCompose(pb, qb)},
+				{"Derivative", func() (Poly[int8], error) { return p.Derivative(policy) }, deriv},
+			}
+			for _, op := range ops {
+				got, err := op.got()
+				want, wantErr := polyFromBigs[int8](op.want, policy)
+				if !got.Equal(want) || (err != nil) != (wantErr != nil) {
+					t.Fatalf("(%v).%s(%v) policy %d = %v %v, want %v %v", p, op.name, q, policy, got, err, want, wantErr)
+				}
+			}
+		}
+		x := int8(rng.Intn(7) - 3)
+		exact := new(big.Int)
+		for i := len(pb) - 1; i >= 0; i-- {
+			exact.Mul(exact, big.NewInt(int64(x))).Add(exact, pb[i])
+		}
+		want, wantErr := fitBig[int8](exact, Saturate)
+		if got, err := p.EvalChecked(x); got != want || err != wantErr || p.Eval(x) != want {
+			t.Fatalf("(%v).Eval(%d) = %d %v, want %d %v", p, x, got, err, want, wantErr)
+		}
+		if got, _ := p.EvalChecked(x, Wrap); got != int8(exact.Int64()) {
+			t.Fatalf("(%v).Eval(%d) with Wrap = %d, want %d", p, x, got, int8(exact.Int64()))
+		}
+	}
+	big := NewPoly[int64](0, 0, 1)
+	if y, err := big.EvalChecked(math.MaxInt32 + 1); y != 1<<62 || err != nil {
+		t.Errorf("(2^31)^2 = %d %v", y, err)
+	}
+	if y, err := big.EvalChecked(-(1 << 32)); y != math.MaxInt64 || err != ErrOverflow {
+		t.Errorf("(2^32)^2 = %d %v", y, err)
+	}
+	if y, err := big.EvalChecked(1<<32+1, Wrap); y != 1<<33+1 || err != ErrOverflow {
+		t.Errorf("wrapped (2^32+1)^2 = %d %v", y, err)
+	}
+	if y := NewPoly[uint8](5, 1).Eval(255); y != 255 {
+		t.Errorf("uint8 5 + 255 = %d", y)
+	}
+}
+
+func TestPolyDivModAndIntegral(t *testing.T) {
+	// (x^3 - 2x^2 - 4) / (x - 3) = x^2 + x + 3 remainder 5
+	q, r, err := NewPoly[int](-4, 0, -2, 1).DivMod(NewPoly(-3, 1))
+	if err != nil || !q.Equal(NewPoly(3, 1, 1)) || !r.Equal(NewPoly(5)) {
+		t.Errorf("DivMod = %v, %v, %v", q, r, err)
+	}
+	// 2 doesn't divide 3 so this stops early, p = q*d + r still has to hold
+	p, d := NewPoly[int64](1, 4, 3), NewPoly[int64](1, 2)
+	q2, r2, err := p.DivMod(d)
+	if !errors.Is(err, ErrInexact) {
+		t.Errorf("inexact DivMod err = %v", err)
+	}
+	back, _ := q2.Mul(d)
+	if back, _ = back.Add(r2); !back.Equal(p) {
+		t.Errorf("q*d + r = %v, want %v", back, p)
+	}
+	if _, _, err := p.DivMod(Poly[int64]{}); err != ErrDivideByZero {
+		t.Errorf("DivMod by 0 = %v", err)
+	}
+	if q, r, err := d.DivMod(p); err != nil || q.Degree() != -1 || !r.Equal(d) {
+		t.Errorf("low degree DivMod = %v %v %v", q, r, err)
+	}
+	qf, rf, _ := NewPoly(1.0, 4, 3).DivMod(NewPoly(1.0, 2))
+	if !qf.Equal(NewPoly(1.25, 1.5)) || !rf.Equal(NewPoly(-0.25)) {
+		t.Errorf("float DivMod = %v, %v", qf, rf)
+	}
+
+	// integral of 3x^2 + 2x + 1 is x^3 + x^2 + x + c
+	in, err := NewPoly[int](1, 2, 3).Integral(7)
+	if err != nil || !in.Equal(NewPoly(7, 1, 1, 1)) {
+		t.Errorf("Integral = %v %v", in, err)
+	}
+	// x has integral x^2/2, not a whole coefficient
+	if in, err := NewPoly[int](0, 1).Integral(0); !errors.Is(err, ErrInexact) || !in.Equal(NewPoly(0, 0, 1)) {
+		t.Errorf("Integral of x = %v %v, want x^2 rounded from 0.5 and ErrInexact", in, err)
+	}
+	if in, err := NewPoly[int](0, 1).Integral(0, math.Floor); !errors.Is(err, ErrInexact) || in.Degree() != -1 {
+		t.Errorf("floored Integral of x = %v %v", in, err)
+	}
+	fin, _ := NewPoly(0.0, 1).Integral(2)
+	if !fin.Equal(NewPoly(2, 0, 0.5)) {
+		t.Errorf("float Integral = %v", fin)
+	}
+	if dv, _ := fin.Derivative(); !dv.Equal(NewPoly(0.0, 1)) {
+		t.Errorf("Derivative of the Integral = %v", dv)
+	}
+}
+
+func TestPolyRoots(t *testing.T) {
+	tests := []struct {
+		name string
+		p    Poly[float64]
+		want []complex128
+	}{
+		{"three real", NewPoly(-6.0, 11, -6, 1), []complex128{1, 2, 3}},
+		{"conjugate pair", NewPoly(1.0, 0, 1), []complex128{-1i, 1i}},
+		{"roots at 0", NewPoly(0.0, 0, -1, 1), []complex128{0, 0, 1}},
+		{"triple root", NewPoly(-1.0, 3, -3, 1), []complex128{1, 1, 1}},
+		{"linear", NewPoly(3.0, -2), []complex128{1.5}},
+		{"huge coefficients", NewPoly(2e300, -3e150, 1), []complex128{1e150, 2e150}},
+	}
+	for _, tt := range tests {
+		roots, err := tt.p.Roots()
+		if err != nil || len(roots) != len(tt.want) {
+			t.Errorf("%s Roots = %v %v", tt.name, roots, err)
+			continue
+		}
+		for i, r := range roots {
+			// a triple root is only good to about the cube root of epsilon
+			if cmplx.Abs(r-tt.want[i]) > 1e-5*math.Max(1, cmplx.Abs(tt.want[i])) {
+				t.Errorf("%s root %d = %v, want %v", tt.name, i, r, tt.want[i])
+			}
+		}
+	}
+	// random real roots, every one has to be a root of the product
+	rng := rand.New(rand.NewSource(23))
+	want := make([]float64, 12)
+	p := NewPoly(1.0)
+	for i := range want {
+		want[i] = rng.Float64()*20 - 10
+		p, _ = p.Mul(NewPoly(-want[i], 1))
+	}
+	roots, err := p.Roots()
+	if err != nil || len(roots) != 12 {
+		t.Fatalf("degree 12 Roots = %v %v", roots, err)
+	}
+	for _, w := range want {
+		best := math.Inf(1)
+		for _, r := range roots {
+			best = math.Min(best, cmplx.Abs(r-complex(w, 0)))
+		}
+		if best > 1e-6 {
+			t.Errorf("root %v missing, nearest is %v away", w, best)
+		}
+	}
+	if roots, err := NewPoly[int](5).Roots(); err != nil || len(roots) != 0 {
+		t.Errorf("constant Roots = %v %v", roots, err)
+	}
+	if _, err := (Poly[int]{}).Roots(); err != ErrShape {
+		t.Errorf("zero polynomial Roots = %v", err)
+	}
+	if roots, _ := NewPoly[int8](-4, 0, 1).Roots(); len(roots) != 2 || roots[0] != -2 || roots[1] != 2 {
+		t.Errorf("int8 Roots = %v", roots)
+	}
+}
+
+func TestPolyFitAndBasics(t *testing.T) {
+	truth := NewPoly(1.0, -2, 0.5, 0.25)
+	xs, ys := make([]float64, 20), make([]float64, 20)
+	for i := range xs {
+		xs[i] = 1000 + float64(i)
+		ys[i] = truth.Eval(xs[i])
+	}
+	fit, err := PolyFit(xs, ys, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, x := range xs {
+		if got, want := fit.Eval(x), truth.Eval(x); math.Abs(got-want) > 1e-6*math.Abs(want) {
+			t.Errorf("fit(%v) = %v, want %v", x, got, want)
+		}
+	}
+	if _, err := PolyFit(xs[:3], ys, 3); err != ErrShape {
+		t.Errorf("PolyFit with 3 points for degree 3 = %v", err)
+	}
+	if _, err := PolyFit([]float32{1, 1, 1}, []float32{1, 2, 3}, 1); err != ErrSingular {
+		t.Errorf("PolyFit with one distinct x = %v", err)
+	}
+	if f32, err := PolyFit([]float32{0, 1, 2}, []float32{1, 3, 5}, 1); err != nil || math.Abs(float64(f32.Eval(10)-21)) > 1e-4 {
+		t.Errorf("float32 PolyFit = %v %v", f32, err)
+	}
+
+	if s := NewPoly(1, -1, 0, -3).String(); s != "-3x^3 - x + 1" {
+		t.Errorf("String = %s", s)
+	}
+	if s := NewPoly(0, 0).String(); s != "0" {
+		t.Errorf("zero String = %s", s)
+	}
+	if p := NewPoly(1, 2, 0, 0); p.Degree() != 1 || p.Coeff(5) != 0 || p.Coeff(-1) != 0 {
+		t.Errorf("trailing zeros kept: %v", p.Coeffs())
+	}
+	// (1+2^-52)(1-2^-52) - 1 is -2^-104, rounding the product first would give 0
+	if y := NewPoly(-1.0, 1+0x1p-52).Eval(1 - 0x1p-52); y != -0x1p-104 {
+		t.Errorf("FMA Horner = %v", y)
+	}
+	if y := NewPoly(1.0, 1).Eval(math.NaN()); y == y {
+		t.Errorf("Eval(NaN) = %v", y)
+	}
+	if y := NewPoly[int](3, 2).EvalFloat(0.5); y != 4 {
+		t.Errorf("EvalFloat = %v", y)
+	}
+	if z := NewPoly(1.0, 0, 1).EvalComplex(1i); z != 0 {
+		t.Errorf("EvalComplex = %v", z)
+	}
+}
@@ -0,0 +1,295 @@
+package RUNK
+
+import (
+	"math"
+)
+
+/*
+The integrators all do their arithmetic in N so a float32 integrand is summed in float32 the whole way, only the
+fixed node tables are written down in float64. They return the integral, an estimate of its absolute error and
+ErrNoConvergence when the tolerance couldn't be met within MaxIter subdivisions, in which case the integral is
+still the best one found. Leaving the Tolerance empty asks for about three quarters of the digits N has, which is
+what these methods can reliably give. Limits can be given in either order and may be infinite, the infinite
+ones are mapped onto a finite range first so the integrand has to fall off fast enough to be integrable.
+*/
+
+/*pickQuadTolerance is pickTolerance with a looser default, asking quadrature for the last bits is a waste of time*/
+func pickQuadTolerance[N Float](tol []Tolerance, maxIter int) Tolerance {
+	var t Tolerance
+	if len(tol) > 0 {
+		t = tol[0]
+	}
+	if t.Abs <= 0 && t.Rel <= 0 {
+		t.Rel = math.Pow(epsilonOf[N](), 0.75)
+	}
+	return pickTolerance[N]([]Tolerance{t}, maxIter)
+}
+
+/*
+finiteRange maps the integral of f over a..b onto one over a finite range, it returns the new integrand, the new
+limits and the sign to put back for limits given the wrong way round. The substitutions are x = a + t/(1-t) for
+a half line to the right, x = b - (1-t)/t to the left and x = t/(1-t^2) for the whole line. The ends of the new
+range map to infinity, they are evaluated half a float spacing inside so a method that uses them gets the limit,
+which isn't 0 for an f like 1/(1+x^2).
+*/
+func finiteRange[N Float](f func(N) N, a N, b N) (g func(N) N, lo N, hi N, sign N) {
+	sign = 1
+	if b < a {
+		a, b, sign = b, a, -1
+	}
+	inf := N(math.Inf(1))
+	edge := N(epsilonOf[N]() / 2)
+	switch {
+	case a == -inf && b == inf:
+		g = func(t N) N {
+			t = max(-1+edge, min(1-edge, t))
+			d := 1 - t*t
+			x := t / d
+			if d == 0 || IsInf(x, 0) {
+				return 0
+			}
+			return f(x) * (1 + t*t) / (d * d)
+		}
+		return g, -1, 1, sign
+	case b == inf:
+		g = func(t N) N {
+			t = min(1-edge, t)
+			d := 1 - t
+			x := a + t/d
+			if d == 0 || IsInf(x, 0) {
+				return 0
+			}
+			return f(x) / (d * d)
+		}
+		return g, 0, 1, sign
+	case a == -inf:
+		g = func(t N) N {
+			t = max(edge, t)
+			x := b - (1-t)/t
+			if t == 0 || IsInf(x, 0) {
+				return 0
+			}
+			return f(x) / (t * t)
+		}
+		return g, 0, 1, sign
+	}
+	return f, a, b, sign
+}
+
+/*
+Simpson integrates f from a to b with adaptive Simpson's rule, halving each piece until the two halves agree with
+the whole to within its share of the tolerance. It is simple and good for smooth integrands that are cheap to
+call, GaussKronrod needs a lot fewer calls for the same accuracy. MaxIter caps the number of halvings.
+*/
+func Simpson[N Float](f func(N) N, a N, b N, tol ...Tolerance) (N, N, error) {
+	t := pickQuadTolerance[N](tol, 10000)
+	if IsNaN(a) || IsNaN(b) {
+		return NaN[N](), NaN[N](), ErrNoConvergence
+	}
+	if a == b {
+		return 0, 0, nil
+	}
+	g, lo, hi, sign := finiteRange(f, a, b)
+	flo, fhi := g(lo), g(hi)
+	mid := lo + (hi-lo)/2
+	fmid := g(mid)
+	whole := (hi - lo) / 6 * (flo + 4*fmid + fhi)
+	s := simpson[N]{f: g, splits: t.MaxIter}
+	goal := N(t.Abs + t.Rel*math.Abs(float64(whole)))
+	sum := s.adapt(lo, mid, hi, flo, fmid, fhi, whole, goal)
+	if s.failed || IsNaN(sum) {
+		return sign * sum, s.err, ErrNoConvergence
+	}
+	return sign * sum, s.err, nil
+}
+
+type simpson[N Float] struct {
+	f      func(N) N
+	splits int
+	err    N
+	failed bool
+}
+
+func (s *simpson[N]) adapt(a N, m N, b N, fa N, fm N, fb N, whole N, goal N) N {
+	lm, rm := a+(m-a)/2, m+(b-m)/2
+	flm, frm := s.f(lm), s.f(rm)
+	left := (m - a) / 6 * (fa + 4*flm + fm)
+	right := (b - m) / 6 * (fm + 4*frm + fb)
+	diff := left + right - whole
+	// 15 is how much the error of Simpson's rule shrinks when the step is halved
+	if Abs(diff) <= 15*goal {
+		s.err += Abs(diff) / 15
+		return left + right + diff/15
+	}
+	if s.splits <= 0 || lm == a || lm == m || rm == m || rm == b {
+		s.failed = true
+		s.err += Abs(diff) / 15
+		return left + right + diff/15
+	}
+	s.splits--
+	return s.adapt(a, lm, m, fa, flm, fm, left, goal/2) + s.adapt(m, rm, b, fm, frm, fb, right, goal/2)
+}
+
+/*legendreNodes are the n point Gauss-Legendre nodes and weights on -1..1, found by Newton's method on P_n*/
+func legendreNodes(n int) ([]float64, []float64) {
+	xs, ws := make([]float64, n), make([]float64, n)
+	for i := 0; i < (n+1)/2; i++ {
+		x := math.Cos(math.Pi * (float64(i) + 0.75) / (float64(n) + 0.5))
+		var dp float64
+		for k := 0; k < 100; k++ {
+			p0, p1 := 1.0, x
+			for j := 2; j <= n; j++ {
+				p0, p1 = p1, ((2*float64(j)-1)*x*p1-(float64(j)-1)*p0)/float64(j)
+			}
+			dp = float64(n) * (x*p1 - p0) / (x*x - 1)
+			step := p1 / dp
+			x -= step
+			if math.Abs(step) <= 1e-16 {
+				break
+			}
+		}
+		w := 2 / ((1 - x*x) * dp * dp)
+		xs[i], xs[n-1-i] = -x, x
+		ws[i], ws[n-1-i] = w, w
+	}
+	return xs, ws
+}
+
+/*
+GaussLegendre integrates f from a to b with the n point Gauss-Legendre rule, which is exact for polynomials up to
+degree 2n-1. There is no error estimate, it is for when you know n is enough, otherwise use GaussKronrod. The
+nodes are worked out on every call which costs O(n^2), n below 1 is taken as 1.
+*/
+func GaussLegendre[N Float](f func(N) N, a N, b N, n int) N {
+	if IsNaN(a) || IsNaN(b) {
+		return NaN[N]()
+	}
+	if a == b {
+		return 0
+	}
+	g, lo, hi, sign := finiteRange(f, a, b)
+	xs, ws := legendreNodes(max(n, 1))
+	half, center := (hi-lo)/2, lo+(hi-lo)/2
+	var sum N
+	for i, x := range xs {
+		sum += N(ws[i]) * g(center+half*N(x))
+	}
+	return sign * sum * half
+}
+
+/*the 15 point Kronrod extension of the 7 point Gauss rule, nodes from the outside in with 0 last*/
+var (
+	kronrodNodes = [8]float64{
+		0.991455371120812639206854697526329, 0.949107912342758524526189684047851,
+		0.864864423359769072789712788640926, 0.741531185599394439863864773280788,
+		0.586087235467691130294144845693013, 0.405845151377397166906606412076961,
+		0.207784955007898467600689403773245, 0,
+	}
+	kronrodWeights = [8]float64{
+		0.022935322010529224963732008058970, 0.063092092629978553290700663189204,
+		0.104790010322250183839876322541518, 0.140653259715525918745189590510238,
+		0.169004726639267902826583426598550, 0.190350578064785409913256402421014,
+		0.204432940075298892414161999234649, 0.209482141084727828012999174891714,
+	}
+	// the Gauss weights for the odd numbered Kronrod nodes
+	gaussWeights = [4]float64{
+		0.129484966168869693270611432679082, 0.279705391489276667901467771423780,
+		0.381830050505118944950369775488975, 0.417959183673469387755102040816327,
+	}
+)
+
+type kronrodPiece[N Float] struct {
+	a, b     N
+	sum, err N
+}
+
+/*kronrod15 is one G7-K15 step on a..b with the QUADPACK error estimate*/
+func kronrod15[N Float](f func(N) N, a N, b N) kronrodPiece[N] {
+	half, center := (b-a)/2, a+(b-a)/2
+	fc := f(center)
+	k := N(kronrodWeights[7]) * fc
+	g := N(gaussWeights[3]) * fc
+	var fs [15]N
+	fs[7] = fc
+	for i := 0; i < 7; i++ {
+		d := half * N(kronrodNodes[i])
+		f1, f2 := f(center-d), f(center+d)
+		fs[i], fs[14-i] = f1, f2
+		k += N(kronrodWeights[i]) * (f1 + f2)
+		if i%2 == 1 {
+			g += N(gaussWeights[i/2]) * (f1 + f2)
+		}
+	}
+	// resasc measures how much f moves about the mean, it scales the raw difference of the two rules
+	mean := k / 2
+	var resabs, resasc N
+	for i, fx := range fs {
+		j := i
+		if j > 7 {
+			j = 14 - i
+		}
+		resabs += N(kronrodWeights[j]) * Abs(fx)
+		resasc += N(kronrodWeights[j]) * Abs(fx-mean)
+	}
+	half = Abs(half)
+	sum := k * (b - a) / 2
+	err := Abs((k - g) * half)
+	resabs *= half
+	resasc *= half
+	if resasc != 0 && err != 0 {
+		err = resasc * min(1, Pow(200*err/resasc, 1.5))
+	}
+	// nothing below the rounding in the sum itself can be trusted
+	if floor := N(50*epsilonOf[N]()) * resabs; err < floor {
+		err = floor
+	}
+	return kronrodPiece[N]{a: a, b: b, sum: sum, err: err}
+}
+
+/*
+GaussKronrod integrates f from a to b with the adaptive 15 point Gauss-Kronrod rule, the same scheme as QUADPACK's
+QAG. It keeps splitting whichever piece has the largest error estimate until the total is within tolerance, so
+it spends its calls where f is hard, and it copes with end point singularities and infinite limits. MaxIter
+caps the number of splits, each costs 30 calls of f.
+*/
+func GaussKronrod[N Float](f func(N) N, a N, b N, tol ...Tolerance) (N, N, error) {
+	t := pickQuadTolerance[N](tol, 2000)
+	if IsNaN(a) || IsNaN(b) {
+		return NaN[N](), NaN[N](), ErrNoConvergence
+	}
+	if a == b {
+		return 0, 0, nil
+	}
+	g, lo, hi, sign := finiteRange(f, a, b)
+	pieces := []kronrodPiece[N]{kronrod15(g, lo, hi)}
+	total := func() (N, N) {
+		var sum, err N
+		for _, p := range pieces {
+			sum += p.sum
+			err += p.err
+		}
+		return sum, err
+	}
+	for i := 0; ; i++ {
+		sum, err := total()
+		if IsNaN(sum) {
+			return sign * sum, err, ErrNoConvergence
+		}
+		if float64(err) <= t.Abs+t.Rel*math.Abs(float64(sum)) {
+			return sign * sum, err, nil
+		}
+		worst := 0
+		for j, p := range pieces {
+			if p.err > pieces[worst].err {
+				worst = j
+			}
+		}
+		p := pieces[worst]
+		mid := p.a + (p.b-p.a)/2
+		if i >= t.MaxIter || mid == p.a || mid == p.b {
+			return sign * sum, err, ErrNoConvergence
+		}
+		pieces[worst] = kronrod15(g, p.a, mid)
+		pieces = append(pieces, kronrod15(g, mid, p.b))
+	}
+}
@@ -0,0 +1,142 @@
+package RUNK
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestQuadrature(t *testing.T) {
+	inf := math.Inf(1)
+	tests := []struct {
+		name string
+		f    func(float64) float64
+		a, b float64
+		want float64
+	}{
+		{"sin", math.Sin, 0, math.Pi, 2},
+		{"exp", math.Exp, 0, 1, math.E - 1},
+		{"reversed", math.Exp, 1, 0, 1 - math.E},
+		{"empty", math.Exp, 3, 3, 0},
+		{"gaussian", func(x float64) float64 { return math.Exp(-x * x) }, -inf, inf, math.Sqrt(math.Pi)},
+		{"right half line", func(x float64) float64 { return 1 / (1 + x*x) }, 0, inf, math.Pi / 2},
+		{"left half line", math.Exp, -inf, 0, 1},
+		{"whole line reversed", func(x float64) float64 { return 1 / (1 + x*x) }, inf, -inf, -math.Pi},
+		{"shifted half line", func(x float64) float64 { return math.Exp(-x) }, 2, inf, math.Exp(-2)},
+	}
+	for _, tt := range tests {
+		for _, m := range []struct {
+			name string
+			run  func(func(float64) float64, float64, float64, ...Tolerance) (float64, float64, error)
+		}{{"Simpson", Simpson[float64]}, {"GaussKronrod", GaussKronrod[float64]}} {
+			got, est, err := m.run(tt.f, tt.a, tt.b)
+			if err != nil || math.Abs(got-tt.want) > 1e-10*math.Max(1, math.Abs(tt.want)) {
+				t.Errorf("%s %s = %v ± %v, %v, want %v", m.name, tt.name, got, est, err, tt.want)
+			}
+			if tt.want == 0 && (got != 0 || est != 0) {
+				t.Errorf("%s %s should be exactly 0", m.name, tt.name)
+			}
+		}
+	}
+
+	// an end point singularity, Kronrod nodes never touch the ends
+	got, est, err := GaussKronrod(func(x float64) float64 { return 1 / math.Sqrt(x) }, 0, 1)
+	if err != nil || math.Abs(got-2) > 1e-9 || math.Abs(got-2) > est*10 {
+		t.Errorf("GaussKronrod 1/sqrt(x) = %v ± %v, %v", got, est, err)
+	}
+	got, _, err = GaussKronrod(func(x float64) float64 { return math.Log(x) }, 0, 1, Tolerance{Abs: 1e-6})
+	if err != nil || math.Abs(got+1) > 1e-6 {
+		t.Errorf("GaussKronrod log = %v, %v", got, err)
+	}
+
+	// the error estimate is honest on a function with a sharp peak
+	peak := func(x float64) float64 { return 1 / (1e-4 + (x-0.3)*(x-0.3)) }
+	want := 100 * (math.Atan(0.7/1e-2) + math.Atan(0.3/1e-2))
+	for _, m := range []func(func(float64) float64, float64, float64, ...Tolerance) (float64, float64, error){Simpson[float64], GaussKronrod[float64]} {
+		got, est, err := m(peak, 0, 1, Tolerance{Rel: 1e-8})
+		if err != nil || math.Abs(got-want) > 1e-8*want || math.Abs(got-want) > 10*est+1e-12*want {
+			t.Errorf("peak = %v ± %v, %v, want %v", got, est, err, want)
+		}
+	}
+
+	f32, _, err := GaussKronrod(func(x float32) float32 { return x * x }, 0, 3)
+	if err != nil || Abs(f32-9) > 1e-5 {
+		t.Errorf("float32 GaussKronrod = %v, %v", f32, err)
+	}
+	s32, _, err := Simpson(func(x float32) float32 { return float32(math.Cos(float64(x))) }, 0, math.Pi/2)
+	if err != nil || Abs(s32-1) > 1e-5 {
+		t.Errorf("float32 Simpson = %v, %v", s32, err)
+	}
+
+	// out of splits is an error with the best value so far, NaN limits and NaN values are errors
+	wiggle := func(x float64) float64 { return math.Sin(1 / x) }
+	for _, m := range []func(func(float64) float64, float64, float64, ...Tolerance) (float64, float64, error){Simpson[float64], GaussKronrod[float64]} {
+		if got, _, err := m(wiggle, 1e-3, 1, Tolerance{MaxIter: 2}); err != ErrNoConvergence || got != got {
+			t.Errorf("out of splits = %v, %v", got, err)
+		}
+		if _, _, err := m(math.Exp, math.NaN(), 1); err != ErrNoConvergence {
+			t.Errorf("NaN limit = %v", err)
+		}
+		if _, _, err := m(func(float64) float64 { return math.NaN() }, 0, 1); err != ErrNoConvergence {
+			t.Errorf("NaN integrand = %v", err)
+		}
+	}
+}
+
+func TestGaussLegendre(t *testing.T) {
+	// exact up to degree 2n-1, and not for 2n
+	rng := rand.New(rand.NewSource(28))
+	for n := 1; n <= 12; n++ {
+		cs := make([]float64, 2*n+1)
+		for i := range cs {
+			cs[i] = rng.NormFloat64()
+		}
+		integral := func(deg int, a float64, b float64) float64 {
+			var s float64
+			for i := 0; i <= deg; i++ {
+				s += cs[i] * (math.Pow(b, float64(i+1)) - math.Pow(a, float64(i+1))) / float64(i+1)
+			}
+			return s
+		}
+		poly := func(deg int) func(float64) float64 {
+			return func(x float64) float64 {
+				var s float64
+				for i := deg; i >= 0; i-- {
+					s = s*x + cs[i]
+				}
+				return s
+			}
+		}
+		got, want := GaussLegendre(poly(2*n-1), -0.5, 2, n), integral(2*n-1, -0.5, 2)
+		if math.Abs(got-want) > 1e-12*math.Max(1, math.Abs(want)) {
+			t.Errorf("n = %d on degree %d = %v, want %v", n, 2*n-1, got, want)
+		}
+		if got, want := GaussLegendre(func(x float64) float64 { return math.Pow(x, float64(2*n)) }, -1, 1, n), 2/float64(2*n+1); n <= 6 && math.Abs(got-want) < 1e-6 {
+			t.Errorf("n = %d should not be exact on x^%d", n, 2*n)
+		}
+		xs, ws := legendreNodes(n)
+		var sum float64
+		for i, w := range ws {
+			sum += w
+			if xs[i] != -xs[n-1-i] || (i > 0 && xs[i] <= xs[i-1]) {
+				t.Errorf("n = %d nodes are not symmetric and sorted: %v", n, xs)
+				break
+			}
+		}
+		if math.Abs(sum-2) > 1e-13 {
+			t.Errorf("n = %d weights add to %v", n, sum)
+		}
+	}
+	if got := GaussLegendre(func(x float64) float64 { return 3 }, 0, 2, 0); got != 6 {
+		t.Errorf("n = 0 taken as 1 = %v", got)
+	}
+	if got := GaussLegendre(func(x float64) float64 { return math.Exp(-x * x) }, math.Inf(1), math.Inf(-1), 60); math.Abs(got+math.Sqrt(math.Pi)) > 1e-6 {
+		t.Errorf("GaussLegendre over the whole line backwards = %v", got)
+	}
+	if got := GaussLegendre(math.Exp, 1, math.NaN(), 5); got == got {
+		t.Errorf("NaN limit = %v", got)
+	}
+	if got := GaussLegendre(math.Exp, 1, 1, 5); got != 0 {
+		t.Errorf("empty range = %v", got)
+	}
+}
@@ -0,0 +1,203 @@
+package RUNK
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+)
+
+var ErrOverflow = errors.New("RUNK: overflow")
+
+/*
+Rat is an exact fraction backed by any of the integer types. It is always kept in lowest terms with a positive
+denominator so the sign lives on the numerator. The zero value is 0/1.
+The arithmetic is done exactly and then checked against the range of N, if the reduced result doesn't fit you get
+ErrOverflow back instead of a silently wrapped value.
+*/
+type Rat[N Int] struct {
+	num N
+	den N
+}
+
+/*NewRat builds num/den in lowest terms. A zero denominator returns ErrDivideByZero.*/
+func NewRat[N Int](num N, den N) (Rat[N], error) {
+	if den == 0 {
+		return Rat[N]{}, ErrDivideByZero
+	}
+	return ratFromBig[N](new(big.Rat).SetFrac(bigFromInt(num), bigFromInt(den)))
+}
+
+/*
+RatFrom converts any Number into a Rat, saturating like ConvertNumber. Integers out of range become MaxNum or MinNum
+over 1. Floats use the closest fraction whose denominator fits in N (see Rationalize), NaN becomes 0 and +-Inf
+saturates.
+*/
+func RatFrom[N Int, M Number](m M) Rat[N] {
+	if isFloat[M]() {
+		return Rationalize(float64(m), MaxNum[N]())
+	}
+	return Rat[N]{num: ConvertNumber[N](m), den: 1}
+}
+
+/*RatTo converts a Rat into any Number. Integer targets are rounded with roundMode (math.Round by default).*/
+func RatTo[To Number, N Int](r Rat[N], roundMode ...RoundingMode) To {
+	if isFloat[To]() {
+		return ConvertNumber[To](r.Float64())
+	}
+	return bigIntTo[To](roundBigQuo(bigFromInt(r.num), bigFromInt(r.Den()), pickRoundingMode(roundMode)))
+}
+
+/*
+Rationalize finds the fraction closest to f with a denominator no bigger than maxDenominator using continued
+fractions, so Rationalize(0.333333, 1000) is 1/3. The semiconvergents are checked too which makes the answer the
+best possible one and not just the last convergent that fits. Values outside the range of N saturate and NaN is 0.
+*/
+func Rationalize[N Int](f float64, maxDenominator N) Rat[N] {
+	switch {
+	case math.IsNaN(f):
+		return Rat[N]{den: 1}
+	case math.IsInf(f, 0):
+		return Rat[N]{num: ConvertNumber[N](f), den: 1}
+	}
+	maxd := bigFromInt(maxDenominator)
+	if maxd.Sign() <= 0 {
+		maxd.SetInt64(1)
+	}
+	exact := new(big.Rat).SetFloat64(f)
+	neg := exact.Sign() < 0
+	n := new(big.Int).Abs(exact.Num())
+	d := new(big.Int).Set(exact.Denom())
+	if d.Cmp(maxd) > 0 {
+		p0, q0, p1, q1 := big.NewInt(0), big.NewInt(1), big.NewInt(1), big.NewInt(0)
+		for d.Sign() != 0 {
+			a, rem := new(big.Int).QuoRem(n, d, new(big.Int))
+			q2 := new(big.Int).Add(q0, new(big.Int).Mul(a, q1))
+			if q2.Cmp(maxd) > 0 {
+				break
+			}
+			p0, q0, p1, q1 = p1, q1, new(big.Int).Add(p0, new(big.Int).Mul(a, p1)), q2
+			n, d = d, rem
+		}
+		k := new(big.Int).Quo(new(big.Int).Sub(maxd, q0), q1)
+		semi := new(big.Rat).SetFrac(new(big.Int).Add(p0, new(big.Int).Mul(k, p1)), new(big.Int).Add(q0, new(big.Int).Mul(k, q1)))
+		conv := new(big.Rat).SetFrac(p1, q1)
+		abs := new(big.Rat).Abs(exact)
+		semiErr := new(big.Rat).Abs(new(big.Rat).Sub(semi, abs))
+		convErr := new(big.Rat).Abs(new(big.Rat).Sub(conv, abs))
+		n, d = semi.Num(), semi.Denom()
+		if convErr.Cmp(semiErr) <= 0 {
+			n, d = conv.Num(), conv.Denom()
+		}
+	}
+	if neg {
+		n = new(big.Int).Neg(n)
+	}
+	r, err := ratFromBig[N](new(big.Rat).SetFrac(n, d))
+	if err != nil {
+		return Rat[N]{num: bigIntTo[N](new(big.Int).Quo(n, d)), den: 1}
+	}
+	return r
+}
+
+/*Num is the numerator, it carries the sign*/
+func (r Rat[N]) Num() N {
+	return r.num
+}
+
+/*Den is the denominator, it is always positive*/
+func (r Rat[N]) Den() N {
+	if r.den == 0 {
+		return 1
+	}
+	return r.den
+}
+
+/*Big returns the exact value as a big.Rat*/
+func (r Rat[N]) Big() *big.Rat {
+	return new(big.Rat).SetFrac(bigFromInt(r.num), bigFromInt(r.Den()))
+}
+
+func (r Rat[N]) Float64() float64 {
+	f, _ := r.Big().Float64()
+	return f
+}
+
+func (r Rat[N]) String() string {
+	return r.Big().RatString()
+}
+
+func (x Rat[N]) Add(y Rat[N]) (Rat[N], error) {
+	return ratFromBig[N](new(big.Rat).Add(x.Big(), y.Big()))
+}
+
+func (x Rat[N]) Sub(y Rat[N]) (Rat[N], error) {
+	return ratFromBig[N](new(big.Rat).Sub(x.Big(), y.Big()))
+}
+
+func (x Rat[N]) Mul(y Rat[N]) (Rat[N], error) {
+	return ratFromBig[N](new(big.Rat).Mul(x.Big(), y.Big()))
+}
+
+func (x Rat[N]) Div(y Rat[N]) (Rat[N], error) {
+	if y.num == 0 {
+		return Rat[N]{}, ErrDivideByZero
+	}
+	return ratFromBig[N](new(big.Rat).Quo(x.Big(), y.Big()))
+}
+
+/*Neg can overflow for the signed types because -MinNum doesn't fit*/
+func (r Rat[N]) Neg() (Rat[N], error) {
+	return ratFromBig[N](new(big.Rat).Neg(r.Big()))
+}
+
+/*Inv flips the fraction over*/
+func (r Rat[N]) Inv() (Rat[N], error) {
+	if r.num == 0 {
+		return Rat[N]{}, ErrDivideByZero
+	}
+	return ratFromBig[N](new(big.Rat).Inv(r.Big()))
+}
+
+/*Cmp compares exactly and never overflows. It returns -1, 0 or +1.*/
+func (x Rat[N]) Cmp(y Rat[N]) int {
+	return x.Big().Cmp(y.Big())
+}
+
+/*ratFromBig checks that a reduced big.Rat fits in N*/
+func ratFromBig[N Int](b *big.Rat) (Rat[N], error) {
+	num, ok := bigFits[N](b.Num())
+	if !ok {
+		return Rat[N]{}, ErrOverflow
+	}
+	den, ok := bigFits[N](b.Denom())
+	if !ok {
+		return Rat[N]{}, ErrOverflow
+	}
+	return Rat[N]{num: num, den: den}, nil
+}
+
+/*bigFromInt converts any integer into a big.Int without going through int64 so uint64 stays positive*/
+func bigFromInt[N Number](n N) *big.Int {
+	neg, mag := magnitude(n)
+	b := new(big.Int).SetUint64(mag)
+	if neg {
+		b.Neg(b)
+	}
+	return b
+}
+
+/*bigFits converts b to N and reports whether it fit without saturating*/
+func bigFits[N Int](b *big.Int) (N, bool) {
+	n := bigIntTo[N](b)
+	return n, bigFromInt(n).Cmp(b) == 0
+}
+
+/*ParseRat reads "3/4", "-0.75" or "1e-3" into a Rat*/
+func ParseRat[N Int](s string) (Rat[N], error) {
+	b, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Rat[N]{}, errors.New("RUNK: invalid rational " + strconv.Quote(s))
+	}
+	return ratFromBig[N](b)
+}
@@ -0,0 +1,130 @@
+package RUNK
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNewRat(t *testing.T) {
+	r, err := NewRat(6, -8)
+	if err != nil || r.Num() != -3 || r.Den() != 4 || r.String() != "-3/4" {
+		t.Errorf("NewRat(6, -8) = %s %v", r, err)
+	}
+	if _, err := NewRat(1, 0); err != ErrDivideByZero {
+		t.Errorf("NewRat(1, 0) = %v", err)
+	}
+	// -128/-1 reduces to 128 which doesn't fit an int8
+	if _, err := NewRat[int8](-128, -1); err != ErrOverflow {
+		t.Errorf("NewRat[int8](-128, -1) = %v", err)
+	}
+	var zero Rat[int]
+	if zero.Den() != 1 || zero.String() != "0" || zero.Float64() != 0 {
+		t.Errorf("zero value = %s", zero)
+	}
+}
+
+func TestRatArithmetic(t *testing.T) {
+	a, _ := NewRat(1, 3)
+	b, _ := NewRat(1, 6)
+	tests := []struct {
+		name string
+		f    func() (Rat[int], error)
+		want string
+	}{
+		{"add", func() (Rat[int], error) { return a.Add(b) }, "1/2"},
+		{"sub", func() (Rat[int], error) { return b.Sub(a) }, "-1/6"},
+		{"mul", func() (Rat[int], error) { return a.Mul(b) }, "1/18"},
+		{"div", func() (Rat[int], error) { return a.Div(b) }, "2"},
+		{"neg", a.Neg, "-1/3"},
+		{"inv", b.Inv, "6"},
+	}
+	for _, tt := range tests {
+		got, err := tt.f()
+		if err != nil || got.String() != tt.want {
+			t.Errorf("%s = %s %v, want %s", tt.name, got, err, tt.want)
+		}
+	}
+	if _, err := a.Div(Rat[int]{}); err != ErrDivideByZero {
+		t.Errorf("divide by zero = %v", err)
+	}
+	if _, err := (Rat[int]{}).Inv(); err != ErrDivideByZero {
+		t.Errorf("Inv of zero = %v", err)
+	}
+	x, _ := NewRat[int8](1, 100)
+	y, _ := NewRat[int8](1, 99)
+	if _, err := x.Add(y); err != ErrOverflow {
+		t.Errorf("1/100 + 1/99 in int8 = %v", err)
+	}
+	m, _ := NewRat[int8](-128, 1)
+	if _, err := m.Neg(); err != ErrOverflow {
+		t.Errorf("-(-128) in int8 = %v", err)
+	}
+	// an intermediate that overflows is fine when the reduced result fits
+	p, _ := NewRat[int8](100, 3)
+	q, _ := NewRat[int8](3, 100)
+	if got, err := p.Mul(q); err != nil || got.String() != "1" {
+		t.Errorf("100/3 * 3/100 in int8 = %s %v", got, err)
+	}
+	if a.Cmp(b) != 1 || b.Cmp(a) != -1 || a.Cmp(a) != 0 {
+		t.Error("Cmp is wrong")
+	}
+}
+
+func TestRationalize(t *testing.T) {
+	tests := []struct {
+		f    float64
+		max  int
+		want string
+	}{
+		{0.333333, 1000, "1/3"},
+		{math.Pi, 10, "22/7"},
+		{math.Pi, 1000, "355/113"},
+		{-0.75, 100, "-3/4"},
+		{0.6, 1, "1"},
+		{2.4, 0, "2"},
+		{math.NaN(), 10, "0"},
+	}
+	for _, tt := range tests {
+		if got := Rationalize(tt.f, tt.max).String(); got != tt.want {
+			t.Errorf("Rationalize(%v, %d) = %s, want %s", tt.f, tt.max, got, tt.want)
+		}
+	}
+	if got := Rationalize[int8](math.Inf(1), 10); got.Num() != math.MaxInt8 || got.Den() != 1 {
+		t.Errorf("Rationalize(+Inf) = %s", got)
+	}
+	if got := Rationalize[int8](1000.25, 10); got.Num() != math.MaxInt8 {
+		t.Errorf("Rationalize(1000.25) in int8 = %s", got)
+	}
+}
+
+func TestRatConvert(t *testing.T) {
+	if got := RatFrom[int16](0.1); got.String() != "1/10" {
+		t.Errorf("RatFrom(0.1) = %s", got)
+	}
+	if got := RatFrom[int8](1000); got.Num() != math.MaxInt8 {
+		t.Errorf("RatFrom[int8](1000) = %s", got)
+	}
+	r, _ := NewRat(-7, 2)
+	if got := RatTo[int](r); got != -4 {
+		t.Errorf("RatTo[int](-7/2) = %d", got)
+	}
+	if got := RatTo[int](r, math.Trunc); got != -3 {
+		t.Errorf("RatTo[int] trunc = %d", got)
+	}
+	if got := RatTo[uint8](r); got != 0 {
+		t.Errorf("RatTo[uint8] of a negative = %d", got)
+	}
+	if got := RatTo[float32](r); got != -3.5 {
+		t.Errorf("RatTo[float32] = %v", got)
+	}
+	p, err := ParseRat[int]("1e-3")
+	if err != nil || p.String() != "1/1000" {
+		t.Errorf("ParseRat(1e-3) = %s %v", p, err)
+	}
+	if _, err := ParseRat[int]("1/x"); err == nil {
+		t.Error("ParseRat accepted 1/x")
+	}
+	if _, err := ParseRat[int8]("300/7"); err != ErrOverflow {
+		t.Errorf("ParseRat[int8](300/7) = %v", err)
+	}
+}
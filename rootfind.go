@@ -0,0 +1,312 @@
+package RUNK
+
+import (
+	"errors"
+	"math"
+)
+
+var ErrNoBracket = errors.New("RUNK: root is not bracketed")
+
+/*
+Tolerance controls when an iterative method stops. It is done when the answer is known to within Abs + Rel*|x|
+and it gives up with ErrNoConvergence after MaxIter rounds. Leaving Abs and Rel both zero picks a few units of
+rounding for N and for integer N the answer only has to be within 0.5, which is as close as N can get. Zero
+MaxIter picks a default that suits the method.
+*/
+type Tolerance struct {
+	Abs     float64
+	Rel     float64
+	MaxIter int
+}
+
+func pickTolerance[N Number](tol []Tolerance, maxIter int) Tolerance {
+	var t Tolerance
+	if len(tol) > 0 {
+		t = tol[0]
+	}
+	if t.Abs <= 0 && t.Rel <= 0 {
+		t.Rel = 4 * epsilonOf[N]()
+	}
+	if !isFloat[N]() {
+		t.Abs = max(t.Abs, 0.5)
+	}
+	if t.MaxIter <= 0 {
+		t.MaxIter = maxIter
+	}
+	return t
+}
+
+func (t Tolerance) within(step float64, x float64) bool {
+	return math.Abs(step) <= t.Abs+t.Rel*math.Abs(x)
+}
+
+/*epsilonOf is the float spacing at 1 for N, integers get the float64 one since the math is done in float64*/
+func epsilonOf[N Number]() float64 {
+	if isFloat[N]() {
+		tiny := 0x1p-30
+		if one := N(1); one+N(tiny) == one {
+			return 0x1p-23
+		}
+	}
+	return 0x1p-52
+}
+
+/*toNumber rounds the float64 working value into N, floats go straight across so NaN stays NaN*/
+func toNumber[N Number](x float64) N {
+	if isFloat[N]() {
+		return N(x)
+	}
+	return ConvertNumber[N](x)
+}
+
+/*evalAt wraps f so the methods can work in float64, for integer N f only ever sees rounded arguments*/
+func evalAt[N Number](f func(N) N) func(float64) float64 {
+	return func(x float64) float64 {
+		return float64(f(toNumber[N](x)))
+	}
+}
+
+/*opposite reports whether fa and fb have strictly opposite signs*/
+func opposite(fa float64, fb float64) bool {
+	return (fa < 0 && fb > 0) || (fa > 0 && fb < 0)
+}
+
+/*
+Bisect finds a root of f between a and b by halving the bracket, which has to have f(a) and f(b) on opposite sides
+of zero (or one of them be zero), otherwise it is ErrNoBracket. It is slow but can't fail. For integer N it is
+done exactly on integers and returns whichever of the last two points has the smaller |f|.
+*/
+func Bisect[N Number](f func(N) N, a N, b N, tol ...Tolerance) (N, error) {
+	t := pickTolerance[N](tol, 2100)
+	if b < a {
+		a, b = b, a
+	}
+	fa, fb := float64(f(a)), float64(f(b))
+	switch {
+	case fa == 0:
+		return a, nil
+	case fb == 0:
+		return b, nil
+	case !opposite(fa, fb):
+		return 0, ErrNoBracket
+	}
+	if !isFloat[N]() {
+		for i := 0; i < t.MaxIter; i++ {
+			if numberBits(b)-numberBits(a) <= 1 {
+				if math.Abs(fb) < math.Abs(fa) {
+					return b, nil
+				}
+				return a, nil
+			}
+			mid := midpointInt(a, b)
+			fm := float64(f(mid))
+			switch {
+			case fm == 0:
+				return mid, nil
+			case opposite(fa, fm):
+				b, fb = mid, fm
+			default:
+				a, fa = mid, fm
+			}
+		}
+		return a, ErrNoConvergence
+	}
+	lo, hi := float64(a), float64(b)
+	for i := 0; i < t.MaxIter; i++ {
+		mid := lo + (hi-lo)/2
+		if math.IsInf(hi-lo, 0) {
+			mid = lo/2 + hi/2
+		}
+		m := toNumber[N](mid)
+		// the bracket is down to neighboring values of N
+		if m == toNumber[N](lo) || m == toNumber[N](hi) || t.within(hi-lo, mid) {
+			return m, nil
+		}
+		fm := float64(f(m))
+		switch {
+		case fm == 0:
+			return m, nil
+		case opposite(fa, fm):
+			hi = mid
+		default:
+			lo, fa = mid, fm
+		}
+	}
+	return toNumber[N](lo + (hi-lo)/2), ErrNoConvergence
+}
+
+/*midpointInt is floor((a+b)/2) for a <= b without overflow, the difference always fits in a uint64*/
+func midpointInt[N Number](a N, b N) N {
+	return numberFromBits[N](numberBits(a) + (numberBits(b)-numberBits(a))/2)
+}
+
+/*
+Newton runs Newton-Raphson from x0. df is the derivative, pass nil to have it estimated with central differences.
+It converges fast near a simple root but can wander off from a bad start, so it returns ErrNoConvergence when the
+derivative vanishes, the iterate blows up or it runs out of rounds.
+*/
+func Newton[N Number](f func(N) N, df func(N) N, x0 N, tol ...Tolerance) (N, error) {
+	t := pickTolerance[N](tol, 100)
+	ev := evalAt(f)
+	deriv := func(x float64) float64 {
+		if df != nil {
+			return float64(df(toNumber[N](x)))
+		}
+		h := math.Cbrt(epsilonOf[N]()) * max(1, math.Abs(x))
+		if !isFloat[N]() {
+			h = math.Max(h, 1)
+		}
+		return (ev(x+h) - ev(x-h)) / (2 * h)
+	}
+	x := float64(x0)
+	for i := 0; i < t.MaxIter; i++ {
+		fx := ev(x)
+		if fx == 0 {
+			return toNumber[N](x), nil
+		}
+		d := deriv(x)
+		if d == 0 || math.IsNaN(d) {
+			return toNumber[N](x), ErrNoConvergence
+		}
+		step := fx / d
+		x -= step
+		if math.IsNaN(x) || math.IsInf(x, 0) {
+			return toNumber[N](x), ErrNoConvergence
+		}
+		if t.within(step, x) {
+			return toNumber[N](x), nil
+		}
+	}
+	return toNumber[N](x), ErrNoConvergence
+}
+
+/*
+Secant is Newton with the derivative taken from the last two points, it needs two starting guesses instead. For
+integer N it only stops once the two points are neighbors, the slope over a wide gap can't place the root to 0.5.
+*/
+func Secant[N Number](f func(N) N, x0 N, x1 N, tol ...Tolerance) (N, error) {
+	t := pickTolerance[N](tol, 100)
+	ev := evalAt(f)
+	a, b := float64(x0), float64(x1)
+	fa, fb := ev(a), ev(b)
+	for i := 0; i < t.MaxIter; i++ {
+		if fb == 0 {
+			return toNumber[N](b), nil
+		}
+		if fb == fa {
+			return toNumber[N](b), ErrNoConvergence
+		}
+		step, span := fb*(b-a)/(fb-fa), b-a
+		a, fa = b, fb
+		b -= step
+		if math.IsNaN(b) || math.IsInf(b, 0) {
+			return toNumber[N](b), ErrNoConvergence
+		}
+		if t.within(step, b) {
+			if isFloat[N]() || math.Abs(span) <= 1 {
+				return toNumber[N](b), nil
+			}
+			// on integers a step under 0.5 off a wide secant says little, a unit step gets a local slope first
+			b = a - math.Copysign(1, step)
+		}
+		fb = ev(b)
+	}
+	return toNumber[N](b), ErrNoConvergence
+}
+
+/*
+Brent finds a root in a bracket like Bisect but mixes in secant and inverse quadratic steps when they are safe, so
+it is about as fast as Secant on smooth functions and never slower than bisection. This is the zeroin algorithm
+the way Brent published it, the usual default for a bracketed root.
+*/
+func Brent[N Number](f func(N) N, a N, b N, tol ...Tolerance) (N, error) {
+	t := pickTolerance[N](tol, 200)
+	ev := evalAt(f)
+	xa, xb := float64(a), float64(b)
+	fa, fb := ev(xa), ev(xb)
+	switch {
+	case fa == 0:
+		return a, nil
+	case fb == 0:
+		return b, nil
+	case !opposite(fa, fb):
+		return 0, ErrNoBracket
+	}
+	xc, fc := xa, fa
+	d := xb - xa
+	e := d
+	for i := 0; i < t.MaxIter; i++ {
+		if !opposite(fb, fc) {
+			xc, fc = xa, fa
+			d = xb - xa
+			e = d
+		}
+		if math.Abs(fc) < math.Abs(fb) {
+			xa, xb, xc = xb, xc, xb
+			fa, fb, fc = fb, fc, fb
+		}
+		tol1 := 2*epsilonOf[N]()*math.Abs(xb) + 0.5*(t.Abs+t.Rel*math.Abs(xb))
+		xm := (xc - xb) / 2
+		if math.Abs(xm) <= tol1 || fb == 0 {
+			return toNumber[N](xb), nil
+		}
+		if math.Abs(e) >= tol1 && math.Abs(fa) > math.Abs(fb) {
+			var p, q float64
+			s := fb / fa
+			if xa == xc {
+				// secant
+				p, q = 2*xm*s, 1-s
+			} else {
+				// inverse quadratic interpolation
+				r := fb / fc
+				q = fa / fc
+				p = s * (2*xm*q*(q-r) - (xb-xa)*(r-1))
+				q = (q - 1) * (r - 1) * (s - 1)
+			}
+			if p > 0 {
+				q = -q
+			}
+			p = math.Abs(p)
+			if 2*p < min(3*xm*q-math.Abs(tol1*q), math.Abs(e*q)) {
+				e, d = d, p/q
+			} else {
+				d, e = xm, xm
+			}
+		} else {
+			d, e = xm, xm
+		}
+		xa, fa = xb, fb
+		if math.Abs(d) > tol1 {
+			xb += d
+		} else {
+			xb += math.Copysign(tol1, xm)
+		}
+		fb = ev(xb)
+	}
+	return toNumber[N](xb), ErrNoConvergence
+}
+
+/*
+BinarySearch finds the smallest x of all of N with f(x) >= target, for a non decreasing f. It is exact and works
+over the whole MinNum..MaxNum range, the midpoint is taken on the bits so it can't overflow. ok is false when even
+f(MaxNum) is below target.
+*/
+func BinarySearch[N Int, M Number](f func(N) M, target M) (x N, ok bool) {
+	return BinarySearchRange(MinNum[N](), MaxNum[N](), f, target)
+}
+
+/*BinarySearchRange is BinarySearch limited to lo..hi*/
+func BinarySearchRange[N Int, M Number](lo N, hi N, f func(N) M, target M) (x N, ok bool) {
+	if hi < lo {
+		return lo, false
+	}
+	for lo < hi {
+		mid := midpointInt(lo, hi)
+		if f(mid) >= target {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	return lo, f(lo) >= target
+}
@@ -0,0 +1,202 @@
+package RUNK
+
+import (
+	"math"
+	"math/bits"
+	"math/rand"
+	"testing"
+)
+
+func TestBisect(t *testing.T) {
+	x, err := Bisect(func(x float64) float64 { return x*x - 2 }, 0, 2)
+	if err != nil || ulpDiff(x, math.Sqrt2) > 4 {
+		t.Errorf("Bisect sqrt(2) = %v, %v", x, err)
+	}
+	x32, err := Bisect(func(x float32) float32 { return x*x - 2 }, 2, 0)
+	if err != nil || x32*x32-2 > 1e-6 || x32*x32-2 < -1e-6 {
+		t.Errorf("float32 Bisect with the ends swapped = %v, %v", x32, err)
+	}
+	// on integers the answer is the nearer of the two points around the root
+	n, err := Bisect(func(x int) int { return x*x - 50 }, 0, 100)
+	if err != nil || n != 7 {
+		t.Errorf("int Bisect = %d, %v, want 7", n, err)
+	}
+	n64, err := Bisect(func(x int64) int64 { return x>>1 - 12345 }, math.MinInt64, math.MaxInt64)
+	if err != nil || n64>>1 != 12345 {
+		t.Errorf("Bisect over all of int64 = %d, %v", n64, err)
+	}
+	if x, err := Bisect(func(x float64) float64 { return x - 3 }, 3, 10); err != nil || x != 3 {
+		t.Errorf("root at the end = %v, %v", x, err)
+	}
+	// the bracket still gets found across the whole float range
+	if x, err := Bisect(func(x float64) float64 { return x - 1e300 }, -math.MaxFloat64, math.MaxFloat64); err != nil || ulpDiff(x, 1e300) > 4 {
+		t.Errorf("Bisect over all floats = %v, %v", x, err)
+	}
+	for _, f := range []func(float64) float64{
+		func(x float64) float64 { return x*x + 1 },
+		func(x float64) float64 { return math.NaN() },
+	} {
+		if _, err := Bisect(f, -1, 1); err != ErrNoBracket {
+			t.Errorf("no bracket = %v", err)
+		}
+	}
+	if _, err := Bisect(math.Cos, 0, 3, Tolerance{MaxIter: 3}); err != ErrNoConvergence {
+		t.Errorf("Bisect out of rounds = %v", err)
+	}
+	x, err = Bisect(math.Cos, 0, 3, Tolerance{Abs: 1e-3})
+	if err != nil || math.Abs(x-math.Pi/2) > 1e-3 {
+		t.Errorf("Bisect to 1e-3 = %v, %v", x, err)
+	}
+}
+
+func TestNewtonAndSecant(t *testing.T) {
+	f := func(x float64) float64 { return math.Cos(x) - x }
+	df := func(x float64) float64 { return -math.Sin(x) - 1 }
+	const dottie = 0.7390851332151607
+	for _, c := range []struct {
+		name string
+		run  func() (float64, error)
+	}{
+		{"Newton", func() (float64, error) { return Newton(f, df, 1) }},
+		{"Newton estimated", func() (float64, error) { return Newton(f, nil, 1) }},
+		{"Secant", func() (float64, error) { return Secant(f, 0, 1) }},
+	} {
+		if x, err := c.run(); err != nil || ulpDiff(x, dottie) > 4 {
+			t.Errorf("%s = %v, %v", c.name, x, err)
+		}
+	}
+	n, err := Newton(func(x int64) int64 { return x*x - 1000000 }, nil, 1)
+	if err != nil || n != 1000 {
+		t.Errorf("int Newton = %d, %v", n, err)
+	}
+	s, err := Secant(func(x int64) int64 { return x*x*x - 27000 }, 1, 2)
+	if err != nil || s != 30 {
+		t.Errorf("int Secant = %d, %v", s, err)
+	}
+	r, err := Newton(func(x float32) float32 { return x*x - 3 }, nil, 1)
+	if err != nil || ulpDiff(float64(r), float64(float32(math.Sqrt(3)))) > 1<<30 {
+		t.Errorf("float32 Newton = %v, %v", r, err)
+	}
+
+	// no root, a flat start and running out of rounds all give up instead of looping
+	if _, err := Newton(func(x float64) float64 { return x*x + 1 }, nil, 3); err != ErrNoConvergence {
+		t.Errorf("Newton with no root = %v", err)
+	}
+	if _, err := Newton(func(x float64) float64 { return x*x - 1 }, func(x float64) float64 { return 2 * x }, 0); err != ErrNoConvergence {
+		t.Errorf("Newton from a flat point = %v", err)
+	}
+	if _, err := Newton(f, df, 100, Tolerance{MaxIter: 1}); err != ErrNoConvergence {
+		t.Errorf("Newton out of rounds = %v", err)
+	}
+	if _, err := Secant(func(x float64) float64 { return 5 }, 0, 1); err != ErrNoConvergence {
+		t.Errorf("Secant on a constant = %v", err)
+	}
+	if _, err := Secant(func(x float64) float64 { return math.Exp(x) }, 0, 1); err != ErrNoConvergence {
+		t.Errorf("Secant with no root = %v", err)
+	}
+}
+
+func TestBrent(t *testing.T) {
+	evals := 0
+	f := func(x float64) float64 {
+		evals++
+		return x*x*x - 2*x - 5
+	}
+	x, err := Brent(f, 2, 3)
+	if err != nil || ulpDiff(x, 2.0945514815423265) > 4 {
+		t.Errorf("Brent = %v, %v", x, err)
+	}
+	brentEvals := evals
+	evals = 0
+	Bisect(f, 2, 3)
+	if brentEvals*3 > evals {
+		t.Errorf("Brent took %d evaluations, Bisect %d", brentEvals, evals)
+	}
+
+	// a root where f is flat and one where f jumps, both still inside the bracket
+	if x, err := Brent(func(x float64) float64 { return math.Pow(x-1, 5) }, 0, 3); err != nil || math.Abs(x-1) > 1e-3 {
+		t.Errorf("Brent on a flat root = %v, %v", x, err)
+	}
+	if x, err := Brent(func(x float64) float64 { return math.Copysign(1, x-0.3) }, -1, 1); err != nil || math.Abs(x-0.3) > 1e-15 {
+		t.Errorf("Brent on a step = %v, %v", x, err)
+	}
+	rng := rand.New(rand.NewSource(26))
+	for trial := 0; trial < 200; trial++ {
+		root := rng.Float64()*20 - 10
+		x, err := Brent(func(x float64) float64 { return math.Atan(x - root) }, -10.5, 10.5)
+		if err != nil || math.Abs(x-root) > 1e-14*math.Max(1, math.Abs(root)) {
+			t.Fatalf("Brent for %v = %v, %v", root, x, err)
+		}
+	}
+	n, err := Brent(func(x int32) int32 { return 3*x - 1000 }, 0, 1000)
+	if err != nil || n != 333 {
+		t.Errorf("int Brent = %d, %v", n, err)
+	}
+	if _, err := Brent(f, 3, 4); err != ErrNoBracket {
+		t.Errorf("Brent with no bracket = %v", err)
+	}
+	if _, err := Brent(f, 2, 3, Tolerance{MaxIter: 2}); err != ErrNoConvergence {
+		t.Errorf("Brent out of rounds = %v", err)
+	}
+	if x, err := Brent(f, 2, 3, Tolerance{Abs: 0.01}); err != nil || math.Abs(x-2.0945514815423265) > 0.01 {
+		t.Errorf("Brent to 0.01 = %v, %v", x, err)
+	}
+}
+
+func TestBinarySearch(t *testing.T) {
+	// every target against a step function on all of int8, checked by scanning
+	rng := rand.New(rand.NewSource(27))
+	var steps [256]int
+	for i := 1; i < 256; i++ {
+		steps[i] = steps[i-1] + rng.Intn(3)
+	}
+	f := func(x int8) int { return steps[int(x)+128] }
+	for target := -1; target <= steps[255]+1; target++ {
+		want, found := int8(0), false
+		for x := -128; x <= 127; x++ {
+			if f(int8(x)) >= target {
+				want, found = int8(x), true
+				break
+			}
+		}
+		got, ok := BinarySearch(f, target)
+		if ok != found || (found && got != want) {
+			t.Fatalf("BinarySearch(%d) = %d, %v, want %d, %v", target, got, ok, want, found)
+		}
+	}
+
+	// integer square root over the whole of uint64, where mid+1 and the midpoint must not overflow
+	isqrt := func(n uint64) uint64 {
+		x, ok := BinarySearch(func(x uint64) uint8 {
+			if hi, lo := bits.Mul64(x, x); hi > 0 || lo > n {
+				return 1
+			}
+			return 0
+		}, 1)
+		if !ok {
+			return math.MaxUint64
+		}
+		return x - 1
+	}
+	for _, n := range []uint64{0, 1, 2, 3, 4, 99, 100, 1<<62 + 5, math.MaxUint64} {
+		r := isqrt(n)
+		hi, lo := bits.Mul64(r, r)
+		hi1, lo1 := bits.Mul64(r+1, r+1)
+		if hi != 0 || lo > n || (hi1 == 0 && lo1 <= n) {
+			t.Errorf("isqrt(%d) = %d", n, r)
+		}
+	}
+
+	if x, ok := BinarySearch(func(x int64) int64 { return x }, math.MaxInt64); !ok || x != math.MaxInt64 {
+		t.Errorf("search for the top = %d, %v", x, ok)
+	}
+	if x, ok := BinarySearch(func(x int64) int64 { return x / 4 }, math.MinInt64/4); !ok || x != math.MinInt64 {
+		t.Errorf("search for the bottom = %d, %v", x, ok)
+	}
+	if _, ok := BinarySearchRange(0, 10, func(x int) int { return x }, 11); ok {
+		t.Error("a target past f(hi) should not be found")
+	}
+	if x, ok := BinarySearchRange(10, 0, func(x int) int { return x }, 5); ok || x != 10 {
+		t.Errorf("empty range = %d, %v", x, ok)
+	}
+}
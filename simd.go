@@ -0,0 +1,189 @@
+package RUNK
+
+import "math"
+
+/*
+The bulk kernels here are for big buffers of the narrow types, audio in []int16, pixels in []uint8 and tensors in
+[]float32, where calling a generic function per element is far too slow. On amd64 they run in assembly with AVX2,
+or SSE4.1 on older CPUs, on arm64 they use NEON, and everywhere else (or when built with the purego tag) they run
+the plain Go versions below. All of them give bit for bit the same answers. To make that possible the float32 sums are defined the way the vector unit does them: 8 running
+lanes that are added together pairwise at the end, and then the leftover elements one at a time. That is not the
+same rounding as a simple loop but it is fixed and doesn't depend on the machine. A NaN result is always the
+canonical NaN.
+Like copy they work on min(len) of the slices and the element wise ones return how many that was.
+*/
+
+/*simdLanes is how many float32s the sums keep running at once*/
+const simdLanes = 8
+
+/*simdKernel is one set of kernels, the arch files list the ones this CPU can run in simdKernels, best first*/
+type simdKernel uint8
+
+const (
+	kernelGo simdKernel = iota
+	kernelSSE41
+	kernelAVX2
+	kernelNEON
+)
+
+/*activeKernel is what the dispatch uses. It is picked once at start up, the tests switch it to compare them.*/
+var activeKernel = simdKernels[0]
+
+/*
+ConvertSlice converts every element of src into dst with the same rules as ConvertNumberBy. float32 to int16 and
+float32 to uint8 with the default rounding (math.Round) have fast paths, which is the usual audio and image case.
+*/
+func ConvertSlice[To Number, From Number](dst []To, src []From, roundMode ...RoundingMode) int {
+	n := min(len(dst), len(src))
+	if len(roundMode) == 0 || roundMode[0] == nil {
+		if s, ok := any(src).([]float32); ok {
+			switch d := any(dst).(type) {
+			case []int16:
+				convertFloat32sToInt16s(d[:n], s[:n])
+				return n
+			case []uint8:
+				convertFloat32sToUint8s(d[:n], s[:n])
+				return n
+			}
+		}
+	}
+	for i := 0; i < n; i++ {
+		dst[i] = ConvertNumberBy[To](src[i], roundMode...)
+	}
+	return n
+}
+
+/*SumFloat32s adds up xs in 8 lanes, see above for the exact order*/
+func SumFloat32s(xs []float32) float32 {
+	return canonicalNaN(sumFloat32s(xs))
+}
+
+/*DotFloat32s is the sum of x[i]*y[i] in 8 lanes. Each product is rounded to float32 before it is added, there is no FMA.*/
+func DotFloat32s(x []float32, y []float32) float32 {
+	n := min(len(x), len(y))
+	return canonicalNaN(dotFloat32s(x[:n], y[:n]))
+}
+
+/*MinMaxInt16s returns the smallest and largest value, or ErrEmpty*/
+func MinMaxInt16s(xs []int16) (int16, int16, error) {
+	if len(xs) == 0 {
+		return 0, 0, ErrEmpty
+	}
+	mn, mx := minMaxInt16s(xs)
+	return mn, mx, nil
+}
+
+func MinMaxUint8s(xs []uint8) (uint8, uint8, error) {
+	if len(xs) == 0 {
+		return 0, 0, ErrEmpty
+	}
+	mn, mx := minMaxUint8s(xs)
+	return mn, mx, nil
+}
+
+/*AddSatInt16s sets dst[i] = x[i] + y[i] clamped to the int16 range*/
+func AddSatInt16s(dst []int16, x []int16, y []int16) int {
+	n := min(len(dst), len(x), len(y))
+	addSatInt16s(dst[:n], x[:n], y[:n])
+	return n
+}
+
+/*AddSatUint8s sets dst[i] = x[i] + y[i] clamped at 255*/
+func AddSatUint8s(dst []uint8, x []uint8, y []uint8) int {
+	n := min(len(dst), len(x), len(y))
+	addSatUint8s(dst[:n], x[:n], y[:n])
+	return n
+}
+
+func canonicalNaN(f float32) float32 {
+	if f != f {
+		return float32(math.NaN())
+	}
+	return f
+}
+
+/*
+The Go versions. The assembly only ever handles whole blocks so these also finish off the tail for it, which is
+why the sums take the lane totals to start from.
+*/
+
+func convertFloat32sToInt16sGo(dst []int16, src []float32) {
+	for i, f := range src {
+		dst[i] = ConvertNumber[int16](f)
+	}
+}
+
+func convertFloat32sToUint8sGo(dst []uint8, src []float32) {
+	for i, f := range src {
+		dst[i] = ConvertNumber[uint8](f)
+	}
+}
+
+/*reduceLanes adds the 8 lanes the same way the assembly does, halves first and then pairs*/
+func reduceLanes(acc *[simdLanes]float32) float32 {
+	s0, s1, s2, s3 := acc[0]+acc[4], acc[1]+acc[5], acc[2]+acc[6], acc[3]+acc[7]
+	return (s0 + s2) + (s1 + s3)
+}
+
+func sumFloat32sGo(xs []float32) float32 {
+	var acc [simdLanes]float32
+	blocks := len(xs) &^ (simdLanes - 1)
+	for i := 0; i < blocks; i += simdLanes {
+		for l := range acc {
+			acc[l] += xs[i+l]
+		}
+	}
+	return sumTail(reduceLanes(&acc), xs[blocks:])
+}
+
+func sumTail(total float32, tail []float32) float32 {
+	for _, x := range tail {
+		total += x
+	}
+	return total
+}
+
+func dotFloat32sGo(x []float32, y []float32) float32 {
+	var acc [simdLanes]float32
+	blocks := len(x) &^ (simdLanes - 1)
+	for i := 0; i < blocks; i += simdLanes {
+		for l := range acc {
+			// the conversion stops the compiler from fusing this into an FMA
+			acc[l] += float32(x[i+l] * y[i+l])
+		}
+	}
+	return dotTail(reduceLanes(&acc), x[blocks:], y[blocks:])
+}
+
+func dotTail(total float32, x []float32, y []float32) float32 {
+	for i := range x {
+		total += float32(x[i] * y[i])
+	}
+	return total
+}
+
+func minMaxInt16sGo(xs []int16, mn int16, mx int16) (int16, int16) {
+	for _, x := range xs {
+		mn, mx = min(mn, x), max(mx, x)
+	}
+	return mn, mx
+}
+
+func minMaxUint8sGo(xs []uint8, mn uint8, mx uint8) (uint8, uint8) {
+	for _, x := range xs {
+		mn, mx = min(mn, x), max(mx, x)
+	}
+	return mn, mx
+}
+
+func addSatInt16sGo(dst []int16, x []int16, y []int16) {
+	for i := range dst {
+		dst[i] = int16(max(math.MinInt16, min(int32(x[i])+int32(y[i]), math.MaxInt16)))
+	}
+}
+
+func addSatUint8sGo(dst []uint8, x []uint8, y []uint8) {
+	for i := range dst {
+		dst[i] = uint8(min(uint16(x[i])+uint16(y[i]), math.MaxUint8))
+	}
+}
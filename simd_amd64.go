@@ -0,0 +1,216 @@
+//go:build amd64 && !purego
+
+package RUNK
+
+/*simdKernels is worked out once at start up, see detectAVX2 and detectSSE41*/
+var simdKernels = detectKernels()
+
+func cpuid(eaxArg uint32, ecxArg uint32) (eax uint32, ebx uint32, ecx uint32, edx uint32)
+
+func xgetbv() (eax uint32, edx uint32)
+
+func detectKernels() []simdKernel {
+	var kernels []simdKernel
+	if detectAVX2() {
+		kernels = append(kernels, kernelAVX2)
+	}
+	if detectSSE41() {
+		kernels = append(kernels, kernelSSE41)
+	}
+	return append(kernels, kernelGo)
+}
+
+/*
+AVX2 needs the CPU flag and also the OS has to be saving the YMM registers, which is what OSXSAVE and XCR0 tell
+us.
+*/
+func detectAVX2() bool {
+	maxLeaf, _, _, _ := cpuid(0, 0)
+	if maxLeaf < 7 {
+		return false
+	}
+	_, _, ecx1, _ := cpuid(1, 0)
+	const osxsave, avx = 1 << 27, 1 << 28
+	if ecx1&osxsave == 0 || ecx1&avx == 0 {
+		return false
+	}
+	if xcr0, _ := xgetbv(); xcr0&6 != 6 {
+		return false
+	}
+	_, ebx7, _, _ := cpuid(7, 0)
+	return ebx7&(1<<5) != 0
+}
+
+/*detectSSE41 checks SSE4.1, every CPU that has it also has the SSE3 MOVSHDUP the sums use*/
+func detectSSE41() bool {
+	_, _, ecx1, _ := cpuid(1, 0)
+	const sse3, sse41 = 1 << 0, 1 << 19
+	return ecx1&sse3 != 0 && ecx1&sse41 != 0
+}
+
+/*
+The assembly kernels take raw pointers and a count that is a whole number of blocks, the Go side slices off the
+blocks and finishes the tail. The SSE4.1 blocks are half the AVX2 ones except for the sums, which keep 8 lanes in
+two registers.
+*/
+
+//go:noescape
+func convertFloat32sToInt16sAVX2(dst *int16, src *float32, n int)
+
+//go:noescape
+func convertFloat32sToUint8sAVX2(dst *uint8, src *float32, n int)
+
+//go:noescape
+func sumFloat32sAVX2(xs *float32, n int) float32
+
+//go:noescape
+func dotFloat32sAVX2(x *float32, y *float32, n int) float32
+
+//go:noescape
+func minMaxInt16sAVX2(xs *int16, n int) (mn int16, mx int16)
+
+//go:noescape
+func minMaxUint8sAVX2(xs *uint8, n int) (mn uint8, mx uint8)
+
+//go:noescape
+func addSatInt16sAVX2(dst *int16, x *int16, y *int16, n int)
+
+//go:noescape
+func addSatUint8sAVX2(dst *uint8, x *uint8, y *uint8, n int)
+
+//go:noescape
+func convertFloat32sToInt16sSSE41(dst *int16, src *float32, n int)
+
+//go:noescape
+func convertFloat32sToUint8sSSE41(dst *uint8, src *float32, n int)
+
+//go:noescape
+func sumFloat32sSSE41(xs *float32, n int) float32
+
+//go:noescape
+func dotFloat32sSSE41(x *float32, y *float32, n int) float32
+
+//go:noescape
+func minMaxInt16sSSE41(xs *int16, n int) (mn int16, mx int16)
+
+//go:noescape
+func minMaxUint8sSSE41(xs *uint8, n int) (mn uint8, mx uint8)
+
+//go:noescape
+func addSatInt16sSSE41(dst *int16, x *int16, y *int16, n int)
+
+//go:noescape
+func addSatUint8sSSE41(dst *uint8, x *uint8, y *uint8, n int)
+
+/*wholeBlocks is n rounded down to a multiple of the AVX2 or the SSE4.1 block, or 0 for the Go kernels*/
+func wholeBlocks(n int, avx2 int, sse41 int) int {
+	switch activeKernel {
+	case kernelAVX2:
+		return n &^ (avx2 - 1)
+	case kernelSSE41:
+		return n &^ (sse41 - 1)
+	}
+	return 0
+}
+
+func convertFloat32sToInt16s(dst []int16, src []float32) {
+	blocks := wholeBlocks(len(src), 16, 8)
+	if blocks > 0 {
+		if activeKernel == kernelAVX2 {
+			convertFloat32sToInt16sAVX2(&dst[0], &src[0], blocks)
+		} else {
+			convertFloat32sToInt16sSSE41(&dst[0], &src[0], blocks)
+		}
+	}
+	convertFloat32sToInt16sGo(dst[blocks:], src[blocks:])
+}
+
+func convertFloat32sToUint8s(dst []uint8, src []float32) {
+	blocks := wholeBlocks(len(src), 32, 16)
+	if blocks > 0 {
+		if activeKernel == kernelAVX2 {
+			convertFloat32sToUint8sAVX2(&dst[0], &src[0], blocks)
+		} else {
+			convertFloat32sToUint8sSSE41(&dst[0], &src[0], blocks)
+		}
+	}
+	convertFloat32sToUint8sGo(dst[blocks:], src[blocks:])
+}
+
+func sumFloat32s(xs []float32) float32 {
+	blocks := wholeBlocks(len(xs), simdLanes, simdLanes)
+	if blocks == 0 {
+		return sumFloat32sGo(xs)
+	}
+	var total float32
+	if activeKernel == kernelAVX2 {
+		total = sumFloat32sAVX2(&xs[0], blocks)
+	} else {
+		total = sumFloat32sSSE41(&xs[0], blocks)
+	}
+	return sumTail(total, xs[blocks:])
+}
+
+func dotFloat32s(x []float32, y []float32) float32 {
+	blocks := wholeBlocks(len(x), simdLanes, simdLanes)
+	if blocks == 0 {
+		return dotFloat32sGo(x, y)
+	}
+	var total float32
+	if activeKernel == kernelAVX2 {
+		total = dotFloat32sAVX2(&x[0], &y[0], blocks)
+	} else {
+		total = dotFloat32sSSE41(&x[0], &y[0], blocks)
+	}
+	return dotTail(total, x[blocks:], y[blocks:])
+}
+
+func minMaxInt16s(xs []int16) (int16, int16) {
+	blocks := wholeBlocks(len(xs), 16, 8)
+	mn, mx := xs[0], xs[0]
+	if blocks > 0 {
+		if activeKernel == kernelAVX2 {
+			mn, mx = minMaxInt16sAVX2(&xs[0], blocks)
+		} else {
+			mn, mx = minMaxInt16sSSE41(&xs[0], blocks)
+		}
+	}
+	return minMaxInt16sGo(xs[blocks:], mn, mx)
+}
+
+func minMaxUint8s(xs []uint8) (uint8, uint8) {
+	blocks := wholeBlocks(len(xs), 32, 16)
+	mn, mx := xs[0], xs[0]
+	if blocks > 0 {
+		if activeKernel == kernelAVX2 {
+			mn, mx = minMaxUint8sAVX2(&xs[0], blocks)
+		} else {
+			mn, mx = minMaxUint8sSSE41(&xs[0], blocks)
+		}
+	}
+	return minMaxUint8sGo(xs[blocks:], mn, mx)
+}
+
+func addSatInt16s(dst []int16, x []int16, y []int16) {
+	blocks := wholeBlocks(len(dst), 16, 8)
+	if blocks > 0 {
+		if activeKernel == kernelAVX2 {
+			addSatInt16sAVX2(&dst[0], &x[0], &y[0], blocks)
+		} else {
+			addSatInt16sSSE41(&dst[0], &x[0], &y[0], blocks)
+		}
+	}
+	addSatInt16sGo(dst[blocks:], x[blocks:], y[blocks:])
+}
+
+func addSatUint8s(dst []uint8, x []uint8, y []uint8) {
+	blocks := wholeBlocks(len(dst), 32, 16)
+	if blocks > 0 {
+		if activeKernel == kernelAVX2 {
+			addSatUint8sAVX2(&dst[0], &x[0], &y[0], blocks)
+		} else {
+			addSatUint8sSSE41(&dst[0], &x[0], &y[0], blocks)
+		}
+	}
+	addSatUint8sGo(dst[blocks:], x[blocks:], y[blocks:])
+}
@@ -0,0 +1,547 @@
+//go:build amd64 && !purego
+
+#include "textflag.h"
+
+// permute order that undoes the in lane packing of VPACKSSDW/VPACKUSWB
+DATA packOrder<>+0(SB)/4, $0
+DATA packOrder<>+4(SB)/4, $4
+DATA packOrder<>+8(SB)/4, $1
+DATA packOrder<>+12(SB)/4, $5
+DATA packOrder<>+16(SB)/4, $2
+DATA packOrder<>+20(SB)/4, $6
+DATA packOrder<>+24(SB)/4, $3
+DATA packOrder<>+28(SB)/4, $7
+GLOBL packOrder<>(SB), RODATA|NOPTR, $32
+
+// func cpuid(eaxArg uint32, ecxArg uint32) (eax uint32, ebx uint32, ecx uint32, edx uint32)
+TEXT ·cpuid(SB), NOSPLIT, $0-24
+	MOVL eaxArg+0(FP), AX
+	MOVL ecxArg+4(FP), CX
+	CPUID
+	MOVL AX, eax+8(FP)
+	MOVL BX, ebx+12(FP)
+	MOVL CX, ecx+16(FP)
+	MOVL DX, edx+20(FP)
+	RET
+
+// func xgetbv() (eax uint32, edx uint32)
+TEXT ·xgetbv(SB), NOSPLIT, $0-8
+	MOVL $0, CX
+	XGETBV
+	MOVL AX, eax+0(FP)
+	MOVL DX, edx+4(FP)
+	RET
+
+// ROUNDCLAMP rounds the 8 floats in r half away from zero to integers clamped to [lo, hi] with NaN as 0.
+// Y12 holds the sign mask, Y13 0.49999997, Y14 lo and Y15 hi. t is a scratch register.
+#define ROUNDCLAMP(r, t) \
+	VCMPPS     $0, r, r, t \
+	VANDPS     t, r, r     \
+	VANDPS     Y12, r, t   \
+	VORPS      Y13, t, t   \
+	VADDPS     t, r, r     \
+	VMAXPS     Y14, r, r   \
+	VMINPS     Y15, r, r   \
+	VCVTTPS2DQ r, r
+
+#define ROUNDCONSTS(lo, hi) \
+	MOVL         $0x80000000, AX \
+	MOVQ         AX, X12         \
+	VPBROADCASTD X12, Y12        \
+	MOVL         $0x3effffff, AX \
+	MOVQ         AX, X13         \
+	VPBROADCASTD X13, Y13        \
+	MOVL         lo, AX          \
+	MOVQ         AX, X14         \
+	VPBROADCASTD X14, Y14        \
+	MOVL         hi, AX          \
+	MOVQ         AX, X15         \
+	VPBROADCASTD X15, Y15
+
+// func convertFloat32sToInt16sAVX2(dst *int16, src *float32, n int)
+TEXT ·convertFloat32sToInt16sAVX2(SB), NOSPLIT, $0-24
+	MOVQ dst+0(FP), DI
+	MOVQ src+8(FP), SI
+	MOVQ n+16(FP), CX
+	ROUNDCONSTS($0xc7000000, $0x46fffe00)
+	XORQ AX, AX
+
+i16loop:
+	CMPQ      AX, CX
+	JAE       i16done
+	VMOVUPS   (SI)(AX*4), Y0
+	VMOVUPS   32(SI)(AX*4), Y1
+	ROUNDCLAMP(Y0, Y2)
+	ROUNDCLAMP(Y1, Y3)
+	VPACKSSDW Y1, Y0, Y0
+	VPERMQ    $0xd8, Y0, Y0
+	VMOVDQU   Y0, (DI)(AX*2)
+	ADDQ      $16, AX
+	JMP       i16loop
+
+i16done:
+	VZEROUPPER
+	RET
+
+// func convertFloat32sToUint8sAVX2(dst *uint8, src *float32, n int)
+TEXT ·convertFloat32sToUint8sAVX2(SB), NOSPLIT, $0-24
+	MOVQ    dst+0(FP), DI
+	MOVQ    src+8(FP), SI
+	MOVQ    n+16(FP), CX
+	ROUNDCONSTS($0, $0x437f0000)
+	VMOVDQU packOrder<>(SB), Y11
+	XORQ    AX, AX
+
+u8loop:
+	CMPQ      AX, CX
+	JAE       u8done
+	VMOVUPS   (SI)(AX*4), Y0
+	VMOVUPS   32(SI)(AX*4), Y1
+	VMOVUPS   64(SI)(AX*4), Y2
+	VMOVUPS   96(SI)(AX*4), Y3
+	ROUNDCLAMP(Y0, Y4)
+	ROUNDCLAMP(Y1, Y5)
+	ROUNDCLAMP(Y2, Y6)
+	ROUNDCLAMP(Y3, Y7)
+	VPACKSSDW Y1, Y0, Y0
+	VPACKSSDW Y3, Y2, Y2
+	VPACKUSWB Y2, Y0, Y0
+	VPERMD    Y0, Y11, Y0
+	VMOVDQU   Y0, (DI)(AX*1)
+	ADDQ      $32, AX
+	JMP       u8loop
+
+u8done:
+	VZEROUPPER
+	RET
+
+// REDUCE8 adds the 8 lanes of Y0 into X0 as (a0+a4 + a2+a6) + (a1+a5 + a3+a7)
+#define REDUCE8 \
+	VEXTRACTF128 $1, Y0, X1 \
+	VADDPS       X1, X0, X0 \
+	VMOVHLPS     X0, X0, X1 \
+	VADDPS       X1, X0, X0 \
+	VMOVSHDUP    X0, X1     \
+	VADDSS       X1, X0, X0
+
+// func sumFloat32sAVX2(xs *float32, n int) float32
+TEXT ·sumFloat32sAVX2(SB), NOSPLIT, $0-20
+	MOVQ   xs+0(FP), SI
+	MOVQ   n+8(FP), CX
+	VXORPS Y0, Y0, Y0
+	XORQ   AX, AX
+
+sumloop:
+	CMPQ   AX, CX
+	JAE    sumdone
+	VADDPS (SI)(AX*4), Y0, Y0
+	ADDQ   $8, AX
+	JMP    sumloop
+
+sumdone:
+	REDUCE8
+	MOVSS X0, ret+16(FP)
+	VZEROUPPER
+	RET
+
+// func dotFloat32sAVX2(x *float32, y *float32, n int) float32
+TEXT ·dotFloat32sAVX2(SB), NOSPLIT, $0-28
+	MOVQ   x+0(FP), SI
+	MOVQ   y+8(FP), DX
+	MOVQ   n+16(FP), CX
+	VXORPS Y0, Y0, Y0
+	XORQ   AX, AX
+
+dotloop:
+	CMPQ    AX, CX
+	JAE     dotdone
+	VMOVUPS (SI)(AX*4), Y1
+	VMULPS  (DX)(AX*4), Y1, Y1
+	VADDPS  Y1, Y0, Y0
+	ADDQ    $8, AX
+	JMP     dotloop
+
+dotdone:
+	REDUCE8
+	MOVSS X0, ret+24(FP)
+	VZEROUPPER
+	RET
+
+// func minMaxInt16sAVX2(xs *int16, n int) (mn int16, mx int16)
+TEXT ·minMaxInt16sAVX2(SB), NOSPLIT, $0-20
+	MOVQ    xs+0(FP), SI
+	MOVQ    n+8(FP), CX
+	SHLQ    $1, CX
+	VMOVDQU (SI), Y0
+	VMOVDQA Y0, Y1
+	MOVQ    $32, AX
+
+i16mmloop:
+	CMPQ    AX, CX
+	JAE     i16mmdone
+	VMOVDQU (SI)(AX*1), Y2
+	VPMINSW Y2, Y0, Y0
+	VPMAXSW Y2, Y1, Y1
+	ADDQ    $32, AX
+	JMP     i16mmloop
+
+i16mmdone:
+	VEXTRACTI128 $1, Y0, X2
+	VPMINSW      X2, X0, X0
+	VEXTRACTI128 $1, Y1, X3
+	VPMAXSW      X3, X1, X1
+	VPSRLDQ      $8, X0, X2
+	VPMINSW      X2, X0, X0
+	VPSRLDQ      $8, X1, X3
+	VPMAXSW      X3, X1, X1
+	VPSRLDQ      $4, X0, X2
+	VPMINSW      X2, X0, X0
+	VPSRLDQ      $4, X1, X3
+	VPMAXSW      X3, X1, X1
+	VPSRLDQ      $2, X0, X2
+	VPMINSW      X2, X0, X0
+	VPSRLDQ      $2, X1, X3
+	VPMAXSW      X3, X1, X1
+	MOVQ         X0, AX
+	MOVW         AX, mn+16(FP)
+	MOVQ         X1, AX
+	MOVW         AX, mx+18(FP)
+	VZEROUPPER
+	RET
+
+// func minMaxUint8sAVX2(xs *uint8, n int) (mn uint8, mx uint8)
+TEXT ·minMaxUint8sAVX2(SB), NOSPLIT, $0-18
+	MOVQ    xs+0(FP), SI
+	MOVQ    n+8(FP), CX
+	VMOVDQU (SI), Y0
+	VMOVDQA Y0, Y1
+	MOVQ    $32, AX
+
+u8mmloop:
+	CMPQ    AX, CX
+	JAE     u8mmdone
+	VMOVDQU (SI)(AX*1), Y2
+	VPMINUB Y2, Y0, Y0
+	VPMAXUB Y2, Y1, Y1
+	ADDQ    $32, AX
+	JMP     u8mmloop
+
+u8mmdone:
+	VEXTRACTI128 $1, Y0, X2
+	VPMINUB      X2, X0, X0
+	VEXTRACTI128 $1, Y1, X3
+	VPMAXUB      X3, X1, X1
+	VPSRLDQ      $8, X0, X2
+	VPMINUB      X2, X0, X0
+	VPSRLDQ      $8, X1, X3
+	VPMAXUB      X3, X1, X1
+	VPSRLDQ      $4, X0, X2
+	VPMINUB      X2, X0, X0
+	VPSRLDQ      $4, X1, X3
+	VPMAXUB      X3, X1, X1
+	VPSRLDQ      $2, X0, X2
+	VPMINUB      X2, X0, X0
+	VPSRLDQ      $2, X1, X3
+	VPMAXUB      X3, X1, X1
+	VPSRLDQ      $1, X0, X2
+	VPMINUB      X2, X0, X0
+	VPSRLDQ      $1, X1, X3
+	VPMAXUB      X3, X1, X1
+	MOVQ         X0, AX
+	MOVB         AX, mn+16(FP)
+	MOVQ         X1, AX
+	MOVB         AX, mx+17(FP)
+	VZEROUPPER
+	RET
+
+// func addSatInt16sAVX2(dst *int16, x *int16, y *int16, n int)
+TEXT ·addSatInt16sAVX2(SB), NOSPLIT, $0-32
+	MOVQ dst+0(FP), DI
+	MOVQ x+8(FP), SI
+	MOVQ y+16(FP), DX
+	MOVQ n+24(FP), CX
+	SHLQ $1, CX
+	XORQ AX, AX
+
+i16addloop:
+	CMPQ     AX, CX
+	JAE      i16adddone
+	VMOVDQU  (SI)(AX*1), Y0
+	VPADDSW  (DX)(AX*1), Y0, Y0
+	VMOVDQU  Y0, (DI)(AX*1)
+	ADDQ     $32, AX
+	JMP      i16addloop
+
+i16adddone:
+	VZEROUPPER
+	RET
+
+// func addSatUint8sAVX2(dst *uint8, x *uint8, y *uint8, n int)
+TEXT ·addSatUint8sAVX2(SB), NOSPLIT, $0-32
+	MOVQ dst+0(FP), DI
+	MOVQ x+8(FP), SI
+	MOVQ y+16(FP), DX
+	MOVQ n+24(FP), CX
+	XORQ AX, AX
+
+u8addloop:
+	CMPQ     AX, CX
+	JAE      u8adddone
+	VMOVDQU  (SI)(AX*1), Y0
+	VPADDUSB (DX)(AX*1), Y0, Y0
+	VMOVDQU  Y0, (DI)(AX*1)
+	ADDQ     $32, AX
+	JMP      u8addloop
+
+u8adddone:
+	VZEROUPPER
+	RET
+
+// The SSE4.1 kernels do the same with 16 byte registers. Loads go through MOVUPS/MOVOU as the slices needn't be
+// aligned.
+
+// ROUNDCLAMPSSE is ROUNDCLAMP for one XMM register, with the constants in X12 to X15.
+#define ROUNDCLAMPSSE(r, t) \
+	MOVAPS    r, t      \
+	CMPPS     r, t, $0  \
+	ANDPS     t, r      \
+	MOVAPS    r, t      \
+	ANDPS     X12, t    \
+	ORPS      X13, t    \
+	ADDPS     t, r      \
+	MAXPS     X14, r    \
+	MINPS     X15, r    \
+	CVTTPS2PL r, r
+
+#define ROUNDCONSTSSSE(lo, hi) \
+	MOVL   $0x80000000, AX \
+	MOVQ   AX, X12         \
+	PSHUFD $0, X12, X12    \
+	MOVL   $0x3effffff, AX \
+	MOVQ   AX, X13         \
+	PSHUFD $0, X13, X13    \
+	MOVL   lo, AX          \
+	MOVQ   AX, X14         \
+	PSHUFD $0, X14, X14    \
+	MOVL   hi, AX          \
+	MOVQ   AX, X15         \
+	PSHUFD $0, X15, X15
+
+// func convertFloat32sToInt16sSSE41(dst *int16, src *float32, n int)
+TEXT ·convertFloat32sToInt16sSSE41(SB), NOSPLIT, $0-24
+	MOVQ dst+0(FP), DI
+	MOVQ src+8(FP), SI
+	MOVQ n+16(FP), CX
+	ROUNDCONSTSSSE($0xc7000000, $0x46fffe00)
+	XORQ AX, AX
+
+i16sseloop:
+	CMPQ     AX, CX
+	JAE      i16ssedone
+	MOVUPS   (SI)(AX*4), X0
+	MOVUPS   16(SI)(AX*4), X1
+	ROUNDCLAMPSSE(X0, X2)
+	ROUNDCLAMPSSE(X1, X3)
+	PACKSSLW X1, X0
+	MOVOU    X0, (DI)(AX*2)
+	ADDQ     $8, AX
+	JMP      i16sseloop
+
+i16ssedone:
+	RET
+
+// func convertFloat32sToUint8sSSE41(dst *uint8, src *float32, n int)
+TEXT ·convertFloat32sToUint8sSSE41(SB), NOSPLIT, $0-24
+	MOVQ dst+0(FP), DI
+	MOVQ src+8(FP), SI
+	MOVQ n+16(FP), CX
+	ROUNDCONSTSSSE($0, $0x437f0000)
+	XORQ AX, AX
+
+u8sseloop:
+	CMPQ     AX, CX
+	JAE      u8ssedone
+	MOVUPS   (SI)(AX*4), X0
+	MOVUPS   16(SI)(AX*4), X1
+	MOVUPS   32(SI)(AX*4), X2
+	MOVUPS   48(SI)(AX*4), X3
+	ROUNDCLAMPSSE(X0, X4)
+	ROUNDCLAMPSSE(X1, X5)
+	ROUNDCLAMPSSE(X2, X6)
+	ROUNDCLAMPSSE(X3, X7)
+	PACKUSDW X1, X0
+	PACKUSDW X3, X2
+	PACKUSWB X2, X0
+	MOVOU    X0, (DI)(AX*1)
+	ADDQ     $16, AX
+	JMP      u8sseloop
+
+u8ssedone:
+	RET
+
+// REDUCE8SSE adds the lanes of X0 (0 to 3) and X1 (4 to 7) into X0 in the same order as REDUCE8
+#define REDUCE8SSE \
+	ADDPS    X1, X0 \
+	MOVHLPS  X0, X1 \
+	ADDPS    X1, X0 \
+	MOVSHDUP X0, X1 \
+	ADDSS    X1, X0
+
+// func sumFloat32sSSE41(xs *float32, n int) float32
+TEXT ·sumFloat32sSSE41(SB), NOSPLIT, $0-20
+	MOVQ  xs+0(FP), SI
+	MOVQ  n+8(FP), CX
+	XORPS X0, X0
+	XORPS X1, X1
+	XORQ  AX, AX
+
+sumsseloop:
+	CMPQ   AX, CX
+	JAE    sumssedone
+	MOVUPS (SI)(AX*4), X2
+	MOVUPS 16(SI)(AX*4), X3
+	ADDPS  X2, X0
+	ADDPS  X3, X1
+	ADDQ   $8, AX
+	JMP    sumsseloop
+
+sumssedone:
+	REDUCE8SSE
+	MOVSS X0, ret+16(FP)
+	RET
+
+// func dotFloat32sSSE41(x *float32, y *float32, n int) float32
+TEXT ·dotFloat32sSSE41(SB), NOSPLIT, $0-28
+	MOVQ  x+0(FP), SI
+	MOVQ  y+8(FP), DX
+	MOVQ  n+16(FP), CX
+	XORPS X0, X0
+	XORPS X1, X1
+	XORQ  AX, AX
+
+dotsseloop:
+	CMPQ   AX, CX
+	JAE    dotssedone
+	MOVUPS (SI)(AX*4), X2
+	MOVUPS 16(SI)(AX*4), X3
+	MOVUPS (DX)(AX*4), X4
+	MOVUPS 16(DX)(AX*4), X5
+	MULPS  X4, X2
+	MULPS  X5, X3
+	ADDPS  X2, X0
+	ADDPS  X3, X1
+	ADDQ   $8, AX
+	JMP    dotsseloop
+
+dotssedone:
+	REDUCE8SSE
+	MOVSS X0, ret+24(FP)
+	RET
+
+// FOLDSSE combines the halves of the two accumulators X0 and X1 with min and max, shifting by s bytes
+#define FOLDSSE(s, minop, maxop) \
+	MOVO   X0, X2 \
+	PSRLO  s, X2  \
+	minop  X2, X0 \
+	MOVO   X1, X3 \
+	PSRLO  s, X3  \
+	maxop  X3, X1
+
+// func minMaxInt16sSSE41(xs *int16, n int) (mn int16, mx int16)
+TEXT ·minMaxInt16sSSE41(SB), NOSPLIT, $0-20
+	MOVQ  xs+0(FP), SI
+	MOVQ  n+8(FP), CX
+	SHLQ  $1, CX
+	MOVOU (SI), X0
+	MOVO  X0, X1
+	MOVQ  $16, AX
+
+i16mmsseloop:
+	CMPQ   AX, CX
+	JAE    i16mmssedone
+	MOVOU  (SI)(AX*1), X2
+	PMINSW X2, X0
+	PMAXSW X2, X1
+	ADDQ   $16, AX
+	JMP    i16mmsseloop
+
+i16mmssedone:
+	FOLDSSE($8, PMINSW, PMAXSW)
+	FOLDSSE($4, PMINSW, PMAXSW)
+	FOLDSSE($2, PMINSW, PMAXSW)
+	MOVQ X0, AX
+	MOVW AX, mn+16(FP)
+	MOVQ X1, AX
+	MOVW AX, mx+18(FP)
+	RET
+
+// func minMaxUint8sSSE41(xs *uint8, n int) (mn uint8, mx uint8)
+TEXT ·minMaxUint8sSSE41(SB), NOSPLIT, $0-18
+	MOVQ  xs+0(FP), SI
+	MOVQ  n+8(FP), CX
+	MOVOU (SI), X0
+	MOVO  X0, X1
+	MOVQ  $16, AX
+
+u8mmsseloop:
+	CMPQ   AX, CX
+	JAE    u8mmssedone
+	MOVOU  (SI)(AX*1), X2
+	PMINUB X2, X0
+	PMAXUB X2, X1
+	ADDQ   $16, AX
+	JMP    u8mmsseloop
+
+u8mmssedone:
+	FOLDSSE($8, PMINUB, PMAXUB)
+	FOLDSSE($4, PMINUB, PMAXUB)
+	FOLDSSE($2, PMINUB, PMAXUB)
+	FOLDSSE($1, PMINUB, PMAXUB)
+	MOVQ X0, AX
+	MOVB AX, mn+16(FP)
+	MOVQ X1, AX
+	MOVB AX, mx+17(FP)
+	RET
+
+// func addSatInt16sSSE41(dst *int16, x *int16, y *int16, n int)
+TEXT ·addSatInt16sSSE41(SB), NOSPLIT, $0-32
+	MOVQ dst+0(FP), DI
+	MOVQ x+8(FP), SI
+	MOVQ y+16(FP), DX
+	MOVQ n+24(FP), CX
+	SHLQ $1, CX
+	XORQ AX, AX
+
+i16addsseloop:
+	CMPQ   AX, CX
+	JAE    i16addssedone
+	MOVOU  (SI)(AX*1), X0
+	MOVOU  (DX)(AX*1), X1
+	PADDSW X1, X0
+	MOVOU  X0, (DI)(AX*1)
+	ADDQ   $16, AX
+	JMP    i16addsseloop
+
+i16addssedone:
+	RET
+
+// func addSatUint8sSSE41(dst *uint8, x *uint8, y *uint8, n int)
+TEXT ·addSatUint8sSSE41(SB), NOSPLIT, $0-32
+	MOVQ dst+0(FP), DI
+	MOVQ x+8(FP), SI
+	MOVQ y+16(FP), DX
+	MOVQ n+24(FP), CX
+	XORQ AX, AX
+
+u8addsseloop:
+	CMPQ    AX, CX
+	JAE     u8addssedone
+	MOVOU   (SI)(AX*1), X0
+	MOVOU   (DX)(AX*1), X1
+	PADDUSB X1, X0
+	MOVOU   X0, (DI)(AX*1)
+	ADDQ    $16, AX
+	JMP     u8addsseloop
+
+u8addssedone:
+	RET
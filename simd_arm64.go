@@ -0,0 +1,112 @@
+//go:build arm64 && !purego
+
+package RUNK
+
+/*
+NEON (ASIMD) is part of every arm64 CPU Go runs on, so there is nothing to detect, the Go kernels are only here
+for the tests.
+*/
+var simdKernels = []simdKernel{kernelNEON, kernelGo}
+
+/*
+The assembly kernels take raw pointers and a count that is a whole, non zero number of blocks, the Go side
+slices off the blocks and finishes the tail. A block is one 16 byte register of output, or 8 lanes for the sums.
+*/
+
+//go:noescape
+func convertFloat32sToInt16sNEON(dst *int16, src *float32, n int)
+
+//go:noescape
+func convertFloat32sToUint8sNEON(dst *uint8, src *float32, n int)
+
+//go:noescape
+func sumFloat32sNEON(xs *float32, n int) float32
+
+//go:noescape
+func dotFloat32sNEON(x *float32, y *float32, n int) float32
+
+//go:noescape
+func minMaxInt16sNEON(xs *int16, n int) (mn int16, mx int16)
+
+//go:noescape
+func minMaxUint8sNEON(xs *uint8, n int) (mn uint8, mx uint8)
+
+//go:noescape
+func addSatInt16sNEON(dst *int16, x *int16, y *int16, n int)
+
+//go:noescape
+func addSatUint8sNEON(dst *uint8, x *uint8, y *uint8, n int)
+
+/*wholeBlocks is n rounded down to a multiple of block, or 0 for the Go kernels*/
+func wholeBlocks(n int, block int) int {
+	if activeKernel != kernelNEON {
+		return 0
+	}
+	return n &^ (block - 1)
+}
+
+func convertFloat32sToInt16s(dst []int16, src []float32) {
+	blocks := wholeBlocks(len(src), 8)
+	if blocks > 0 {
+		convertFloat32sToInt16sNEON(&dst[0], &src[0], blocks)
+	}
+	convertFloat32sToInt16sGo(dst[blocks:], src[blocks:])
+}
+
+func convertFloat32sToUint8s(dst []uint8, src []float32) {
+	blocks := wholeBlocks(len(src), 16)
+	if blocks > 0 {
+		convertFloat32sToUint8sNEON(&dst[0], &src[0], blocks)
+	}
+	convertFloat32sToUint8sGo(dst[blocks:], src[blocks:])
+}
+
+func sumFloat32s(xs []float32) float32 {
+	blocks := wholeBlocks(len(xs), simdLanes)
+	if blocks == 0 {
+		return sumFloat32sGo(xs)
+	}
+	return sumTail(sumFloat32sNEON(&xs[0], blocks), xs[blocks:])
+}
+
+func dotFloat32s(x []float32, y []float32) float32 {
+	blocks := wholeBlocks(len(x), simdLanes)
+	if blocks == 0 {
+		return dotFloat32sGo(x, y)
+	}
+	return dotTail(dotFloat32sNEON(&x[0], &y[0], blocks), x[blocks:], y[blocks:])
+}
+
+func minMaxInt16s(xs []int16) (int16, int16) {
+	blocks := wholeBlocks(len(xs), 8)
+	mn, mx := xs[0], xs[0]
+	if blocks > 0 {
+		mn, mx = minMaxInt16sNEON(&xs[0], blocks)
+	}
+	return minMaxInt16sGo(xs[blocks:], mn, mx)
+}
+
+func minMaxUint8s(xs []uint8) (uint8, uint8) {
+	blocks := wholeBlocks(len(xs), 16)
+	mn, mx := xs[0], xs[0]
+	if blocks > 0 {
+		mn, mx = minMaxUint8sNEON(&xs[0], blocks)
+	}
+	return minMaxUint8sGo(xs[blocks:], mn, mx)
+}
+
+func addSatInt16s(dst []int16, x []int16, y []int16) {
+	blocks := wholeBlocks(len(dst), 8)
+	if blocks > 0 {
+		addSatInt16sNEON(&dst[0], &x[0], &y[0], blocks)
+	}
+	addSatInt16sGo(dst[blocks:], x[blocks:], y[blocks:])
+}
+
+func addSatUint8s(dst []uint8, x []uint8, y []uint8) {
+	blocks := wholeBlocks(len(dst), 16)
+	if blocks > 0 {
+		addSatUint8sNEON(&dst[0], &x[0], &y[0], blocks)
+	}
+	addSatUint8sGo(dst[blocks:], x[blocks:], y[blocks:])
+}
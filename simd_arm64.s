@@ -0,0 +1,174 @@
+//go:build arm64 && !purego
+
+#include "textflag.h"
+
+// The assembler in the Go versions go.mod allows doesn't know the NEON float, saturating and across lanes
+// instructions, those are written out as WORDs with the instruction in the comment. Their registers are fixed.
+
+// func convertFloat32sToInt16sNEON(dst *int16, src *float32, n int)
+TEXT ·convertFloat32sToInt16sNEON(SB), NOSPLIT, $0-24
+	MOVD dst+0(FP), R0
+	MOVD src+8(FP), R1
+	MOVD n+16(FP), R2
+
+i16loop:
+	VLD1.P 32(R1), [V0.S4, V1.S4]
+	WORD   $0x4e21c800            // FCVTAS V0.4S, V0.4S: round half away from zero, saturate, NaN to 0
+	WORD   $0x4e21c821            // FCVTAS V1.4S, V1.4S
+	WORD   $0x0e614800            // SQXTN  V0.4H, V0.4S
+	WORD   $0x4e614820            // SQXTN2 V0.8H, V1.4S
+	VST1.P [V0.H8], 16(R0)
+	SUBS   $8, R2, R2
+	BNE    i16loop
+	RET
+
+// func convertFloat32sToUint8sNEON(dst *uint8, src *float32, n int)
+TEXT ·convertFloat32sToUint8sNEON(SB), NOSPLIT, $0-24
+	MOVD dst+0(FP), R0
+	MOVD src+8(FP), R1
+	MOVD n+16(FP), R2
+
+u8loop:
+	VLD1.P 64(R1), [V0.S4, V1.S4, V2.S4, V3.S4]
+	WORD   $0x4e21c800                          // FCVTAS  V0.4S, V0.4S
+	WORD   $0x4e21c821                          // FCVTAS  V1.4S, V1.4S
+	WORD   $0x4e21c842                          // FCVTAS  V2.4S, V2.4S
+	WORD   $0x4e21c863                          // FCVTAS  V3.4S, V3.4S
+	WORD   $0x2e612800                          // SQXTUN  V0.4H, V0.4S
+	WORD   $0x6e612820                          // SQXTUN2 V0.8H, V1.4S
+	WORD   $0x2e612842                          // SQXTUN  V2.4H, V2.4S
+	WORD   $0x6e612862                          // SQXTUN2 V2.8H, V3.4S
+	WORD   $0x2e214800                          // UQXTN   V0.8B, V0.8H
+	WORD   $0x6e214840                          // UQXTN2  V0.16B, V2.8H
+	VST1.P [V0.B16], 16(R0)
+	SUBS   $16, R2, R2
+	BNE    u8loop
+	RET
+
+// REDUCE8 adds the lanes of V0 (0 to 3) and V1 (4 to 7) into F0 as (a0+a4 + a2+a6) + (a1+a5 + a3+a7)
+#define REDUCE8 \
+	WORD  $0x4e21d400                \ // FADD V0.4S, V0.4S, V1.4S
+	VEXT  $8, V0.B16, V0.B16, V1.B16 \
+	WORD  $0x4e21d400                \ // FADD V0.4S, V0.4S, V1.4S
+	VDUP  V0.S[1], V1.S4             \
+	FADDS F1, F0, F0
+
+// func sumFloat32sNEON(xs *float32, n int) float32
+TEXT ·sumFloat32sNEON(SB), NOSPLIT, $0-20
+	MOVD xs+0(FP), R0
+	MOVD n+8(FP), R1
+	VEOR V0.B16, V0.B16, V0.B16
+	VEOR V1.B16, V1.B16, V1.B16
+
+sumloop:
+	VLD1.P 32(R0), [V2.S4, V3.S4]
+	WORD   $0x4e22d400            // FADD V0.4S, V0.4S, V2.4S
+	WORD   $0x4e23d421            // FADD V1.4S, V1.4S, V3.4S
+	SUBS   $8, R1, R1
+	BNE    sumloop
+	REDUCE8
+	FMOVS  F0, ret+16(FP)
+	RET
+
+// func dotFloat32sNEON(x *float32, y *float32, n int) float32
+TEXT ·dotFloat32sNEON(SB), NOSPLIT, $0-28
+	MOVD x+0(FP), R0
+	MOVD y+8(FP), R1
+	MOVD n+16(FP), R2
+	VEOR V0.B16, V0.B16, V0.B16
+	VEOR V1.B16, V1.B16, V1.B16
+
+dotloop:
+	VLD1.P 32(R0), [V2.S4, V3.S4]
+	VLD1.P 32(R1), [V4.S4, V5.S4]
+	WORD   $0x6e24dc42            // FMUL V2.4S, V2.4S, V4.4S
+	WORD   $0x6e25dc63            // FMUL V3.4S, V3.4S, V5.4S
+	WORD   $0x4e22d400            // FADD V0.4S, V0.4S, V2.4S
+	WORD   $0x4e23d421            // FADD V1.4S, V1.4S, V3.4S
+	SUBS   $8, R2, R2
+	BNE    dotloop
+	REDUCE8
+	FMOVS  F0, ret+24(FP)
+	RET
+
+// func minMaxInt16sNEON(xs *int16, n int) (mn int16, mx int16)
+TEXT ·minMaxInt16sNEON(SB), NOSPLIT, $0-20
+	MOVD   xs+0(FP), R0
+	MOVD   n+8(FP), R1
+	VLD1.P 16(R0), [V0.H8]
+	VMOV   V0.B16, V1.B16
+	SUBS   $8, R1, R1
+	BEQ    i16mmdone
+
+i16mmloop:
+	VLD1.P 16(R0), [V2.H8]
+	WORD   $0x4e626c00     // SMIN V0.8H, V0.8H, V2.8H
+	WORD   $0x4e626421     // SMAX V1.8H, V1.8H, V2.8H
+	SUBS   $8, R1, R1
+	BNE    i16mmloop
+
+i16mmdone:
+	WORD $0x4e71a800       // SMINV H0, V0.8H
+	WORD $0x4e70a821       // SMAXV H1, V1.8H
+	VMOV V0.H[0], R2
+	VMOV V1.H[0], R3
+	MOVH R2, mn+16(FP)
+	MOVH R3, mx+18(FP)
+	RET
+
+// func minMaxUint8sNEON(xs *uint8, n int) (mn uint8, mx uint8)
+TEXT ·minMaxUint8sNEON(SB), NOSPLIT, $0-18
+	MOVD   xs+0(FP), R0
+	MOVD   n+8(FP), R1
+	VLD1.P 16(R0), [V0.B16]
+	VMOV   V0.B16, V1.B16
+	SUBS   $16, R1, R1
+	BEQ    u8mmdone
+
+u8mmloop:
+	VLD1.P 16(R0), [V2.B16]
+	VUMIN  V2.B16, V0.B16, V0.B16
+	VUMAX  V2.B16, V1.B16, V1.B16
+	SUBS   $16, R1, R1
+	BNE    u8mmloop
+
+u8mmdone:
+	WORD $0x6e31a800       // UMINV B0, V0.16B
+	WORD $0x6e30a821       // UMAXV B1, V1.16B
+	VMOV V0.B[0], R2
+	VMOV V1.B[0], R3
+	MOVB R2, mn+16(FP)
+	MOVB R3, mx+17(FP)
+	RET
+
+// func addSatInt16sNEON(dst *int16, x *int16, y *int16, n int)
+TEXT ·addSatInt16sNEON(SB), NOSPLIT, $0-32
+	MOVD dst+0(FP), R0
+	MOVD x+8(FP), R1
+	MOVD y+16(FP), R2
+	MOVD n+24(FP), R3
+
+i16addloop:
+	VLD1.P 16(R1), [V0.H8]
+	VLD1.P 16(R2), [V1.H8]
+	WORD   $0x4e610c00     // SQADD V0.8H, V0.8H, V1.8H
+	VST1.P [V0.H8], 16(R0)
+	SUBS   $8, R3, R3
+	BNE    i16addloop
+	RET
+
+// func addSatUint8sNEON(dst *uint8, x *uint8, y *uint8, n int)
+TEXT ·addSatUint8sNEON(SB), NOSPLIT, $0-32
+	MOVD dst+0(FP), R0
+	MOVD x+8(FP), R1
+	MOVD y+16(FP), R2
+	MOVD n+24(FP), R3
+
+u8addloop:
+	VLD1.P 16(R1), [V0.B16]
+	VLD1.P 16(R2), [V1.B16]
+	WORD   $0x6e210c00     // UQADD V0.16B, V0.16B, V1.16B
+	VST1.P [V0.B16], 16(R0)
+	SUBS   $16, R3, R3
+	BNE    u8addloop
+	RET
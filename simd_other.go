@@ -0,0 +1,37 @@
+//go:build (!amd64 && !arm64) || purego
+
+package RUNK
+
+var simdKernels = []simdKernel{kernelGo}
+
+func convertFloat32sToInt16s(dst []int16, src []float32) {
+	convertFloat32sToInt16sGo(dst, src)
+}
+
+func convertFloat32sToUint8s(dst []uint8, src []float32) {
+	convertFloat32sToUint8sGo(dst, src)
+}
+
+func sumFloat32s(xs []float32) float32 {
+	return sumFloat32sGo(xs)
+}
+
+func dotFloat32s(x []float32, y []float32) float32 {
+	return dotFloat32sGo(x, y)
+}
+
+func minMaxInt16s(xs []int16) (int16, int16) {
+	return minMaxInt16sGo(xs, xs[0], xs[0])
+}
+
+func minMaxUint8s(xs []uint8) (uint8, uint8) {
+	return minMaxUint8sGo(xs, xs[0], xs[0])
+}
+
+func addSatInt16s(dst []int16, x []int16, y []int16) {
+	addSatInt16sGo(dst, x, y)
+}
+
+func addSatUint8s(dst []uint8, x []uint8, y []uint8) {
+	addSatUint8sGo(dst, x, y)
+}
@@ -0,0 +1,199 @@
+package RUNK
+
+import (
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+/*eachKernel runs check with every kernel this CPU can run, the Go one last*/
+func eachKernel(t *testing.T, check func(k simdKernel)) {
+	t.Helper()
+	defer func(k simdKernel) { activeKernel = k }(activeKernel)
+	if simdKernels[len(simdKernels)-1] != kernelGo {
+		t.Fatalf("kernels %v don't end with the Go one", simdKernels)
+	}
+	for _, k := range simdKernels {
+		activeKernel = k
+		check(k)
+	}
+}
+
+/*float32sOf reads the fuzz bytes as float32s, the first byte picks an offset so the slice starts unaligned*/
+func float32sOf(data []byte) []float32 {
+	if len(data) == 0 {
+		return nil
+	}
+	off, data := int(data[0]%4), data[1:]
+	xs := make([]float32, off+len(data)/4)
+	for i := off; i < len(xs); i++ {
+		xs[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[4*(i-off):]))
+	}
+	return xs[off:]
+}
+
+func bytesOfFloat32s(off byte, xs ...float32) []byte {
+	b := []byte{off}
+	for _, x := range xs {
+		b = binary.LittleEndian.AppendUint32(b, math.Float32bits(x))
+	}
+	return b
+}
+
+/*simdFloatSeeds are the awkward values, repeated so every block size and tail length shows up*/
+func simdFloatSeeds() [][]byte {
+	nan, inf := float32(math.NaN()), float32(math.Inf(1))
+	odd := []float32{
+		0.5, -0.5, 1.5, -2.5, 0.49999997, -0.49999997, 32766.5, 32767.5, -32768.5, -32769, 254.5, 255.5, 255.49998,
+		-0.0, 0, nan, inf, -inf, math.MaxFloat32, -math.MaxFloat32, math.SmallestNonzeroFloat32, 8388609, 16777217,
+		1e-40, 2147483520, -2147483648, 1e10, 3e38, -3e38,
+	}
+	var seeds [][]byte
+	for _, n := range []int{0, 1, 7, 8, 9, 15, 16, 17, 31, 32, 33, 64, 100} {
+		xs := make([]float32, n)
+		for i := range xs {
+			xs[i] = odd[(i*7)%len(odd)]
+		}
+		seeds = append(seeds, bytesOfFloat32s(byte(n), xs...))
+	}
+	rng := rand.New(rand.NewSource(16))
+	xs := make([]float32, 257)
+	for i := range xs {
+		xs[i] = float32(rng.NormFloat64() * 1000)
+	}
+	return append(seeds, bytesOfFloat32s(3, xs...))
+}
+
+func FuzzSIMDFloat32s(f *testing.F) {
+	for _, seed := range simdFloatSeeds() {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		xs := float32sOf(data)
+		x, y := xs[:len(xs)/2], xs[len(xs)/2:]
+		wantI16, wantU8 := make([]int16, len(xs)), make([]uint8, len(xs))
+		for i, f := range xs {
+			wantI16[i], wantU8[i] = ConvertNumber[int16](f), ConvertNumber[uint8](f)
+		}
+		wantSum := math.Float32bits(canonicalNaN(sumFloat32sGo(xs)))
+		wantDot := math.Float32bits(canonicalNaN(dotFloat32sGo(x, y[:len(x)])))
+		eachKernel(t, func(k simdKernel) {
+			i16, u8 := make([]int16, len(xs)), make([]uint8, len(xs))
+			ConvertSlice(i16, xs)
+			ConvertSlice(u8, xs)
+			for i := range xs {
+				if i16[i] != wantI16[i] || u8[i] != wantU8[i] {
+					t.Fatalf("kernel %d converts %v to %d and %d, want %d and %d", k, xs[i], i16[i], u8[i], wantI16[i], wantU8[i])
+				}
+			}
+			if got := math.Float32bits(SumFloat32s(xs)); got != wantSum {
+				t.Fatalf("kernel %d sum = %#x, want %#x", k, got, wantSum)
+			}
+			if got := math.Float32bits(DotFloat32s(x, y)); got != wantDot {
+				t.Fatalf("kernel %d dot = %#x, want %#x", k, got, wantDot)
+			}
+		})
+	})
+}
+
+/*int16sOf reads the fuzz bytes as int16s and the uint8s as they are, with the same offset trick as float32sOf*/
+func int16sOf(data []byte) []int16 {
+	if len(data) == 0 {
+		return nil
+	}
+	off, data := int(data[0]%8), data[1:]
+	xs := make([]int16, off+len(data)/2)
+	for i := off; i < len(xs); i++ {
+		xs[i] = int16(binary.LittleEndian.Uint16(data[2*(i-off):]))
+	}
+	return xs[off:]
+}
+
+func simdIntSeeds() [][]byte {
+	var seeds [][]byte
+	rng := rand.New(rand.NewSource(17))
+	for _, n := range []int{0, 1, 2, 15, 16, 17, 31, 32, 33, 63, 64, 65, 130} {
+		b := make([]byte, n)
+		rng.Read(b)
+		seeds = append(seeds, b)
+	}
+	// the extremes in the first block, the last block and the tail
+	edge := make([]byte, 101)
+	edge[3], edge[4], edge[60], edge[61], edge[99], edge[100] = 0xff, 0x7f, 0x00, 0x80, 0xff, 0xff
+	return append(seeds, edge)
+}
+
+func FuzzSIMDInt16s(f *testing.F) {
+	for _, seed := range simdIntSeeds() {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		xs := int16sOf(data)
+		x, y := xs[:len(xs)/2], xs[len(xs)/2:]
+		want := make([]int16, len(x))
+		addSatInt16sGo(want, x, y[:len(x)])
+		eachKernel(t, func(k simdKernel) {
+			mn, mx, err := MinMaxInt16s(xs)
+			if len(xs) == 0 {
+				if err != ErrEmpty {
+					t.Fatalf("kernel %d MinMax of nothing = %v", k, err)
+				}
+			} else if mn != slices.Min(xs) || mx != slices.Max(xs) || err != nil {
+				t.Fatalf("kernel %d MinMax = %d %d %v, want %d %d", k, mn, mx, err, slices.Min(xs), slices.Max(xs))
+			}
+			got := make([]int16, len(x)+1)
+			if n := AddSatInt16s(got, x, y); n != len(x) || !slices.Equal(got[:n], want) {
+				t.Fatalf("kernel %d AddSat = %v, want %v", k, got[:n], want)
+			}
+		})
+	})
+}
+
+func FuzzSIMDUint8s(f *testing.F) {
+	for _, seed := range simdIntSeeds() {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, xs []byte) {
+		x, y := xs[:len(xs)/2], xs[len(xs)/2:]
+		want := make([]uint8, len(x))
+		addSatUint8sGo(want, x, y[:len(x)])
+		eachKernel(t, func(k simdKernel) {
+			mn, mx, err := MinMaxUint8s(xs)
+			if len(xs) == 0 {
+				if err != ErrEmpty {
+					t.Fatalf("kernel %d MinMax of nothing = %v", k, err)
+				}
+			} else if mn != slices.Min(xs) || mx != slices.Max(xs) || err != nil {
+				t.Fatalf("kernel %d MinMax = %d %d %v, want %d %d", k, mn, mx, err, slices.Min(xs), slices.Max(xs))
+			}
+			got := make([]uint8, len(x))
+			if n := AddSatUint8s(got, x, y); n != len(x) || !slices.Equal(got, want) {
+				t.Fatalf("kernel %d AddSat = %v, want %v", k, got, want)
+			}
+			// in place, as audio and image code tends to
+			in := slices.Clone(x)
+			AddSatUint8s(in, in, y)
+			if !slices.Equal(in, want) {
+				t.Fatalf("kernel %d in place AddSat = %v, want %v", k, in, want)
+			}
+		})
+	})
+}
+
+func TestSIMDLaneOrder(t *testing.T) {
+	// 2^24 + 1 + 1 is 2^24 in float32 but 2^24 + (1 + 1) isn't, so this shows the order of the adds
+	xs := []float32{1 << 24, 0, 0, 0, 0, 1, 0, 1, 2}
+	eachKernel(t, func(k simdKernel) {
+		if got := SumFloat32s(xs); got != 1<<24+4 {
+			t.Errorf("kernel %d sum = %v, want the lanes reduced as (a0+a4 + a2+a6) + (a1+a5 + a3+a7) then the tail", k, got)
+		}
+		if got := SumFloat32s([]float32{float32(math.Inf(1)), float32(math.Inf(-1))}); got == got {
+			t.Errorf("kernel %d Inf - Inf = %v", k, got)
+		}
+		if got := DotFloat32s(make([]float32, 16), []float32{1}); got != 0 || math.Signbit(float64(got)) {
+			t.Errorf("kernel %d dot of zeros = %v", k, got)
+		}
+	})
+}
@@ -0,0 +1,982 @@
+package RUNK
+
+import (
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/bits"
+	"slices"
+)
+
+var errSketchEncoding = errors.New("RUNK: invalid sketch encoding")
+
+/*
+TDigest estimates quantiles of a stream of values in a small fixed amount of memory. It is a merging t-digest:
+samples are buffered and then squashed into weighted centroids whose size is capped by the k2 scale function, so
+centroids near the tails stay tiny and the error in rank is smallest exactly where p99 and p999 live. The rank
+error shrinks with q(1-q)/compression but it isn't a hard bound, use DDSketch (or Histogram for integers) when a
+guaranteed relative error is needed.
+Values go in through float64 so any Number works. NaNs are skipped since they have no place in the order.
+The zero value is ready to use with a compression of 100.
+*/
+type TDigest[N Number] struct {
+	compression float64
+	centroids   []centroid
+	buffer      []centroid
+	count       float64
+	min         float64
+	max         float64
+}
+
+type centroid struct {
+	mean   float64
+	weight float64
+}
+
+const defaultCompression = 100
+
+/*NewTDigest makes a TDigest, more compression means more centroids and a more accurate answer*/
+func NewTDigest[N Number](compression float64) *TDigest[N] {
+	return &TDigest[N]{compression: compression}
+}
+
+func (t *TDigest[N]) delta() float64 {
+	if !(t.compression > 0) {
+		return defaultCompression
+	}
+	return t.compression
+}
+
+func (t *TDigest[N]) Add(xs ...N) {
+	for _, x := range xs {
+		t.AddWeighted(x, 1)
+	}
+}
+
+/*AddWeighted adds x as if it had been seen weight times*/
+func (t *TDigest[N]) AddWeighted(x N, weight float64) {
+	f := float64(x)
+	if math.IsNaN(f) || !(weight > 0) {
+		return
+	}
+	t.push(centroid{mean: f, weight: weight})
+}
+
+func (t *TDigest[N]) push(c centroid) {
+	if t.count == 0 || c.mean < t.min {
+		t.min = c.mean
+	}
+	if t.count == 0 || c.mean > t.max {
+		t.max = c.mean
+	}
+	t.count += c.weight
+	t.buffer = append(t.buffer, c)
+	if len(t.buffer) >= int(5*t.delta()) {
+		t.compress()
+	}
+}
+
+/*Count is the total weight that has been added*/
+func (t *TDigest[N]) Count() float64 {
+	return t.count
+}
+
+func (t *TDigest[N]) Min() float64 {
+	return t.min
+}
+
+func (t *TDigest[N]) Max() float64 {
+	return t.max
+}
+
+/*
+k2 maps a quantile onto the scale where every centroid is allowed a width of 1. The log makes the allowed size
+shrink like q(1-q) toward the ends so the extreme tails stay close to single samples. The normaliser keeps the
+number of centroids near the compression whatever the count.
+*/
+func (t *TDigest[N]) k2(q float64) float64 {
+	z := 4*math.Log(max(t.count/t.delta(), 1)) + 24
+	return t.delta() / z * math.Log(q/(1-q))
+}
+
+/*compress sorts everything by mean and merges neighbours for as long as the k2 width allows*/
+func (t *TDigest[N]) compress() {
+	if len(t.buffer) == 0 {
+		return
+	}
+	all := append(t.centroids, t.buffer...)
+	t.buffer = t.buffer[:0]
+	slices.SortFunc(all, func(a, b centroid) int {
+		return cmp.Compare(a.mean, b.mean)
+	})
+	total := t.count
+	out := make([]centroid, 0, len(all))
+	out = append(out, all[0])
+	soFar := 0.0
+	for _, c := range all[1:] {
+		last := &out[len(out)-1]
+		proposed := last.weight + c.weight
+		if t.k2((soFar+proposed)/total)-t.k2(soFar/total) <= 1 {
+			last.mean += (c.mean - last.mean) * c.weight / proposed
+			last.weight = proposed
+			continue
+		}
+		soFar += last.weight
+		out = append(out, c)
+	}
+	t.centroids = out
+}
+
+/*
+Quantile is the estimated value with a fraction q of the weight below it. Between the centroids it interpolates
+linearly from one centre to the next and the ends are pinned to the exact min and max. It is NaN when nothing has
+been added or q is outside [0, 1].
+*/
+func (t *TDigest[N]) Quantile(q float64) float64 {
+	t.compress()
+	if len(t.centroids) == 0 || !(q >= 0 && q <= 1) {
+		return math.NaN()
+	}
+	cs := t.centroids
+	total := t.Count()
+	idx := q * total
+	if idx < cs[0].weight/2 {
+		return t.min + (cs[0].mean-t.min)*idx/(cs[0].weight/2)
+	}
+	cum := 0.0
+	for i := 0; i < len(cs)-1; i++ {
+		left := cum + cs[i].weight/2
+		right := cum + cs[i].weight + cs[i+1].weight/2
+		if idx < right {
+			return cs[i].mean + (cs[i+1].mean-cs[i].mean)*(idx-left)/(right-left)
+		}
+		cum += cs[i].weight
+	}
+	last := cs[len(cs)-1]
+	left := total - last.weight/2
+	return min(t.max, last.mean+(t.max-last.mean)*(idx-left)/(last.weight/2))
+}
+
+/*CDF is the estimated fraction of the weight at or below x, the inverse of Quantile*/
+func (t *TDigest[N]) CDF(x N) float64 {
+	t.compress()
+	f := float64(x)
+	if len(t.centroids) == 0 || math.IsNaN(f) {
+		return math.NaN()
+	}
+	switch {
+	case f < t.min:
+		return 0
+	case f >= t.max:
+		return 1
+	}
+	cs := t.centroids
+	total := t.Count()
+	if f < cs[0].mean {
+		return (f - t.min) / (cs[0].mean - t.min) * cs[0].weight / 2 / total
+	}
+	cum := 0.0
+	for i := 0; i < len(cs)-1; i++ {
+		if f < cs[i+1].mean {
+			span := (cs[i].weight + cs[i+1].weight) / 2
+			return (cum + cs[i].weight/2 + span*(f-cs[i].mean)/(cs[i+1].mean-cs[i].mean)) / total
+		}
+		cum += cs[i].weight
+	}
+	last := cs[len(cs)-1]
+	return (total - last.weight/2 + last.weight/2*(f-last.mean)/(t.max-last.mean)) / total
+}
+
+/*Merge adds everything o has seen into t, o is left as it was*/
+func (t *TDigest[N]) Merge(o *TDigest[N]) {
+	if o.count == 0 {
+		return
+	}
+	if t.count == 0 || o.min < t.min {
+		t.min = o.min
+	}
+	if t.count == 0 || o.max > t.max {
+		t.max = o.max
+	}
+	t.count += o.count
+	t.buffer = append(t.buffer, o.centroids...)
+	t.buffer = append(t.buffer, o.buffer...)
+	t.compress()
+}
+
+/*
+The binary form is a version byte, then the compression, min and max and the number of centroids followed by each
+centroid's mean and weight. The floats are little endian float64 bits and the count is a uvarint.
+*/
+const tdigestVersion = 1
+
+func (t *TDigest[N]) MarshalBinary() ([]byte, error) {
+	t.compress()
+	b := []byte{tdigestVersion}
+	for _, f := range []float64{t.compression, t.min, t.max} {
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(f))
+	}
+	b = binary.AppendUvarint(b, uint64(len(t.centroids)))
+	for _, c := range t.centroids {
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(c.mean))
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(c.weight))
+	}
+	return b, nil
+}
+
+func (t *TDigest[N]) UnmarshalBinary(data []byte) error {
+	if len(data) < 1+3*8 || data[0] != tdigestVersion {
+		return errSketchEncoding
+	}
+	f := func(i int) float64 {
+		return math.Float64frombits(binary.LittleEndian.Uint64(data[i:]))
+	}
+	v := TDigest[N]{compression: f(1), min: f(9), max: f(17)}
+	n, k := binary.Uvarint(data[25:])
+	rest := data[25+max(k, 0):]
+	if k <= 0 || len(rest)%16 != 0 || uint64(len(rest)/16) != n {
+		return errSketchEncoding
+	}
+	v.centroids = make([]centroid, n)
+	for i := range v.centroids {
+		v.centroids[i] = centroid{
+			mean:   math.Float64frombits(binary.LittleEndian.Uint64(rest[16*i:])),
+			weight: math.Float64frombits(binary.LittleEndian.Uint64(rest[16*i+8:])),
+		}
+		if !(v.centroids[i].weight > 0) {
+			return errSketchEncoding
+		}
+		v.count += v.centroids[i].weight
+	}
+	*t = v
+	return nil
+}
+
+/*
+Histogram is an HDR style log-linear histogram. Values are bucketed exactly up to 2*10^digits and past that each
+power of two is split into at least 10^digits equal buckets, so every value that comes out of it is within a
+relative error of 10^-digits of one that went in, however big the values get. Memory only grows with the log of
+the largest value.
+It is meant for integers. Other Numbers are converted with ConvertNumber first so record floats in a unit where
+the fraction doesn't matter (microseconds instead of seconds). Negative values get their own mirrored buckets.
+The zero value is ready to use with 3 significant digits.
+*/
+type Histogram[N Number] struct {
+	digits int
+	pos    []uint64
+	neg    []uint64
+	count  uint64
+	// min and max are kept exactly as sign and magnitude
+	minNeg bool
+	minMag uint64
+	maxNeg bool
+	maxMag uint64
+}
+
+const defaultHistogramDigits = 3
+
+/*NewHistogram makes a Histogram with 1 to 5 significant digits of precision*/
+func NewHistogram[N Number](significantDigits int) *Histogram[N] {
+	return &Histogram[N]{digits: max(1, min(significantDigits, 5))}
+}
+
+/*subBits is log2 of the number of exact buckets, the first power of two that is at least 2*10^digits*/
+func (h *Histogram[N]) subBits() uint {
+	if h.digits == 0 {
+		h.digits = defaultHistogramDigits
+	}
+	return uint(bits.Len64(2*uint64(math.Pow10(h.digits)) - 1))
+}
+
+func (h *Histogram[N]) index(mag uint64) int {
+	s := h.subBits()
+	if mag < 1<<s {
+		return int(mag)
+	}
+	e := uint(bits.Len64(mag)) - s
+	half := uint64(1) << (s - 1)
+	return int(1<<s + uint64(e-1)*half + (mag >> e) - half)
+}
+
+/*bucket is the smallest magnitude in bucket i and how many magnitudes it covers*/
+func (h *Histogram[N]) bucket(i int) (uint64, uint64) {
+	s := h.subBits()
+	if i < 1<<s {
+		return uint64(i), 1
+	}
+	half := uint64(1) << (s - 1)
+	k := uint64(i) - 1<<s
+	e := k/half + 1
+	return (k%half + half) << e, 1 << e
+}
+
+func (h *Histogram[N]) Add(xs ...N) {
+	for _, x := range xs {
+		h.AddCount(x, 1)
+	}
+}
+
+/*AddCount records x count times*/
+func (h *Histogram[N]) AddCount(x N, count uint64) {
+	var neg bool
+	var mag uint64
+	switch {
+	case !isFloat[N]():
+		neg, mag = magnitude(x)
+	case x < 0:
+		neg, mag = magnitude(ConvertNumber[int64](x))
+	default:
+		neg, mag = magnitude(ConvertNumber[uint64](x))
+	}
+	h.record(neg, mag, count)
+}
+
+func (h *Histogram[N]) record(neg bool, mag uint64, count uint64) {
+	if count == 0 {
+		return
+	}
+	counts := &h.pos
+	if neg {
+		counts = &h.neg
+	}
+	i := h.index(mag)
+	if i >= len(*counts) {
+		*counts = append(*counts, make([]uint64, i+1-len(*counts))...)
+	}
+	(*counts)[i] += count
+	if h.count == 0 || lessMagnitude(neg, mag, h.minNeg, h.minMag) {
+		h.minNeg, h.minMag = neg, mag
+	}
+	if h.count == 0 || lessMagnitude(h.maxNeg, h.maxMag, neg, mag) {
+		h.maxNeg, h.maxMag = neg, mag
+	}
+	h.count += count
+}
+
+/*lessMagnitude compares two sign and magnitude values*/
+func lessMagnitude(aneg bool, amag uint64, bneg bool, bmag uint64) bool {
+	switch {
+	case aneg && bneg:
+		return amag > bmag
+	case aneg != bneg:
+		return aneg
+	}
+	return amag < bmag
+}
+
+func (h *Histogram[N]) Count() uint64 {
+	return h.count
+}
+
+/*Min and Max are exact, not bucketed. They are 0 until something has been added.*/
+func (h *Histogram[N]) Min() N {
+	return fromMagnitude[N](h.minNeg, h.minMag)
+}
+
+func (h *Histogram[N]) Max() N {
+	return fromMagnitude[N](h.maxNeg, h.maxMag)
+}
+
+/*
+walk visits the buckets from the most negative to the most positive with the lowest and highest value each one
+covers, as sign and magnitude. It stops when visit returns false.
+*/
+func (h *Histogram[N]) walk(visit func(count uint64, loNeg bool, loMag uint64, hiNeg bool, hiMag uint64) bool) {
+	for i := len(h.neg) - 1; i >= 0; i-- {
+		if h.neg[i] == 0 {
+			continue
+		}
+		lo, width := h.bucket(i)
+		if !visit(h.neg[i], true, lo+width-1, true, lo) {
+			return
+		}
+	}
+	for i, c := range h.pos {
+		if c == 0 {
+			continue
+		}
+		lo, width := h.bucket(i)
+		if !visit(c, false, lo, false, lo+width-1) {
+			return
+		}
+	}
+}
+
+/*
+Quantile is the smallest recorded value (to within the precision) that has at least a fraction q of the count at
+or below it. The middle of the bucket is returned, clamped to the exact Min and Max. It is 0 when nothing has been
+added and NaN (so 0 for integers) when q is outside [0, 1].
+*/
+func (h *Histogram[N]) Quantile(q float64) N {
+	if !(q >= 0 && q <= 1) {
+		return NaN[N]()
+	}
+	if h.count == 0 {
+		return 0
+	}
+	rank := uint64(math.Ceil(q * float64(h.count)))
+	rank = max(1, min(rank, h.count))
+	var resNeg bool
+	var resMag uint64
+	seen := uint64(0)
+	h.walk(func(count uint64, loNeg bool, loMag uint64, hiNeg bool, hiMag uint64) bool {
+		seen += count
+		if seen < rank {
+			return true
+		}
+		lo, hi := min(loMag, hiMag), max(loMag, hiMag)
+		resNeg, resMag = loNeg, lo+(hi-lo)/2
+		return false
+	})
+	if lessMagnitude(resNeg, resMag, h.minNeg, h.minMag) {
+		return h.Min()
+	}
+	if lessMagnitude(h.maxNeg, h.maxMag, resNeg, resMag) {
+		return h.Max()
+	}
+	return fromMagnitude[N](resNeg, resMag)
+}
+
+/*
+CDF is the fraction of the count at or below x. Like HdrHistogram the whole bucket that x falls in is counted, so
+it can be over by at most that one bucket.
+*/
+func (h *Histogram[N]) CDF(x N) float64 {
+	if h.count == 0 || x != x {
+		return math.NaN()
+	}
+	var xneg bool
+	var xmag uint64
+	switch {
+	case !isFloat[N]():
+		xneg, xmag = magnitude(x)
+	case x < 0:
+		xneg, xmag = magnitude(ConvertNumber[int64](x))
+	default:
+		xneg, xmag = magnitude(ConvertNumber[uint64](x))
+	}
+	below := uint64(0)
+	h.walk(func(count uint64, loNeg bool, loMag uint64, hiNeg bool, hiMag uint64) bool {
+		if lessMagnitude(xneg, xmag, loNeg, loMag) {
+			return false
+		}
+		below += count
+		return true
+	})
+	return float64(below) / float64(h.count)
+}
+
+/*
+Merge adds the counts of o into h. If the precisions match the buckets line up and are added straight across,
+otherwise each of o's buckets is recorded again at its middle value.
+*/
+func (h *Histogram[N]) Merge(o *Histogram[N]) {
+	if o.count == 0 {
+		return
+	}
+	h.subBits()
+	if o.subBits() == h.subBits() {
+		if len(h.pos) < len(o.pos) {
+			h.pos = append(h.pos, make([]uint64, len(o.pos)-len(h.pos))...)
+		}
+		if len(h.neg) < len(o.neg) {
+			h.neg = append(h.neg, make([]uint64, len(o.neg)-len(h.neg))...)
+		}
+		for i, c := range o.pos {
+			h.pos[i] += c
+		}
+		for i, c := range o.neg {
+			h.neg[i] += c
+		}
+		if h.count == 0 || lessMagnitude(o.minNeg, o.minMag, h.minNeg, h.minMag) {
+			h.minNeg, h.minMag = o.minNeg, o.minMag
+		}
+		if h.count == 0 || lessMagnitude(h.maxNeg, h.maxMag, o.maxNeg, o.maxMag) {
+			h.maxNeg, h.maxMag = o.maxNeg, o.maxMag
+		}
+		h.count += o.count
+		return
+	}
+	// the middle values would overwrite the exact min and max so they are put back afterwards
+	empty := h.count == 0
+	minNeg, minMag, maxNeg, maxMag := h.minNeg, h.minMag, h.maxNeg, h.maxMag
+	o.walk(func(count uint64, loNeg bool, loMag uint64, hiNeg bool, hiMag uint64) bool {
+		lo, hi := min(loMag, hiMag), max(loMag, hiMag)
+		h.record(loNeg, lo+(hi-lo)/2, count)
+		return true
+	})
+	h.minNeg, h.minMag, h.maxNeg, h.maxMag = o.minNeg, o.minMag, o.maxNeg, o.maxMag
+	if !empty && lessMagnitude(minNeg, minMag, o.minNeg, o.minMag) {
+		h.minNeg, h.minMag = minNeg, minMag
+	}
+	if !empty && lessMagnitude(o.maxNeg, o.maxMag, maxNeg, maxMag) {
+		h.maxNeg, h.maxMag = maxNeg, maxMag
+	}
+}
+
+/*
+The binary form is a version byte, the number of significant digits, the exact min and max as a sign byte and a
+uvarint magnitude each, then the number of non empty buckets and each of them as a zigzag varint index (negative
+for the negative side) followed by a uvarint count.
+*/
+const histogramVersion = 1
+
+func (h *Histogram[N]) MarshalBinary() ([]byte, error) {
+	h.subBits()
+	b := []byte{histogramVersion, byte(h.digits)}
+	for _, v := range []struct {
+		neg bool
+		mag uint64
+	}{{h.minNeg, h.minMag}, {h.maxNeg, h.maxMag}} {
+		sign := byte(0)
+		if v.neg {
+			sign = 1
+		}
+		b = append(b, sign)
+		b = binary.AppendUvarint(b, v.mag)
+	}
+	buckets := 0
+	for _, c := range h.pos {
+		if c != 0 {
+			buckets++
+		}
+	}
+	for _, c := range h.neg {
+		if c != 0 {
+			buckets++
+		}
+	}
+	b = binary.AppendUvarint(b, uint64(buckets))
+	for i, c := range h.neg {
+		if c != 0 {
+			b = binary.AppendVarint(b, -int64(i)-1)
+			b = binary.AppendUvarint(b, c)
+		}
+	}
+	for i, c := range h.pos {
+		if c != 0 {
+			b = binary.AppendVarint(b, int64(i))
+			b = binary.AppendUvarint(b, c)
+		}
+	}
+	return b, nil
+}
+
+func (h *Histogram[N]) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 || data[0] != histogramVersion || data[1] < 1 || data[1] > 5 {
+		return errSketchEncoding
+	}
+	v := Histogram[N]{digits: int(data[1])}
+	rest := data[2:]
+	uvarint := func() (uint64, bool) {
+		x, k := binary.Uvarint(rest)
+		if k <= 0 {
+			return 0, false
+		}
+		rest = rest[k:]
+		return x, true
+	}
+	var ok bool
+	for _, p := range []struct {
+		neg *bool
+		mag *uint64
+	}{{&v.minNeg, &v.minMag}, {&v.maxNeg, &v.maxMag}} {
+		if len(rest) == 0 || rest[0] > 1 {
+			return errSketchEncoding
+		}
+		*p.neg = rest[0] == 1
+		rest = rest[1:]
+		if *p.mag, ok = uvarint(); !ok {
+			return errSketchEncoding
+		}
+	}
+	buckets, ok := uvarint()
+	if !ok {
+		return errSketchEncoding
+	}
+	limit := int64(v.index(math.MaxUint64))
+	for ; buckets > 0; buckets-- {
+		i, k := binary.Varint(rest)
+		if k <= 0 || i >= limit+1 || i < -limit-1 {
+			return errSketchEncoding
+		}
+		rest = rest[k:]
+		c, ok := uvarint()
+		if !ok || c == 0 {
+			return errSketchEncoding
+		}
+		counts := &v.pos
+		if i < 0 {
+			counts, i = &v.neg, -i-1
+		}
+		if int(i) >= len(*counts) {
+			*counts = append(*counts, make([]uint64, int(i)+1-len(*counts))...)
+		}
+		(*counts)[i] += c
+		v.count += c
+	}
+	if len(rest) != 0 {
+		return errSketchEncoding
+	}
+	*h = v
+	return nil
+}
+
+/*
+DDSketch is the quantile sketch for floats with a guaranteed error. Every quantile it returns is within a relative
+error of alpha of the exact sample quantile, the same kind of bound Histogram gives for integers. It is a log
+bucketed histogram: bucket i holds the magnitudes in (γ^(i-1), γ^i] with γ = (1+alpha)/(1-alpha) and answers with
+the point of the bucket that is within alpha of both ends.
+Unlike TDigest the bound holds for any stream and any quantile. The cost is memory that grows with the log of
+max/min over alpha rather than staying fixed, a few hundred buckets per factor of 10^10 at alpha = 0.01.
+Negative values get their own mirrored buckets, exact zeros and infinities are counted on their own and NaNs are
+skipped. Subnormals only have the precision they have, so down there the bound is as loose as the floats are.
+The zero value is ready to use with an alpha of 0.01.
+*/
+type DDSketch[N Number] struct {
+	alpha  float64
+	pos    logBuckets
+	neg    logBuckets
+	zero   uint64
+	posInf uint64
+	negInf uint64
+	count  uint64
+	min    float64
+	max    float64
+}
+
+/*logBuckets is a run of bucket counts starting at bucket index offset*/
+type logBuckets struct {
+	offset int
+	counts []uint64
+}
+
+func (b *logBuckets) add(i int, count uint64) {
+	switch {
+	case len(b.counts) == 0:
+		b.offset = i
+		b.counts = []uint64{0}
+	case i < b.offset:
+		b.counts = append(make([]uint64, b.offset-i), b.counts...)
+		b.offset = i
+	case i-b.offset >= len(b.counts):
+		b.counts = append(b.counts, make([]uint64, i-b.offset+1-len(b.counts))...)
+	}
+	b.counts[i-b.offset] += count
+}
+
+func (b *logBuckets) buckets() int {
+	n := 0
+	for _, c := range b.counts {
+		if c != 0 {
+			n++
+		}
+	}
+	return n
+}
+
+const defaultRelativeAccuracy = 0.01
+
+/*NewDDSketch makes a DDSketch with a relative accuracy between 0 and 1, anything else gets the default of 0.01*/
+func NewDDSketch[N Number](relativeAccuracy float64) *DDSketch[N] {
+	return &DDSketch[N]{alpha: relativeAccuracy}
+}
+
+/*RelativeAccuracy is the alpha that every quantile is guaranteed to be within*/
+func (d *DDSketch[N]) RelativeAccuracy() float64 {
+	if !(d.alpha > 0 && d.alpha < 1) {
+		d.alpha = defaultRelativeAccuracy
+	}
+	return d.alpha
+}
+
+/*lnGamma is the log of the ratio between the ends of a bucket*/
+func (d *DDSketch[N]) lnGamma() float64 {
+	a := d.RelativeAccuracy()
+	return math.Log1p(2 * a / (1 - a))
+}
+
+/*index is the bucket for a positive finite magnitude*/
+func (d *DDSketch[N]) index(mag float64) int {
+	l := math.Log(mag)
+	if mag < 0x1p-1022 {
+		// math.Log is off for subnormals on some platforms, Frexp normalises them first
+		frac, exp := math.Frexp(mag)
+		l = math.Log(frac) + float64(exp)*math.Ln2
+	}
+	return int(math.Ceil(l / d.lnGamma()))
+}
+
+/*
+value is the point of bucket i within alpha of everything in it, 2γ^i/(γ+1). The top and bottom buckets can have
+it past MaxFloat64 or under the smallest subnormal, it is pulled back in which only moves it closer to the values.
+*/
+func (d *DDSketch[N]) value(i int) float64 {
+	a := d.RelativeAccuracy()
+	e := float64(i)*d.lnGamma() + math.Log1p(-a)
+	v := math.Exp(e)
+	if e > 700 {
+		// some platforms' math.Exp gives +Inf a little before it has to, so the top buckets take off 2^64 first
+		v = math.Exp(e-64*math.Ln2) * 0x1p64
+	}
+	return max(math.SmallestNonzeroFloat64, min(v, math.MaxFloat64))
+}
+
+func (d *DDSketch[N]) Add(xs ...N) {
+	for _, x := range xs {
+		d.AddCount(x, 1)
+	}
+}
+
+/*AddCount records x count times*/
+func (d *DDSketch[N]) AddCount(x N, count uint64) {
+	f := float64(x)
+	if math.IsNaN(f) || count == 0 {
+		return
+	}
+	d.record(f, count)
+}
+
+func (d *DDSketch[N]) record(f float64, count uint64) {
+	switch {
+	case f == 0:
+		d.zero += count
+	case math.IsInf(f, 1):
+		d.posInf += count
+	case math.IsInf(f, -1):
+		d.negInf += count
+	case f > 0:
+		d.pos.add(d.index(f), count)
+	default:
+		d.neg.add(d.index(-f), count)
+	}
+	if d.count == 0 || f < d.min {
+		d.min = f
+	}
+	if d.count == 0 || f > d.max {
+		d.max = f
+	}
+	d.count += count
+}
+
+func (d *DDSketch[N]) Count() uint64 {
+	return d.count
+}
+
+/*Min and Max are exact, not bucketed. They are 0 until something has been added.*/
+func (d *DDSketch[N]) Min() float64 {
+	return d.min
+}
+
+func (d *DDSketch[N]) Max() float64 {
+	return d.max
+}
+
+/*
+walk visits the buckets from the most negative to the most positive with the value that stands for each and the
+largest value it covers. It stops when visit returns false.
+*/
+func (d *DDSketch[N]) walk(visit func(count uint64, value float64, top float64) bool) {
+	inf := math.Inf(1)
+	if d.negInf != 0 && !visit(d.negInf, -inf, -inf) {
+		return
+	}
+	for j := len(d.neg.counts) - 1; j >= 0; j-- {
+		if c := d.neg.counts[j]; c != 0 {
+			i := d.neg.offset + j
+			if !visit(c, -d.value(i), -math.Exp(float64(i-1)*d.lnGamma())) {
+				return
+			}
+		}
+	}
+	if d.zero != 0 && !visit(d.zero, 0, 0) {
+		return
+	}
+	for j, c := range d.pos.counts {
+		if c != 0 {
+			i := d.pos.offset + j
+			if !visit(c, d.value(i), math.Exp(float64(i)*d.lnGamma())) {
+				return
+			}
+		}
+	}
+	if d.posInf != 0 {
+		visit(d.posInf, inf, inf)
+	}
+}
+
+/*
+Quantile is the recorded value, to within the relative accuracy, that has at least a fraction q of the count at
+or below it, clamped to the exact Min and Max. It is NaN when nothing has been added or q is outside [0, 1].
+*/
+func (d *DDSketch[N]) Quantile(q float64) float64 {
+	if d.count == 0 || !(q >= 0 && q <= 1) {
+		return math.NaN()
+	}
+	rank := uint64(math.Ceil(q * float64(d.count)))
+	rank = max(1, min(rank, d.count))
+	res, seen := 0.0, uint64(0)
+	d.walk(func(count uint64, value float64, top float64) bool {
+		seen += count
+		res = value
+		return seen < rank
+	})
+	return max(d.min, min(res, d.max))
+}
+
+/*
+CDF is the fraction of the count at or below x. The whole bucket that x falls in is counted like it is for
+Histogram, so it can be over by at most that one bucket.
+*/
+func (d *DDSketch[N]) CDF(x N) float64 {
+	f := float64(x)
+	if d.count == 0 || math.IsNaN(f) {
+		return math.NaN()
+	}
+	if f >= d.max {
+		return 1
+	}
+	// the top of x's own bucket, everything up to it counts
+	limit := f
+	switch {
+	case f > 0 && !math.IsInf(f, 1):
+		limit = math.Exp(float64(d.index(f)) * d.lnGamma())
+	case f < 0 && !math.IsInf(f, -1):
+		limit = -math.Exp(float64(d.index(-f)-1) * d.lnGamma())
+	}
+	below := uint64(0)
+	d.walk(func(count uint64, value float64, top float64) bool {
+		if top > limit {
+			return false
+		}
+		below += count
+		return true
+	})
+	return float64(below) / float64(d.count)
+}
+
+/*
+Merge adds the counts of o into d. If the accuracies match the buckets line up and are added straight across,
+otherwise each of o's buckets is recorded again at its value, which can add o's error on top of d's.
+*/
+func (d *DDSketch[N]) Merge(o *DDSketch[N]) {
+	if o.count == 0 {
+		return
+	}
+	if o.RelativeAccuracy() != d.RelativeAccuracy() {
+		// the bucket values would overwrite the exact min and max so they are put back afterwards
+		lo, hi, empty := d.min, d.max, d.count == 0
+		o.walk(func(count uint64, value float64, top float64) bool {
+			d.record(value, count)
+			return true
+		})
+		d.min, d.max = o.min, o.max
+		if !empty {
+			d.min, d.max = math.Min(lo, o.min), math.Max(hi, o.max)
+		}
+		return
+	}
+	for j, c := range o.pos.counts {
+		if c != 0 {
+			d.pos.add(o.pos.offset+j, c)
+		}
+	}
+	for j, c := range o.neg.counts {
+		if c != 0 {
+			d.neg.add(o.neg.offset+j, c)
+		}
+	}
+	if d.count == 0 || o.min < d.min {
+		d.min = o.min
+	}
+	if d.count == 0 || o.max > d.max {
+		d.max = o.max
+	}
+	d.zero += o.zero
+	d.posInf += o.posInf
+	d.negInf += o.negInf
+	d.count += o.count
+}
+
+/*
+The binary form is a version byte, the relative accuracy, min and max as little endian float64 bits, the zero,
+-Inf and +Inf counts as uvarints, then for the positive and then the negative buckets the number of non empty ones
+and each of them as a varint index followed by a uvarint count.
+*/
+const ddsketchVersion = 1
+
+func (d *DDSketch[N]) MarshalBinary() ([]byte, error) {
+	b := []byte{ddsketchVersion}
+	for _, f := range []float64{d.RelativeAccuracy(), d.min, d.max} {
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(f))
+	}
+	for _, c := range []uint64{d.zero, d.negInf, d.posInf} {
+		b = binary.AppendUvarint(b, c)
+	}
+	for _, side := range []*logBuckets{&d.pos, &d.neg} {
+		b = binary.AppendUvarint(b, uint64(side.buckets()))
+		for j, c := range side.counts {
+			if c != 0 {
+				b = binary.AppendVarint(b, int64(side.offset+j))
+				b = binary.AppendUvarint(b, c)
+			}
+		}
+	}
+	return b, nil
+}
+
+func (d *DDSketch[N]) UnmarshalBinary(data []byte) error {
+	if len(data) < 1+3*8 || data[0] != ddsketchVersion {
+		return errSketchEncoding
+	}
+	f := func(i int) float64 {
+		return math.Float64frombits(binary.LittleEndian.Uint64(data[i:]))
+	}
+	v := DDSketch[N]{alpha: f(1), min: f(9), max: f(17)}
+	if !(v.alpha > 0 && v.alpha < 1) {
+		return errSketchEncoding
+	}
+	rest := data[25:]
+	uvarint := func() (uint64, bool) {
+		x, k := binary.Uvarint(rest)
+		if k <= 0 {
+			return 0, false
+		}
+		rest = rest[k:]
+		return x, true
+	}
+	for _, c := range []*uint64{&v.zero, &v.negInf, &v.posInf} {
+		var ok bool
+		if *c, ok = uvarint(); !ok {
+			return errSketchEncoding
+		}
+		v.count += *c
+	}
+	// an index past this is a magnitude past MaxFloat64 or below the smallest subnormal
+	limit := int64(v.index(math.MaxFloat64)) + 1
+	for _, side := range []*logBuckets{&v.pos, &v.neg} {
+		buckets, ok := uvarint()
+		if !ok {
+			return errSketchEncoding
+		}
+		for ; buckets > 0; buckets-- {
+			i, k := binary.Varint(rest)
+			if k <= 0 || i > limit || i < -2*limit {
+				return errSketchEncoding
+			}
+			rest = rest[k:]
+			c, ok := uvarint()
+			if !ok || c == 0 {
+				return errSketchEncoding
+			}
+			side.add(int(i), c)
+			v.count += c
+		}
+	}
+	if len(rest) != 0 {
+		return errSketchEncoding
+	}
+	*d = v
+	return nil
+}
@@ -0,0 +1,322 @@
+package RUNK
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+/*exactQuantile is the value the sketches aim for, the smallest sample with at least a fraction q at or below it*/
+func exactQuantile(sorted []float64, q float64) float64 {
+	rank := int(math.Ceil(q * float64(len(sorted))))
+	return sorted[max(1, min(rank, len(sorted)))-1]
+}
+
+var sketchQs = []float64{0, 0.001, 0.01, 0.1, 0.25, 0.5, 0.75, 0.9, 0.99, 0.999, 1}
+
+func TestDDSketchRelativeError(t *testing.T) {
+	rng := rand.New(rand.NewSource(11))
+	xs := make([]float64, 20000)
+	for i := range xs {
+		switch i % 10 {
+		case 0:
+			xs[i] = -math.Exp(rng.NormFloat64() * 5)
+		case 1:
+			xs[i] = 0
+		default:
+			xs[i] = math.Exp(rng.NormFloat64() * 10)
+		}
+	}
+	sorted := slices.Clone(xs)
+	slices.Sort(sorted)
+	for _, alpha := range []float64{0.05, 0.01, 0.001} {
+		d := NewDDSketch[float64](alpha)
+		d.Add(xs...)
+		for _, q := range sketchQs {
+			got, want := d.Quantile(q), exactQuantile(sorted, q)
+			if math.Abs(got-want) > alpha*(1+1e-9)*math.Abs(want) {
+				t.Errorf("alpha %v Quantile(%v) = %v, want %v within %v", alpha, q, got, want, alpha)
+			}
+		}
+		if d.Min() != sorted[0] || d.Max() != sorted[len(sorted)-1] || d.Count() != uint64(len(xs)) {
+			t.Errorf("alpha %v min, max, count = %v %v %d", alpha, d.Min(), d.Max(), d.Count())
+		}
+		// the CDF counts the whole bucket so it is never under and only over by that bucket
+		for _, q := range []float64{0.05, 0.3, 0.5, 0.95} {
+			x := exactQuantile(sorted, q)
+			exact := float64(countAtOrBelow(sorted, x)) / float64(len(sorted))
+			hi := float64(countAtOrBelow(sorted, x+3*alpha*math.Abs(x))) / float64(len(sorted))
+			if got := d.CDF(x); got < exact || got > hi {
+				t.Errorf("alpha %v CDF(%v) = %v, want between %v and %v", alpha, x, got, exact, hi)
+			}
+		}
+	}
+}
+
+/*countAtOrBelow is how many of the sorted values are at or below x*/
+func countAtOrBelow(sorted []float64, x float64) int {
+	i, _ := slices.BinarySearchFunc(sorted, x, func(a, b float64) int {
+		if a <= b {
+			return -1
+		}
+		return 1
+	})
+	return i
+}
+
+func TestDDSketchMergeAndEncoding(t *testing.T) {
+	rng := rand.New(rand.NewSource(12))
+	var whole, merged DDSketch[float32]
+	for p := 0; p < 5; p++ {
+		var part DDSketch[float32]
+		for i := 0; i < 1000; i++ {
+			x := float32(rng.ExpFloat64() * 1000 * float64(p+1))
+			whole.Add(x)
+			part.Add(x)
+		}
+		merged.Merge(&part)
+	}
+	merged.Merge(&DDSketch[float32]{})
+	for _, q := range sketchQs {
+		if whole.Quantile(q) != merged.Quantile(q) {
+			t.Errorf("merged Quantile(%v) = %v, want %v", q, merged.Quantile(q), whole.Quantile(q))
+		}
+	}
+	b, err := whole.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var back DDSketch[float32]
+	if err := back.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+	for _, q := range sketchQs {
+		if back.Quantile(q) != whole.Quantile(q) {
+			t.Errorf("decoded Quantile(%v) = %v, want %v", q, back.Quantile(q), whole.Quantile(q))
+		}
+	}
+	if back.Count() != whole.Count() || back.RelativeAccuracy() != 0.01 {
+		t.Errorf("decoded count and accuracy = %d %v", back.Count(), back.RelativeAccuracy())
+	}
+	for _, bad := range [][]byte{nil, b[:10], append(slices.Clone(b), 0), append([]byte{9}, b[1:]...)} {
+		if err := back.UnmarshalBinary(bad); err == nil {
+			t.Errorf("decoded a bad encoding of %d bytes", len(bad))
+		}
+	}
+
+	// different accuracies still merge, with the two errors on top of each other
+	coarse := NewDDSketch[float64](0.05)
+	fine := NewDDSketch[float64](0.001)
+	fine.Add(100, 200, 300)
+	coarse.Add(1)
+	coarse.Merge(fine)
+	if coarse.Count() != 4 || coarse.Min() != 1 || coarse.Max() != 300 {
+		t.Errorf("mixed merge count, min, max = %d %v %v", coarse.Count(), coarse.Min(), coarse.Max())
+	}
+	if got := coarse.Quantile(0.75); math.Abs(got-200) > 200*0.052 {
+		t.Errorf("mixed merge Quantile(0.75) = %v", got)
+	}
+}
+
+func TestDDSketchSpecialValues(t *testing.T) {
+	var d DDSketch[float64]
+	if q := d.Quantile(0.5); q == q {
+		t.Errorf("empty Quantile = %v", q)
+	}
+	if c := d.CDF(1); c == c {
+		t.Errorf("empty CDF = %v", c)
+	}
+	d.Add(math.NaN(), math.Inf(-1), -2, 0, 0, 5, math.Inf(1), math.MaxFloat64, math.SmallestNonzeroFloat64)
+	if d.Count() != 8 {
+		t.Errorf("Count = %d, NaN should be skipped", d.Count())
+	}
+	if !math.IsInf(d.Quantile(0), -1) || !math.IsInf(d.Quantile(1), 1) {
+		t.Errorf("the ends are %v and %v, want the infinities", d.Quantile(0), d.Quantile(1))
+	}
+	if d.Quantile(0.375) != 0 || d.Quantile(0.5) != 0 {
+		t.Errorf("the zeros are lost: %v %v", d.Quantile(0.375), d.Quantile(0.5))
+	}
+	if got := d.Quantile(0.875); got > math.MaxFloat64 || got < math.MaxFloat64*(1-d.RelativeAccuracy()) {
+		t.Errorf("Quantile(0.875) = %v, want MaxFloat64 within the accuracy", got)
+	}
+	// the buckets just under the top are still within the accuracy, math.Exp can't overflow them early
+	for x := math.MaxFloat64; x > math.MaxFloat64/4; x *= 0.995 {
+		var top DDSketch[float64]
+		top.Add(x, x, x, math.MaxFloat64)
+		if got := top.Quantile(0.5); math.Abs(got-x) > x*top.RelativeAccuracy() {
+			t.Fatalf("median of %v three times and MaxFloat64 = %v", x, got)
+		}
+	}
+	if got := d.Quantile(0.625); got <= 0 || got > 1e-322 {
+		t.Errorf("Quantile(0.625) = %v, want the smallest subnormal", got)
+	}
+	if got := d.CDF(0); got != 0.5 {
+		t.Errorf("CDF(0) = %v", got)
+	}
+	if got := d.CDF(math.Inf(-1)); got != 0.125 {
+		t.Errorf("CDF(-Inf) = %v", got)
+	}
+	if q := d.Quantile(1.5); q == q {
+		t.Errorf("Quantile(1.5) = %v", q)
+	}
+	b, _ := d.MarshalBinary()
+	var back DDSketch[float64]
+	if err := back.UnmarshalBinary(b); err != nil || back.Count() != 8 || !math.IsInf(back.Quantile(1), 1) {
+		t.Errorf("special values round trip = %v %v", back.Count(), err)
+	}
+	if NewDDSketch[int](2).RelativeAccuracy() != 0.01 || NewDDSketch[int](math.NaN()).RelativeAccuracy() != 0.01 {
+		t.Error("a bad accuracy should fall back to the default")
+	}
+	var ints DDSketch[int]
+	ints.AddCount(1000, 99)
+	ints.AddCount(-7, 1)
+	if got := ints.Quantile(0.5); math.Abs(got-1000) > 10 {
+		t.Errorf("int Quantile(0.5) = %v", got)
+	}
+	if got := ints.Quantile(0.01); got != -7 {
+		t.Errorf("int Quantile(0.01) = %v", got)
+	}
+}
+
+func TestHistogramRelativeError(t *testing.T) {
+	rng := rand.New(rand.NewSource(13))
+	xs := make([]int64, 20000)
+	for i := range xs {
+		xs[i] = int64(math.Exp(rng.Float64() * 40))
+		if i%7 == 0 {
+			xs[i] = -xs[i]
+		}
+	}
+	xs = append(xs, math.MaxInt64, math.MinInt64)
+	sorted := make([]float64, len(xs))
+	for i, x := range xs {
+		sorted[i] = float64(x)
+	}
+	slices.Sort(sorted)
+	for digits := 1; digits <= 5; digits++ {
+		h := NewHistogram[int64](digits)
+		h.Add(xs...)
+		bound := math.Pow10(-digits)
+		for _, q := range sketchQs {
+			got, want := float64(h.Quantile(q)), exactQuantile(sorted, q)
+			if math.Abs(got-want) > bound*math.Abs(want) {
+				t.Errorf("%d digits Quantile(%v) = %v, want %v", digits, q, got, want)
+			}
+		}
+		if h.Min() != math.MinInt64 || h.Max() != math.MaxInt64 {
+			t.Errorf("%d digits min and max = %d %d", digits, h.Min(), h.Max())
+		}
+	}
+	// small values have their own buckets so they come back exactly
+	var h Histogram[uint16]
+	for i := uint16(0); i < 2000; i++ {
+		h.Add(i)
+	}
+	for _, q := range []float64{0.0005, 0.5, 0.999} {
+		if got := h.Quantile(q); got != uint16(math.Ceil(q*2000))-1 {
+			t.Errorf("exact range Quantile(%v) = %d", q, got)
+		}
+	}
+	if got := h.CDF(999); got != 0.5 {
+		t.Errorf("CDF(999) = %v", got)
+	}
+}
+
+func TestHistogramMergeAndEncoding(t *testing.T) {
+	a, b := NewHistogram[int](2), NewHistogram[int](2)
+	a.Add(1, 5, 1000, -300)
+	b.AddCount(123456, 3)
+	a.Merge(b)
+	if a.Count() != 7 || a.Max() != 123456 || a.Min() != -300 {
+		t.Errorf("merged count, min, max = %d %d %d", a.Count(), a.Min(), a.Max())
+	}
+	c := NewHistogram[int](4)
+	c.Merge(a)
+	if c.Count() != 7 || c.Max() != 123456 || c.Min() != -300 {
+		t.Errorf("cross precision merge = %d %d %d", c.Count(), c.Min(), c.Max())
+	}
+	enc, _ := a.MarshalBinary()
+	var back Histogram[int]
+	if err := back.UnmarshalBinary(enc); err != nil || back.Count() != 7 {
+		t.Fatalf("round trip = %d %v", back.Count(), err)
+	}
+	for _, q := range sketchQs {
+		if back.Quantile(q) != a.Quantile(q) {
+			t.Errorf("decoded Quantile(%v) = %d, want %d", q, back.Quantile(q), a.Quantile(q))
+		}
+	}
+	for _, bad := range [][]byte{nil, {histogramVersion, 9}, enc[:len(enc)-1], append(slices.Clone(enc), 1)} {
+		if err := back.UnmarshalBinary(bad); err == nil {
+			t.Errorf("decoded a bad encoding % x", bad)
+		}
+	}
+	var empty Histogram[float64]
+	if empty.Quantile(0.5) != 0 || empty.CDF(1) == empty.CDF(1) {
+		t.Error("empty histogram answers are wrong")
+	}
+	if q := a.Quantile(-1); q != 0 {
+		t.Errorf("Quantile(-1) = %d, want NaN as 0", q)
+	}
+	var f Histogram[float64]
+	f.Add(2.6, -1e300, math.NaN())
+	if f.Min() != math.MinInt64 || f.Count() != 3 {
+		t.Errorf("float histogram min and count = %v %d", f.Min(), f.Count())
+	}
+}
+
+func TestTDigest(t *testing.T) {
+	rng := rand.New(rand.NewSource(14))
+	xs := make([]float64, 50000)
+	for i := range xs {
+		xs[i] = rng.Float64()
+	}
+	sorted := slices.Clone(xs)
+	slices.Sort(sorted)
+	var whole TDigest[float64]
+	whole.Add(xs...)
+	merged := NewTDigest[float64](100)
+	for p := 0; p < 10; p++ {
+		part := NewTDigest[float64](100)
+		part.Add(xs[p*5000 : (p+1)*5000]...)
+		merged.Merge(part)
+	}
+	for _, td := range []*TDigest[float64]{&whole, merged} {
+		for _, q := range sketchQs {
+			// the data is uniform on [0, 1] so the value is the rank, the tails have to be much tighter
+			got := td.Quantile(q)
+			if limit := 0.002 + 0.05*q*(1-q); math.Abs(got-exactQuantile(sorted, q)) > limit {
+				t.Errorf("Quantile(%v) = %v, want %v within %v", q, got, exactQuantile(sorted, q), limit)
+			}
+			if c := td.CDF(got); math.Abs(c-q) > 0.002+0.05*q*(1-q) {
+				t.Errorf("CDF(Quantile(%v)) = %v", q, c)
+			}
+		}
+		if td.Count() != 50000 || td.Min() != sorted[0] || td.Max() != sorted[len(sorted)-1] {
+			t.Errorf("count, min, max = %v %v %v", td.Count(), td.Min(), td.Max())
+		}
+	}
+	b, _ := whole.MarshalBinary()
+	var back TDigest[float64]
+	if err := back.UnmarshalBinary(b); err != nil || back.Quantile(0.5) != whole.Quantile(0.5) || back.Count() != 50000 {
+		t.Errorf("round trip = %v %v", back.Quantile(0.5), err)
+	}
+	if err := back.UnmarshalBinary(b[:len(b)-3]); err == nil {
+		t.Error("decoded a truncated encoding")
+	}
+	var empty TDigest[int]
+	if q := empty.Quantile(0.5); q == q {
+		t.Errorf("empty Quantile = %v", q)
+	}
+	empty.Add(3)
+	empty.AddWeighted(9, 3)
+	empty.AddWeighted(100, -1)
+	if empty.Count() != 4 || empty.Quantile(0) != 3 || empty.Quantile(1) != 9 || empty.CDF(2) != 0 || empty.CDF(9) != 1 {
+		t.Errorf("small digest = %v %v %v", empty.Count(), empty.Quantile(0), empty.Quantile(1))
+	}
+	var nan TDigest[float64]
+	nan.Add(math.NaN())
+	if nan.Count() != 0 {
+		t.Error("NaN was added")
+	}
+}
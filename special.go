@@ -0,0 +1,496 @@
+package RUNK
+
+import (
+	"math"
+)
+
+/*
+The special functions math doesn't have. Like the other wrappers they do the math in float64 and hand the result
+to ConvertNumber[N], so an integer N gets it rounded and saturated and NaN comes back as 0. The float64 versions
+underneath are unexported and are where the edge cases are dealt with.
+*/
+
+/*Beta is Γ(a)Γ(b)/Γ(a+b). It is NaN where a or b is a pole of Gamma, 0 or a negative integer*/
+func Beta[N Number, M Number](a N, b M) N {
+	return ConvertNumber[N](beta(float64(a), float64(b)))
+}
+
+/*LogBeta is log|B(a, b)|, which stays finite long after Beta has overflowed or underflowed*/
+func LogBeta[N Number, M Number](a N, b M) N {
+	return ConvertNumber[N](logBeta(float64(a), float64(b)))
+}
+
+/*GammaP is the regularized lower incomplete gamma function P(a, x), the CDF of a Gamma(a) distribution at x*/
+func GammaP[N Number, M Number](a N, x M) N {
+	return ConvertNumber[N](gammaP(float64(a), float64(x)))
+}
+
+/*GammaQ is 1 - P(a, x) but worked out directly so the far tail keeps its digits*/
+func GammaQ[N Number, M Number](a N, x M) N {
+	return ConvertNumber[N](gammaQ(float64(a), float64(x)))
+}
+
+/*BetaInc is the regularized incomplete beta function I_x(a, b), the CDF of a Beta(a, b) distribution at x*/
+func BetaInc[N Number, M Number, W Number](a N, b M, x W) N {
+	return ConvertNumber[N](betaInc(float64(a), float64(b), float64(x)))
+}
+
+/*Digamma is ψ(x) = Γ'(x)/Γ(x), NaN at the poles*/
+func Digamma[N Number](num N) N {
+	return ConvertNumber[N](digamma(float64(num)))
+}
+
+/*Trigamma is ψ'(x), +Inf at the poles since it is positive on both sides of them*/
+func Trigamma[N Number](num N) N {
+	return ConvertNumber[N](trigamma(float64(num)))
+}
+
+/*Zeta is the Riemann zeta function for real s, +Inf at s = 1*/
+func Zeta[N Number](s N) N {
+	return ConvertNumber[N](zeta(float64(s)))
+}
+
+/*
+LambertW solves w*e^w = x for w on branch k, converted to an int the same as the order in Jn. Branch 0 is the
+principal one for x >= -1/e and branch -1 is the lower one for -1/e <= x < 0. Anything else is NaN.
+*/
+func LambertW[N Number, M Number](k N, x M) M {
+	return ConvertNumber[M](lambertW(ConvertNumber[int](k), float64(x)))
+}
+
+/*
+Expint is the exponential integral E1(x), the integral of e^-t/t from x to infinity. For negative x it is the
+real part, -Ei(-x), the way MATLAB's expint gives it.
+*/
+func Expint[N Number](num N) N {
+	return ConvertNumber[N](expint(float64(num)))
+}
+
+/*Sinc is the normalized sinc, sin(πx)/(πx) with Sinc(0) = 1 and exact zeros at the other integers*/
+func Sinc[N Number](num N) N {
+	return ConvertNumber[N](sinc(float64(num)))
+}
+
+/*Logit is log(p/(1-p)), the inverse of Expit, NaN outside 0..1 and -Inf and +Inf at the ends*/
+func Logit[N Number](p N) N {
+	return ConvertNumber[N](logit(float64(p)))
+}
+
+/*Expit is the logistic sigmoid 1/(1+e^-x), it never overflows on either side*/
+func Expit[N Number](num N) N {
+	return ConvertNumber[N](expit(float64(num)))
+}
+
+/*nonPositiveInt reports whether x is one of the poles of Gamma*/
+func nonPositiveInt(x float64) bool {
+	return x <= 0 && x == math.Floor(x)
+}
+
+func beta(a float64, b float64) float64 {
+	switch {
+	case math.IsNaN(a) || math.IsNaN(b) || nonPositiveInt(a) || nonPositiveInt(b):
+		return math.NaN()
+	case nonPositiveInt(a + b):
+		// Γ(a+b) has a pole where Γ(a) and Γ(b) don't
+		return 0
+	case a > 0 && b > 0 && a+b < 171:
+		return math.Gamma(a) / math.Gamma(a+b) * math.Gamma(b)
+	}
+	la, sa := math.Lgamma(a)
+	lb, sb := math.Lgamma(b)
+	lab, sab := math.Lgamma(a + b)
+	return float64(sa*sb*sab) * math.Exp(la+lb-lab)
+}
+
+func logBeta(a float64, b float64) float64 {
+	switch {
+	case math.IsNaN(a) || math.IsNaN(b) || nonPositiveInt(a) || nonPositiveInt(b):
+		return math.NaN()
+	case nonPositiveInt(a + b):
+		return math.Inf(-1)
+	}
+	la, _ := math.Lgamma(a)
+	lb, _ := math.Lgamma(b)
+	lab, _ := math.Lgamma(a + b)
+	return la + lb - lab
+}
+
+/*
+gammaInc gives both P(a, x) and Q(a, x), from the series when x < a+1 where it converges fast and from the
+continued fraction otherwise, whichever is computed directly is the accurate one and the other is 1 minus it.
+*/
+func gammaInc(a float64, x float64) (float64, float64) {
+	switch {
+	case math.IsNaN(a) || math.IsNaN(x) || a <= 0 || x < 0:
+		return math.NaN(), math.NaN()
+	case x == 0:
+		return 0, 1
+	case math.IsInf(x, 1):
+		return 1, 0
+	case math.IsInf(a, 1):
+		return 0, 1
+	}
+	lg, _ := math.Lgamma(a)
+	front := math.Exp(a*math.Log(x) - x - lg)
+	if x < a+1 {
+		sum, term := 1/a, 1/a
+		for n := 1.0; n < 100000; n++ {
+			term *= x / (a + n)
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*epsilon {
+				break
+			}
+		}
+		p := sum * front
+		return p, 1 - p
+	}
+	// modified Lentz on the continued fraction for Q
+	const tiny = 0x1p-1000
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for i := 1.0; i < 100000; i++ {
+		an := -i * (i - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < epsilon {
+			break
+		}
+	}
+	q := front * h
+	return 1 - q, q
+}
+
+func gammaP(a float64, x float64) float64 {
+	p, _ := gammaInc(a, x)
+	return p
+}
+
+func gammaQ(a float64, x float64) float64 {
+	_, q := gammaInc(a, x)
+	return q
+}
+
+/*
+betaInc is I_x(a, b) from the continued fraction, which converges fast for x below (a+1)/(a+b+2). Above that it
+uses I_x(a, b) = 1 - I_{1-x}(b, a) to get back there.
+*/
+func betaInc(a float64, b float64, x float64) float64 {
+	switch {
+	case math.IsNaN(a) || math.IsNaN(b) || math.IsNaN(x) || a <= 0 || b <= 0:
+		return math.NaN()
+	case x <= 0:
+		return 0
+	case x >= 1:
+		return 1
+	}
+	if x > (a+1)/(a+b+2) {
+		return 1 - betaInc(b, a, 1-x)
+	}
+	front := math.Exp(a*math.Log(x) + b*math.Log1p(-x) - logBeta(a, b))
+	const tiny = 0x1p-1000
+	c, d := 1.0, 1-(a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+	for m := 1.0; m < 100000; m++ {
+		// the even and odd terms of the fraction come in pairs
+		for _, an := range [2]float64{
+			m * (b - m) * x / ((a + 2*m - 1) * (a + 2*m)),
+			-(a + m) * (a + b + m) * x / ((a + 2*m) * (a + 2*m + 1)),
+		} {
+			d = 1 + an*d
+			if math.Abs(d) < tiny {
+				d = tiny
+			}
+			c = 1 + an/c
+			if math.Abs(c) < tiny {
+				c = tiny
+			}
+			d = 1 / d
+			h *= d * c
+		}
+		if math.Abs(d*c-1) < epsilon {
+			break
+		}
+	}
+	return front * h / a
+}
+
+/*sinPi is sin(πx) with the argument reduced first, so it is exactly 0 at the integers and ±1 at the halves*/
+func sinPi(x float64) float64 {
+	if math.IsNaN(x) || math.IsInf(x, 0) {
+		return math.NaN()
+	}
+	if x == math.Trunc(x) {
+		return math.Copysign(0, x)
+	}
+	r := math.Mod(x, 2)
+	switch {
+	case r > 1:
+		r -= 2
+	case r < -1:
+		r += 2
+	}
+	switch {
+	case r > 0.5:
+		r = 1 - r
+	case r < -0.5:
+		r = -1 - r
+	}
+	return math.Sin(math.Pi * r)
+}
+
+/*trigamma is pushed up with ψ'(x) = ψ'(x+1) + 1/x² like digamma and reflected for negative x*/
+func trigamma(x float64) float64 {
+	switch {
+	case math.IsNaN(x) || math.IsInf(x, -1):
+		return math.NaN()
+	case math.IsInf(x, 1):
+		return 0
+	case nonPositiveInt(x):
+		return math.Inf(1)
+	case x < 0:
+		s := sinPi(x)
+		return math.Pi*math.Pi/(s*s) - trigamma(1-x)
+	}
+	r := 0.0
+	for x < 16 {
+		r += 1 / (x * x)
+		x++
+	}
+	f := 1 / (x * x)
+	return r + 1/x + f/2 + f/x*(1.0/6-f*(1.0/30-f*(1.0/42-f*(1.0/30-f*(5.0/66-f*691/2730)))))
+}
+
+/*B_2k/(2k)! for the Euler-Maclaurin tail of zeta*/
+var bernoulliOverFactorial = [...]float64{
+	1.0 / 6 / 2,
+	-1.0 / 30 / 24,
+	1.0 / 42 / 720,
+	-1.0 / 30 / 40320,
+	5.0 / 66 / 3628800,
+	-691.0 / 2730 / 479001600,
+	7.0 / 6 / 87178291200,
+	-3617.0 / 510 / 20922789888000,
+	43867.0 / 798 / 6402373705728000,
+	-174611.0 / 330 / 2432902008176640000,
+}
+
+/*
+zeta sums the first terms directly and finishes with the Euler-Maclaurin tail, which is good for every s >= 0.
+Negative s goes through the functional equation in logs since Γ(1-s) overflows long before the answer does.
+*/
+func zeta(s float64) float64 {
+	switch {
+	case math.IsNaN(s) || math.IsInf(s, -1):
+		return math.NaN()
+	case s == 1:
+		return math.Inf(1)
+	case math.IsInf(s, 1) || s > 64:
+		// 2^-64 is already under the rounding of 1
+		return 1
+	case s < 0:
+		if s == math.Floor(s) && math.Mod(s, 2) == 0 {
+			// the trivial zeros
+			return 0
+		}
+		sin := sinPi(s / 2)
+		lg, _ := math.Lgamma(1 - s)
+		z := zeta(1 - s)
+		return math.Copysign(math.Exp(s*math.Ln2+(s-1)*math.Log(math.Pi)+math.Log(math.Abs(sin))+lg+math.Log(z)), sin)
+	}
+	const n = 16
+	var sum float64
+	for k := n - 1; k >= 1; k-- {
+		sum += math.Pow(float64(k), -s)
+	}
+	sum += math.Pow(n, 1-s)/(s-1) + math.Pow(n, -s)/2
+	t := s * math.Pow(n, -s-1)
+	for k, b := range bernoulliOverFactorial {
+		term := b * t
+		sum += term
+		if math.Abs(term) < math.Abs(sum)*epsilon {
+			break
+		}
+		j := float64(2*k + 2)
+		t *= (s + j - 1) * (s + j) / (n * n)
+	}
+	return sum
+}
+
+/*
+lambertW starts from the series at the branch point or the log asymptotics and polishes with Halley's method on
+w*e^w = x, switching to Newton's method on w + log|w| = log|x| where e^w would overflow or underflow.
+*/
+func lambertW(k int, x float64) float64 {
+	const branch = -1 / math.E
+	switch {
+	case math.IsNaN(x) || (k != 0 && k != -1) || x < branch:
+		return math.NaN()
+	case x == branch:
+		return -1
+	case k == 0 && (x == 0 || math.IsInf(x, 1)):
+		return x
+	case k == -1 && x == 0:
+		return math.Inf(-1)
+	case k == -1 && x > 0:
+		return math.NaN()
+	}
+	var w float64
+	p := math.Sqrt(2 * (math.E*x + 1))
+	if k == -1 {
+		p = -p
+	}
+	switch {
+	case math.Abs(p) < 1e-3:
+		// close enough to the branch point that the series is as good as it gets
+		return -1 + p*(1+p*(-1.0/3+p*(11.0/72+p*(-43.0/540+p*(769.0/17280)))))
+	case x < -0.25:
+		w = -1 + p*(1+p*(-1.0/3+p*(11.0/72)))
+	case k == 0 && x <= math.E:
+		w = math.Log1p(x)
+	default:
+		l1 := math.Log(math.Abs(x))
+		l2 := math.Log(math.Abs(l1))
+		w = l1 - l2 + l2/l1
+	}
+	if k == -1 || x > math.E {
+		lx := math.Log(math.Abs(x))
+		for i := 0; i < 100; i++ {
+			step := (w + math.Log(math.Abs(w)) - lx) / (1 + 1/w)
+			w -= step
+			if math.Abs(step) <= 2*epsilon*math.Abs(w) {
+				break
+			}
+		}
+		return w
+	}
+	for i := 0; i < 100; i++ {
+		e := math.Exp(w)
+		f := w*e - x
+		step := f / (e*(w+1) - (w+2)*f/(2*w+2))
+		w -= step
+		if math.Abs(step) <= 2*epsilon*(1+math.Abs(w)) {
+			break
+		}
+	}
+	return w
+}
+
+/*eulerGamma is the Euler-Mascheroni constant γ*/
+const eulerGamma = 0.57721566490153286060651209008240243
+
+/*expint is E1 from its series for small x and a continued fraction past 1, negative x goes to Ei*/
+func expint(x float64) float64 {
+	switch {
+	case math.IsNaN(x):
+		return math.NaN()
+	case x == 0:
+		return math.Inf(1)
+	case math.IsInf(x, 1):
+		return 0
+	case x < 0:
+		return -ei(-x)
+	case x <= 1:
+		sum, term := 0.0, 1.0
+		for k := 1.0; k < 100; k++ {
+			term *= -x / k
+			sum += term / k
+			if math.Abs(term/k) < math.Abs(sum)*epsilon {
+				break
+			}
+		}
+		return -eulerGamma - math.Log(x) - sum
+	}
+	const tiny = 0x1p-1000
+	b := x + 1
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for i := 1.0; i < 1000; i++ {
+		an := -i * i
+		b += 2
+		d = 1 / (an*d + b)
+		c = b + an/c
+		delta := c * d
+		h *= delta
+		if math.Abs(delta-1) < epsilon {
+			break
+		}
+	}
+	return h * math.Exp(-x)
+}
+
+/*ei is the exponential integral Ei(x) for x > 0, the series is all positive terms so it is fine up to where the asymptotic series takes over*/
+func ei(x float64) float64 {
+	if math.IsInf(x, 1) {
+		return x
+	}
+	if x <= 40 {
+		sum, term := 0.0, 1.0
+		for k := 1.0; k < 1000; k++ {
+			term *= x / k
+			sum += term / k
+			if term/k < sum*epsilon {
+				break
+			}
+		}
+		return eulerGamma + math.Log(x) + sum
+	}
+	sum, term := 1.0, 1.0
+	for k := 1.0; k < 40; k++ {
+		term *= k / x
+		sum += term
+		if term < sum*epsilon {
+			break
+		}
+	}
+	if x > 700 {
+		// e^x overflows a little before the answer does
+		return math.Exp(x-math.Log(x)) * sum
+	}
+	return math.Exp(x) / x * sum
+}
+
+func sinc(x float64) float64 {
+	switch {
+	case x == 0:
+		return 1
+	case math.IsInf(x, 0):
+		return 0
+	}
+	return sinPi(x) / (math.Pi * x)
+}
+
+func logit(p float64) float64 {
+	switch {
+	case math.IsNaN(p) || p < 0 || p > 1:
+		return math.NaN()
+	case p >= 0.25 && p <= 0.75:
+		// p/(1-p) is near 1 here so log1p keeps the digits
+		return math.Log1p((2*p - 1) / (1 - p))
+	}
+	return math.Log(p) - math.Log1p(-p)
+}
+
+func expit(x float64) float64 {
+	if x >= 0 {
+		return 1 / (1 + math.Exp(-x))
+	}
+	e := math.Exp(x)
+	return e / (1 + e)
+}
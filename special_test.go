@@ -0,0 +1,215 @@
+package RUNK
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+/*relClose is closeTo measured against |b| alone, for values far below 1 where the digits are what matter*/
+func relClose(a float64, b float64, rel float64) bool {
+	return a == b || math.Abs(a-b) <= rel*math.Abs(b)
+}
+
+func TestSpecialValues(t *testing.T) {
+	inf, nan := math.Inf(1), math.NaN()
+	tests := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"Beta(2, 3)", Beta(2.0, 3), 1.0 / 12},
+		{"Beta(1/2, 1/2)", Beta(0.5, 0.5), math.Pi},
+		{"Beta(-1.5, 2)", Beta(-1.5, 2), 1 / (-1.5 * -0.5)},
+		{"Beta at a pole", Beta(-1.0, 2), nan},
+		{"Beta where a+b is a pole", Beta(1.5, -1.5), 0},
+		{"Beta(NaN, 1)", Beta(nan, 1), nan},
+		{"LogBeta(1000, 1000)", LogBeta(1000.0, 1000), 2*lgammaOf(1000) - lgammaOf(2000)},
+		{"LogBeta where a+b is a pole", LogBeta(0.5, -0.5), -inf},
+		{"GammaP(1, 2)", GammaP(1.0, 2), -math.Expm1(-2)},
+		{"GammaQ(1, 700)", GammaQ(1.0, 700), math.Exp(-700)},
+		{"GammaP(1/2, 3)", GammaP(0.5, 3), math.Erf(math.Sqrt(3))},
+		{"GammaQ(1/2, 30)", GammaQ(0.5, 30), math.Erfc(math.Sqrt(30))},
+		{"GammaP(3, 0)", GammaP(3.0, 0), 0},
+		{"GammaQ(3, Inf)", GammaQ(3.0, inf), 0},
+		{"GammaP(0, 1)", GammaP(0.0, 1), nan},
+		{"GammaP(1, -1)", GammaP(1.0, -1), nan},
+		{"BetaInc(2.5, 1, 0.3)", BetaInc(2.5, 1, 0.3), math.Pow(0.3, 2.5)},
+		{"BetaInc(1, 4, 0.2)", BetaInc(1.0, 4, 0.2), 1 - math.Pow(0.8, 4)},
+		{"BetaInc(7, 7, 1/2)", BetaInc(7.0, 7, 0.5), 0.5},
+		{"BetaInc at 0", BetaInc(2.0, 3, 0), 0},
+		{"BetaInc past 1", BetaInc(2.0, 3, 1.5), 1},
+		{"BetaInc(0, 1, 1/2)", BetaInc(0.0, 1, 0.5), nan},
+		{"Digamma(1)", Digamma(1.0), -eulerGamma},
+		{"Digamma(1/2)", Digamma(0.5), -eulerGamma - 2*math.Ln2},
+		{"Digamma(-3/2)", Digamma(-1.5), 0.03648997397857652 + 2.0/3},
+		{"Digamma(0)", Digamma(0.0), nan},
+		{"Digamma(Inf)", Digamma(inf), inf},
+		{"Trigamma(1)", Trigamma(1.0), math.Pi * math.Pi / 6},
+		{"Trigamma(1/2)", Trigamma(0.5), math.Pi * math.Pi / 2},
+		{"Trigamma(-2)", Trigamma(-2.0), inf},
+		{"Trigamma(Inf)", Trigamma(inf), 0},
+		{"Zeta(2)", Zeta(2.0), math.Pi * math.Pi / 6},
+		{"Zeta(4)", Zeta(4.0), math.Pow(math.Pi, 4) / 90},
+		{"Zeta(3)", Zeta(3.0), 1.2020569031595942},
+		{"Zeta(1/2)", Zeta(0.5), -1.4603545088095868},
+		{"Zeta(0)", Zeta(0.0), -0.5},
+		{"Zeta(-1)", Zeta(-1.0), -1.0 / 12},
+		{"Zeta(-3)", Zeta(-3.0), 1.0 / 120},
+		{"Zeta(-13)", Zeta(-13.0), -1.0 / 12},
+		{"Zeta(-2)", Zeta(-2.0), 0},
+		{"Zeta(1)", Zeta(1.0), inf},
+		{"Zeta(100)", Zeta(100.0), 1},
+		{"W0(e)", LambertW(0, math.E), 1},
+		{"W0(1)", LambertW(0, 1.0), 0.5671432904097838},
+		{"W0(-1/e)", LambertW(0, -1/math.E), -1},
+		{"W-1(-1/e)", LambertW(-1, -1/math.E), -1},
+		{"W-1(-0.1)", LambertW(-1, -0.1), -3.577152063957297},
+		{"W0(0)", LambertW(0, 0.0), 0},
+		{"W-1(0)", LambertW(-1, 0.0), -inf},
+		{"W-1(1)", LambertW(-1, 1.0), nan},
+		{"W2(1)", LambertW(2, 1.0), nan},
+		{"W0(-1)", LambertW(0, -1.0), nan},
+		{"E1(1)", Expint(1.0), 0.21938393439552027},
+		{"E1(-1)", Expint(-1.0), -1.8951178163559367},
+		{"E1(0)", Expint(0.0), inf},
+		{"E1(Inf)", Expint(inf), 0},
+		{"E1(-Inf)", Expint(-inf), -inf},
+		{"Sinc(0)", Sinc(0.0), 1},
+		{"Sinc(1/2)", Sinc(0.5), 2 / math.Pi},
+		{"Sinc(-3)", Sinc(-3.0), 0},
+		{"Sinc(Inf)", Sinc(inf), 0},
+		{"Logit(1/2)", Logit(0.5), 0},
+		{"Logit(0)", Logit(0.0), -inf},
+		{"Logit(1)", Logit(1.0), inf},
+		{"Logit(1.1)", Logit(1.1), nan},
+		{"Expit(0)", Expit(0.0), 0.5},
+		{"Expit(-1000)", Expit(-1000.0), 0},
+		{"Expit(1000)", Expit(1000.0), 1},
+		{"Expit(-Inf)", Expit(-inf), 0},
+		{"Expit(NaN)", Expit(nan), nan},
+	}
+	for _, tt := range tests {
+		if !relClose(tt.got, tt.want, 1e-13) && !(tt.got != tt.got && tt.want != tt.want) {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+	if Sinc(7.0) != 0 || Sinc(-7.0) != 0 || Sinc(1e15+1) != 0 {
+		t.Error("Sinc should be exactly 0 at the integers")
+	}
+	if Expit(-745.0) <= 0 {
+		t.Error("Expit(-745) should still be above 0")
+	}
+}
+
+/*lgammaOf is math.Lgamma without the sign*/
+func lgammaOf(x float64) float64 {
+	l, _ := math.Lgamma(x)
+	return l
+}
+
+func TestSpecialIdentities(t *testing.T) {
+	rng := rand.New(rand.NewSource(29))
+	for trial := 0; trial < 2000; trial++ {
+		a, b := rng.ExpFloat64()*10+0.05, rng.ExpFloat64()*10+0.05
+		x := rng.Float64()
+		if p, q := GammaP(a, x*3*a), GammaQ(a, x*3*a); math.Abs(p+q-1) > 1e-14 {
+			t.Fatalf("P + Q at %v, %v = %v", a, x*3*a, p+q)
+		}
+		if i, j := BetaInc(a, b, x), BetaInc(b, a, 1-x); math.Abs(i+j-1) > 1e-13 {
+			t.Fatalf("I_x(%v, %v) + I_1-x(%v, %v) = %v", a, b, b, a, i+j)
+		}
+		// the Beta CDF steps up by x^a (1-x)^b / (a B(a, b)) from a to a+1
+		if step := BetaInc(a, b, x) - BetaInc(a+1, b, x); !closeTo(step, math.Exp(a*math.Log(x)+b*math.Log1p(-x)-LogBeta(a, b))/a, 1e-12) {
+			t.Fatalf("BetaInc recurrence at %v, %v, %v", a, b, x)
+		}
+		if !relClose(Beta(a, b), math.Exp(LogBeta(a, b)), 1e-12) {
+			t.Fatalf("Beta(%v, %v) = %v against exp LogBeta %v", a, b, Beta(a, b), math.Exp(LogBeta(a, b)))
+		}
+
+		// the recurrences also cover the reflection for negative x
+		y := rng.Float64()*40 - 20
+		if y == math.Floor(y) {
+			continue
+		}
+		if d := Digamma(y+1) - Digamma(y) - 1/y; !closeTo(d, 0, 1e-11*math.Max(1, math.Abs(Digamma(y)))) {
+			t.Fatalf("ψ(%v+1) - ψ(%v) - 1/x = %v", y, y, d)
+		}
+		if d := Trigamma(y) - Trigamma(y+1) - 1/(y*y); !closeTo(d, 0, 1e-11*Trigamma(y)) {
+			t.Fatalf("ψ'(%v) - ψ'(%v+1) - 1/x² = %v", y, y, d)
+		}
+	}
+
+	// W is the inverse of w e^w on both branches, including where e^w overflows
+	for _, x := range []float64{-0.36787944117144, -0.3, -0.1, -1e-5, -1e-300, 1e-10, 0.5, 3, 100, 1e10, 1e300, math.MaxFloat64} {
+		for _, k := range []int{0, -1} {
+			w := LambertW(k, x)
+			if k == -1 && x > 0 {
+				continue
+			}
+			if (k == 0 && w < -1) || (k == -1 && w > -1) {
+				t.Errorf("W%d(%v) = %v is on the wrong branch", k, x, w)
+			}
+			back := w * math.Exp(w)
+			if math.IsInf(back, 0) {
+				back = math.Exp(w + math.Log(w))
+			}
+			if !relClose(back, x, 1e-12) {
+				t.Errorf("W%d(%v) = %v gives back %v", k, x, w, back)
+			}
+		}
+	}
+
+	// E1 is the integral it is defined as, and Ei has e^x/x for its slope on both sides of the switch at 40
+	for _, x := range []float64{0.01, 0.5, 1, 2, 10, 50} {
+		want, _, err := GaussKronrod(func(t float64) float64 { return math.Exp(-t) / t }, x, math.Inf(1), Tolerance{Rel: 1e-13})
+		if err != nil || !relClose(Expint(x), want, 1e-12) {
+			t.Errorf("E1(%v) = %v, the integral gives %v", x, Expint(x), want)
+		}
+	}
+	for _, x := range []float64{5, 30, 39.99, 40.01, 60, 699.9, 700.1} {
+		const h = 1e-4
+		slope := (-Expint(-(x + h)) + Expint(-(x - h))) / (2 * h)
+		if !relClose(slope, math.Exp(x)/x, 1e-7) {
+			t.Errorf("Ei'(%v) = %v, want %v", x, slope, math.Exp(x)/x)
+		}
+	}
+
+	// Expit and Logit undo each other, and for tiny p Logit keeps the digits log(p) has
+	for _, p := range []float64{1e-300, 1e-10, 0.1, 0.25, 0.3, 0.5, 0.7, 0.9, 1 - 1e-10} {
+		if back := Expit(Logit(p)); !relClose(back, p, 1e-12) {
+			t.Errorf("Expit(Logit(%v)) = %v", p, back)
+		}
+	}
+	if !relClose(Logit(1e-300), math.Log(1e-300), 1e-15) {
+		t.Errorf("Logit(1e-300) = %v", Logit(1e-300))
+	}
+}
+
+func TestSpecialIntegers(t *testing.T) {
+	// integer N gets the float64 answer rounded and saturated, NaN becomes 0
+	if got := Beta[int](1, 1); got != 1 {
+		t.Errorf("Beta[int](1, 1) = %d", got)
+	}
+	if got := Digamma[int](100); got != 5 {
+		t.Errorf("Digamma[int](100) = %d", got)
+	}
+	if got := Digamma[int](0); got != 0 {
+		t.Errorf("Digamma[int](0) = %d, want NaN as 0", got)
+	}
+	if got := Trigamma[int8](0); got != math.MaxInt8 {
+		t.Errorf("Trigamma[int8](0) = %d, want +Inf saturated", got)
+	}
+	if got := Expint[int16](-50); got != math.MinInt16 {
+		t.Errorf("Expint[int16](-50) = %d", got)
+	}
+	if got := LambertW(0, 22026); got != 8 {
+		t.Errorf("LambertW into an int = %d", got)
+	}
+	if got := LambertW(-1.0, 0.1); got == got {
+		t.Errorf("W-1 past 0 = %v", got)
+	}
+	if got := Zeta[float32](2); Abs(got-float32(math.Pi*math.Pi/6)) > 1e-6 {
+		t.Errorf("float32 Zeta = %v", got)
+	}
+}
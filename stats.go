@@ -0,0 +1,305 @@
+package RUNK
+
+import (
+	"math"
+	"slices"
+)
+
+/*
+The statistics functions work on a slice of any Number. Integer inputs are summed exactly (in an Int128, so even
+a slice of MaxInt64s can't overflow) and float inputs are summed in float64 with Neumaier's compensation so the
+rounding error doesn't grow with the length of the slice.
+Everything that isn't a sum or a mode comes back as a float64 which can go through ConvertNumber if
+another type is wanted. Empty input gives NaN, the same as 0/0, and NaN in the input comes out as NaN.
+*/
+
+/*Sum adds up nums and converts the total to N, saturating like ConvertNumber if it doesn't fit*/
+func Sum[N Number](nums []N) N {
+	if isFloat[N]() {
+		return ConvertNumber[N](compensatedSum[float64](nums))
+	}
+	return Int128To[N](exactSum(nums))
+}
+
+/*
+SumPairwise adds floats by splitting the slice in half until the pieces are small and adding those up the naive
+way. The error grows with log(n) instead of n and it is faster than Sum's compensation. Integers are exact either
+way.
+*/
+func SumPairwise[N Number](nums []N) N {
+	if isFloat[N]() {
+		return ConvertNumber[N](pairwiseSum(nums))
+	}
+	return Int128To[N](exactSum(nums))
+}
+
+/*
+SumAs accumulates in the type Acc, so SumAs[int32](int8s) can go past 127 and SumAs[float64](float32s) keeps the
+extra precision. Each value is converted to Acc first. Integer totals saturate at the edges of Acc at the end
+rather than wrapping partway through.
+*/
+func SumAs[Acc Number, N Number](nums []N) Acc {
+	if isFloat[Acc]() {
+		return compensatedSum[Acc](nums)
+	}
+	var total Int128
+	for _, n := range nums {
+		total = total.Add(Int128From(ConvertNumber[Acc](n)))
+	}
+	return Int128To[Acc](total)
+}
+
+func exactSum[N Number](nums []N) Int128 {
+	var total Int128
+	for _, n := range nums {
+		total = total.Add(Int128From(n))
+	}
+	return total
+}
+
+/*compensatedSum is the Kahan-Neumaier sum done in A*/
+func compensatedSum[A Number, N Number](nums []N) A {
+	var sum, c A
+	for _, n := range nums {
+		x := ConvertNumber[A](n)
+		t := sum + x
+		if Abs(sum) >= Abs(x) {
+			c += (sum - t) + x
+		} else {
+			c += (x - t) + sum
+		}
+		sum = t
+	}
+	// once the sum is Inf or NaN the compensation is garbage
+	if IsNaN(sum) || IsInf(sum, 0) {
+		return sum
+	}
+	return sum + c
+}
+
+func pairwiseSum[N Number](nums []N) float64 {
+	if len(nums) <= 128 {
+		sum := 0.0
+		for _, n := range nums {
+			sum += float64(n)
+		}
+		return sum
+	}
+	half := len(nums) / 2
+	return pairwiseSum(nums[:half]) + pairwiseSum(nums[half:])
+}
+
+/*floatSum is the float64 sum used by the other statistics*/
+func floatSum[N Number](nums []N) float64 {
+	if isFloat[N]() {
+		return compensatedSum[float64](nums)
+	}
+	return Int128To[float64](exactSum(nums))
+}
+
+func Mean[N Number](nums []N) float64 {
+	return floatSum(nums) / float64(len(nums))
+}
+
+/*
+sumSquares is the sum of the squared differences from the mean. It uses the corrected two pass formula, the second
+term is what the rounding of the mean left behind and is tiny unless the mean was way off.
+*/
+func sumSquares[N Number](nums []N) float64 {
+	m := Mean(nums)
+	dev := make([]float64, len(nums))
+	sq := make([]float64, len(nums))
+	for i, n := range nums {
+		dev[i] = float64(n) - m
+		sq[i] = dev[i] * dev[i]
+	}
+	d := compensatedSum[float64](dev)
+	return compensatedSum[float64](sq) - d*d/float64(len(nums))
+}
+
+/*Variance is the population variance, the mean squared distance from the mean*/
+func Variance[N Number](nums []N) float64 {
+	return sumSquares(nums) / float64(len(nums))
+}
+
+/*SampleVariance divides by n-1 instead of n (Bessel's correction), it is NaN for fewer than 2 values*/
+func SampleVariance[N Number](nums []N) float64 {
+	if len(nums) < 2 {
+		return math.NaN()
+	}
+	return sumSquares(nums) / float64(len(nums)-1)
+}
+
+func StdDev[N Number](nums []N) float64 {
+	return math.Sqrt(Variance(nums))
+}
+
+func SampleStdDev[N Number](nums []N) float64 {
+	return math.Sqrt(SampleVariance(nums))
+}
+
+/*sortedCopy sorts a copy of nums, NaNs end up at the front*/
+func sortedCopy[N Number](nums []N) []N {
+	s := slices.Clone(nums)
+	slices.Sort(s)
+	return s
+}
+
+func hasNaN[N Number](nums []N) bool {
+	for _, n := range nums {
+		if n != n {
+			return true
+		}
+	}
+	return false
+}
+
+/*Median is the middle value, or halfway between the two middle values when there is an even number of them*/
+func Median[N Number](nums []N) float64 {
+	if len(nums) == 0 || hasNaN(nums) {
+		return math.NaN()
+	}
+	s := sortedCopy(nums)
+	mid := len(s) / 2
+	if len(s)%2 == 1 {
+		return float64(s[mid])
+	}
+	a, b := float64(s[mid-1]), float64(s[mid])
+	return a + (b-a)/2
+}
+
+/*
+Quantile is the p quantile (0 <= p <= 1) using one of the nine methods from Hyndman and Fan that R's quantile()
+implements. rType is the R type number and defaults to 7, which is also the default in R, NumPy and Excel's
+PERCENTILE.INC. Types 1-3 always give one of the values in nums, 4-9 interpolate between neighbours.
+An out of range p or rType gives NaN.
+*/
+func Quantile[N Number](nums []N, p float64, rType ...int) float64 {
+	t := 7
+	if len(rType) > 0 {
+		t = rType[0]
+	}
+	if len(nums) == 0 || hasNaN(nums) || !(p >= 0 && p <= 1) || t < 1 || t > 9 {
+		return math.NaN()
+	}
+	s := sortedCopy(nums)
+	n := float64(len(s))
+	var m float64
+	switch t {
+	case 3:
+		m = -0.5
+	case 5:
+		m = 0.5
+	case 6:
+		m = p
+	case 7:
+		m = 1 - p
+	case 8:
+		m = (p + 1) / 3
+	case 9:
+		m = p/4 + 3.0/8
+	}
+	// R nudges by a few ulps so that n*p landing a hair under an integer still counts as that integer
+	const fuzz = 4 * 0x1p-52
+	np := n*p + m
+	j := math.Floor(np + fuzz)
+	g := np - j
+	if math.Abs(g) < fuzz {
+		g = 0
+	}
+	switch t {
+	case 1:
+		g = math.Ceil(g)
+	case 2:
+		if g == 0 {
+			g = 0.5
+		} else {
+			g = 1
+		}
+	case 3:
+		if g == 0 && math.Mod(j, 2) == 0 {
+			g = 0
+		} else {
+			g = 1
+		}
+	}
+	// j is 1 based, the values past either end are the ends
+	at := func(k float64) float64 {
+		return float64(s[int(max(1, min(k, n)))-1])
+	}
+	lo, hi := at(j), at(j+1)
+	if g == 0 || lo == hi {
+		return lo
+	}
+	return lo + g*(hi-lo)
+}
+
+/*
+Mode is the most common value, the smallest one if there is a tie. NaNs are never equal to each other so they are
+skipped. It returns ErrEmpty if there is nothing left to count.
+*/
+func Mode[N Number](nums []N) (N, error) {
+	s := sortedCopy(nums)
+	var best N
+	bestCount := 0
+	for i := 0; i < len(s); {
+		if s[i] != s[i] {
+			i++
+			continue
+		}
+		j := i + 1
+		for j < len(s) && s[j] == s[i] {
+			j++
+		}
+		if j-i > bestCount {
+			best, bestCount = s[i], j-i
+		}
+		i = j
+	}
+	if bestCount == 0 {
+		return 0, ErrEmpty
+	}
+	return best, nil
+}
+
+/*crossProducts is the sum of (x - mean x)(y - mean y), the covariance version of sumSquares*/
+func crossProducts[N Number, M Number](xs []N, ys []M) float64 {
+	mx, my := Mean(xs), Mean(ys)
+	prod := make([]float64, len(xs))
+	dx := make([]float64, len(xs))
+	dy := make([]float64, len(xs))
+	for i := range xs {
+		dx[i] = float64(xs[i]) - mx
+		dy[i] = float64(ys[i]) - my
+		prod[i] = dx[i] * dy[i]
+	}
+	return compensatedSum[float64](prod) - compensatedSum[float64](dx)*compensatedSum[float64](dy)/float64(len(xs))
+}
+
+/*Covariance is the population covariance. xs and ys have to be the same length or the result is NaN.*/
+func Covariance[N Number, M Number](xs []N, ys []M) float64 {
+	if len(xs) != len(ys) {
+		return math.NaN()
+	}
+	return crossProducts(xs, ys) / float64(len(xs))
+}
+
+func SampleCovariance[N Number, M Number](xs []N, ys []M) float64 {
+	if len(xs) != len(ys) || len(xs) < 2 {
+		return math.NaN()
+	}
+	return crossProducts(xs, ys) / float64(len(xs)-1)
+}
+
+/*Correlation is Pearson's r, between -1 and 1. It is NaN if either side has no spread at all.*/
+func Correlation[N Number, M Number](xs []N, ys []M) float64 {
+	if len(xs) != len(ys) || len(xs) == 0 {
+		return math.NaN()
+	}
+	sxx, syy := sumSquares(xs), sumSquares(ys)
+	if sxx <= 0 || syy <= 0 {
+		return math.NaN()
+	}
+	r := crossProducts(xs, ys) / (math.Sqrt(sxx) * math.Sqrt(syy))
+	return max(-1, min(r, 1))
+}
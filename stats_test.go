@@ -0,0 +1,184 @@
+package RUNK
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestSumExactAndCompensated(t *testing.T) {
+	big := []int64{math.MaxInt64, math.MaxInt64, math.MinInt64, -5}
+	if got := Sum(big); got != math.MaxInt64-6 {
+		t.Errorf("int64 Sum = %d, the partial sums go past int64 but the total fits", got)
+	}
+	if got := Sum([]int8{100, 100}); got != math.MaxInt8 {
+		t.Errorf("int8 Sum = %d, want it saturated", got)
+	}
+	if got := Sum([]uint8{200, 100}); got != math.MaxUint8 {
+		t.Errorf("uint8 Sum = %d", got)
+	}
+	if got := SumAs[int32]([]int8{100, 100, 100}); got != 300 {
+		t.Errorf("SumAs[int32] = %d", got)
+	}
+	if got := SumAs[uint8]([]int{-5, 300}); got != 255 {
+		t.Errorf("SumAs[uint8] = %d, each value converts to Acc first", got)
+	}
+	// 1 followed by a million 1e-16s, naive float adding loses every one of them
+	xs := make([]float64, 1_000_001)
+	xs[0] = 1
+	for i := 1; i < len(xs); i++ {
+		xs[i] = 1e-16
+	}
+	if got := Sum(xs); math.Abs(got-(1+1e-10)) > 1e-15 {
+		t.Errorf("compensated Sum = %.17g", got)
+	}
+	if got := SumPairwise(xs); math.Abs(got-(1+1e-10)) > 1e-13 {
+		t.Errorf("pairwise Sum = %.17g", got)
+	}
+	if got := Sum([]float64{1e308, 1e308, -1e308}); !math.IsInf(got, 1) {
+		t.Errorf("overflowing float Sum = %v", got)
+	}
+	if got := Sum([]float64{1, math.NaN()}); got == got {
+		t.Errorf("Sum with NaN = %v", got)
+	}
+	if got := Sum([]float32{}); got != 0 {
+		t.Errorf("empty Sum = %v", got)
+	}
+	if got := SumAs[float64]([]float32{0.1, 0.2}); got != float64(float32(0.1))+float64(float32(0.2)) {
+		t.Errorf("SumAs[float64] of float32s = %v", got)
+	}
+}
+
+func TestMeanAndVariance(t *testing.T) {
+	xs := []int{2, 4, 4, 4, 5, 5, 7, 9}
+	if Mean(xs) != 5 || Variance(xs) != 4 || StdDev(xs) != 2 {
+		t.Errorf("Mean, Variance, StdDev = %v %v %v", Mean(xs), Variance(xs), StdDev(xs))
+	}
+	if got := SampleVariance(xs); got != 32.0/7 {
+		t.Errorf("SampleVariance = %v", got)
+	}
+	// a huge offset wrecks the textbook sum of squares formula but not the two pass one
+	shifted := []float64{1e9 + 4, 1e9 + 7, 1e9 + 13, 1e9 + 16}
+	if got := Variance(shifted); got != 22.5 {
+		t.Errorf("Variance with an offset = %v", got)
+	}
+	if got := Mean([]int64{math.MaxInt64, math.MaxInt64}); got != math.MaxInt64 {
+		t.Errorf("Mean of MaxInt64s = %v", got)
+	}
+	for name, got := range map[string]float64{
+		"Mean":           Mean([]int{}),
+		"Variance":       Variance([]float64{}),
+		"SampleVariance": SampleVariance([]float64{3}),
+		"SampleStdDev":   SampleStdDev([]int{}),
+		"Mean NaN":       Mean([]float64{1, math.NaN()}),
+		"Median":         Median([]int{}),
+		"Median NaN":     Median([]float64{math.NaN(), 1}),
+	} {
+		if got == got {
+			t.Errorf("%s = %v, want NaN", name, got)
+		}
+	}
+	if got := Median([]int{5, 1, 3}); got != 3 {
+		t.Errorf("odd Median = %v", got)
+	}
+	if got := Median([]int64{math.MaxInt64, math.MaxInt64 - 2}); got != math.MaxInt64-1 {
+		t.Errorf("even Median = %v", got)
+	}
+	if got := Median([]float64{math.MaxFloat64, math.MaxFloat64}); got != math.MaxFloat64 {
+		t.Errorf("Median of MaxFloat64s = %v, the halfway point must not overflow", got)
+	}
+}
+
+func TestQuantileTypes(t *testing.T) {
+	xs := []int{13, 2, 4, 4, 5, 7, 9, 10}
+	tests := []struct {
+		p    float64
+		want [9]float64
+	}{
+		{0.1, [9]float64{2, 2, 2, 2, 2.6, 2, 3.4, 7.0 / 3, 2.4}},
+		{0.25, [9]float64{4, 4, 4, 4, 4, 4, 4, 4, 4}},
+		{0.5, [9]float64{5, 6, 5, 5, 6, 6, 6, 6, 6}},
+		{0.9, [9]float64{13, 13, 10, 10.6, 12.1, 13, 10.9, 12.5, 12.4}},
+		{0, [9]float64{2, 2, 2, 2, 2, 2, 2, 2, 2}},
+		{1, [9]float64{13, 13, 13, 13, 13, 13, 13, 13, 13}},
+	}
+	for _, tt := range tests {
+		for typ := 1; typ <= 9; typ++ {
+			if got := Quantile(xs, tt.p, typ); math.Abs(got-tt.want[typ-1]) > 1e-12 {
+				t.Errorf("type %d Quantile(%v) = %v, want %v", typ, tt.p, got, tt.want[typ-1])
+			}
+		}
+	}
+	if got := Quantile(xs, 0.9); got != Quantile(xs, 0.9, 7) {
+		t.Errorf("default Quantile = %v, want type 7", got)
+	}
+	// type 3 rounds n*p to even on a tie
+	if Quantile(xs, 0.3125, 3) != 4 || Quantile(xs, 0.4375, 3) != 5 {
+		t.Error("type 3 ties don't go to even")
+	}
+	for _, bad := range []float64{-0.1, 1.1, math.NaN()} {
+		if got := Quantile(xs, bad); got == got {
+			t.Errorf("Quantile(%v) = %v", bad, got)
+		}
+	}
+	if got := Quantile(xs, 0.5, 10); got == got {
+		t.Errorf("type 10 = %v", got)
+	}
+	if got := Quantile([]float64{}, 0.5); got == got {
+		t.Errorf("empty Quantile = %v", got)
+	}
+}
+
+func TestMode(t *testing.T) {
+	if m, err := Mode([]int{3, 1, 3, 1, 2}); m != 1 || err != nil {
+		t.Errorf("tied Mode = %v %v, want the smallest", m, err)
+	}
+	nan := math.NaN()
+	if m, err := Mode([]float64{nan, nan, nan, 2}); m != 2 || err != nil {
+		t.Errorf("Mode with NaNs = %v %v", m, err)
+	}
+	if _, err := Mode([]float64{nan}); err != ErrEmpty {
+		t.Errorf("all NaN Mode = %v", err)
+	}
+	if _, err := Mode([]uint{}); err != ErrEmpty {
+		t.Errorf("empty Mode = %v", err)
+	}
+}
+
+func TestCovarianceAndCorrelation(t *testing.T) {
+	xs := []float64{1, 2, 3, 4, 5}
+	ys := []int{2, 4, 6, 8, 10}
+	if got := Covariance(xs, ys); got != 4 {
+		t.Errorf("Covariance = %v", got)
+	}
+	if got := SampleCovariance(xs, ys); got != 5 {
+		t.Errorf("SampleCovariance = %v", got)
+	}
+	if got := Correlation(xs, ys); math.Abs(got-1) > 1e-15 {
+		t.Errorf("Correlation = %v", got)
+	}
+	if got := Correlation(xs, []int{10, 8, 6, 4, 2}); math.Abs(got+1) > 1e-15 {
+		t.Errorf("negative Correlation = %v", got)
+	}
+	if got := Correlation(xs, []int{3, 3, 3, 3, 3}); got == got {
+		t.Errorf("Correlation with a constant = %v", got)
+	}
+	if got := Covariance(xs, ys[:4]); got == got {
+		t.Errorf("Covariance of different lengths = %v", got)
+	}
+	if got := Correlation([]int{}, []int{}); got == got {
+		t.Errorf("empty Correlation = %v", got)
+	}
+	rng := rand.New(rand.NewSource(9))
+	a, b := make([]float64, 1000), make([]float64, 1000)
+	for i := range a {
+		a[i] = rng.NormFloat64()
+		b[i] = rng.NormFloat64()
+	}
+	if r := Correlation(a, b); math.Abs(r) > 0.1 {
+		t.Errorf("independent samples have Correlation %v", r)
+	}
+	if got := Covariance(a, a); math.Abs(got-Variance(a)) > 1e-12 {
+		t.Errorf("Covariance(a, a) = %v, Variance = %v", got, Variance(a))
+	}
+}
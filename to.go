@@ -0,0 +1,292 @@
+package RUNK
+
+import "math"
+
+/*
+The To variants are the wrappers with the result type picked separately from the input type, so
+SqrtTo[float64](int16(5)) is 2.23606797749979 where Sqrt(int16(5)) is 2. The math is the same float64 math the
+wrappers do and the result goes through ConvertNumber[To] so picking a narrower type saturates exactly the way the
+wrappers do. With To set to the input type they give exactly what the wrappers give.
+*/
+
+func AcosTo[To Number, N Number](num N) To {
+	return ConvertNumber[To](math.Acos(float64(num)))
+}
+
+func AcoshTo[To Number, N Number](num N) To {
+	return ConvertNumber[To](math.Acosh(float64(num)))
+}
+
+func AsinTo[To Number, N Number](num N) To {
+	return ConvertNumber[To](math.Asin(float64(num)))
+}
+
+func AsinhTo[To Number, N Number](num N) To {
+	return ConvertNumber[To](math.Asinh(float64(num)))
+}
+
+func AtanTo[To Number, N Number](num N) To {
+	return ConvertNumber[To](math.Atan(float64(num)))
+}
+
+func Atan2To[To Number, N Number, M Number](x N, y M) To {
+	return ConvertNumber[To](math.Atan2(float64(x), float64(y)))
+}
+
+func AtanhTo[To Number, N Number](num N) To {
+	return ConvertNumber[To](math.Atanh(float64(num)))
+}
+
+func CbrtTo[To Number, N Number](num N) To {
+	return ConvertNumber[To](math.Cbrt(float64(num)))
+}
+
+func CeilTo[To Number, N Number](num N) To {
+	return ConvertNumberBy[To](math.Ceil(float64(num)), math.Ceil)
+}
+
+func CopysignTo[To Number, N Number, M Number](f N, sign M) To {
+	return ConvertNumber[To](math.Copysign(float64(f), float64(sign)))
+}
+
+func CosTo[To Number, N Number](num N) To {
+	return ConvertNumber[To](math.Cos(float64(num)))
+}
+
+func CoshTo[To Number, N Number](num N) To {
+	return ConvertNumber[To](math.Cosh(float64(num)))
+}
+
+func DimTo[To Number, N Number, M Number](x N, y M) To {
+	return ConvertNumber[To](math.Dim(float64(x), float64(y)))
+}
+
+func ErfTo[To Number, N Number](num N) To {
+	return ConvertNumber[To](math.Erf(float64(num)))
+}
+
+func ErfcTo[To Number, N Number](num N) To {
+	return ConvertNumber[To](math.Erfc(float64(num)))
+}
+
+func ErfcinvTo[To Number, N Number](num N) To {
+	return ConvertNumber[To](math.Erfcinv(float64(num)))
+}
+
+func ErfinvTo[To Number, N Number](num N) To {
+	return ConvertNumber[To](math.Erfinv(float64(num)))
+}
+
+func ExpTo[To Number, N Number](num N) To {
+	return ConvertNumber[To](math.Exp(float64(num)))
+}
+
+func Exp2To[To Number, N Number](num N) To {
+	return ConvertNumber[To](math.Exp2(float64(num)))
+}
+
+func Expm1To[To Number, N Number](num N) To {
+	return ConvertNumber[To](math.Expm1(float64(num)))
+}
+
+func FMATo[To Number, N Number, M Number, W Number](x N, y M, z W) To {
+	return ConvertNumber[To](math.FMA(float64(x), float64(y), float64(z)))
+}
+
+func FloorTo[To Number, N Number](num N) To {
+	return ConvertNumberBy[To](math.Floor(float64(num)), math.Floor)
+}
+
+func GammaTo[To Number, N Number](num N) To {
+	return ConvertNumber[To](math.Gamma(float64(num)))
+}
+
+func HypotTo[To Number, N Number, M Number](x N, y M) To {
+	return ConvertNumber[To](math.Hypot(float64(x), float64(y)))
+}
+
+func IlogbTo[To Number, N Number](num N) To {
+	return ConvertNumber[To](math.Ilogb(float64(num)))
+}
+
+func J0To[To Number, N Number](num N) To {
+	return ConvertNumber[To](math.J0(float64(num)))
+}
+
+func J1To[To Number, N Number](num N) To {
+	return ConvertNumber[To](math.J1(float64(num)))
+}
+
+/*n is the order and is converted to an int the same as in Jn*/
+func JnTo[To Number, N Number, M Number](n N, x M) To {
+	return ConvertNumber[To](math.Jn(ConvertNumber[int](n), float64(x)))
+}
+
+func LdexpTo[To Number, N Number, M Number](frac N, exp M) To {
+	return ConvertNumber[To](math.Ldexp(float64(frac), ConvertNumber[int](exp)))
+}
+
+func LgammaTo[To Number, N Number](num N) (To, int) {
+	x, i := math.Lgamma(float64(num))
+	return ConvertNumber[To](x), i
+}
+
+/*LgamTo is Lgam with the sign of Gamma put back on, see Lgam*/
+func LgamTo[To Number, N Number](num N) To {
+	x, i := math.Lgamma(float64(num))
+	g := ConvertNumber[To](x * float64(i))
+	h := ConvertNumber[To](x)
+	if Abs(h) > Abs(g) {
+		return h
+	}
+	return g
+}
+
+func LogTo[To Number, N Number](num N) To {
+	return ConvertNumber[To](math.Log(float64(num)))
+}
+
+func Log10To[To Number, N Number](num N) To {
+	return ConvertNumber[To](math.Log10(float64(num)))
+}
+
+func Log1pTo[To Number, N Number](num N) To {
+	return ConvertNumber[To](math.Log1p(float64(num)))
+}
+
+func Log2To[To Number, N Number](num N) To {
+	return ConvertNumber[To](math.Log2(float64(num)))
+}
+
+func LogbTo[To Number, N Number](num N) To {
+	return ConvertNumber[To](math.Logb(float64(num)))
+}
+
+func ModTo[To Number, N Number, M Number](x N, y M) To {
+	return ConvertNumber[To](math.Mod(float64(x), float64(y)))
+}
+
+func ModfTo[To Number, N Number](num N) (To, float64) {
+	x, i := math.Modf(float64(num))
+	return ConvertNumber[To](x), i
+}
+
+func NextafterTo[To Number, N Number, M Number](x N, y M) To {
+	return ConvertNumber[To](math.Nextafter(float64(x), float64(y)))
+}
+
+func PowTo[To Number, N Number, M Number](x N, y M) To {
+	return ConvertNumber[To](math.Pow(float64(x), float64(y)))
+}
+
+func Pow10To[To Number, N Number](num N) To {
+	return ConvertNumber[To](math.Pow10(ConvertNumber[int](num)))
+}
+
+func RemainderTo[To Number, N Number, M Number](x N, y M) To {
+	return ConvertNumber[To](math.Remainder(float64(x), float64(y)))
+}
+
+func RoundTo[To Number, N Number](num N) To {
+	return ConvertNumberBy[To](math.Round(float64(num)), math.Round)
+}
+
+func RoundToEvenTo[To Number, N Number](num N) To {
+	return ConvertNumberBy[To](math.RoundToEven(float64(num)), math.RoundToEven)
+}
+
+func SinTo[To Number, N Number](num N) To {
+	return ConvertNumber[To](math.Sin(float64(num)))
+}
+
+func SincosTo[To Number, N Number](num N) (To, To) {
+	x, y := math.Sincos(float64(num))
+	return ConvertNumber[To](x), ConvertNumber[To](y)
+}
+
+func SinhTo[To Number, N Number](num N) To {
+	return ConvertNumber[To](math.Sinh(float64(num)))
+}
+
+func SqrtTo[To Number, N Number](num N) To {
+	return ConvertNumber[To](math.Sqrt(float64(num)))
+}
+
+func TanTo[To Number, N Number](num N) To {
+	return ConvertNumber[To](math.Tan(float64(num)))
+}
+
+func TanhTo[To Number, N Number](num N) To {
+	return ConvertNumber[To](math.Tanh(float64(num)))
+}
+
+func TruncTo[To Number, N Number](num N) To {
+	return ConvertNumberBy[To](math.Trunc(float64(num)), math.Trunc)
+}
+
+func Y0To[To Number, N Number](num N) To {
+	return ConvertNumber[To](math.Y0(float64(num)))
+}
+
+func Y1To[To Number, N Number](num N) To {
+	return ConvertNumber[To](math.Y1(float64(num)))
+}
+
+func YnTo[To Number, N Number, M Number](n N, x M) To {
+	return ConvertNumber[To](math.Yn(ConvertNumber[int](n), float64(x)))
+}
+
+/*the special functions from special.go*/
+
+func BetaTo[To Number, N Number, M Number](a N, b M) To {
+	return ConvertNumber[To](beta(float64(a), float64(b)))
+}
+
+func LogBetaTo[To Number, N Number, M Number](a N, b M) To {
+	return ConvertNumber[To](logBeta(float64(a), float64(b)))
+}
+
+func GammaPTo[To Number, N Number, M Number](a N, x M) To {
+	return ConvertNumber[To](gammaP(float64(a), float64(x)))
+}
+
+func GammaQTo[To Number, N Number, M Number](a N, x M) To {
+	return ConvertNumber[To](gammaQ(float64(a), float64(x)))
+}
+
+func BetaIncTo[To Number, N Number, M Number, W Number](a N, b M, x W) To {
+	return ConvertNumber[To](betaInc(float64(a), float64(b), float64(x)))
+}
+
+func DigammaTo[To Number, N Number](num N) To {
+	return ConvertNumber[To](digamma(float64(num)))
+}
+
+func TrigammaTo[To Number, N Number](num N) To {
+	return ConvertNumber[To](trigamma(float64(num)))
+}
+
+func ZetaTo[To Number, N Number](s N) To {
+	return ConvertNumber[To](zeta(float64(s)))
+}
+
+/*k is the branch and is converted to an int the same as in LambertW*/
+func LambertWTo[To Number, N Number, M Number](k N, x M) To {
+	return ConvertNumber[To](lambertW(ConvertNumber[int](k), float64(x)))
+}
+
+func ExpintTo[To Number, N Number](num N) To {
+	return ConvertNumber[To](expint(float64(num)))
+}
+
+func SincTo[To Number, N Number](num N) To {
+	return ConvertNumber[To](sinc(float64(num)))
+}
+
+func LogitTo[To Number, N Number](p N) To {
+	return ConvertNumber[To](logit(float64(p)))
+}
+
+func ExpitTo[To Number, N Number](num N) To {
+	return ConvertNumber[To](expit(float64(num)))
+}
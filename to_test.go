@@ -0,0 +1,103 @@
+package RUNK
+
+import (
+	"math"
+	"testing"
+)
+
+func TestToMatchesWrappers(t *testing.T) {
+	xs := []float64{-7.5, -1, -0.25, 0, 0.3, 1, 2.5, 8, 1e10, math.Inf(1), math.NaN()}
+	fns := []struct {
+		name string
+		to   func(float64) float64
+		wrap func(float64) float64
+	}{
+		{"sqrt", SqrtTo[float64, float64], Sqrt[float64]},
+		{"cbrt", CbrtTo[float64, float64], Cbrt[float64]},
+		{"exp", ExpTo[float64, float64], Exp[float64]},
+		{"log", LogTo[float64, float64], Log[float64]},
+		{"log2", Log2To[float64, float64], Log2[float64]},
+		{"sin", SinTo[float64, float64], Sin[float64]},
+		{"atan", AtanTo[float64, float64], Atan[float64]},
+		{"gamma", GammaTo[float64, float64], Gamma[float64]},
+		{"lgam", LgamTo[float64, float64], Lgam[float64]},
+		{"floor", FloorTo[float64, float64], Floor[float64]},
+		{"round", RoundTo[float64, float64], Round[float64]},
+		{"ilogb", IlogbTo[float64, float64], Ilogb[float64]},
+		{"pow10", Pow10To[float64, float64], Pow10[float64]},
+		{"erf", ErfTo[float64, float64], Erf[float64]},
+	}
+	for _, fn := range fns {
+		for _, x := range xs {
+			got, want := fn.to(x), fn.wrap(x)
+			if got != want && !(math.IsNaN(got) && math.IsNaN(want)) {
+				t.Errorf("%sTo(%v) = %v, the wrapper gives %v", fn.name, x, got, want)
+			}
+		}
+	}
+	for _, x := range []int16{-5, 0, 3, 255, math.MaxInt16} {
+		if SqrtTo[int16](x) != Sqrt(x) || Log10To[int16](x) != Log10(x) || Pow10To[int16](x) != Pow10(x) {
+			t.Errorf("int16 To variants of %d don't match the wrappers", x)
+		}
+	}
+}
+
+func TestToResultType(t *testing.T) {
+	if got := SqrtTo[float64](int16(5)); got != math.Sqrt(5) {
+		t.Errorf("SqrtTo[float64](int16(5)) = %v", got)
+	}
+	if got := Sqrt(int16(5)); got != 2 {
+		t.Errorf("Sqrt(int16(5)) = %v", got)
+	}
+	if got := ExpTo[int8](10); got != math.MaxInt8 {
+		t.Errorf("ExpTo[int8](10) = %d, want it saturated", got)
+	}
+	if got := LogTo[uint8](0.5); got != 0 {
+		t.Errorf("LogTo[uint8](0.5) = %d", got)
+	}
+	if got := LogTo[int](-1); got != 0 {
+		t.Errorf("LogTo[int](-1) = %d, want NaN as 0", got)
+	}
+	if got := LogTo[int64](0); got != math.MinInt64 {
+		t.Errorf("LogTo[int64](0) = %d, want -Inf as the minimum", got)
+	}
+	if got := FloorTo[int](uint8(200)); got != 200 {
+		t.Errorf("FloorTo[int](200) = %d", got)
+	}
+	if got := CeilTo[int](2.1); got != 3 {
+		t.Errorf("CeilTo[int](2.1) = %d", got)
+	}
+	if got := PowTo[float32](2, 0.5); got != float32(math.Sqrt2) {
+		t.Errorf("PowTo[float32](2, 0.5) = %v", got)
+	}
+	if g, s := LgammaTo[int](-2.5); g != 0 || s != -1 {
+		t.Errorf("LgammaTo[int](-2.5) = %d %d", g, s)
+	}
+	if s, c := SincosTo[float32](0); s != 0 || c != 1 {
+		t.Errorf("SincosTo(0) = %v %v", s, c)
+	}
+	if i, frac := ModfTo[int](3.75); i != 3 || frac != 0.75 {
+		t.Errorf("ModfTo[int](3.75) = %d %v", i, frac)
+	}
+}
+
+func TestIlogb(t *testing.T) {
+	tests := []struct {
+		in   float64
+		want int
+	}{{8, 3}, {10, 3}, {1, 0}, {0.3, -2}, {-1024, 10}}
+	for _, tt := range tests {
+		if got := Ilogb(int(tt.in)); tt.in == math.Trunc(tt.in) && got != tt.want {
+			t.Errorf("Ilogb(int %v) = %d, want %d", tt.in, got, tt.want)
+		}
+		if got := IlogbTo[int](tt.in); got != tt.want {
+			t.Errorf("IlogbTo[int](%v) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+	if got := Ilogb(0.0); got != math.MinInt32 {
+		t.Errorf("Ilogb(0) = %v", got)
+	}
+	if got := Ilogb(int8(0)); got != math.MinInt8 {
+		t.Errorf("Ilogb(int8 0) = %d, want MinInt32 saturated to int8", got)
+	}
+}
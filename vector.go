@@ -0,0 +1,359 @@
+package RUNK
+
+import (
+	"math"
+	"math/big"
+	"math/bits"
+)
+
+/*
+The vector kernels work on plain slices. The element wise ones write into dst and, like copy and the Float16 bulk
+converters, handle min(len) of the slices and return how many that was. dst can be one of the inputs.
+The type of dst (or Acc for the reductions, which has to be given) is the accumulator. When the inputs and the
+accumulator are all integers the math is exact, in 128 bits and big integers for sums past that, and only the
+final value goes through the overflow policy, Saturate by default, so AddVec of two []uint8 into a []uint8 clips at
+255 like image code wants and Wrap gives the usual Go wrap around. When either side is a float the math is float64,
+the same as the scalar wrappers do it, and the result is converted the same way a Context would.
+*/
+
+/*wide is an exact sign and magnitude integer up to 128 bits, the magnitude saturates past that*/
+type wide struct {
+	neg bool
+	hi  uint64
+	lo  uint64
+}
+
+func wideOf[N Number](n N) wide {
+	neg, mag := magnitude(n)
+	return wide{neg: neg, lo: mag}
+}
+
+func (a wide) isZero() bool {
+	return a.hi == 0 && a.lo == 0
+}
+
+func (a wide) less(b wide) bool {
+	if a.hi != b.hi {
+		return a.hi < b.hi
+	}
+	return a.lo < b.lo
+}
+
+func (a wide) add(b wide) wide {
+	if a.neg == b.neg {
+		lo, c := bits.Add64(a.lo, b.lo, 0)
+		hi, c := bits.Add64(a.hi, b.hi, c)
+		if c != 0 {
+			return wide{neg: a.neg, hi: math.MaxUint64, lo: math.MaxUint64}
+		}
+		return wide{neg: a.neg, hi: hi, lo: lo}
+	}
+	if a.less(b) {
+		a, b = b, a
+	}
+	lo, c := bits.Sub64(a.lo, b.lo, 0)
+	hi, _ := bits.Sub64(a.hi, b.hi, c)
+	return wide{neg: a.neg && (hi != 0 || lo != 0), hi: hi, lo: lo}
+}
+
+/*mul only ever gets 64 bit magnitudes so the 128 bit product is exact*/
+func (a wide) mul(b wide) wide {
+	hi, lo := bits.Mul64(a.lo, b.lo)
+	return wide{neg: a.neg != b.neg && (hi != 0 || lo != 0), hi: hi, lo: lo}
+}
+
+/*quo divides two 64 bit magnitudes rounding half away from zero like math.Round. x/0 saturates, 0/0 is 0.*/
+func (a wide) quo(b wide) (wide, bool) {
+	neg := a.neg != b.neg
+	if b.lo == 0 {
+		if a.lo == 0 {
+			return wide{}, true
+		}
+		return wide{neg: neg, hi: math.MaxUint64, lo: math.MaxUint64}, true
+	}
+	q, r := a.lo/b.lo, a.lo%b.lo
+	q, _ = roundMagnitude(neg, q, fracOf(r, b.lo), math.Round)
+	return wide{neg: neg && q != 0, lo: q}, false
+}
+
+/*
+wideSum is an exact running total of wide terms. A sum of 128 bit products can pass 128 bits where wide would
+saturate and then lose track of terms of the other sign, so from the first carry out the total moves to a big.Int.
+*/
+type wideSum struct {
+	w   wide
+	big *big.Int
+}
+
+func (s *wideSum) add(t wide) {
+	if s.big == nil {
+		_, c := bits.Add64(s.w.lo, t.lo, 0)
+		_, c = bits.Add64(s.w.hi, t.hi, c)
+		if s.w.neg != t.neg || c == 0 {
+			s.w = s.w.add(t)
+			return
+		}
+		s.big = bigOfWide(s.w)
+	}
+	s.big.Add(s.big, bigOfWide(t))
+}
+
+func bigOfWide(a wide) *big.Int {
+	b := new(big.Int).SetUint64(a.hi)
+	b.Lsh(b, 64).Or(b, new(big.Int).SetUint64(a.lo))
+	if a.neg {
+		b.Neg(b)
+	}
+	return b
+}
+
+/*wideSumTo is fitWide for a wideSum*/
+func wideSumTo[N Number](s *wideSum, policy OverflowPolicy, err *error) N {
+	b := s.big
+	if b == nil {
+		if s.w.hi != math.MaxUint64 {
+			return fitWide[N](s.w, policy, err)
+		}
+		// wideTo takes all ones as saturated, here it is exact
+		b = bigOfWide(s.w)
+	}
+	n, e := fitBig[N](b, policy)
+	if e != nil {
+		*err = e
+	}
+	return n
+}
+
+/*wideTo converts with the overflow policy. A saturated value has no meaningful low bits so it never wraps.*/
+func wideTo[To Number](a wide, overflow OverflowPolicy, saturated bool) To {
+	if isFloat[To]() {
+		f := math.Ldexp(float64(a.hi), 64) + float64(a.lo)
+		if a.neg {
+			f = -f
+		}
+		return ConvertNumber[To](f)
+	}
+	if overflow == Wrap && !saturated && !(a.hi == math.MaxUint64 && a.lo == math.MaxUint64) {
+		v := a.lo
+		if a.neg {
+			v = -v
+		}
+		return To(v)
+	}
+	if a.hi != 0 {
+		if a.neg {
+			return MinNum[To]()
+		}
+		return MaxNum[To]()
+	}
+	return fromMagnitude[To](a.neg, a.lo)
+}
+
+func pickOverflowPolicy(overflow []OverflowPolicy) OverflowPolicy {
+	if len(overflow) > 0 {
+		return overflow[0]
+	}
+	return Saturate
+}
+
+/*floatPath reports whether the float64 math has to be used*/
+func floatPath[Acc Number, N Number]() bool {
+	return isFloat[Acc]() || isFloat[N]()
+}
+
+/*
+elementwise runs the exact integer version or the float64 version of an operation over the slices. The unary
+operations only take x and get zeros for y, a nil y on a binary one is just an empty slice.
+*/
+func elementwise[Acc Number, N Number](dst []Acc, x []N, y []N, unary bool, overflow []OverflowPolicy,
+	intOp func(a wide, b wide) (wide, bool), floatOp func(a float64, b float64) float64) int {
+	n := min(len(dst), len(x))
+	if !unary {
+		n = min(n, len(y))
+	}
+	policy := pickOverflowPolicy(overflow)
+	ctx := NewContext[Acc](nil, policy)
+	var b N
+	for i := 0; i < n; i++ {
+		if !unary {
+			b = y[i]
+		}
+		if floatPath[Acc, N]() {
+			dst[i] = ctx.Convert(floatOp(float64(x[i]), float64(b)))
+			continue
+		}
+		w, saturated := intOp(wideOf(x[i]), wideOf(b))
+		dst[i] = wideTo[Acc](w, policy, saturated)
+	}
+	return n
+}
+
+/*AddVec sets dst[i] = x[i] + y[i]*/
+func AddVec[Acc Number, N Number](dst []Acc, x []N, y []N, overflow ...OverflowPolicy) int {
+	return elementwise(dst, x, y, false, overflow,
+		func(a, b wide) (wide, bool) { return a.add(b), false },
+		func(a, b float64) float64 { return a + b })
+}
+
+func SubVec[Acc Number, N Number](dst []Acc, x []N, y []N, overflow ...OverflowPolicy) int {
+	return elementwise(dst, x, y, false, overflow,
+		func(a, b wide) (wide, bool) { b.neg = !b.neg && !b.isZero(); return a.add(b), false },
+		func(a, b float64) float64 { return a - b })
+}
+
+func MulVec[Acc Number, N Number](dst []Acc, x []N, y []N, overflow ...OverflowPolicy) int {
+	return elementwise(dst, x, y, false, overflow,
+		func(a, b wide) (wide, bool) { return a.mul(b), false },
+		func(a, b float64) float64 { return a * b })
+}
+
+/*
+DivVec sets dst[i] = x[i] / y[i]. Integer quotients are rounded to nearest like the wrappers round, not truncated
+like Go's /. Dividing by zero saturates toward the sign of x[i] the way converting +-Inf does and 0/0 is 0.
+*/
+func DivVec[Acc Number, N Number](dst []Acc, x []N, y []N, overflow ...OverflowPolicy) int {
+	return elementwise(dst, x, y, false, overflow,
+		func(a, b wide) (wide, bool) { return a.quo(b) },
+		func(a, b float64) float64 { return a / b })
+}
+
+/*Scale sets dst[i] = a * x[i]*/
+func Scale[Acc Number, N Number](dst []Acc, a N, x []N, overflow ...OverflowPolicy) int {
+	aw, af := wideOf(a), float64(a)
+	return elementwise(dst, x, nil, true, overflow,
+		func(x, _ wide) (wide, bool) { return aw.mul(x), false },
+		func(x, _ float64) float64 { return af * x })
+}
+
+/*Axpy sets dst[i] = a*x[i] + y[i], the BLAS name. Floats use a fused multiply add like FMA.*/
+func Axpy[Acc Number, N Number](dst []Acc, a N, x []N, y []N, overflow ...OverflowPolicy) int {
+	aw, af := wideOf(a), float64(a)
+	return elementwise(dst, x, y, false, overflow,
+		func(x, y wide) (wide, bool) { return aw.mul(x).add(y), false },
+		func(x, y float64) float64 { return math.FMA(af, x, y) })
+}
+
+/*
+reduce sums term(x[i], y[i]) over the slices. Integers are summed exactly, even past 128 bits. A float Acc is summed
+in Acc with Neumaier's compensation and an integer Acc with float inputs is summed the same way in float64.
+*/
+func reduce[Acc Number, N Number](x []N, y []N, unary bool, overflow []OverflowPolicy,
+	intTerm func(a wide, b wide) wide, floatTerm func(a float64, b float64) float64) Acc {
+	n := len(x)
+	if !unary {
+		n = min(n, len(y))
+	}
+	var b N
+	if !floatPath[Acc, N]() {
+		var total wideSum
+		for i := 0; i < n; i++ {
+			if !unary {
+				b = y[i]
+			}
+			total.add(intTerm(wideOf(x[i]), wideOf(b)))
+		}
+		var err error
+		return wideSumTo[Acc](&total, pickOverflowPolicy(overflow), &err)
+	}
+	terms := make([]float64, n)
+	for i := range terms {
+		if !unary {
+			b = y[i]
+		}
+		terms[i] = floatTerm(float64(x[i]), float64(b))
+	}
+	if isFloat[Acc]() {
+		return compensatedSum[Acc](terms)
+	}
+	return NewContext[Acc](nil, pickOverflowPolicy(overflow)).Convert(compensatedSum[float64](terms))
+}
+
+/*Dot is the sum of x[i]*y[i] over the shorter of the two slices*/
+func Dot[Acc Number, N Number](x []N, y []N, overflow ...OverflowPolicy) Acc {
+	return reduce[Acc](x, y, false, overflow,
+		func(a, b wide) wide { return a.mul(b) },
+		func(a, b float64) float64 { return a * b })
+}
+
+/*Norm1 is the L1 norm, the sum of the absolute values*/
+func Norm1[Acc Number, N Number](x []N, overflow ...OverflowPolicy) Acc {
+	return reduce[Acc](x, nil, true, overflow,
+		func(a, _ wide) wide { a.neg = false; return a },
+		func(a, _ float64) float64 { return math.Abs(a) })
+}
+
+/*
+Norm2 is the L2 (Euclidean) norm, the square root of the sum of squares. The sum of squares is exact for integers
+and the square root is rounded once. For floats the squares can overflow to Inf for values past about 1e154, use
+Hypots for those.
+*/
+func Norm2[Acc Number, N Number](x []N, overflow ...OverflowPolicy) Acc {
+	if floatPath[Acc, N]() {
+		sq := reduce[float64](x, x, false, nil, nil, func(a, b float64) float64 { return a * b })
+		return NewContext[Acc](nil, pickOverflowPolicy(overflow)).Convert(math.Sqrt(sq))
+	}
+	var total wide
+	for _, n := range x {
+		w := wideOf(n)
+		total = total.add(w.mul(w))
+	}
+	return NewContext[Acc](nil, pickOverflowPolicy(overflow)).Convert(math.Sqrt(wideTo[float64](total, Saturate, false)))
+}
+
+/*NormInf is the L∞ norm, the largest absolute value. It is 0 for an empty slice.*/
+func NormInf[Acc Number, N Number](x []N, overflow ...OverflowPolicy) Acc {
+	if isFloat[N]() {
+		m := 0.0
+		for _, n := range x {
+			f := math.Abs(float64(n))
+			if f != f {
+				m = f
+				break
+			}
+			m = max(m, f)
+		}
+		return NewContext[Acc](nil, pickOverflowPolicy(overflow)).Convert(m)
+	}
+	var m wide
+	for _, n := range x {
+		w := wideOf(n)
+		w.neg = false
+		if m.less(w) {
+			m = w
+		}
+	}
+	return wideTo[Acc](m, pickOverflowPolicy(overflow), false)
+}
+
+/*
+Hypots is Hypot for any number of values, the L2 norm worked out without overflow or underflow. The sum of squares
+is kept scaled by the largest magnitude seen so far (the way BLAS nrm2 does it) so Hypots(1e200, 1e200) is 1.414e200
+instead of Inf. With two values it matches Hypot. Any Inf gives +Inf even if there is a NaN, like Hypot.
+*/
+func Hypots[Acc Number, N Number](x []N, overflow ...OverflowPolicy) Acc {
+	ctx := NewContext[Acc](nil, pickOverflowPolicy(overflow))
+	if len(x) == 2 {
+		return ctx.Convert(math.Hypot(float64(x[0]), float64(x[1])))
+	}
+	scale, ssq := 0.0, 1.0
+	nan := false
+	for _, n := range x {
+		f := math.Abs(float64(n))
+		switch {
+		case math.IsInf(f, 1):
+			return ctx.Convert(f)
+		case f != f:
+			nan = true
+		case f == 0:
+		case scale < f:
+			ssq = 1 + ssq*(scale/f)*(scale/f)
+			scale = f
+		default:
+			ssq += (f / scale) * (f / scale)
+		}
+	}
+	if nan {
+		return ctx.Convert(math.NaN())
+	}
+	return ctx.Convert(scale * math.Sqrt(ssq))
+}
@@ -0,0 +1,224 @@
+package RUNK
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+/*fitRef is what an exact result becomes in int8 under each policy*/
+func fitRef(b *big.Int, policy OverflowPolicy) int8 {
+	n, _ := fitBig[int8](b, policy)
+	return n
+}
+
+func TestVecMatchesBig(t *testing.T) {
+	rng := rand.New(rand.NewSource(15))
+	x, y := make([]int8, 500), make([]int8, 500)
+	for i := range x {
+		x[i], y[i] = int8(rng.Intn(256)-128), int8(rng.Intn(256)-128)
+	}
+	x[0], y[0] = math.MinInt8, -1
+	ops := []struct {
+		name string
+		vec  func([]int8, []int8, []int8, ...OverflowPolicy) int
+		ref  func(a, b *big.Int) *big.Int
+	}{
+		{"add", AddVec[int8, int8], func(a, b *big.Int) *big.Int { return new(big.Int).Add(a, b) }},
+		{"sub", SubVec[int8, int8], func(a, b *big.Int) *big.Int { return new(big.Int).Sub(a, b) }},
+		{"mul", MulVec[int8, int8], func(a, b *big.Int) *big.Int { return new(big.Int).Mul(a, b) }},
+	}
+	for _, op := range ops {
+		for _, policy := range []OverflowPolicy{Saturate, Wrap} {
+			dst := make([]int8, len(x))
+			if n := op.vec(dst, x, y, policy); n != len(x) {
+				t.Fatalf("%s handled %d", op.name, n)
+			}
+			for i := range x {
+				want := fitRef(op.ref(big.NewInt(int64(x[i])), big.NewInt(int64(y[i]))), policy)
+				if dst[i] != want {
+					t.Fatalf("%s(%d, %d) policy %d = %d, want %d", op.name, x[i], y[i], policy, dst[i], want)
+				}
+			}
+		}
+	}
+	wide := make([]int16, len(x))
+	MulVec(wide, x, y)
+	for i := range x {
+		if wide[i] != int16(x[i])*int16(y[i]) {
+			t.Fatalf("int16 accumulator mul(%d, %d) = %d", x[i], y[i], wide[i])
+		}
+	}
+	if got, want := Dot[int64](x, y), dotRef(x, y); got != want {
+		t.Errorf("Dot = %d, want %d", got, want)
+	}
+}
+
+func dotRef(x []int8, y []int8) int64 {
+	var s int64
+	for i := range x {
+		s += int64(x[i]) * int64(y[i])
+	}
+	return s
+}
+
+func TestVecIntegerEdges(t *testing.T) {
+	u := []uint8{200, 10, 0}
+	dst := make([]uint8, 3)
+	AddVec(dst, u, []uint8{100, 10, 0})
+	if dst[0] != 255 || dst[1] != 20 {
+		t.Errorf("saturating uint8 add = %v", dst)
+	}
+	AddVec(dst, u, []uint8{100, 10, 0}, Wrap)
+	if dst[0] != 44 {
+		t.Errorf("wrapping uint8 add = %v", dst)
+	}
+	SubVec(dst, []uint8{3}, []uint8{5})
+	if dst[0] != 0 {
+		t.Errorf("saturating 3 - 5 = %d", dst[0])
+	}
+	SubVec(dst, []uint8{3}, []uint8{5}, Wrap)
+	if dst[0] != 254 {
+		t.Errorf("wrapping 3 - 5 = %d", dst[0])
+	}
+	q := make([]int, 5)
+	DivVec(q, []int{7, -7, 5, 0, -3}, []int{2, 2, 0, 0, 0})
+	if q[0] != 4 || q[1] != -4 || q[2] != math.MaxInt || q[3] != 0 || q[4] != math.MinInt {
+		t.Errorf("DivVec = %v, want rounding half away from zero and saturation", q)
+	}
+	// a saturated division has no low bits to wrap
+	DivVec(q, []int{5}, []int{0}, Wrap)
+	if q[0] != math.MaxInt {
+		t.Errorf("wrapping 5/0 = %d", q[0])
+	}
+	big := []int64{math.MaxInt64, math.MaxInt64}
+	if got := Dot[int64](big, big); got != math.MaxInt64 {
+		t.Errorf("saturating Dot = %d", got)
+	}
+	if got := Dot[int64](big, big, Wrap); got != 2 {
+		t.Errorf("wrapping Dot = %d, want 2*(2^63-1)^2 mod 2^64", got)
+	}
+	// past 128 bits and back, the terms of the other sign still count
+	minI, maxI := int64(math.MinInt64), int64(math.MaxInt64)
+	xs := []int64{minI, minI, minI, minI, maxI, maxI, maxI, maxI}
+	ys := []int64{minI, minI, minI, minI, minI, minI, minI, minI}
+	if got := Dot[int64](xs, ys, Wrap); got != 0 {
+		t.Errorf("wrapping Dot past 128 bits = %d, want 2^65 mod 2^64", got)
+	}
+	if got := Dot[int64](xs, ys); got != math.MaxInt64 {
+		t.Errorf("saturating Dot past 128 bits = %d", got)
+	}
+	for _, policy := range []OverflowPolicy{Saturate, Wrap} {
+		if got := Dot[int64](append(xs, minI, 7), append(ys, 4, 1), policy); got != 7 {
+			t.Errorf("Dot past 128 bits and back with policy %d = %d, want 7", policy, got)
+		}
+	}
+	if got := Dot[int64]([]uint64{math.MaxUint64, math.MaxUint64}, []uint64{math.MaxUint64, 2}, Wrap); got != -1 {
+		t.Errorf("wrapping Dot of exactly 2^128-1 = %d", got)
+	}
+	if got := Dot[float64](big, big); got != 2*math.Pow(math.MaxInt64, 2) {
+		t.Errorf("float Dot = %v", got)
+	}
+	if got := Norm1[uint64]([]int64{math.MinInt64, math.MinInt64}); got != math.MaxUint64 {
+		t.Errorf("Norm1 = %d", got)
+	}
+	if got := Norm1[int64]([]int64{math.MinInt64, 1}, Wrap); got != math.MinInt64+1 {
+		t.Errorf("wrapping Norm1 = %d", got)
+	}
+	if got := NormInf[uint8]([]int8{-128, 5}); got != 128 {
+		t.Errorf("NormInf = %d", got)
+	}
+	if got := Norm2[int]([]int{3, 4}); got != 5 {
+		t.Errorf("Norm2 = %d", got)
+	}
+	if got := Scale(dst, uint8(3), []uint8{100}); got != 1 || dst[0] != 255 {
+		t.Errorf("Scale = %d %v", got, dst)
+	}
+	a := make([]int32, 2)
+	Axpy(a, int32(-2), []int32{math.MaxInt32, 5}, []int32{1, 1})
+	if a[0] != math.MinInt32 || a[1] != -9 {
+		t.Errorf("Axpy = %v", a)
+	}
+}
+
+func TestVecFloatsAndLengths(t *testing.T) {
+	x := []float64{1, 2, 3, math.NaN()}
+	y := []float64{0.5, 0.25}
+	dst := make([]float64, 8)
+	if n := AddVec(dst, x, y); n != 2 || dst[0] != 1.5 || dst[1] != 2.25 || dst[2] != 0 {
+		t.Errorf("AddVec over the shortest = %d %v", n, dst)
+	}
+	// dst can be an input
+	in := []float64{1, 2}
+	MulVec(in, in, in)
+	if in[0] != 1 || in[1] != 4 {
+		t.Errorf("in place MulVec = %v", in)
+	}
+	ints := make([]int8, 3)
+	DivVec(ints, []float64{1, -1, 0}, []float64{0, 0, 0})
+	if ints[0] != math.MaxInt8 || ints[1] != math.MinInt8 || ints[2] != 0 {
+		t.Errorf("float DivVec into int8 = %v, want Inf and NaN converted", ints)
+	}
+	AddVec(ints, []float32{100.4, -2.5}, []float32{100, 0}, Wrap)
+	if ints[0] != int8(-56) || ints[1] != -3 {
+		t.Errorf("float into int8 with Wrap = %v", ints)
+	}
+	if got := Dot[float64](x, x); got == got {
+		t.Errorf("Dot with NaN = %v", got)
+	}
+	if got := Dot[float32]([]float32{1e8, 1, -1e8}, []float32{1, 1, 1}); got != 1 {
+		t.Errorf("compensated float32 Dot = %v", got)
+	}
+	if got := Dot[int]([]int{}, []int{1}); got != 0 {
+		t.Errorf("empty Dot = %d", got)
+	}
+	if n := AddVec[int](nil, []int{1}, []int{2}); n != 0 {
+		t.Errorf("AddVec into nil = %d", n)
+	}
+	// a nil y is an empty slice, not a missing argument
+	out := []int{7, 7, 7}
+	for name, op := range map[string]func([]int, []int, []int, ...OverflowPolicy) int{
+		"AddVec": AddVec[int, int], "SubVec": SubVec[int, int], "MulVec": MulVec[int, int], "DivVec": DivVec[int, int],
+	} {
+		if n := op(out, []int{1, 2, 3}, nil); n != 0 || out[0] != 7 {
+			t.Errorf("%s with a nil y = %d %v", name, n, out)
+		}
+	}
+	if n := Axpy(out, 2, []int{1, 2, 3}, nil); n != 0 || out[0] != 7 {
+		t.Errorf("Axpy with a nil y = %d %v", n, out)
+	}
+	if n := Scale(out, 2, []int{1, 2, 3}); n != 3 || out[2] != 6 {
+		t.Errorf("Scale = %d %v", n, out)
+	}
+	if got := NormInf[float64]([]float64{1, math.NaN(), math.Inf(1)}); got == got {
+		t.Errorf("NormInf with NaN = %v", got)
+	}
+	if got := NormInf[float64]([]float64{}); got != 0 {
+		t.Errorf("empty NormInf = %v", got)
+	}
+	if got := Norm2[float64]([]float64{3e200, 4e200}); !math.IsInf(got, 1) {
+		t.Errorf("Norm2 past 1e154 = %v, the doc says it overflows", got)
+	}
+	tests := []struct {
+		xs   []float64
+		want float64
+	}{
+		{[]float64{3e200, 4e200, 0}, 5e200},
+		{[]float64{3e-200, 4e-200, 0}, 5e-200},
+		{[]float64{3e200, 4e200}, 5e200},
+		{[]float64{}, 0},
+		{[]float64{math.NaN(), math.Inf(-1), 1}, math.Inf(1)},
+	}
+	for _, tt := range tests {
+		if got := Hypots[float64](tt.xs); math.Abs(got-tt.want) > 1e-15*tt.want && got != tt.want {
+			t.Errorf("Hypots(%v) = %v, want %v", tt.xs, got, tt.want)
+		}
+	}
+	if got := Hypots[float64]([]float64{math.NaN(), 1, 2}); got == got {
+		t.Errorf("Hypots with NaN = %v", got)
+	}
+	if got := Hypots[int8]([]float64{100, 100, 100}); got != math.MaxInt8 {
+		t.Errorf("Hypots into int8 = %d", got)
+	}
+}
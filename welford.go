@@ -0,0 +1,292 @@
+package RUNK
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math"
+	"strconv"
+)
+
+var errStatsEncoding = errors.New("RUNK: invalid Stats encoding")
+
+/*
+Stats keeps running statistics over a stream without holding on to the samples. The mean and the central moments
+are updated with Welford's algorithm (extended to the 3rd and 4th moments by Terriberry) which doesn't lose
+precision the way summing x and x*x does. Two Stats can be merged so each goroutine can keep its own and combine
+them at the end.
+The zero value is an empty Stats ready to use. It isn't safe for concurrent use.
+Like the slice statistics, the moments of an empty Stats are NaN and a NaN sample turns everything into NaN.
+*/
+type Stats[N Number] struct {
+	n    uint64
+	mean float64
+	m2   float64
+	m3   float64
+	m4   float64
+	min  N
+	max  N
+}
+
+func (s *Stats[N]) Add(xs ...N) {
+	for _, x := range xs {
+		s.add(x)
+	}
+}
+
+func (s *Stats[N]) add(x N) {
+	if s.n == 0 || x != x || less(x, s.min) {
+		s.min = x
+	}
+	if s.n == 0 || x != x || less(s.max, x) {
+		s.max = x
+	}
+	n1 := float64(s.n)
+	s.n++
+	n := float64(s.n)
+	delta := float64(x) - s.mean
+	dn := delta / n
+	dn2 := dn * dn
+	term := delta * dn * n1
+	s.mean += dn
+	s.m4 += term*dn2*(n*n-3*n+3) + 6*dn2*s.m2 - 4*dn*s.m3
+	s.m3 += term*dn*(n-2) - 3*dn*s.m2
+	s.m2 += term
+}
+
+/*Merge folds o into s, the result is the same as if every sample of o had been added to s*/
+func (s *Stats[N]) Merge(o *Stats[N]) {
+	if o.n == 0 {
+		return
+	}
+	if s.n == 0 {
+		*s = *o
+		return
+	}
+	if o.min != o.min || less(o.min, s.min) {
+		s.min = o.min
+	}
+	if o.max != o.max || less(s.max, o.max) {
+		s.max = o.max
+	}
+	na, nb := float64(s.n), float64(o.n)
+	n := na + nb
+	delta := o.mean - s.mean
+	d2 := delta * delta
+	m2 := s.m2 + o.m2 + d2*na*nb/n
+	m3 := s.m3 + o.m3 + d2*delta*na*nb*(na-nb)/(n*n) + 3*delta*(na*o.m2-nb*s.m2)/n
+	m4 := s.m4 + o.m4 + d2*d2*na*nb*(na*na-na*nb+nb*nb)/(n*n*n) +
+		6*d2*(na*na*o.m2+nb*nb*s.m2)/(n*n) + 4*delta*(na*o.m3-nb*s.m3)/n
+	s.n += o.n
+	s.mean += delta * nb / n
+	s.m2, s.m3, s.m4 = m2, m3, m4
+}
+
+func (s *Stats[N]) Reset() {
+	*s = Stats[N]{}
+}
+
+func (s *Stats[N]) Count() uint64 {
+	return s.n
+}
+
+func (s *Stats[N]) Mean() float64 {
+	if s.n == 0 {
+		return math.NaN()
+	}
+	return s.mean
+}
+
+/*Min and Max are 0 until something has been added*/
+func (s *Stats[N]) Min() N {
+	return s.min
+}
+
+func (s *Stats[N]) Max() N {
+	return s.max
+}
+
+/*Variance is the population variance, see SampleVariance for the n-1 version*/
+func (s *Stats[N]) Variance() float64 {
+	if s.n == 0 {
+		return math.NaN()
+	}
+	return s.m2 / float64(s.n)
+}
+
+func (s *Stats[N]) SampleVariance() float64 {
+	if s.n < 2 {
+		return math.NaN()
+	}
+	return s.m2 / float64(s.n-1)
+}
+
+func (s *Stats[N]) StdDev() float64 {
+	return math.Sqrt(s.Variance())
+}
+
+func (s *Stats[N]) SampleStdDev() float64 {
+	return math.Sqrt(s.SampleVariance())
+}
+
+/*Skewness is the population skewness g1, 0 for anything symmetric. It is NaN when all the samples are equal.*/
+func (s *Stats[N]) Skewness() float64 {
+	if s.n == 0 || s.m2 == 0 {
+		return math.NaN()
+	}
+	return math.Sqrt(float64(s.n)) * s.m3 / math.Pow(s.m2, 1.5)
+}
+
+/*Kurtosis is the population excess kurtosis g2, so a normal distribution is 0 rather than 3*/
+func (s *Stats[N]) Kurtosis() float64 {
+	if s.n == 0 || s.m2 == 0 {
+		return math.NaN()
+	}
+	return float64(s.n)*s.m4/(s.m2*s.m2) - 3
+}
+
+/*
+The binary form is a version byte followed by the count, the mean, the three moments and min and max, 8 little
+endian bytes each. Min and max are stored as their float64 bits for the float types and as the integer itself
+otherwise so nothing gets rounded.
+*/
+const statsVersion = 1
+
+func (s Stats[N]) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 1, 1+7*8)
+	b[0] = statsVersion
+	b = binary.LittleEndian.AppendUint64(b, s.n)
+	for _, f := range []float64{s.mean, s.m2, s.m3, s.m4} {
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(f))
+	}
+	b = binary.LittleEndian.AppendUint64(b, numberBits(s.min))
+	b = binary.LittleEndian.AppendUint64(b, numberBits(s.max))
+	return b, nil
+}
+
+func (s *Stats[N]) UnmarshalBinary(data []byte) error {
+	if len(data) != 1+7*8 || data[0] != statsVersion {
+		return errStatsEncoding
+	}
+	u := func(i int) uint64 {
+		return binary.LittleEndian.Uint64(data[1+8*i:])
+	}
+	*s = Stats[N]{
+		n:    u(0),
+		mean: math.Float64frombits(u(1)),
+		m2:   math.Float64frombits(u(2)),
+		m3:   math.Float64frombits(u(3)),
+		m4:   math.Float64frombits(u(4)),
+		min:  numberFromBits[N](u(5)),
+		max:  numberFromBits[N](u(6)),
+	}
+	return nil
+}
+
+func numberBits[N Number](n N) uint64 {
+	if isFloat[N]() {
+		return math.Float64bits(float64(n))
+	}
+	if isSigned[N]() {
+		return uint64(int64(n))
+	}
+	return uint64(n)
+}
+
+func numberFromBits[N Number](b uint64) N {
+	if isFloat[N]() {
+		return N(math.Float64frombits(b))
+	}
+	if isSigned[N]() {
+		return N(int64(b))
+	}
+	return N(b)
+}
+
+type statsJSON struct {
+	Count uint64          `json:"count"`
+	Mean  jsonFloat       `json:"mean"`
+	M2    jsonFloat       `json:"m2"`
+	M3    jsonFloat       `json:"m3"`
+	M4    jsonFloat       `json:"m4"`
+	Min   json.RawMessage `json:"min"`
+	Max   json.RawMessage `json:"max"`
+}
+
+/*MarshalJSON writes the state, not the derived statistics, so that it can be read back and merged*/
+func (s Stats[N]) MarshalJSON() ([]byte, error) {
+	min, err := marshalNumber(s.min)
+	if err != nil {
+		return nil, err
+	}
+	max, err := marshalNumber(s.max)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(statsJSON{
+		Count: s.n, Mean: jsonFloat(s.mean), M2: jsonFloat(s.m2), M3: jsonFloat(s.m3), M4: jsonFloat(s.m4),
+		Min: min, Max: max,
+	})
+}
+
+func (s *Stats[N]) UnmarshalJSON(data []byte) error {
+	var j statsJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	v := Stats[N]{n: j.Count, mean: float64(j.Mean), m2: float64(j.M2), m3: float64(j.M3), m4: float64(j.M4)}
+	if err := unmarshalNumber(j.Min, &v.min); err != nil {
+		return err
+	}
+	if err := unmarshalNumber(j.Max, &v.max); err != nil {
+		return err
+	}
+	*s = v
+	return nil
+}
+
+/*
+jsonFloat is a float64 that survives a trip through JSON. JSON has no NaN or Inf so those are written as the
+strings "NaN", "+Inf" and "-Inf" instead of making json.Marshal fail.
+*/
+type jsonFloat float64
+
+func (f jsonFloat) MarshalJSON() ([]byte, error) {
+	v := float64(f)
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return []byte(strconv.Quote(strconv.FormatFloat(v, 'g', -1, 64))), nil
+	}
+	return []byte(strconv.FormatFloat(v, 'g', -1, 64)), nil
+}
+
+func (f *jsonFloat) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if uq, err := strconv.Unquote(s); err == nil {
+		s = uq
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return err
+	}
+	*f = jsonFloat(v)
+	return nil
+}
+
+func marshalNumber[N Number](n N) (json.RawMessage, error) {
+	if isFloat[N]() {
+		return json.Marshal(jsonFloat(n))
+	}
+	return json.Marshal(n)
+}
+
+func unmarshalNumber[N Number](data json.RawMessage, n *N) error {
+	if !isFloat[N]() {
+		return json.Unmarshal(data, n)
+	}
+	var f jsonFloat
+	if err := json.Unmarshal(data, &f); err != nil {
+		return err
+	}
+	*n = N(f)
+	return nil
+}
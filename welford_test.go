@@ -0,0 +1,172 @@
+package RUNK
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+/*moments is the two pass reference for the population variance, skewness and excess kurtosis*/
+func moments(xs []float64) (v float64, skew float64, kurt float64) {
+	m := Mean(xs)
+	var m2, m3, m4 float64
+	for _, x := range xs {
+		d := x - m
+		m2 += d * d
+		m3 += d * d * d
+		m4 += d * d * d * d
+	}
+	n := float64(len(xs))
+	return m2 / n, math.Sqrt(n) * m3 / math.Pow(m2, 1.5), n*m4/(m2*m2) - 3
+}
+
+func closeTo(a float64, b float64, rel float64) bool {
+	return math.Abs(a-b) <= rel*math.Max(1, math.Abs(b))
+}
+
+func TestStatsMatchesTwoPass(t *testing.T) {
+	rng := rand.New(rand.NewSource(10))
+	xs := make([]float64, 5000)
+	for i := range xs {
+		// skewed and shifted far from 0 so the naive formulas would fall apart
+		xs[i] = 1e6 + rng.ExpFloat64()*3
+	}
+	var s Stats[float64]
+	s.Add(xs...)
+	v, skew, kurt := moments(xs)
+	if s.Count() != 5000 || !closeTo(s.Mean(), Mean(xs), 1e-14) || !closeTo(s.Variance(), v, 1e-9) {
+		t.Errorf("count, mean, variance = %d %v %v, want %v %v", s.Count(), s.Mean(), s.Variance(), Mean(xs), v)
+	}
+	if !closeTo(s.Skewness(), skew, 1e-6) || !closeTo(s.Kurtosis(), kurt, 1e-6) {
+		t.Errorf("skewness, kurtosis = %v %v, want %v %v", s.Skewness(), s.Kurtosis(), skew, kurt)
+	}
+	if !closeTo(s.SampleVariance(), SampleVariance(xs), 1e-9) || !closeTo(s.StdDev(), math.Sqrt(v), 1e-9) {
+		t.Errorf("sample variance = %v, want %v", s.SampleVariance(), SampleVariance(xs))
+	}
+	lo, hi, _ := MinMax(xs)
+	if s.Min() != lo || s.Max() != hi {
+		t.Errorf("min, max = %v %v", s.Min(), s.Max())
+	}
+	// merging pieces of any size has to agree with adding everything to one
+	var merged Stats[float64]
+	for start := 0; start < len(xs); {
+		end := min(len(xs), start+1+rng.Intn(700))
+		var part Stats[float64]
+		part.Add(xs[start:end]...)
+		merged.Merge(&part)
+		start = end
+	}
+	merged.Merge(&Stats[float64]{})
+	if merged.Count() != s.Count() || !closeTo(merged.Mean(), s.Mean(), 1e-14) || !closeTo(merged.Variance(), v, 1e-9) ||
+		!closeTo(merged.Skewness(), skew, 1e-6) || !closeTo(merged.Kurtosis(), kurt, 1e-6) {
+		t.Errorf("merged = %v %v %v %v", merged.Mean(), merged.Variance(), merged.Skewness(), merged.Kurtosis())
+	}
+	if merged.Min() != lo || merged.Max() != hi {
+		t.Error("merged min and max are wrong")
+	}
+}
+
+func TestStatsEmptyAndNaN(t *testing.T) {
+	var s Stats[int]
+	for name, got := range map[string]float64{
+		"Mean": s.Mean(), "Variance": s.Variance(), "SampleVariance": s.SampleVariance(),
+		"Skewness": s.Skewness(), "Kurtosis": s.Kurtosis(),
+	} {
+		if got == got {
+			t.Errorf("empty %s = %v, want NaN", name, got)
+		}
+	}
+	if s.Min() != 0 || s.Max() != 0 {
+		t.Error("empty min and max should be 0")
+	}
+	s.Add(5, 5, 5)
+	if s.Variance() != 0 || s.Skewness() == s.Skewness() {
+		t.Errorf("constant samples = %v %v", s.Variance(), s.Skewness())
+	}
+	s.Reset()
+	if s.Count() != 0 {
+		t.Error("Reset left samples behind")
+	}
+	var f Stats[float64]
+	f.Add(1, math.NaN(), 3)
+	if m := f.Mean(); m == m {
+		t.Errorf("mean with NaN = %v", m)
+	}
+	if m := f.Min(); m == m {
+		t.Errorf("min with NaN = %v, want NaN to stick", m)
+	}
+	var g Stats[float64]
+	g.Add(-1)
+	g.Merge(&f)
+	if m := g.Max(); m == m {
+		t.Errorf("merged max = %v, want the NaN to carry over", m)
+	}
+}
+
+func TestStatsEncoding(t *testing.T) {
+	var s Stats[int64]
+	s.Add(math.MinInt64, math.MaxInt64, 7)
+	b, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var back Stats[int64]
+	if err := back.UnmarshalBinary(b); err != nil || back != s {
+		t.Errorf("binary round trip = %+v %v, want %+v", back, err, s)
+	}
+	if err := back.UnmarshalBinary(b[:10]); err == nil {
+		t.Error("a short encoding was accepted")
+	}
+	bad := append([]byte(nil), b...)
+	bad[0] = 99
+	if err := back.UnmarshalBinary(bad); err == nil {
+		t.Error("an unknown version was accepted")
+	}
+
+	j, err := json.Marshal(&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var jback Stats[int64]
+	if err := json.Unmarshal(j, &jback); err != nil || jback != s {
+		t.Errorf("JSON round trip of %s = %+v %v", j, jback, err)
+	}
+	// held by value inside something else it still writes its state, not {}
+	type holder struct {
+		S  Stats[int64]
+		Ss []Stats[int64]
+	}
+	j, err = json.Marshal(holder{S: s, Ss: []Stats[int64]{s}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var hback holder
+	if err := json.Unmarshal(j, &hback); err != nil || hback.S != s || len(hback.Ss) != 1 || hback.Ss[0] != s {
+		t.Errorf("JSON round trip by value of %s = %+v %v", j, hback, err)
+	}
+
+	// NaN and Inf aren't JSON so they go through as strings
+	var f Stats[float32]
+	f.Add(float32(math.Inf(-1)), 2)
+	j, err = json.Marshal(&f)
+	if err != nil {
+		t.Fatalf("marshal with Inf: %v", err)
+	}
+	var fback Stats[float32]
+	if err := json.Unmarshal(j, &fback); err != nil || !math.IsInf(float64(fback.Min()), -1) || fback.Count() != 2 {
+		t.Errorf("JSON round trip of %s = %+v %v", j, fback, err)
+	}
+	if m := fback.Mean(); m == m {
+		t.Errorf("mean after -Inf = %v", m)
+	}
+	if err := json.Unmarshal([]byte(`{"count":1,"mean":"nope"}`), &fback); err == nil {
+		t.Error("a bad mean was accepted")
+	}
+	var empty Stats[uint8]
+	b, _ = empty.MarshalBinary()
+	var eback Stats[uint8]
+	if err := eback.UnmarshalBinary(b); err != nil || eback.Count() != 0 || eback.Mean() == eback.Mean() {
+		t.Error("empty round trip is wrong")
+	}
+}